@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/traitmeta/gotos/lib/db"
+
+	"github.com/traitmeta/metago/btc/block"
+	"github.com/traitmeta/metago/btc/brc20"
+	"github.com/traitmeta/metago/btc/inscribe/inscriber"
+	tapsync "github.com/traitmeta/metago/btc/ord/tap/sync"
+	"github.com/traitmeta/metago/btc/runes-tools/walletmgr"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/pkg/metrics"
+)
+
+// bitcoin 连接 bitcoind, 退出时关闭连接
+func (a *app) bitcoin() (*rpcclient.Client, *chaincfg.Params, error) {
+	conf := config.Bitcoin
+	if conf.Host == "" {
+		return nil, nil, errors.New("Bitcoin.Host is not configured")
+	}
+	net, err := conf.Params()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         conf.Host,
+		User:         conf.User,
+		Pass:         conf.Pass,
+		HTTPPostMode: true,
+		DisableTLS:   conf.DisableTLS,
+	}, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "connect bitcoind")
+	}
+	a.OnStop(client.Shutdown)
+	return client, net, nil
+}
+
+func runBtcIndexBlocks(ctx context.Context, a *app, args []string) error {
+	newFlagSet("btc index-blocks").Parse(args)
+	client, _, err := a.bitcoin()
+	if err != nil {
+		return err
+	}
+	a.setupDB()
+	if _, err := metrics.Setup(ctx, config.Metrics); err != nil {
+		return errors.Wrap(err, "metrics.Setup")
+	}
+
+	block.New(ctx, client, db.DBEngine.DB).Start()
+	return nil
+}
+
+func runTapIndex(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("tap index")
+	cacheDir := fs.String("cache-dir", config.LevelDB.TapCache, "leveldb directory of the DMT cache")
+	fs.Parse(args)
+
+	client, _, err := a.bitcoin()
+	if err != nil {
+		return err
+	}
+	a.setupDB()
+
+	cache, err := tapsync.NewCache(*cacheDir)
+	if err != nil {
+		return err
+	}
+	a.OnStop(func() { cache.Close() })
+
+	rds := redis.NewClient(&redis.Options{Addr: config.Redis.Addr, Password: config.Redis.Pwd, DB: config.Redis.DB})
+	a.OnStop(func() { rds.Close() })
+	if err := rds.Ping(ctx).Err(); err != nil {
+		return errors.Wrap(err, "connect redis")
+	}
+	if _, err := metrics.Setup(ctx, config.Metrics); err != nil {
+		return errors.Wrap(err, "metrics.Setup")
+	}
+
+	tapsync.NewDMTIndexer(ctx, client, db.DBEngine.DB, cache, rds).Start()
+	return nil
+}
+
+func runBrc20Index(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("brc20 index")
+	start := fs.Int64("start", -1, "height to start from when nothing is indexed, defaults to the first BRC-20 block of the network")
+	migrate := fs.Bool("migrate", true, "migrate the BRC-20 tables before indexing")
+	fs.Parse(args)
+
+	client, net, err := a.bitcoin()
+	if err != nil {
+		return err
+	}
+	a.setupDB()
+	if *migrate {
+		if err := brc20.Migrate(db.DBEngine.DB); err != nil {
+			return errors.Wrap(err, "migrate")
+		}
+	}
+	if *start < 0 {
+		*start = brc20.StartHeight(net)
+	}
+	if _, err := metrics.Setup(ctx, config.Metrics); err != nil {
+		return errors.Wrap(err, "metrics.Setup")
+	}
+
+	brc20.New(ctx, client, net, db.DBEngine.DB, *start).Start()
+	return nil
+}
+
+func runInscribe(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("inscribe")
+	receiver := fs.String("receiver", "", "address receiving the minted runes")
+	runeId := fs.String("rune", "", "rune id to mint, block:tx")
+	count := fs.Int64("count", 1, "number of mints")
+	feeRate := fs.Int64("fee-rate", 10, "fee rate in sat/vB")
+	orderFile := fs.String("order", "", "order file, written when creating the order and read with -pay-tx")
+	payTx := fs.String("pay-tx", "", "hash of the transaction paying the order, mints the order in -order")
+	fs.Parse(args)
+	if *orderFile == "" {
+		fs.Usage()
+		return errors.New("-order is required")
+	}
+
+	client, net, err := a.bitcoin()
+	if err != nil {
+		return err
+	}
+	ins := inscriber.NewInscriber(net, inscriber.RPCClient{Client: client})
+
+	if *payTx != "" {
+		data, err := os.ReadFile(*orderFile)
+		if err != nil {
+			return err
+		}
+		var order inscriber.Order
+		if err := json.Unmarshal(data, &order); err != nil {
+			return errors.Wrap(err, "decode order")
+		}
+		sr, err := ins.Inscribe(&order, *payTx)
+		if err != nil {
+			return err
+		}
+		log.Printf("order %s minted %d reveal txs", order.Id, len(sr.RevealTxs))
+		return nil
+	}
+
+	if *receiver == "" || *runeId == "" || *count <= 0 {
+		fs.Usage()
+		return errors.New("-receiver, -rune and a positive -count are required")
+	}
+	order, err := ins.CreateOrder(*receiver, *runeId, *count)
+	if err != nil {
+		return err
+	}
+	order.FeeRate = *feeRate
+	fee, payAddress, err := ins.CalcFee(order)
+	if err != nil {
+		return err
+	}
+	// 订单中有支付地址的私钥, 只允许当前用户读取
+	data, err := json.MarshalIndent(order, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*orderFile, data, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("pay %d sats to %s, then run: metago inscribe -order %s -pay-tx <hash>\n", fee, payAddress, *orderFile)
+	return nil
+}
+
+func runRunesMint(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("runes mint")
+	runeId := fs.String("rune", "", "rune id to mint, block:tx")
+	to := fs.String("to", "", "address receiving the minted runes")
+	cacheDir := fs.String("cache-dir", config.LevelDB.RunesWallet, "wallet cache directory")
+	initWallets := fs.Bool("init", false, "generate and cache new wallets instead of minting")
+	fs.Parse(args)
+
+	client, net, err := a.bitcoin()
+	if err != nil {
+		return err
+	}
+	if *initWallets {
+		if err := walletmgr.InitAndCacheWalletWif(*cacheDir, net); err != nil {
+			return err
+		}
+		log.Printf("wallets cached in %s", *cacheDir)
+		return nil
+	}
+	if *runeId == "" || *to == "" {
+		fs.Usage()
+		return errors.New("-rune and -to are required")
+	}
+
+	mgr, err := walletmgr.InitWalletMgr(*cacheDir, net)
+	if err != nil {
+		return err
+	}
+	mgr.MintsRunes(client, *runeId, *to)
+	return nil
+}
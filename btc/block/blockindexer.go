@@ -13,15 +13,17 @@ import (
 	"github.com/traitmeta/metago/btc/block/dal"
 	"github.com/traitmeta/metago/btc/block/model"
 	"github.com/traitmeta/metago/btc/sync"
+	"github.com/traitmeta/metago/pkg/metrics"
 )
 
 const TapBlock = "block"
 const RollBackBlockNumber = 3
 
 type Indexer struct {
-	ctx    context.Context
-	dal    *dal.Dal
-	client *rpcclient.Client
+	ctx     context.Context
+	dal     *dal.Dal
+	client  *rpcclient.Client
+	metrics *metrics.Indexer
 	*sync.BaseSync
 }
 
@@ -30,25 +32,13 @@ func New(ctx context.Context, client *rpcclient.Client, db *gorm.DB) *Indexer {
 		ctx:      ctx,
 		dal:      dal.NewDal(db),
 		client:   client,
+		metrics:  metrics.NewIndexer("btc_block"),
 		BaseSync: sync.NewBaseSync(),
 	}
 }
 
 func (s *Indexer) Start() {
-	s.Send()
-	for {
-		select {
-		case <-s.Receive():
-			err := s.SyncBlock()
-			if err != nil {
-				log.WithContext(s.ctx).WithField("err", err).Error("SyncBlock failed on query block table")
-				time.Sleep(10 * time.Second)
-				s.Send()
-			}
-		case <-s.ctx.Done():
-			return
-		}
-	}
+	s.Run(s.ctx, s.SyncBlock, sync.RetryInterval)
 }
 
 func (s *Indexer) SyncBlock() error {
@@ -57,18 +47,31 @@ func (s *Indexer) SyncBlock() error {
 		return err
 	}
 
+	s.metrics.SetIndexed(uint64(indexBlock.BlockHeight))
 	blockHeight := indexBlock.BlockHeight + 1
 	start := time.Now()
+	head, err := s.client.GetBlockCount()
+	if err != nil {
+		s.metrics.RPCError()
+		return err
+	}
+	s.metrics.SetHead(uint64(head))
+	if blockHeight > head {
+		return errors.Errorf("block %d is beyond chain head %d", blockHeight, head)
+	}
+
 	blockHash, err := s.client.GetBlockHash(blockHeight)
 	if err != nil {
+		s.metrics.RPCError()
 		return err
 	}
 
-	start = time.Now()
 	blockHeader, err := s.client.GetBlockHeader(blockHash)
 	if err != nil {
+		s.metrics.RPCError()
 		return err
 	}
+	s.metrics.ObserveStage(metrics.StageFetch, start)
 
 	log.WithContext(s.ctx).WithField("time_spend", time.Since(start)).Info("SyncBlock get block hash end")
 	// check fork
@@ -86,6 +89,7 @@ func (s *Indexer) SyncBlock() error {
 		return nil
 	}
 
+	start = time.Now()
 	blockBits := model.BlockInfo{
 		BlockNumber: blockHeight,
 		BlockHash:   blockHash.String(),
@@ -99,6 +103,8 @@ func (s *Indexer) SyncBlock() error {
 	if err := s.dal.UpdateBlockByName(TapBlock, blockHash.String(), blockHeight); err != nil {
 		return err
 	}
+	s.metrics.ObserveStage(metrics.StagePersist, start)
+	s.metrics.SetIndexed(uint64(blockHeight))
 
 	return nil
 }
@@ -107,11 +113,14 @@ func (s *Indexer) RollBack(blockHeight int64, backNumber int64) error {
 	rollbackNumber := blockHeight - backNumber
 	blockHash, err := s.client.GetBlockHash(rollbackNumber)
 	if err != nil {
+		s.metrics.RPCError()
 		return err
 	}
 
 	if err := s.dal.UpdateBlockByName(TapBlock, blockHash.String(), rollbackNumber); err != nil {
 		return err
 	}
+	s.metrics.Rollback()
+	s.metrics.SetIndexed(uint64(rollbackNumber))
 	return nil
 }
@@ -1,238 +1,75 @@
 package brc20
 
 import (
+	"encoding/json"
+
 	"github.com/shopspring/decimal"
 )
 
-// Global variables
-var (
-	blockStartMaxEventID                   int64
-	brc20EventsInsertCache                 []Event
-	brc20TickersInsertCache                []Ticker
-	brc20TickersRemainingSupplyUpdateCache = make(map[string]decimal.Decimal)
-	brc20TickersBurnedSupplyUpdateCache    = make(map[string]decimal.Decimal)
-	brc20HistoricBalancesInsertCache       []WalletBalance
-	ticks                                  = make(map[string][]interface{})
-	eventTypes                             = map[string]int{"deploy-inscribe": 1, "mint-inscribe": 2, "transfer-inscribe": 3, "transfer-transfer": 4}
-	blockEventsStr                         string
-	EVENT_SEPARATOR                        = "|"
+// 事件类型, 与 brc20_events.event_type 一致
+const (
+	EventDeployInscribe   = 1
+	EventMintInscribe     = 2
+	EventTransferInscribe = 3
+	EventTransferTransfer = 4
 )
 
-// Event structure to hold event details
-type Event struct {
-	ID            int64
-	EventType     int
-	BlockHeight   int64
-	InscriptionID string
-	Event         string
+var eventTypeNames = map[int]string{
+	EventDeployInscribe:   "deploy-inscribe",
+	EventMintInscribe:     "mint-inscribe",
+	EventTransferInscribe: "transfer-inscribe",
+	EventTransferTransfer: "transfer-transfer",
 }
 
-// Ticker structure to hold ticker details
-type Ticker struct {
-	Tick                string
-	OriginalTick        string
-	MaxSupply           decimal.Decimal
-	Decimals            int
-	LimitPerMint        decimal.Decimal
-	RemainingSupply     decimal.Decimal
-	BlockHeight         int64
-	IsSelfMint          bool
-	DeployInscriptionID string
-}
-
-// WalletBalance structure to hold balance details
-type WalletBalance struct {
-	PkScript         string
-	Wallet           string
-	Tick             string
-	OverallBalance   decimal.Decimal
-	AvailableBalance decimal.Decimal
-	BlockHeight      int64
-	EventID          int64
-}
-
-// // deployInscribe handles deploy-inscribe events
-// func deployInscribe(blockHeight int64, inscriptionID, deployerPkScript, deployerWallet, tick, originalTick string, maxSupply, limitPerMint decimal.Decimal, decimals int, isSelfMint string) {
-// 	event := map[string]string{
-// 		"deployer_pkScript": deployerPkScript,
-// 		"deployer_wallet":   deployerWallet,
-// 		"tick":              tick,
-// 		"original_tick":     originalTick,
-// 		"max_supply":        fmt.Sprintf("%d", maxSupply),
-// 		"decimals":          fmt.Sprintf("%d", decimals),
-// 		"limit_per_mint":    fmt.Sprintf("%d", limitPerMint),
-// 		"is_self_mint":      isSelfMint,
-// 	}
-// 	eventStr, _ := json.Marshal(event)
-// 	blockEventsStr += string(eventStr) + EVENT_SEPARATOR
-
-// 	eventID := blockStartMaxEventID + int64(len(brc20EventsInsertCache)+1)
-// 	brc20EventsInsertCache = append(brc20EventsInsertCache, Event{eventID, eventTypes["deploy-inscribe"], blockHeight, inscriptionID, string(eventStr)})
-
-// 	brc20TickersInsertCache = append(brc20TickersInsertCache, Ticker{tick, originalTick, maxSupply, decimals, limitPerMint, maxSupply, blockHeight, isSelfMint == "true", inscriptionID})
-
-// 	ticks[tick] = []interface{}{maxSupply, limitPerMint, decimals, isSelfMint == "true", inscriptionID}
-// }
-
-// // mintInscribe handles mint-inscribe events
-// func mintInscribe(blockHeight int64, inscriptionID, mintedPkScript, mintedWallet, tick, originalTick string, amount decimal.Decimal, parentID string) error {
-// 	event := map[string]string{
-// 		"minted_pkScript": mintedPkScript,
-// 		"minted_wallet":   mintedWallet,
-// 		"tick":            tick,
-// 		"original_tick":   originalTick,
-// 		"amount":          amount.String(),
-// 		"parent_id":       parentID,
-// 	}
-// 	eventStr, _ := json.Marshal(event)
-// 	blockEventsStr += string(eventStr) + EVENT_SEPARATOR
-
-// 	eventID := blockStartMaxEventID + int64(len(brc20EventsInsertCache)+1)
-// 	brc20EventsInsertCache = append(brc20EventsInsertCache, Event{eventID, eventTypes["mint-inscribe"], blockHeight, inscriptionID, string(eventStr)})
-// 	brc20TickersRemainingSupplyUpdateCache[tick] = brc20TickersRemainingSupplyUpdateCache[tick].Add(amount)
-
-// 	lastBalance, err := getLastBalance(mintedPkScript, tick)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	lastBalance.OverallBalance = lastBalance.OverallBalance.Add(amount)
-// 	lastBalance.AvailableBalance = lastBalance.AvailableBalance.Add(amount)
-// 	brc20HistoricBalancesInsertCache = append(brc20HistoricBalancesInsertCache, WalletBalance{mintedPkScript, mintedWallet, tick, lastBalance.OverallBalance, lastBalance.AvailableBalance, blockHeight, eventID})
-
-// 	ticks[tick][0] = ticks[tick][0].(int) - amount
-// 	return nil
-// }
-
-// // transferInscribe handles transfer-inscribe events
-// func transferInscribe(blockHeight int, inscriptionID, sourcePkScript, sourceWallet, tick, originalTick string, amount int) {
-// 	event := map[string]string{
-// 		"source_pkScript": sourcePkScript,
-// 		"source_wallet":   sourceWallet,
-// 		"tick":            tick,
-// 		"original_tick":   originalTick,
-// 		"amount":          fmt.Sprintf("%d", amount),
-// 	}
-// 	eventStr, _ := json.Marshal(event)
-// 	blockEventsStr += string(eventStr) + EVENT_SEPARATOR
-
-// 	eventID := blockStartMaxEventID + len(brc20EventsInsertCache) + 1
-// 	brc20EventsInsertCache = append(brc20EventsInsertCache, Event{eventID, eventTypes["transfer-inscribe"], blockHeight, inscriptionID, string(eventStr)})
-// 	setTransferAsValid(inscriptionID)
-
-// 	lastBalance := getLastBalance(sourcePkScript, tick)
-// 	lastBalance.AvailableBalance -= amount
-// 	brc20HistoricBalancesInsertCache = append(brc20HistoricBalancesInsertCache, WalletBalance{sourcePkScript, sourceWallet, tick, lastBalance.OverallBalance, lastBalance.AvailableBalance, blockHeight, eventID})
+// EVENT_SEPARATOR 区块事件字符串之间的分隔符, 用于计算事件哈希
+const EVENT_SEPARATOR = "|"
 
-// 	saveTransferInscribeEvent(inscriptionID, event)
-// }
+// burnPkScript 只有 OP_RETURN 的输出, 转入的金额计入 tick 的销毁量
+const burnPkScript = "6a"
 
-// // transferTransferNormal handles normal transfer-transfer events
-// func transferTransferNormal(blockHeight int, inscriptionID, spentPkScript, spentWallet, tick, originalTick string, amount int, usingTxID string) {
-// 	inscribeEvent := getTransferInscribeEvent(inscriptionID)
-// 	sourcePkScript := inscribeEvent["source_pkScript"]
-// 	sourceWallet := inscribeEvent["source_wallet"]
-// 	event := map[string]string{
-// 		"source_pkScript": sourcePkScript,
-// 		"source_wallet":   sourceWallet,
-// 		"spent_pkScript":  spentPkScript,
-// 		"spent_wallet":    spentWallet,
-// 		"tick":            tick,
-// 		"original_tick":   originalTick,
-// 		"amount":          fmt.Sprintf("%d", amount),
-// 		"using_tx_id":     usingTxID,
-// 	}
-// 	eventStr, _ := json.Marshal(event)
-// 	blockEventsStr += string(eventStr) + EVENT_SEPARATOR
-
-// 	eventID := blockStartMaxEventID + len(brc20EventsInsertCache) + 1
-// 	brc20EventsInsertCache = append(brc20EventsInsertCache, Event{eventID, eventTypes["transfer-transfer"], blockHeight, inscriptionID, string(eventStr)})
-// 	setTransferAsUsed(inscriptionID)
-
-// 	lastBalance := getLastBalance(sourcePkScript, tick)
-// 	lastBalance.OverallBalance -= amount
-// 	brc20HistoricBalancesInsertCache = append(brc20HistoricBalancesInsertCache, WalletBalance{sourcePkScript, sourceWallet, tick, lastBalance.OverallBalance, lastBalance.AvailableBalance, blockHeight, eventID})
-
-// 	if spentPkScript != sourcePkScript {
-// 		lastBalance = getLastBalance(spentPkScript, tick)
-// 	}
-// 	lastBalance.OverallBalance += amount
-// 	lastBalance.AvailableBalance += amount
-// 	brc20HistoricBalancesInsertCache = append(brc20HistoricBalancesInsertCache, WalletBalance{spentPkScript, spentWallet, tick, lastBalance.OverallBalance, lastBalance.AvailableBalance, blockHeight, -1 * eventID}) // negated to make a unique event_id
-
-// 	if spentPkScript == "6a" {
-// 		brc20TickersBurnedSupplyUpdateCache[tick] += amount
-// 	}
-// }
-
-// // transferTransferSpendToFee handles transfer-transfer events where the spent amount is converted to a fee
-// func transferTransferSpendToFee(blockHeight int, inscriptionID, tick, originalTick string, amount decimal.Decimal, usingTxID string) error {
-// 	inscribeEvent, err := getTransferInscribeEvent(inscriptionID)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	sourcePkScript := inscribeEvent["source_pkScript"]
-// 	sourceWallet := inscribeEvent["source_wallet"]
-// 	event := map[string]string{
-// 		"source_pkScript": sourcePkScript,
-// 		"source_wallet":   sourceWallet,
-// 		"spent_pkScript":  "",
-// 		"spent_wallet":    "",
-// 		"tick":            tick,
-// 		"original_tick":   originalTick,
-// 		"amount":          fmt.Sprintf("%d", amount),
-// 		"using_tx_id":     usingTxID,
-// 	}
-// 	eventStr, _ := json.Marshal(event)
-// 	blockEventsStr += string(eventStr) + EVENT_SEPARATOR
-
-// 	eventID := blockStartMaxEventID + len(brc20EventsInsertCache) + 1
-// 	brc20EventsInsertCache = append(brc20EventsInsertCache, Event{eventID, eventTypes["transfer-transfer"], blockHeight, inscriptionID, string(eventStr)})
-// 	setTransferAsUsed(inscriptionID)
-
-// 	lastBalance, err := getLastBalance(sourcePkScript, tick)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	lastBalance.AvailableBalance = lastBalance.AvailableBalance.Add(amount)
-// 	brc20HistoricBalancesInsertCache = append(brc20HistoricBalancesInsertCache, WalletBalance{sourcePkScript, sourceWallet, tick, lastBalance.OverallBalance, lastBalance.AvailableBalance, blockHeight, eventID})
-// 	return nil
-// }
-
-// // updateEventHashes updates the event hashes for the block
-// func updateEventHashes(blockHeight int64) error {
-// 	if len(blockEventsStr) > 0 && blockEventsStr[len(blockEventsStr)-1] == byte(EVENT_SEPARATOR[0]) {
-// 		blockEventsStr = blockEventsStr[:len(blockEventsStr)-1] // remove last separator
-// 	}
-// 	blockEventHash := getSha256Hash(blockEventsStr)
-
-// 	var eventHash Brc20CumulativeEventHashes
-// 	if err := db.DBEngine.DB.Model(&Brc20CumulativeEventHashes{}).
-// 		Where("block_height = ?", blockHeight-1).
-// 		Scan(&eventHash).Error; err != nil {
-// 		if err == gorm.ErrRecordNotFound {
-// 			eventHash.CumulativeEventHash = blockEventHash
-// 		} else {
-// 			return err
-// 		}
-// 	}
-
-// 	eventHash.CumulativeEventHash = getSha256Hash(eventHash.CumulativeEventHash + blockEventHash)
+// Event 区块内的一个有效事件和它之后的余额
+type Event struct {
+	Brc20Events
+	// Balances 事件改变的余额, 写库时 EventId 设置为事件的 ID
+	Balances []Brc20HistoricBalances
+}
 
-// 	eventHash.BlockHeight = blockHeight
-// 	eventHash.BlockEventHash = blockEventHash
-// 	return db.DBEngine.DB.Model(&Brc20CumulativeEventHashes{}).Create(&eventHash).Error
-// }
+// scaled 金额放大 18 位小数后的整数字符串, 事件 JSON 中的金额使用这个格式
+func scaled(amount decimal.Decimal) string {
+	return amount.Shift(MaxDecimals).StringFixed(0)
+}
 
-// // Dummy functions for missing implementations
-// func getEventStr(event map[string]string, eventType, inscriptionID string) string {
-// 	// Implement this function based on your requirements
-// 	return ""
-// }
+// unscaled scaled 的逆运算
+func unscaled(s string) (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Shift(-MaxDecimals), nil
+}
 
-// func getSha256Hash(data string) string {
-// 	// Implement this function to return SHA256 hash of the input data
-// 	return ""
-// }
+// supplyChanges 统计事件铸造和销毁的数量, 回滚时从 tick 的剩余供应量和销毁量中撤销
+func supplyChanges(events []Brc20Events) (minted, burned map[string]decimal.Decimal, err error) {
+	minted, burned = map[string]decimal.Decimal{}, map[string]decimal.Decimal{}
+	for _, e := range events {
+		if e.EventType != EventMintInscribe && e.EventType != EventTransferTransfer {
+			continue
+		}
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(e.Event), &fields); err != nil {
+			return nil, nil, err
+		}
+		amount, err := unscaled(fields["amount"])
+		if err != nil {
+			return nil, nil, err
+		}
+		tick := fields["tick"]
+		switch {
+		case e.EventType == EventMintInscribe:
+			minted[tick] = minted[tick].Add(amount)
+		case fields["spent_pkScript"] == burnPkScript:
+			burned[tick] = burned[tick].Add(amount)
+		}
+	}
+	return minted, burned, nil
+}
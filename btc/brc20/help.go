@@ -99,13 +99,13 @@ func fixNumStrDecimals(numStr string, decimals int) string {
 	return numStr
 }
 
-// getEventStr generates the event string based on the event type
-func convertEventStr(event map[string]string, eventType string, inscriptionID string, ticks map[string][]interface{}) string {
+// convertEventStr generates the event string based on the event type, decimals is the decimals of the tick
+func convertEventStr(event map[string]string, eventType string, inscriptionID string, decimals int) string {
 	var res string
 	var decimalsInt int
 	switch eventType {
 	case "deploy-inscribe":
-		decimalsInt, _ = strconv.Atoi(event["decimals"])
+		decimalsInt = decimals
 		res = "deploy-inscribe;"
 		res += inscriptionID + ";"
 		res += event["deployer_pkScript"] + ";"
@@ -116,7 +116,7 @@ func convertEventStr(event map[string]string, eventType string, inscriptionID st
 		res += fixNumStrDecimals(event["limit_per_mint"], decimalsInt) + ";"
 		res += event["is_self_mint"]
 	case "mint-inscribe":
-		decimalsInt = ticks[event["tick"]][2].(int)
+		decimalsInt = decimals
 		res = "mint-inscribe;"
 		res += inscriptionID + ";"
 		res += event["minted_pkScript"] + ";"
@@ -125,7 +125,7 @@ func convertEventStr(event map[string]string, eventType string, inscriptionID st
 		res += fixNumStrDecimals(event["amount"], decimalsInt) + ";"
 		res += event["parent_id"]
 	case "transfer-inscribe":
-		decimalsInt = ticks[event["tick"]][2].(int)
+		decimalsInt = decimals
 		res = "transfer-inscribe;"
 		res += inscriptionID + ";"
 		res += event["source_pkScript"] + ";"
@@ -133,7 +133,7 @@ func convertEventStr(event map[string]string, eventType string, inscriptionID st
 		res += event["original_tick"] + ";"
 		res += fixNumStrDecimals(event["amount"], decimalsInt)
 	case "transfer-transfer":
-		decimalsInt = ticks[event["tick"]][2].(int)
+		decimalsInt = decimals
 		res = "transfer-transfer;"
 		res += inscriptionID + ";"
 		res += event["source_pkScript"] + ";"
@@ -192,11 +192,8 @@ func test() {
 		"source_pkScript":   "sourceScript",
 		"spent_pkScript":    "spentScript",
 	}
-	ticks := map[string][]interface{}{
-		"tickValue": {0, 1, 2},
-	}
 	inscriptionID := "inscriptionId"
-	fmt.Println("Event String:", convertEventStr(event, "deploy-inscribe", inscriptionID, ticks))
+	fmt.Println("Event String:", convertEventStr(event, "deploy-inscribe", inscriptionID, 2))
 
 	inputStr := "Hello, World!"
 	fmt.Println("SHA-256 Hash:", getSHA256Hash(inputStr))
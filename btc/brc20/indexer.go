@@ -0,0 +1,138 @@
+package brc20
+
+import (
+	"context"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/btc/ord/common"
+	"github.com/traitmeta/metago/btc/sync"
+	"github.com/traitmeta/metago/pkg/metrics"
+)
+
+var _ Client = (*rpcclient.Client)(nil)
+
+// Client 索引使用的 bitcoind 接口, *rpcclient.Client 实现了该接口
+type Client interface {
+	TxFetcher
+	GetBlockCount() (int64, error)
+	GetBlockHash(blockHeight int64) (*chainhash.Hash, error)
+	GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error)
+}
+
+// StartHeight 没有索引记录时开始同步的高度, 主网为第一个 BRC-20 deploy 所在的区块
+func StartHeight(net *chaincfg.Params) int64 {
+	if net.Name == chaincfg.MainNetParams.Name {
+		return 779832
+	}
+	return 0
+}
+
+type Indexer struct {
+	ctx         context.Context
+	client      Client
+	store       Store
+	processor   *Processor
+	metrics     *metrics.Indexer
+	startHeight int64
+	*sync.BaseSync
+}
+
+func New(ctx context.Context, client Client, net *chaincfg.Params, db *gorm.DB, startHeight int64) *Indexer {
+	return newIndexer(ctx, client, net, NewDal(db), startHeight)
+}
+
+func newIndexer(ctx context.Context, client Client, net *chaincfg.Params, store Store, startHeight int64) *Indexer {
+	return &Indexer{
+		ctx:         ctx,
+		client:      client,
+		store:       store,
+		processor:   NewProcessor(store, client, net),
+		metrics:     metrics.NewIndexer(common.BRC20),
+		startHeight: startHeight,
+		BaseSync:    sync.NewBaseSync(),
+	}
+}
+
+func (s *Indexer) Start() {
+	s.Run(s.ctx, s.SyncBlock, sync.RetryInterval)
+}
+
+func (s *Indexer) SyncBlock() error {
+	lastBlock, err := s.store.LastBlock()
+	if err != nil {
+		return err
+	}
+	blockHeight := s.startHeight
+	if lastBlock != nil {
+		blockHeight = lastBlock.BlockHeight + 1
+		s.metrics.SetIndexed(uint64(lastBlock.BlockHeight))
+	}
+
+	start := time.Now()
+	head, err := s.client.GetBlockCount()
+	if err != nil {
+		s.metrics.RPCError()
+		return err
+	}
+	s.metrics.SetHead(uint64(head))
+	if blockHeight > head {
+		return errors.Errorf("block %d is beyond chain head %d", blockHeight, head)
+	}
+
+	blockHash, err := s.client.GetBlockHash(blockHeight)
+	if err != nil {
+		s.metrics.RPCError()
+		return err
+	}
+	block, err := s.client.GetBlock(blockHash)
+	if err != nil {
+		s.metrics.RPCError()
+		return err
+	}
+	s.metrics.ObserveStage(metrics.StageFetch, start)
+
+	// 检查分叉
+	if lastBlock != nil && lastBlock.BlockHash != block.Header.PrevBlock.String() {
+		return s.RollBack(blockHeight, common.RollBackBlockNumber)
+	}
+
+	start = time.Now()
+	prevHash, err := s.store.CumulativeEventHash(blockHeight - 1)
+	if err != nil {
+		return err
+	}
+	result, err := s.processor.ProcessBlock(blockHeight, block, prevHash)
+	if err != nil {
+		return err
+	}
+	s.metrics.ObserveStage(metrics.StageParse, start)
+
+	start = time.Now()
+	if err := s.store.SaveBlock(result); err != nil {
+		return errors.Wrapf(err, "save block %d", blockHeight)
+	}
+	s.metrics.ObserveStage(metrics.StagePersist, start)
+	s.metrics.SetIndexed(uint64(blockHeight))
+	log.WithContext(s.ctx).WithFields(log.Fields{"block_height": blockHeight, "events": len(result.Events)}).Debug("brc20 block indexed")
+	return nil
+}
+
+// RollBack 删除 blockHeight-backNumber 之后的数据, 下一次从该高度之后重新同步
+func (s *Indexer) RollBack(blockHeight int64, backNumber int64) error {
+	rollbackNumber := blockHeight - backNumber
+	log.WithContext(s.ctx).WithField("block_height", rollbackNumber).Info("brc20 rolling back")
+	if err := s.store.RollBack(rollbackNumber); err != nil {
+		return errors.Wrapf(err, "roll back to %d", rollbackNumber)
+	}
+	s.metrics.Rollback()
+	s.metrics.SetIndexed(uint64(rollbackNumber))
+	return nil
+}
@@ -0,0 +1,374 @@
+package brc20
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// memoryStore 内存中的 Store, RollBack 与 Dal 一样按事件撤销 tick 的供应量变化
+type memoryStore struct {
+	blocks    []Brc20BlockHashes
+	hashes    []Brc20CumulativeEventHashes
+	tickers   map[string]Brc20Tickers
+	events    []Brc20Events
+	balances  []Brc20HistoricBalances
+	transfers map[string]Brc20Transfers
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{tickers: map[string]Brc20Tickers{}, transfers: map[string]Brc20Transfers{}}
+}
+
+func (s *memoryStore) LastBlock() (*Brc20BlockHashes, error) {
+	if len(s.blocks) == 0 {
+		return nil, nil
+	}
+	block := s.blocks[len(s.blocks)-1]
+	return &block, nil
+}
+
+func (s *memoryStore) CumulativeEventHash(height int64) (string, error) {
+	for _, hash := range s.hashes {
+		if hash.BlockHeight == height {
+			return hash.CumulativeEventHash, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *memoryStore) Ticker(tick string) (*Brc20Tickers, error) {
+	ticker, ok := s.tickers[tick]
+	if !ok {
+		return nil, nil
+	}
+	return &ticker, nil
+}
+
+func (s *memoryStore) Balance(pkScript, tick string) (*Brc20HistoricBalances, error) {
+	for i := len(s.balances) - 1; i >= 0; i-- {
+		if balance := s.balances[i]; balance.Pkscript == pkScript && balance.Tick == tick {
+			return &balance, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) UnspentTransfers(txIds []string) ([]Brc20Transfers, error) {
+	var transfers []Brc20Transfers
+	for _, txId := range txIds {
+		for _, transfer := range s.transfers {
+			if transfer.TxId == txId && transfer.SpentHeight == 0 {
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+	return transfers, nil
+}
+
+func (s *memoryStore) SaveBlock(result *BlockResult) error {
+	for _, event := range result.Events {
+		event.Id = int64(len(s.events) + 1)
+		s.events = append(s.events, event.Brc20Events)
+		for _, balance := range event.Balances {
+			balance.Id, balance.EventId = int64(len(s.balances)+1), event.Id
+			s.balances = append(s.balances, balance)
+		}
+	}
+	for _, ticker := range result.Tickers {
+		if ticker.Id == 0 {
+			ticker.Id = int64(len(s.tickers) + 1)
+		}
+		s.tickers[ticker.Tick] = ticker
+	}
+	for _, transfer := range result.Transfers {
+		s.transfers[transfer.InscriptionId] = transfer
+	}
+	for _, id := range result.Spent {
+		transfer := s.transfers[id]
+		transfer.SpentHeight = result.Height
+		s.transfers[id] = transfer
+	}
+	s.hashes = append(s.hashes, Brc20CumulativeEventHashes{BlockHeight: result.Height, BlockEventHash: result.BlockEventHash, CumulativeEventHash: result.CumulativeEventHash})
+	s.blocks = append(s.blocks, Brc20BlockHashes{BlockHeight: result.Height, BlockHash: result.Hash})
+	return nil
+}
+
+func (s *memoryStore) RollBack(height int64) error {
+	var reverted []Brc20Events
+	events := s.events[:0]
+	for _, event := range s.events {
+		if event.BlockHeight > height {
+			reverted = append(reverted, event)
+			continue
+		}
+		events = append(events, event)
+	}
+	s.events = events
+	minted, burned, err := supplyChanges(reverted)
+	if err != nil {
+		return err
+	}
+	for tick, ticker := range s.tickers {
+		ticker.RemainingSupply = ticker.RemainingSupply.Add(minted[tick])
+		ticker.BurnedSupply = ticker.BurnedSupply.Sub(burned[tick])
+		s.tickers[tick] = ticker
+		if ticker.BlockHeight > height {
+			delete(s.tickers, tick)
+		}
+	}
+	for id, transfer := range s.transfers {
+		if transfer.SpentHeight > height {
+			transfer.SpentHeight = 0
+			s.transfers[id] = transfer
+		}
+		if transfer.BlockHeight > height {
+			delete(s.transfers, id)
+		}
+	}
+	balances := s.balances[:0]
+	for _, balance := range s.balances {
+		if balance.BlockHeight <= height {
+			balances = append(balances, balance)
+		}
+	}
+	s.balances = balances
+	for len(s.blocks) > 0 && s.blocks[len(s.blocks)-1].BlockHeight > height {
+		s.blocks = s.blocks[:len(s.blocks)-1]
+		s.hashes = s.hashes[:len(s.hashes)-1]
+	}
+	return nil
+}
+
+// fakeChain 从 base 高度开始的区块, txs 为 GetRawTransaction 能查到的交易
+type fakeChain struct {
+	base   int64
+	blocks []*wire.MsgBlock
+	txs    map[chainhash.Hash]*wire.MsgTx
+}
+
+func (c *fakeChain) GetBlockCount() (int64, error) {
+	return c.base + int64(len(c.blocks)) - 1, nil
+}
+
+func (c *fakeChain) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	hash := c.blocks[height-c.base].BlockHash()
+	return &hash, nil
+}
+
+func (c *fakeChain) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	for _, block := range c.blocks {
+		if block.BlockHash() == *hash {
+			return block, nil
+		}
+	}
+	return nil, errors.Errorf("block %s not found", hash)
+}
+
+func (c *fakeChain) GetRawTransaction(hash *chainhash.Hash) (*btcutil.Tx, error) {
+	tx, ok := c.txs[*hash]
+	if !ok {
+		return nil, errors.Errorf("transaction %s not found", hash)
+	}
+	return btcutil.NewTx(tx), nil
+}
+
+// add 在链尾追加一个包含 coinbase 和 txs 的区块
+func (c *fakeChain) add(txs ...*wire.MsgTx) {
+	height := c.base + int64(len(c.blocks))
+	coinbase := wire.NewMsgTx(2)
+	coinbase.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex}, SignatureScript: []byte(fmt.Sprint(height))})
+	coinbase.AddTxOut(wire.NewTxOut(50*btcutil.SatoshiPerBitcoin, miner))
+	block := &wire.MsgBlock{Transactions: append([]*wire.MsgTx{coinbase}, txs...)}
+	block.Header.MerkleRoot = block.Transactions[len(block.Transactions)-1].TxHash()
+	if len(c.blocks) > 0 {
+		block.Header.PrevBlock = c.blocks[len(c.blocks)-1].BlockHash()
+	}
+	c.blocks = append(c.blocks, block)
+}
+
+func taproot(seed byte) []byte {
+	script, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_1).AddData(bytes.Repeat([]byte{seed}, 32)).Script()
+	return script
+}
+
+var (
+	miner = taproot(0)
+	alice = taproot(1)
+	bob   = taproot(2)
+	burn  = []byte{txscript.OP_RETURN}
+)
+
+// inscribeTx 在输入 0 中铭刻 body, 铭文位于输出 0
+func inscribeTx(owner []byte, body string) *wire.MsgTx {
+	script, _ := txscript.NewScriptBuilder().
+		AddData(bytes.Repeat([]byte{0x02}, 32)).AddOp(txscript.OP_CHECKSIG).
+		AddOp(txscript.OP_FALSE).AddOp(txscript.OP_IF).
+		AddData([]byte("ord")).
+		AddOps([]byte{txscript.OP_DATA_1, 1}).AddData([]byte("text/plain;charset=utf-8")).
+		AddOp(txscript.OP_0).AddData([]byte(body)).
+		AddOp(txscript.OP_ENDIF).Script()
+	tx := wire.NewMsgTx(2)
+	commit := chainhash.HashH(append(append([]byte{}, owner...), body...))
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: commit},
+		Witness:          wire.TxWitness{make([]byte, 64), script, make([]byte, 33)},
+	})
+	tx.AddTxOut(wire.NewTxOut(546, owner))
+	return tx
+}
+
+// spendTx 按顺序花费 inputs, 输出到 outputs
+func spendTx(inputs []wire.OutPoint, outputs ...*wire.TxOut) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	for _, input := range inputs {
+		tx.AddTxIn(wire.NewTxIn(&input, nil, nil))
+	}
+	for _, output := range outputs {
+		tx.AddTxOut(output)
+	}
+	return tx
+}
+
+func outpoint(tx *wire.MsgTx, vout uint32) wire.OutPoint {
+	return wire.OutPoint{Hash: tx.TxHash(), Index: vout}
+}
+
+// syncAll 同步到链头, 返回成功处理的次数
+func syncAll(t *testing.T, s *Indexer) int {
+	t.Helper()
+	for n := 0; ; n++ {
+		last, _ := s.store.LastBlock()
+		head, _ := s.client.GetBlockCount()
+		if last != nil && last.BlockHeight == head {
+			if err := s.SyncBlock(); err == nil {
+				t.Fatal("SyncBlock() beyond the chain head expected error")
+			}
+			return n
+		}
+		if err := s.SyncBlock(); err != nil {
+			t.Fatalf("SyncBlock() error = %v", err)
+		}
+	}
+}
+
+func (s *memoryStore) balanceOf(t *testing.T, pkScript []byte) (overall, available string) {
+	t.Helper()
+	balance, _ := s.Balance(hex.EncodeToString(pkScript), "ordi")
+	if balance == nil {
+		return "0", "0"
+	}
+	return balance.OverallBalance.String(), balance.AvailableBalance.String()
+}
+
+func TestIndexerSyncBlock(t *testing.T) {
+	const base = 200
+	chain := &fakeChain{base: base, txs: map[chainhash.Hash]*wire.MsgTx{}}
+	store := newMemoryStore()
+	s := newIndexer(context.Background(), chain, &chaincfg.RegressionNetParams, store, base)
+
+	deploy := inscribeTx(alice, `{"p":"brc-20","op":"deploy","tick":"OrDi","max":"21000000","lim":"1000"}`)
+	chain.add(deploy)
+	chain.add(
+		inscribeTx(alice, `{"p":"brc-20","op":"mint","tick":"ordi","amt":"1000"}`),
+		// 超过单次铸造上限
+		inscribeTx(bob, `{"p":"brc-20","op":"mint","tick":"ordi","amt":"1500"}`),
+	)
+	transfer := inscribeTx(alice, `{"p":"brc-20","op":"transfer","tick":"ordi","amt":"300"}`)
+	chain.add(
+		inscribeTx(bob, `{"p":"brc-20","op":"mint","tick":"ORDI","amt":"500"}`),
+		transfer,
+		// 可用余额不足
+		inscribeTx(alice, `{"p":"brc-20","op":"transfer","tick":"ordi","amt":"5000"}`),
+	)
+	chain.add(spendTx([]wire.OutPoint{outpoint(transfer, 0)}, wire.NewTxOut(546, bob)))
+
+	if n := syncAll(t, s); n != 4 {
+		t.Fatalf("synced %d blocks, want 4", n)
+	}
+	if overall, available := store.balanceOf(t, alice); overall != "700" || available != "700" {
+		t.Errorf("alice balance = %s/%s, want 700/700", overall, available)
+	}
+	if overall, available := store.balanceOf(t, bob); overall != "800" || available != "800" {
+		t.Errorf("bob balance = %s/%s, want 800/800", overall, available)
+	}
+	ticker := store.tickers["ordi"]
+	if ticker.OriginalTick != "OrDi" || !ticker.RemainingSupply.Equal(decimal.NewFromInt(20998500)) {
+		t.Errorf("ticker = %+v", ticker)
+	}
+	if len(store.events) != 5 {
+		t.Errorf("got %d events, want deploy, 2 mints, transfer-inscribe and transfer-transfer", len(store.events))
+	}
+	wantDeploy := fmt.Sprintf("deploy-inscribe;%si0;%s;ordi;OrDi;21000000.000000000000000000;18;1000.000000000000000000;false",
+		deploy.TxHash(), hex.EncodeToString(alice))
+	if got := store.hashes[0].BlockEventHash; got != getSHA256Hash(wantDeploy) {
+		t.Errorf("block event hash = %s, want hash of %s", got, wantDeploy)
+	}
+	if got, want := store.hashes[1].CumulativeEventHash, getSHA256Hash(store.hashes[0].CumulativeEventHash+store.hashes[1].BlockEventHash); got != want {
+		t.Errorf("cumulative event hash = %s, want %s", got, want)
+	}
+
+	// 区块 203 被替换: transfer 铭文作为手续费花掉, 区块 204 中铭刻并销毁另一个 transfer 铭文
+	funding := spendTx(nil, wire.NewTxOut(1000, alice))
+	chain.txs[funding.TxHash()] = funding
+	chain.blocks = chain.blocks[:3]
+	chain.add(spendTx([]wire.OutPoint{outpoint(funding, 0), outpoint(transfer, 0)}, wire.NewTxOut(1000, alice)))
+	burned := inscribeTx(alice, `{"p":"brc-20","op":"transfer","tick":"ordi","amt":"100"}`)
+	chain.add(burned, spendTx([]wire.OutPoint{outpoint(burned, 0)}, wire.NewTxOut(546, burn)))
+
+	// 发现分叉后回滚到 201, 再同步 202 到 204
+	if n := syncAll(t, s); n != 4 {
+		t.Fatalf("synced %d times after the reorg, want a rollback and 3 blocks", n)
+	}
+	last, _ := store.LastBlock()
+	if last.BlockHeight != 204 || last.BlockHash != chain.blocks[4].BlockHash().String() {
+		t.Errorf("last block = %+v", last)
+	}
+	if overall, available := store.balanceOf(t, alice); overall != "900" || available != "900" {
+		t.Errorf("alice balance = %s/%s, want 900/900", overall, available)
+	}
+	if overall, available := store.balanceOf(t, bob); overall != "500" || available != "500" {
+		t.Errorf("bob balance = %s/%s, want 500/500", overall, available)
+	}
+	if overall, _ := store.balanceOf(t, burn); overall != "100" {
+		t.Errorf("burned balance = %s, want 100", overall)
+	}
+	ticker = store.tickers["ordi"]
+	if !ticker.RemainingSupply.Equal(decimal.NewFromInt(20998500)) || !ticker.BurnedSupply.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("ticker supply = %s remaining, %s burned, want 20998500 and 100", ticker.RemainingSupply, ticker.BurnedSupply)
+	}
+	for _, transfer := range store.transfers {
+		if transfer.SpentHeight == 0 {
+			t.Errorf("transfer %s is still unspent", transfer.InscriptionId)
+		}
+	}
+}
+
+func TestProcessBlockCursedBeforeJubilee(t *testing.T) {
+	deploy := inscribeTx(alice, `{"p":"brc-20","op":"deploy","tick":"ordi","max":"21000000"}`)
+	// 铭文在输入 1 中, jubilee 之前为 cursed 铭文
+	deploy.TxIn = append([]*wire.TxIn{wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil)}, deploy.TxIn...)
+	block := &wire.MsgBlock{Transactions: []*wire.MsgTx{wire.NewMsgTx(2), deploy}}
+	chain := &fakeChain{txs: map[chainhash.Hash]*wire.MsgTx{{}: spendTx(nil, wire.NewTxOut(0, miner), wire.NewTxOut(0, miner))}}
+	p := NewProcessor(newMemoryStore(), chain, &chaincfg.RegressionNetParams)
+
+	for height, want := range map[int64]int{100: 0, 110: 1} {
+		result, err := p.ProcessBlock(height, block, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Events) != want {
+			t.Errorf("height %d: got %d events, want %d", height, len(result.Events), want)
+		}
+	}
+}
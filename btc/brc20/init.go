@@ -1,20 +1,21 @@
 package brc20
 
-import "github.com/traitmeta/gotos/lib/db"
+import (
+	"errors"
 
-// Initialize the database connection
-func InitDB(cfg *db.DbConfig) {
-	if cfg == nil {
-		cfg = &db.DbConfig{
-			DbType:    db.PostgresType,
-			DbName:    "xxx",
-			Host:      "xxx",
-			Port:      "xxx",
-			Username:  "xx",
-			Pwd:       "xx",
-			Charset:   "utf8",
-			ParseTime: true,
-		}
+	"github.com/traitmeta/gotos/lib/db"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+// InitDB Initialize the database connection, cfg 通常为 config.DB
+func InitDB(cfg *db.DbConfig) error {
+	if cfg == nil || cfg.Host == "" {
+		return errors.New("brc20: Database is not configured")
+	}
+	if err := setting.ValidateDB(cfg); err != nil {
+		return err
 	}
 	db.SetupDBEngine(*cfg)
+	return nil
 }
@@ -1,14 +1,13 @@
 package brc20
 
 import (
-	"database/sql"
-
 	"github.com/shopspring/decimal"
 )
 
+// Brc20BlockHashes 已经索引的区块, 高度最大的一条为同步进度
 type Brc20BlockHashes struct {
-	Id          string `gorm:"column:id;NOT NULL"`
-	BlockHeight string `gorm:"column:block_height;NOT NULL"`
+	Id          int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	BlockHeight int64  `gorm:"column:block_height;NOT NULL;uniqueIndex"`
 	BlockHash   string `gorm:"column:block_hash;NOT NULL"`
 }
 
@@ -16,54 +15,76 @@ func (b *Brc20BlockHashes) TableName() string {
 	return "brc20_block_hashes"
 }
 
+// Brc20HistoricBalances 每个事件之后地址的余额, 同一地址和 tick 最新的一条为当前余额
 type Brc20HistoricBalances struct {
-	Id               string          `gorm:"column:id;NOT NULL"`
-	Pkscript         string          `gorm:"column:pkscript;NOT NULL"`
-	Wallet           sql.NullString  `gorm:"column:wallet"`
-	Tick             string          `gorm:"column:tick;NOT NULL"`
-	OverallBalance   decimal.Decimal `gorm:"column:overall_balance;NOT NULL"`
-	AvailableBalance decimal.Decimal `gorm:"column:available_balance;NOT NULL"`
-	BlockHeight      string          `gorm:"column:block_height;NOT NULL"`
-	EventId          string          `gorm:"column:event_id;NOT NULL"`
+	Id               int64           `gorm:"column:id;primaryKey;autoIncrement"`
+	Pkscript         string          `gorm:"column:pkscript;NOT NULL;index:idx_brc20_balance,priority:1"`
+	Wallet           string          `gorm:"column:wallet"`
+	Tick             string          `gorm:"column:tick;NOT NULL;index:idx_brc20_balance,priority:2"`
+	OverallBalance   decimal.Decimal `gorm:"column:overall_balance;type:numeric(38,18);NOT NULL"`
+	AvailableBalance decimal.Decimal `gorm:"column:available_balance;type:numeric(38,18);NOT NULL"`
+	BlockHeight      int64           `gorm:"column:block_height;NOT NULL;index"`
+	EventId          int64           `gorm:"column:event_id;NOT NULL"`
 }
 
 func (b *Brc20HistoricBalances) TableName() string {
 	return "brc20_historic_balances"
 }
 
+// Brc20Events 有效的 BRC-20 事件, Event 为 JSON, 金额按 18 位小数放大为整数
 type Brc20Events struct {
-	Id            string `gorm:"column:id;NOT NULL"`
-	EventType     string `gorm:"column:event_type;NOT NULL"`
-	BlockHeight   string `gorm:"column:block_height;NOT NULL"`
-	InscriptionId string `gorm:"column:inscription_id;NOT NULL"`
-	Event         string `gorm:"column:event;NOT NULL"`
+	Id            int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	EventType     int    `gorm:"column:event_type;NOT NULL"`
+	BlockHeight   int64  `gorm:"column:block_height;NOT NULL;index"`
+	InscriptionId string `gorm:"column:inscription_id;NOT NULL;index"`
+	Event         string `gorm:"column:event;type:text;NOT NULL"`
 }
 
 func (b *Brc20Events) TableName() string {
 	return "brc20_events"
 }
 
+// Brc20Tickers 已部署的 tick, Tick 为小写, OriginalTick 为部署时的写法
 type Brc20Tickers struct {
-	Id                  string `gorm:"column:id;NOT NULL"`
-	OriginalTick        string `gorm:"column:original_tick;NOT NULL"`
-	Tick                string `gorm:"column:tick;NOT NULL"`
-	MaxSupply           string `gorm:"column:max_supply;NOT NULL"`
-	Decimals            string `gorm:"column:decimals;NOT NULL"`
-	LimitPerMint        string `gorm:"column:limit_per_mint;NOT NULL"`
-	RemainingSupply     string `gorm:"column:remaining_supply;NOT NULL"`
-	BurnedSupply        string `gorm:"column:burned_supply;default:0;NOT NULL"`
-	IsSelfMint          string `gorm:"column:is_self_mint;NOT NULL"`
-	DeployInscriptionId string `gorm:"column:deploy_inscription_id;NOT NULL"`
-	BlockHeight         string `gorm:"column:block_height;NOT NULL"`
+	Id                  int64           `gorm:"column:id;primaryKey;autoIncrement"`
+	OriginalTick        string          `gorm:"column:original_tick;NOT NULL"`
+	Tick                string          `gorm:"column:tick;NOT NULL;uniqueIndex"`
+	MaxSupply           decimal.Decimal `gorm:"column:max_supply;type:numeric(38,18);NOT NULL"`
+	Decimals            int             `gorm:"column:decimals;NOT NULL"`
+	LimitPerMint        decimal.Decimal `gorm:"column:limit_per_mint;type:numeric(38,18);NOT NULL"`
+	RemainingSupply     decimal.Decimal `gorm:"column:remaining_supply;type:numeric(38,18);NOT NULL"`
+	BurnedSupply        decimal.Decimal `gorm:"column:burned_supply;type:numeric(38,18);default:0;NOT NULL"`
+	IsSelfMint          bool            `gorm:"column:is_self_mint;NOT NULL"`
+	DeployInscriptionId string          `gorm:"column:deploy_inscription_id;NOT NULL"`
+	BlockHeight         int64           `gorm:"column:block_height;NOT NULL"`
 }
 
 func (b *Brc20Tickers) TableName() string {
 	return "brc20_tickers"
 }
 
+// Brc20Transfers transfer 铭文的位置, 第一次转移时生效, SpentHeight 为生效的高度, 0 表示还没有转移
+type Brc20Transfers struct {
+	InscriptionId  string          `gorm:"column:inscription_id;primaryKey"`
+	Tick           string          `gorm:"column:tick;NOT NULL"`
+	OriginalTick   string          `gorm:"column:original_tick;NOT NULL"`
+	Amount         decimal.Decimal `gorm:"column:amount;type:numeric(38,18);NOT NULL"`
+	SourcePkscript string          `gorm:"column:source_pkscript;NOT NULL"`
+	SourceWallet   string          `gorm:"column:source_wallet"`
+	TxId           string          `gorm:"column:tx_id;NOT NULL;index"`
+	Vout           uint32          `gorm:"column:vout;NOT NULL"`
+	SatOffset      uint64          `gorm:"column:sat_offset;NOT NULL"`
+	BlockHeight    int64           `gorm:"column:block_height;NOT NULL;index"`
+	SpentHeight    int64           `gorm:"column:spent_height;NOT NULL;default:0"`
+}
+
+func (b *Brc20Transfers) TableName() string {
+	return "brc20_transfers"
+}
+
 type Brc20CumulativeEventHashes struct {
-	Id                  string `gorm:"column:id;NOT NULL"`
-	BlockHeight         int64  `gorm:"column:block_height;NOT NULL"`
+	Id                  int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	BlockHeight         int64  `gorm:"column:block_height;NOT NULL;uniqueIndex"`
 	BlockEventHash      string `gorm:"column:block_event_hash;NOT NULL"`
 	CumulativeEventHash string `gorm:"column:cumulative_event_hash;NOT NULL"`
 }
@@ -0,0 +1,108 @@
+package brc20
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/traitmeta/metago/btc/ord/common"
+)
+
+const (
+	Protocol = "brc-20"
+	// MaxDecimals tick 最多的小数位数, 事件中的金额按这个位数放大为整数
+	MaxDecimals = 18
+)
+
+// maxAmount 供应量和金额的上限 2^64-1
+var maxAmount = decimal.RequireFromString("18446744073709551615")
+
+// Operation 铭文内容解析出的 BRC-20 操作, mint 和 transfer 的金额需要按 tick 的小数位数校验, 保留原始字符串
+type Operation struct {
+	Op           string
+	Tick         string
+	OriginalTick string
+	Max          decimal.Decimal
+	Limit        decimal.Decimal
+	Decimals     int
+	Amount       string
+}
+
+// ParseOperation 解析铭文内容, 不是 BRC-20 或者字段不合法时返回错误
+func ParseOperation(contentType string, body []byte) (*Operation, error) {
+	if !strings.HasPrefix(contentType, "text/plain") && !strings.HasPrefix(contentType, "application/json") {
+		return nil, errors.Errorf("content type %q", contentType)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, errors.Wrap(err, "decode body")
+	}
+	field := func(name string) (string, bool) {
+		v, ok := fields[name].(string)
+		return v, ok
+	}
+	if p, _ := field("p"); p != Protocol {
+		return nil, errors.Errorf("protocol %q", p)
+	}
+	tick, ok := field("tick")
+	if !ok || utf8len(tick) != 4 {
+		return nil, errors.Errorf("tick %q", tick)
+	}
+	op, _ := field("op")
+	operation := &Operation{Op: op, Tick: strings.ToLower(tick), OriginalTick: tick}
+
+	switch op {
+	case common.DeployOp:
+		operation.Decimals = MaxDecimals
+		if dec, ok := field("dec"); ok {
+			if !isPositiveNumber(dec, false) {
+				return nil, errors.Errorf("dec %q", dec)
+			}
+			if operation.Decimals, _ = strconv.Atoi(dec); operation.Decimals > MaxDecimals {
+				return nil, errors.Errorf("dec %q", dec)
+			}
+		}
+		max, ok := field("max")
+		if !ok {
+			return nil, errors.New("max is missing")
+		}
+		var err error
+		if operation.Max, err = parseAmount(max, operation.Decimals); err != nil {
+			return nil, errors.Wrap(err, "max")
+		}
+		operation.Limit = operation.Max
+		if lim, ok := field("lim"); ok {
+			if operation.Limit, err = parseAmount(lim, operation.Decimals); err != nil {
+				return nil, errors.Wrap(err, "lim")
+			}
+		}
+	case common.MintOp, common.TransferOp:
+		if operation.Amount, ok = field("amt"); !ok {
+			return nil, errors.New("amt is missing")
+		}
+	default:
+		return nil, errors.Errorf("op %q", op)
+	}
+	return operation, nil
+}
+
+// parseAmount 解析大于 0、不超过 2^64-1 且小数位数不超过 decimals 的金额
+func parseAmount(s string, decimals int) (decimal.Decimal, error) {
+	if !isPositiveNumberWithDot(s, false) {
+		return decimal.Zero, errors.Errorf("amount %q", s)
+	}
+	if _, fraction, ok := strings.Cut(s, "."); ok && len(fraction) > decimals {
+		return decimal.Zero, errors.Errorf("amount %q has more than %d decimals", s, decimals)
+	}
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !amount.IsPositive() || amount.GreaterThan(maxAmount) {
+		return decimal.Zero, errors.Errorf("amount %q out of range", s)
+	}
+	return amount, nil
+}
@@ -0,0 +1,96 @@
+package brc20
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseOperation(t *testing.T) {
+	const text = "text/plain;charset=utf-8"
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        *Operation
+	}{
+		{
+			name:        "deploy with defaults",
+			contentType: text,
+			body:        `{"p":"brc-20","op":"deploy","tick":"OrDi","max":"21000000"}`,
+			want:        &Operation{Op: "deploy", Tick: "ordi", OriginalTick: "OrDi", Max: decimal.NewFromInt(21000000), Limit: decimal.NewFromInt(21000000), Decimals: 18},
+		},
+		{
+			name:        "deploy with lim and dec",
+			contentType: "application/json",
+			body:        `{"p":"brc-20","op":"deploy","tick":"sats","max":"1000.5","lim":"0.25","dec":"2"}`,
+			want:        &Operation{Op: "deploy", Tick: "sats", OriginalTick: "sats", Max: decimal.RequireFromString("1000.5"), Limit: decimal.RequireFromString("0.25"), Decimals: 2},
+		},
+		{
+			name:        "mint keeps the raw amount",
+			contentType: text,
+			body:        `{"p":"brc-20","op":"mint","tick":"ordi","amt":"1000.01"}`,
+			want:        &Operation{Op: "mint", Tick: "ordi", OriginalTick: "ordi", Amount: "1000.01"},
+		},
+		{name: "image", contentType: "image/png", body: `{"p":"brc-20","op":"mint","tick":"ordi","amt":"1"}`},
+		{name: "not json", contentType: text, body: `brc-20 mint ordi 1`},
+		{name: "other protocol", contentType: text, body: `{"p":"brc-21","op":"mint","tick":"ordi","amt":"1"}`},
+		{name: "3 byte tick", contentType: text, body: `{"p":"brc-20","op":"mint","tick":"ord","amt":"1"}`},
+		{name: "5 byte tick", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordis","max":"1"}`},
+		{name: "numeric max", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordi","max":21000000}`},
+		{name: "zero max", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordi","max":"0"}`},
+		{name: "max over 2^64-1", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordi","max":"18446744073709551616"}`},
+		{name: "more decimals than dec", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordi","max":"1.5","dec":"0"}`},
+		{name: "dec over 18", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordi","max":"1","dec":"19"}`},
+		{name: "negative lim", contentType: text, body: `{"p":"brc-20","op":"deploy","tick":"ordi","max":"1","lim":"-1"}`},
+		{name: "mint without amt", contentType: text, body: `{"p":"brc-20","op":"mint","tick":"ordi"}`},
+		{name: "unknown op", contentType: text, body: `{"p":"brc-20","op":"burn","tick":"ordi","amt":"1"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOperation(tt.contentType, []byte(tt.body))
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("ParseOperation() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOperation() error = %v", err)
+			}
+			if got.Op != tt.want.Op || got.Tick != tt.want.Tick || got.OriginalTick != tt.want.OriginalTick ||
+				!got.Max.Equal(tt.want.Max) || !got.Limit.Equal(tt.want.Limit) || got.Decimals != tt.want.Decimals || got.Amount != tt.want.Amount {
+				t.Errorf("ParseOperation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		want     string
+	}{
+		{"1000", 18, "1000"},
+		{"0.001", 3, "0.001"},
+		{"0.0001", 3, ""},
+		{"1.", 18, ""},
+		{".5", 18, ""},
+		{"0", 18, ""},
+		{"1e3", 18, ""},
+		{"18446744073709551615", 0, "18446744073709551615"},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.amount, tt.decimals)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("parseAmount(%q, %d) = %s, want error", tt.amount, tt.decimals, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("parseAmount(%q, %d) = %s, %v, want %s", tt.amount, tt.decimals, got, err, tt.want)
+		}
+	}
+}
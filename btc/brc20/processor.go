@@ -0,0 +1,515 @@
+package brc20
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/btc/ord/common"
+	"github.com/traitmeta/metago/btc/ord/envelops"
+)
+
+/*
+## Overview
+
+Processor 按交易顺序处理区块中的 BRC-20 铭文:
+
+  - 铭文位于所在输入的第一个聪, 有 pointer 且小于输出总额时位于 pointer 指向的聪; 聪落在手续费中的铭文忽略
+  - deploy、mint 和 transfer 铭文分别生成 deploy-inscribe、mint-inscribe 和 transfer-inscribe 事件
+  - transfer 铭文按所在的输出跟踪, 第一次被花费时生成 transfer-transfer 事件, 作为手续费花掉时金额退回来源地址
+  - 同一个输入上先处理已有铭文的转移, 再处理该输入中新的铭文
+  - jubilee 高度之前只有输入 0 的第一个铭文有效, 其余为 cursed 铭文, 不参与 BRC-20
+  - 5 字节的 self mint tick 暂不支持
+  - 事件字符串和累计事件哈希的计算方式见 convertEventStr
+*/
+
+// TxFetcher 查询输入引用的交易, 用于计算铭文所在聪的偏移, 需要 bitcoind 开启 txindex. *rpcclient.Client 实现了该接口
+type TxFetcher interface {
+	GetRawTransaction(txHash *chainhash.Hash) (*btcutil.Tx, error)
+}
+
+type Processor struct {
+	store Store
+	txs   TxFetcher
+	net   *chaincfg.Params
+}
+
+func NewProcessor(store Store, txs TxFetcher, net *chaincfg.Params) *Processor {
+	return &Processor{store: store, txs: txs, net: net}
+}
+
+// JubileeHeight cursed 铭文转为普通铭文的高度
+func JubileeHeight(net *chaincfg.Params) int64 {
+	switch net.Name {
+	case chaincfg.MainNetParams.Name:
+		return 824544
+	case chaincfg.TestNet3Params.Name:
+		return 2544192
+	case chaincfg.SigNetParams.Name:
+		return 175392
+	default:
+		return 110
+	}
+}
+
+// blockProcess 处理一个区块时的状态, tick、余额和 transfer 铭文在区块内的修改先记录在这里
+type blockProcess struct {
+	*Processor
+	result     *BlockResult
+	jubilee    bool
+	tickers    map[string]*Brc20Tickers
+	changed    []string
+	balances   map[string]*Brc20HistoricBalances
+	transfers  map[wire.OutPoint][]*Brc20Transfers
+	inscribed  []*Brc20Transfers
+	blockTxs   map[chainhash.Hash]*wire.MsgTx
+	prevValues map[wire.OutPoint]int64
+	eventStrs  []string
+}
+
+// ProcessBlock 处理区块, prevCumulativeHash 为上一个区块的累计事件哈希, 第一个区块为空
+func (p *Processor) ProcessBlock(height int64, block *wire.MsgBlock, prevCumulativeHash string) (*BlockResult, error) {
+	b := &blockProcess{
+		Processor:  p,
+		result:     &BlockResult{Height: height, Hash: block.BlockHash().String()},
+		jubilee:    height >= JubileeHeight(p.net),
+		tickers:    map[string]*Brc20Tickers{},
+		balances:   map[string]*Brc20HistoricBalances{},
+		transfers:  map[wire.OutPoint][]*Brc20Transfers{},
+		blockTxs:   map[chainhash.Hash]*wire.MsgTx{},
+		prevValues: map[wire.OutPoint]int64{},
+	}
+	for _, tx := range block.Transactions {
+		b.blockTxs[tx.TxHash()] = tx
+	}
+	if err := b.loadTransfers(block); err != nil {
+		return nil, errors.Wrap(err, "load transfers")
+	}
+	// coinbase 没有铭文, 也不会花费 transfer 铭文所在的输出
+	for i, tx := range block.Transactions {
+		if i == 0 {
+			continue
+		}
+		if err := b.processTx(tx); err != nil {
+			return nil, errors.Wrapf(err, "process tx %s", tx.TxHash())
+		}
+	}
+
+	for _, tick := range b.changed {
+		b.result.Tickers = append(b.result.Tickers, *b.tickers[tick])
+	}
+	for _, transfer := range b.inscribed {
+		b.result.Transfers = append(b.result.Transfers, *transfer)
+	}
+	b.result.BlockEventHash = getSHA256Hash(strings.Join(b.eventStrs, EVENT_SEPARATOR))
+	b.result.CumulativeEventHash = b.result.BlockEventHash
+	if prevCumulativeHash != "" {
+		b.result.CumulativeEventHash = getSHA256Hash(prevCumulativeHash + b.result.BlockEventHash)
+	}
+	return b.result, nil
+}
+
+// loadTransfers 查询区块中被花费的输出上还没有转移的 transfer 铭文
+func (b *blockProcess) loadTransfers(block *wire.MsgBlock) error {
+	seen := map[chainhash.Hash]bool{}
+	var txIds []string
+	for _, tx := range block.Transactions {
+		for _, in := range tx.TxIn {
+			hash := in.PreviousOutPoint.Hash
+			if _, ok := b.blockTxs[hash]; ok || seen[hash] {
+				continue
+			}
+			seen[hash] = true
+			txIds = append(txIds, hash.String())
+		}
+	}
+	transfers, err := b.store.UnspentTransfers(txIds)
+	if err != nil {
+		return err
+	}
+	for i := range transfers {
+		transfer := &transfers[i]
+		hash, err := chainhash.NewHashFromStr(transfer.TxId)
+		if err != nil {
+			return err
+		}
+		outpoint := wire.OutPoint{Hash: *hash, Index: transfer.Vout}
+		b.transfers[outpoint] = append(b.transfers[outpoint], transfer)
+	}
+	return nil
+}
+
+func (b *blockProcess) processTx(tx *wire.MsgTx) error {
+	envelopes := map[uint32][]int{}
+	all := envelops.FromTransaction(tx)
+	for i, env := range all {
+		envelopes[env.Input] = append(envelopes[env.Input], i)
+	}
+
+	for k, in := range tx.TxIn {
+		if moved := b.transfers[in.PreviousOutPoint]; len(moved) > 0 {
+			delete(b.transfers, in.PreviousOutPoint)
+			offset, err := b.inputOffset(tx, k)
+			if err != nil {
+				return err
+			}
+			for _, transfer := range moved {
+				if err := b.transferTransfer(tx, transfer, offset+transfer.SatOffset); err != nil {
+					return err
+				}
+			}
+		}
+		for _, i := range envelopes[uint32(k)] {
+			if err := b.inscribe(tx, i, all[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// inscribe 处理交易中的第 index 个铭文
+func (b *blockProcess) inscribe(tx *wire.MsgTx, index int, env envelops.Envelope) error {
+	if !b.jubilee && (env.Input != 0 || env.Offset != 0 || env.Pushnum || env.Stutter) {
+		return nil
+	}
+	op, err := ParseOperation(env.GetContentType(), env.GetContent())
+	if err != nil {
+		return nil
+	}
+
+	offset, err := b.inputOffset(tx, int(env.Input))
+	if err != nil {
+		return err
+	}
+	if ptr, ok := pointer(env); ok && ptr < outputValue(tx) {
+		offset = ptr
+	}
+	vout, satOffset, ok := locate(tx, offset)
+	if !ok {
+		return nil
+	}
+	pkScript := hex.EncodeToString(tx.TxOut[vout].PkScript)
+	wallet := b.wallet(tx.TxOut[vout].PkScript)
+	inscriptionId := fmt.Sprintf("%si%d", tx.TxHash(), index)
+
+	switch op.Op {
+	case common.DeployOp:
+		return b.deployInscribe(inscriptionId, pkScript, wallet, op)
+	case common.MintOp:
+		return b.mintInscribe(inscriptionId, pkScript, wallet, op, parent(env))
+	default:
+		outpoint := wire.OutPoint{Hash: tx.TxHash(), Index: vout}
+		return b.transferInscribe(inscriptionId, pkScript, wallet, op, outpoint, satOffset)
+	}
+}
+
+func (b *blockProcess) deployInscribe(inscriptionId, pkScript, wallet string, op *Operation) error {
+	ticker, err := b.ticker(op.Tick)
+	if err != nil || ticker != nil {
+		return err
+	}
+	ticker = &Brc20Tickers{
+		OriginalTick:        op.OriginalTick,
+		Tick:                op.Tick,
+		MaxSupply:           op.Max,
+		Decimals:            op.Decimals,
+		LimitPerMint:        op.Limit,
+		RemainingSupply:     op.Max,
+		DeployInscriptionId: inscriptionId,
+		BlockHeight:         b.result.Height,
+	}
+	b.tickers[op.Tick] = ticker
+	b.markChanged(op.Tick)
+	b.addEvent(EventDeployInscribe, inscriptionId, ticker.Decimals, map[string]string{
+		"deployer_pkScript": pkScript,
+		"deployer_wallet":   wallet,
+		"tick":              op.Tick,
+		"original_tick":     op.OriginalTick,
+		"max_supply":        scaled(op.Max),
+		"decimals":          fmt.Sprint(op.Decimals),
+		"limit_per_mint":    scaled(op.Limit),
+		"is_self_mint":      "false",
+	})
+	return nil
+}
+
+// mintInscribe 超过单次铸造上限的 mint 无效, 超过剩余供应量时只铸造剩余的部分
+func (b *blockProcess) mintInscribe(inscriptionId, pkScript, wallet string, op *Operation, parentId string) error {
+	ticker, err := b.ticker(op.Tick)
+	if err != nil || ticker == nil || !ticker.RemainingSupply.IsPositive() {
+		return err
+	}
+	amount, err := parseAmount(op.Amount, ticker.Decimals)
+	if err != nil || amount.GreaterThan(ticker.LimitPerMint) {
+		return nil
+	}
+	if amount.GreaterThan(ticker.RemainingSupply) {
+		amount = ticker.RemainingSupply
+	}
+	balance, err := b.balance(pkScript, op.Tick, wallet)
+	if err != nil {
+		return err
+	}
+
+	ticker.RemainingSupply = ticker.RemainingSupply.Sub(amount)
+	b.markChanged(op.Tick)
+	balance.OverallBalance = balance.OverallBalance.Add(amount)
+	balance.AvailableBalance = balance.AvailableBalance.Add(amount)
+	b.addEvent(EventMintInscribe, inscriptionId, ticker.Decimals, map[string]string{
+		"minted_pkScript": pkScript,
+		"minted_wallet":   wallet,
+		"tick":            op.Tick,
+		"original_tick":   op.OriginalTick,
+		"amount":          scaled(amount),
+		"parent_id":       parentId,
+	}, *balance)
+	return nil
+}
+
+// transferInscribe 从可用余额中冻结金额, 铭文第一次转移时转给接收方
+func (b *blockProcess) transferInscribe(inscriptionId, pkScript, wallet string, op *Operation, outpoint wire.OutPoint, satOffset uint64) error {
+	ticker, err := b.ticker(op.Tick)
+	if err != nil || ticker == nil {
+		return err
+	}
+	amount, err := parseAmount(op.Amount, ticker.Decimals)
+	if err != nil {
+		return nil
+	}
+	balance, err := b.balance(pkScript, op.Tick, wallet)
+	if err != nil || balance.AvailableBalance.LessThan(amount) {
+		return err
+	}
+
+	balance.AvailableBalance = balance.AvailableBalance.Sub(amount)
+	transfer := &Brc20Transfers{
+		InscriptionId:  inscriptionId,
+		Tick:           op.Tick,
+		OriginalTick:   op.OriginalTick,
+		Amount:         amount,
+		SourcePkscript: pkScript,
+		SourceWallet:   wallet,
+		TxId:           outpoint.Hash.String(),
+		Vout:           outpoint.Index,
+		SatOffset:      satOffset,
+		BlockHeight:    b.result.Height,
+	}
+	b.inscribed = append(b.inscribed, transfer)
+	b.transfers[outpoint] = append(b.transfers[outpoint], transfer)
+	b.addEvent(EventTransferInscribe, inscriptionId, ticker.Decimals, map[string]string{
+		"source_pkScript": pkScript,
+		"source_wallet":   wallet,
+		"tick":            op.Tick,
+		"original_tick":   op.OriginalTick,
+		"amount":          scaled(amount),
+	}, *balance)
+	return nil
+}
+
+// transferTransfer transfer 铭文第一次转移, offset 为铭文所在的聪在交易输入中的偏移
+func (b *blockProcess) transferTransfer(tx *wire.MsgTx, transfer *Brc20Transfers, offset uint64) error {
+	transfer.SpentHeight = b.result.Height
+	if transfer.BlockHeight < b.result.Height {
+		b.result.Spent = append(b.result.Spent, transfer.InscriptionId)
+	}
+	ticker, err := b.ticker(transfer.Tick)
+	if err != nil {
+		return err
+	}
+	if ticker == nil {
+		return errors.Errorf("tick %s of transfer %s is not deployed", transfer.Tick, transfer.InscriptionId)
+	}
+	source, err := b.balance(transfer.SourcePkscript, transfer.Tick, transfer.SourceWallet)
+	if err != nil {
+		return err
+	}
+	fields := map[string]string{
+		"source_pkScript": transfer.SourcePkscript,
+		"source_wallet":   transfer.SourceWallet,
+		"spent_pkScript":  "",
+		"spent_wallet":    "",
+		"tick":            transfer.Tick,
+		"original_tick":   transfer.OriginalTick,
+		"amount":          scaled(transfer.Amount),
+		"using_tx_id":     tx.TxHash().String(),
+	}
+
+	vout, _, ok := locate(tx, offset)
+	if !ok {
+		source.AvailableBalance = source.AvailableBalance.Add(transfer.Amount)
+		b.addEvent(EventTransferTransfer, transfer.InscriptionId, ticker.Decimals, fields, *source)
+		return nil
+	}
+
+	spentPkScript := hex.EncodeToString(tx.TxOut[vout].PkScript)
+	spentWallet := b.wallet(tx.TxOut[vout].PkScript)
+	fields["spent_pkScript"], fields["spent_wallet"] = spentPkScript, spentWallet
+	source.OverallBalance = source.OverallBalance.Sub(transfer.Amount)
+	debit := *source
+	spent, err := b.balance(spentPkScript, transfer.Tick, spentWallet)
+	if err != nil {
+		return err
+	}
+	spent.OverallBalance = spent.OverallBalance.Add(transfer.Amount)
+	spent.AvailableBalance = spent.AvailableBalance.Add(transfer.Amount)
+	if spentPkScript == burnPkScript {
+		ticker.BurnedSupply = ticker.BurnedSupply.Add(transfer.Amount)
+		b.markChanged(transfer.Tick)
+	}
+	b.addEvent(EventTransferTransfer, transfer.InscriptionId, ticker.Decimals, fields, debit, *spent)
+	return nil
+}
+
+// addEvent 记录事件和事件之后的余额
+func (b *blockProcess) addEvent(eventType int, inscriptionId string, decimals int, fields map[string]string, balances ...Brc20HistoricBalances) {
+	data, _ := json.Marshal(fields)
+	for i := range balances {
+		balances[i].Id = 0
+		balances[i].EventId = 0
+		balances[i].BlockHeight = b.result.Height
+	}
+	b.result.Events = append(b.result.Events, Event{
+		Brc20Events: Brc20Events{
+			EventType:     eventType,
+			BlockHeight:   b.result.Height,
+			InscriptionId: inscriptionId,
+			Event:         string(data),
+		},
+		Balances: balances,
+	})
+	b.eventStrs = append(b.eventStrs, convertEventStr(fields, eventTypeNames[eventType], inscriptionId, decimals))
+}
+
+func (b *blockProcess) ticker(tick string) (*Brc20Tickers, error) {
+	if ticker, ok := b.tickers[tick]; ok {
+		return ticker, nil
+	}
+	ticker, err := b.store.Ticker(tick)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get ticker %s", tick)
+	}
+	b.tickers[tick] = ticker
+	return ticker, nil
+}
+
+func (b *blockProcess) markChanged(tick string) {
+	for _, changed := range b.changed {
+		if changed == tick {
+			return
+		}
+	}
+	b.changed = append(b.changed, tick)
+}
+
+// balance 地址在区块内的当前余额, 之前没有余额时为 0
+func (b *blockProcess) balance(pkScript, tick, wallet string) (*Brc20HistoricBalances, error) {
+	key := pkScript + ":" + tick
+	if balance, ok := b.balances[key]; ok {
+		return balance, nil
+	}
+	balance, err := b.store.Balance(pkScript, tick)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get balance of %s %s", pkScript, tick)
+	}
+	if balance == nil {
+		balance = &Brc20HistoricBalances{Pkscript: pkScript, Wallet: wallet, Tick: tick}
+	}
+	b.balances[key] = balance
+	return balance, nil
+}
+
+// inputOffset 交易第 index 个输入的第一个聪在所有输入中的偏移
+func (b *blockProcess) inputOffset(tx *wire.MsgTx, index int) (uint64, error) {
+	var offset uint64
+	for _, in := range tx.TxIn[:index] {
+		value, err := b.prevValue(in.PreviousOutPoint)
+		if err != nil {
+			return 0, err
+		}
+		offset += uint64(value)
+	}
+	return offset, nil
+}
+
+func (b *blockProcess) prevValue(outpoint wire.OutPoint) (int64, error) {
+	if value, ok := b.prevValues[outpoint]; ok {
+		return value, nil
+	}
+	prev, ok := b.blockTxs[outpoint.Hash]
+	if !ok {
+		tx, err := b.txs.GetRawTransaction(&outpoint.Hash)
+		if err != nil {
+			return 0, errors.Wrapf(err, "get transaction %s", outpoint.Hash)
+		}
+		prev = tx.MsgTx()
+	}
+	if int(outpoint.Index) >= len(prev.TxOut) {
+		return 0, errors.Errorf("output %s not found", outpoint)
+	}
+	value := prev.TxOut[outpoint.Index].Value
+	b.prevValues[outpoint] = value
+	return value, nil
+}
+
+// wallet 输出脚本对应的地址, 非标准脚本为空
+func (b *blockProcess) wallet(pkScript []byte) string {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, b.net)
+	if err != nil || len(addrs) != 1 {
+		return ""
+	}
+	return addrs[0].EncodeAddress()
+}
+
+// locate 输入中偏移为 offset 的聪所在的输出和在输出中的偏移, 落在手续费中时返回 false
+func locate(tx *wire.MsgTx, offset uint64) (uint32, uint64, bool) {
+	var start uint64
+	for vout, out := range tx.TxOut {
+		end := start + uint64(out.Value)
+		if offset < end {
+			return uint32(vout), offset - start, true
+		}
+		start = end
+	}
+	return 0, 0, false
+}
+
+func outputValue(tx *wire.MsgTx) uint64 {
+	var total uint64
+	for _, out := range tx.TxOut {
+		total += uint64(out.Value)
+	}
+	return total
+}
+
+// pointer 铭文的 pointer 字段, 为去掉末尾 0 的小端整数
+func pointer(env envelops.Envelope) (uint64, bool) {
+	v, ok := env.TypeDataMap[2]
+	if !ok || len(v) == 0 || len(v) > 8 {
+		return 0, false
+	}
+	var buf [8]byte
+	copy(buf[:], v)
+	return binary.LittleEndian.Uint64(buf[:]), true
+}
+
+// parent 铭文的 parent 字段, 为交易哈希和去掉末尾 0 的小端序号, 不完整时为空
+func parent(env envelops.Envelope) string {
+	v := env.TypeDataMap[3]
+	if len(v) < chainhash.HashSize || len(v) > chainhash.HashSize+4 {
+		return ""
+	}
+	hash, _ := chainhash.NewHash(v[:chainhash.HashSize])
+	var index [4]byte
+	copy(index[:], v[chainhash.HashSize:])
+	return fmt.Sprintf("%si%d", hash, binary.LittleEndian.Uint32(index[:]))
+}
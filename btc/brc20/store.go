@@ -0,0 +1,198 @@
+package brc20
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/dal"
+)
+
+// queryBatchSize 按交易查询 transfer 铭文时每次查询的交易数
+const queryBatchSize = 1000
+
+// BlockResult 一个区块的处理结果, 在同一个事务中写库
+type BlockResult struct {
+	Height int64
+	Hash   string
+	Events []Event
+	// Tickers 本区块部署或者供应量变化的 tick, 新部署的 Id 为 0
+	Tickers []Brc20Tickers
+	// Transfers 本区块铭刻的 transfer 铭文, 在本区块中已经转移的 SpentHeight 不为 0
+	Transfers []Brc20Transfers
+	// Spent 本区块转移的之前区块铭刻的 transfer 铭文
+	Spent               []string
+	BlockEventHash      string
+	CumulativeEventHash string
+}
+
+// Store 索引读写的数据, 默认使用 Dal, 测试中替换为内存实现. 查询不到时返回 nil
+type Store interface {
+	LastBlock() (*Brc20BlockHashes, error)
+	CumulativeEventHash(height int64) (string, error)
+	Ticker(tick string) (*Brc20Tickers, error)
+	// Balance 地址在 tick 上最新的余额
+	Balance(pkScript, tick string) (*Brc20HistoricBalances, error)
+	// UnspentTransfers 还没有转移、位于这些交易输出中的 transfer 铭文
+	UnspentTransfers(txIds []string) ([]Brc20Transfers, error)
+	SaveBlock(result *BlockResult) error
+	// RollBack 删除 height 之后的数据
+	RollBack(height int64) error
+}
+
+type Dal struct {
+	DB *gorm.DB
+}
+
+func NewDal(db *gorm.DB) *Dal {
+	return &Dal{DB: db}
+}
+
+// Migrate 创建 BRC-20 索引使用的表
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Brc20BlockHashes{}, &Brc20Events{}, &Brc20HistoricBalances{}, &Brc20Tickers{},
+		&Brc20Transfers{}, &Brc20CumulativeEventHashes{})
+}
+
+func (d *Dal) LastBlock() (*Brc20BlockHashes, error) {
+	var block Brc20BlockHashes
+	err := d.DB.Model(&Brc20BlockHashes{}).Order("block_height DESC").Take(&block).Error
+	return dal.IgnoreNotFound(&block, err)
+}
+
+func (d *Dal) CumulativeEventHash(height int64) (string, error) {
+	var hash Brc20CumulativeEventHashes
+	err := d.DB.Model(&Brc20CumulativeEventHashes{}).Where("block_height = ?", height).Take(&hash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return hash.CumulativeEventHash, err
+}
+
+func (d *Dal) Ticker(tick string) (*Brc20Tickers, error) {
+	var ticker Brc20Tickers
+	err := d.DB.Model(&Brc20Tickers{}).Where("tick = ?", tick).Take(&ticker).Error
+	return dal.IgnoreNotFound(&ticker, err)
+}
+
+func (d *Dal) Balance(pkScript, tick string) (*Brc20HistoricBalances, error) {
+	var balance Brc20HistoricBalances
+	err := d.DB.Model(&Brc20HistoricBalances{}).
+		Where("pkscript = ? AND tick = ?", pkScript, tick).
+		Order("block_height DESC, id DESC").Take(&balance).Error
+	return dal.IgnoreNotFound(&balance, err)
+}
+
+func (d *Dal) UnspentTransfers(txIds []string) ([]Brc20Transfers, error) {
+	var transfers []Brc20Transfers
+	for start := 0; start < len(txIds); start += queryBatchSize {
+		end := min(start+queryBatchSize, len(txIds))
+		var batch []Brc20Transfers
+		if err := d.DB.Model(&Brc20Transfers{}).
+			Where("tx_id IN ? AND spent_height = 0", txIds[start:end]).
+			Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, batch...)
+	}
+	return transfers, nil
+}
+
+func (d *Dal) SaveBlock(result *BlockResult) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		events := make([]Brc20Events, len(result.Events))
+		for i, event := range result.Events {
+			events[i] = event.Brc20Events
+		}
+		if len(events) > 0 {
+			if err := tx.CreateInBatches(events, 500).Error; err != nil {
+				return err
+			}
+		}
+		var balances []Brc20HistoricBalances
+		for i, event := range result.Events {
+			for _, balance := range event.Balances {
+				balance.EventId = events[i].Id
+				balances = append(balances, balance)
+			}
+		}
+		if len(balances) > 0 {
+			if err := tx.CreateInBatches(balances, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range result.Tickers {
+			ticker := &result.Tickers[i]
+			if ticker.Id == 0 {
+				if err := tx.Create(ticker).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(ticker).Select("remaining_supply", "burned_supply").Updates(ticker).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(result.Transfers) > 0 {
+			if err := tx.CreateInBatches(result.Transfers, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(result.Spent) > 0 {
+			if err := tx.Model(&Brc20Transfers{}).Where("inscription_id IN ?", result.Spent).
+				Update("spent_height", result.Height).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&Brc20CumulativeEventHashes{
+			BlockHeight:         result.Height,
+			BlockEventHash:      result.BlockEventHash,
+			CumulativeEventHash: result.CumulativeEventHash,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&Brc20BlockHashes{BlockHeight: result.Height, BlockHash: result.Hash}).Error
+	})
+}
+
+func (d *Dal) RollBack(height int64) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		var events []Brc20Events
+		if err := tx.Model(&Brc20Events{}).
+			Where("block_height > ? AND event_type IN ?", height, []int{EventMintInscribe, EventTransferTransfer}).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		minted, burned, err := supplyChanges(events)
+		if err != nil {
+			return err
+		}
+		for tick, amount := range minted {
+			if err := tx.Model(&Brc20Tickers{}).Where("tick = ?", tick).
+				Update("remaining_supply", gorm.Expr("remaining_supply + ?", amount)).Error; err != nil {
+				return err
+			}
+		}
+		for tick, amount := range burned {
+			if err := tx.Model(&Brc20Tickers{}).Where("tick = ?", tick).
+				Update("burned_supply", gorm.Expr("burned_supply - ?", amount)).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&Brc20Transfers{}).Where("spent_height > ?", height).
+			Update("spent_height", 0).Error; err != nil {
+			return err
+		}
+		for _, table := range []interface{}{&Brc20Tickers{}, &Brc20Events{}, &Brc20HistoricBalances{}, &Brc20Transfers{},
+			&Brc20CumulativeEventHashes{}, &Brc20BlockHashes{}} {
+			if err := tx.Where("block_height > ?", height).Delete(table).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
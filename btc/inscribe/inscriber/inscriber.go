@@ -16,11 +16,12 @@ type Inscriber struct {
 	net    *chaincfg.Params
 }
 
-func NewInscriber(btcClient BTCBaseClient) *Inscriber {
-	minter := NewRunesMinter(&chaincfg.MainNetParams, btcClient)
+func NewInscriber(net *chaincfg.Params, btcClient BTCBaseClient) *Inscriber {
+	minter := NewRunesMinter(net, btcClient)
 	return &Inscriber{
 		minter: minter,
 		task:   nil,
+		net:    net,
 	}
 }
 
@@ -112,3 +113,20 @@ func (i *Inscriber) FirstStep(who, runes string, count int64) error {
 	task.LoopSendTxs(sr)
 	return nil
 }
+
+// Inscribe 用户向 order.PayAddress 支付 order.MintFee 之后, 使用支付交易构建并广播所有铸造交易
+func (i *Inscriber) Inscribe(order *Order, payTxHash string) (*SendResult, error) {
+	task := NewSendTask(i.minter.client, order.Who, order.Runes, order.Id, order.Count)
+	sr, err := i.minter.Inscribe(payTxHash, order.MintFee, order.WifPrivateKey, MintReq{
+		RuneId:   order.Runes,
+		Receiver: order.Who,
+		FeeRate:  order.FeeRate,
+		Count:    int(order.Count),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Inscribe")
+	}
+
+	task.LoopSendTxs(sr)
+	return sr, nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 )
@@ -13,6 +14,15 @@ type BTCBaseClient interface {
 	SendRawTransaction(tx *wire.MsgTx) (*chainhash.Hash, error)
 }
 
+// RPCClient 把 bitcoind RPC 客户端适配为 BTCBaseClient, 广播时不允许过高的手续费
+type RPCClient struct {
+	*rpcclient.Client
+}
+
+func (c RPCClient) SendRawTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	return c.Client.SendRawTransaction(tx, false)
+}
+
 type MintReq struct {
 	RuneId   string `json:"rune_id"`  // blockNum:TxIdx
 	Receiver string `json:"receiver"` // receiver address
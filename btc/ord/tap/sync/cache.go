@@ -195,3 +195,7 @@ func (c *Cache) BatchRollBack(blockHeight int64) error {
 
 	return nil
 }
+
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
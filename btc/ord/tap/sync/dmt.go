@@ -21,6 +21,7 @@ import (
 	"github.com/traitmeta/metago/btc/ord/tap/dal"
 	"github.com/traitmeta/metago/btc/ord/tap/model"
 	"github.com/traitmeta/metago/btc/sync"
+	"github.com/traitmeta/metago/pkg/metrics"
 )
 
 type DMTIndexer struct {
@@ -30,6 +31,7 @@ type DMTIndexer struct {
 	client    *rpcclient.Client
 	processor *DMTProcessor
 	cache     *Cache
+	metrics   *metrics.Indexer
 	*sync.BaseSync
 }
 
@@ -41,25 +43,13 @@ func NewDMTIndexer(ctx context.Context, client *rpcclient.Client, db *gorm.DB, c
 		client:    client,
 		processor: NewDMTProcessor(ctx, db, cache),
 		cache:     cache,
+		metrics:   metrics.NewIndexer("tap_dmt"),
 		BaseSync:  sync.NewBaseSync(),
 	}
 }
 
 func (s *DMTIndexer) Start() {
-	s.Send()
-	for {
-		select {
-		case <-s.Receive():
-			err := s.SyncBlock()
-			if err != nil {
-				log.WithContext(s.ctx).WithField("err", err).Error("SyncBlock failed on query block table")
-				time.Sleep(10 * time.Second)
-				s.Send()
-			}
-		case <-s.ctx.Done():
-			return
-		}
-	}
+	s.Run(s.ctx, s.SyncBlock, sync.RetryInterval)
 }
 
 func (s *DMTIndexer) SyncBlock() error {
@@ -68,16 +58,31 @@ func (s *DMTIndexer) SyncBlock() error {
 		return err
 	}
 
+	s.metrics.SetIndexed(uint64(indexBlock.BlockHeight))
 	blockHeight := indexBlock.BlockHeight + 1
+	start := time.Now()
+	head, err := s.client.GetBlockCount()
+	if err != nil {
+		s.metrics.RPCError()
+		return err
+	}
+	s.metrics.SetHead(uint64(head))
+	if blockHeight > head {
+		return errors.Errorf("block %d is beyond chain head %d", blockHeight, head)
+	}
+
 	blockHash, err := s.client.GetBlockHash(blockHeight)
 	if err != nil {
+		s.metrics.RPCError()
 		return err
 	}
 
 	block, err := s.client.GetBlock(blockHash)
 	if err != nil {
+		s.metrics.RPCError()
 		return err
 	}
+	s.metrics.ObserveStage(metrics.StageFetch, start)
 
 	// 检查分叉
 	lastBlock, err := s.dao.GetSyncBlockByName(common.Tap)
@@ -97,6 +102,7 @@ func (s *DMTIndexer) SyncBlock() error {
 	if err := s.processBlock(blockHeight, block); err != nil {
 		return err
 	}
+	s.metrics.SetIndexed(uint64(blockHeight))
 
 	return nil
 }
@@ -106,6 +112,7 @@ func (s *DMTIndexer) processBlock(blockHeight int64, block *wire.MsgBlock) (err
 	var deployActivities []model.TapActivity
 	var mintActivities []model.TapActivity
 	var deployTicks []model.TapElementTick
+	start := time.Now()
 	for txIdx := 0; txIdx < len(block.Transactions); txIdx++ {
 		tx := block.Transactions[txIdx]
 		elems, deployActs, mintActs, ticks, err := s.HandleTx(blockHeight, block.Header.Timestamp.Unix(), tx)
@@ -145,6 +152,10 @@ func (s *DMTIndexer) processBlock(blockHeight int64, block *wire.MsgBlock) (err
 		validActivities = append(validActivities, mintActivities...)
 	}
 
+	s.metrics.ObserveStage(metrics.StageParse, start)
+	start = time.Now()
+	defer s.metrics.ObserveStage(metrics.StagePersist, start)
+
 	if len(validActivities) == 0 && len(elements) == 0 {
 		return s.dao.UpdateBlockByName(common.Tap, block.BlockHash().String(), blockHeight)
 	}
@@ -289,6 +300,7 @@ func (s *DMTIndexer) RollBack(blockHeight int64, rollbackNumber int) error {
 	log.WithContext(s.ctx).Info("DMTIndexer Rolling Back Block", zap.Int64("block_height", rollbackBlockNumber))
 	blockHash, err := s.client.GetBlockHash(rollbackBlockNumber)
 	if err != nil {
+		s.metrics.RPCError()
 		log.WithContext(s.ctx).WithField("block_height", rollbackBlockNumber).Warn("DMTIndexer Rolling Back Block failed on get block hash:%v", err)
 		return err
 	}
@@ -297,6 +309,8 @@ func (s *DMTIndexer) RollBack(blockHeight int64, rollbackNumber int) error {
 		log.WithContext(s.ctx).Errorf("DMTIndexer Rolling Back Block failed on update block tap init index block number:%v", err)
 		return err
 	}
+	s.metrics.Rollback()
+	s.metrics.SetIndexed(uint64(rollbackBlockNumber))
 
 	return nil
 }
@@ -1,5 +1,15 @@
 package sync
 
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RetryInterval SyncBlock 出错(包括已经追上链头)后等待的时间
+const RetryInterval = 10 * time.Second
+
 type BaseSync struct {
 	ch chan bool
 }
@@ -12,8 +22,33 @@ func (b *BaseSync) Receive() <-chan bool {
 	return b.ch
 }
 
+// Send 通知同步下一个区块, 已经有未处理的通知时直接返回
 func (b *BaseSync) Send() {
-	b.ch <- true
+	select {
+	case b.ch <- true:
+	default:
+	}
+}
+
+// Run 收到通知后调用 syncBlock, 成功后立即同步下一个区块, 失败时等待 retry 后重试, ctx 结束时返回
+func (b *BaseSync) Run(ctx context.Context, syncBlock func() error, retry time.Duration) {
+	b.Send()
+	for {
+		select {
+		case <-b.Receive():
+			if err := syncBlock(); err != nil {
+				log.WithContext(ctx).WithField("err", err).Error("SyncBlock failed")
+				select {
+				case <-time.After(retry):
+				case <-ctx.Done():
+					return
+				}
+			}
+			b.Send()
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 type Sync interface {
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBaseSyncRun(t *testing.T) {
+	tests := []struct {
+		name string
+		// results 依次作为 syncBlock 的返回值, 用完后取消 ctx
+		results []error
+		retry   time.Duration
+		want    int
+	}{
+		{name: "syncs the next block after success", results: []error{nil, nil, nil}, retry: time.Hour, want: 3},
+		{name: "retries after an error", results: []error{nil, errors.New("beyond chain head"), nil}, retry: time.Millisecond, want: 3},
+		{name: "cancel during retry", results: []error{errors.New("rpc down")}, retry: time.Hour, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var synced int
+			syncBlock := func() error {
+				err := tt.results[synced]
+				synced++
+				if synced == len(tt.results) {
+					cancel()
+					// 取消后仍然返回错误, Run 必须在退避等待中响应 ctx
+					return errors.New("stop")
+				}
+				return err
+			}
+
+			done := make(chan struct{})
+			go func() {
+				NewBaseSync().Run(ctx, syncBlock, tt.retry)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Run() did not return, synced %d blocks", synced)
+			}
+			if synced != tt.want {
+				t.Errorf("synced %d blocks, want %d", synced, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseSyncSendDoesNotBlock(t *testing.T) {
+	b := NewBaseSync()
+	b.Send()
+	b.Send()
+	if len(b.Receive()) != 1 {
+		t.Errorf("pending notifications = %d, want 1", len(b.Receive()))
+	}
+}
@@ -1,54 +1,438 @@
+/*
+## Overview
+配置分为多个段, 每段对应 setting 中的一个类型和本包的一个全局变量, 由 SetupConfig 或 Load 在启动时读取.
+
+  - 配置文件按 Options.Path、$METAGO_CONFIG、./config/config.yml、程序所在目录下的 config/config.yml 的顺序查找
+  - 读取顺序为默认值、配置文件、环境变量 METAGO_<段>_<字段>、命令行 -set 段.字段=值, 后者覆盖前者
+  - map 类型的配置项用 key=value,key=value 的格式覆盖, 例如 METAGO_ADMIN_KEYS=alice=xxx
+  - 配置文件中未知的段和字段、无法解析的值以及 Validate 检查失败的配置项一次性汇总返回
+  - Print 打印生效的配置, 密码、密钥和地址中的 API key 会被隐藏
+  - Watch 监听配置文件的变更, 只热加载 reloadable 中的配置项, 其它配置项需要重启
+*/
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"github.com/traitmeta/gotos/lib/db"
+
 	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/pkg/blog"
 )
 
+// EnvPrefix 环境变量覆盖配置项时的前缀
+const EnvPrefix = "METAGO_"
+
 // global def
 var (
 	DB         *db.DbConfig
 	BlockChain *setting.BlockChainConfig
+	Sink       *setting.SinkConfig
+	Mempool    *setting.MempoolConfig
+	Admin      *setting.AdminConfig
+	Pricing    *setting.PricingConfig
+	Metrics    *setting.MetricsConfig
+	Bitcoin    *setting.BitcoinConfig
+	Redis      *setting.RedisConfig
+	LevelDB    *setting.LevelDBConfig
+	Server     *setting.ServerConfig
+	Log        *blog.LogConf
+)
+
+// section 配置段, ptr 为指向全局变量的指针, def 为默认值, 配置文件中没有的字段保持默认值
+type section struct {
+	name string
+	ptr  interface{}
+	def  interface{}
+}
+
+var sections = []section{
+	{name: "Database", ptr: &DB, def: &db.DbConfig{DbType: db.PostgresType, Charset: "utf8", ParseTime: true}},
+	{name: "BlockChain", ptr: &BlockChain},
+	{name: "Sink", ptr: &Sink},
+	{name: "Mempool", ptr: &Mempool},
+	{name: "Admin", ptr: &Admin},
+	{name: "Pricing", ptr: &Pricing},
+	{name: "Metrics", ptr: &Metrics},
+	{name: "Bitcoin", ptr: &Bitcoin, def: &setting.BitcoinConfig{Network: "mainnet"}},
+	{name: "Redis", ptr: &Redis, def: &setting.RedisConfig{Addr: "127.0.0.1:6379"}},
+	{name: "LevelDB", ptr: &LevelDB, def: &setting.LevelDBConfig{TapCache: "data/tap", RunesWallet: "data/runes"}},
+	{name: "Server", ptr: &Server, def: &setting.ServerConfig{Explorer: ":8081", Graphql: ":8080", JsonRpc: ":8545"}},
+	{name: "Log", ptr: &Log, def: &blog.LogConf{ServiceName: "metago", Mode: "console", Path: "logs", Level: "info", KeepDays: 7}},
+}
+
+// Options 配置来源
+type Options struct {
+	// Path 配置文件路径, 为空时按默认顺序查找
+	Path string
+	// Sets 命令行覆盖的配置项, 格式为 段.字段=值
+	Sets []string
+}
+
+var (
+	mu     sync.Mutex
+	loaded Options
+	// loadedAt 读取时配置文件的修改时间
+	loadedAt time.Time
 )
 
 func SetupConfig() {
-	conf, err := NewConfig()
-	if err != nil {
-		log.Panic("NewConfig error : ", err)
+	if err := Load(Options{}); err != nil {
+		log.Panic("config.Load error : ", err)
 	}
-	err = conf.ReadSection("Database", &DB)
+}
+
+// Load 读取并检查配置, 成功后替换全局变量, 失败时全局变量保持不变
+func Load(opts Options) error {
+	conf, err := OpenConfig(opts.Path)
 	if err != nil {
-		log.Panic("ReadSection - Database error : ", err)
+		return err
 	}
-	err = conf.ReadSection("BlockChain", &BlockChain)
+	modTime := fileModTime(conf.Path())
+	values, err := conf.read(opts.Sets)
 	if err != nil {
-		log.Panic("ReadSection - BlockChain error : ", err)
+		return err
 	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, s := range sections {
+		reflect.ValueOf(s.ptr).Elem().Set(values[i])
+	}
+	loaded, loadedAt = Options{Path: conf.Path(), Sets: opts.Sets}, modTime
+	return nil
 }
 
 type Config struct {
-	vp *viper.Viper
+	vp   *viper.Viper
+	path string
 }
 
 func NewConfig() (*Config, error) {
+	return OpenConfig("")
+}
+
+// OpenConfig 读取配置文件, path 为空时按默认顺序查找
+func OpenConfig(path string) (*Config, error) {
+	path, err := findConfig(path)
+	if err != nil {
+		return nil, err
+	}
 	vp := viper.New()
-	vp.SetConfigName("config")
-	vp.AddConfigPath("config")
+	vp.SetConfigFile(path)
 	vp.SetConfigType("yaml")
-	err := vp.ReadInConfig()
-	if err != nil {
+	if err := vp.ReadInConfig(); err != nil {
 		return nil, err
 	}
-	return &Config{vp}, nil
+	return &Config{vp: vp, path: path}, nil
 }
 
+// findConfig 查找配置文件, 都不存在时返回查找过的路径
+func findConfig(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	if env := os.Getenv(EnvPrefix + "CONFIG"); env != "" {
+		return env, nil
+	}
+	candidates := []string{filepath.Join("config", "config.yml")}
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), "config", "config.yml"))
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("config file not found in %s, set %sCONFIG or pass -config", strings.Join(candidates, ", "), EnvPrefix)
+}
+
+// Path 配置文件路径
+func (config *Config) Path() string {
+	return config.path
+}
+
+// ReadSection 读取配置段, 配置文件中有结构体没有的字段时返回错误
 func (config *Config) ReadSection(k string, v interface{}) error {
-	err := config.vp.UnmarshalKey(k, v)
+	raw := config.vp.Get(k)
+	if raw == nil {
+		return nil
+	}
+	if m, ok := raw.(map[string]interface{}); ok && strings.EqualFold(k, "Database") {
+		for key, value := range m {
+			if strings.EqualFold(key, "DbType") {
+				m[key] = dbType(value)
+			}
+		}
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           v,
+	})
 	if err != nil {
 		return err
 	}
+	return decoder.Decode(raw)
+}
+
+// read 按配置文件、环境变量、命令行的顺序读取所有配置段并检查, 返回与 sections 一一对应的新值
+func (config *Config) read(sets []string) ([]reflect.Value, error) {
+	var errs []error
+	known := map[string]bool{}
+	values := make([]reflect.Value, len(sections))
+	for i, s := range sections {
+		known[strings.ToLower(s.name)] = true
+		value := reflect.New(reflect.TypeOf(s.ptr).Elem().Elem())
+		if s.def != nil {
+			value.Elem().Set(reflect.ValueOf(s.def).Elem())
+		}
+		if err := config.ReadSection(s.name, value.Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", s.name, err))
+		}
+		for _, f := range fields(s.name, value) {
+			if env, ok := os.LookupEnv(f.env); ok {
+				if err := f.set(env); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %v", f.env, err))
+				}
+			}
+		}
+		values[i] = value
+	}
+	for _, key := range config.vp.AllKeys() {
+		name, _, _ := strings.Cut(key, ".")
+		if !known[name] {
+			known[name] = true
+			errs = append(errs, fmt.Errorf("%s: unknown config section", name))
+		}
+	}
+	for _, set := range sets {
+		if err := applySet(values, set); err != nil {
+			errs = append(errs, fmt.Errorf("-set %s: %v", set, err))
+		}
+	}
+	if len(errs) == 0 {
+		for _, value := range values {
+			errs = append(errs, validate(value.Interface()))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", config.path, err)
+	}
+	return values, nil
+}
+
+// applySet 应用 段.字段=值 格式的命令行配置, 段和字段不区分大小写
+func applySet(values []reflect.Value, set string) error {
+	key, value, ok := strings.Cut(set, "=")
+	if !ok {
+		return errors.New("want Section.Field=value")
+	}
+	for i, s := range sections {
+		for _, f := range fields(s.name, values[i]) {
+			if strings.EqualFold(f.key, key) {
+				return f.set(value)
+			}
+		}
+	}
+	return errors.New("unknown config key")
+}
+
+func validate(v interface{}) error {
+	switch c := v.(type) {
+	case *db.DbConfig:
+		return setting.ValidateDB(c)
+	case *blog.LogConf:
+		return setting.ValidateLog(c)
+	case interface{ Validate() error }:
+		return c.Validate()
+	}
+	return nil
+}
+
+// field 配置项, key 为 段.字段, env 为覆盖它的环境变量
+type field struct {
+	key   string
+	env   string
+	name  string
+	value reflect.Value
+}
+
+// fields 配置段的所有字段, value 为指向配置段的指针
+func fields(sectionName string, value reflect.Value) []field {
+	v := value.Elem()
+	var out []field
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := sf.Name
+		if tag, _, _ := strings.Cut(sf.Tag.Get("mapstructure"), ","); tag != "" {
+			name = tag
+		}
+		out = append(out, field{
+			key:   sectionName + "." + name,
+			env:   EnvPrefix + strings.ToUpper(sectionName+"_"+name),
+			name:  name,
+			value: v.Field(i),
+		})
+	}
+	return out
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (f field) set(s string) error {
+	if f.key == "Database.DbType" {
+		s = fmt.Sprint(dbType(s))
+	}
+	return setValue(f.value, s)
+}
+
+// setValue 把字符串解析为配置项的类型, map 的格式为 key=value,key=value
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range strings.Split(s, ",") {
+			if pair == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid pair %q, want key=value", pair)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(elem, value); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
 	return nil
 }
+
+// dbType 支持用 postgres、mysql 配置数据库类型
+func dbType(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch strings.ToLower(s) {
+	case "postgres", "postgresql":
+		return db.PostgresType
+	case "mysql":
+		return db.MysqlType
+	}
+	return v
+}
+
+// Print 按 段.字段 = 值 的格式打印生效的配置, 隐藏敏感信息
+func Print(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range sections {
+		value := reflect.ValueOf(s.ptr).Elem()
+		if value.IsNil() {
+			continue
+		}
+		for _, f := range fields(s.name, value) {
+			fmt.Fprintf(w, "%s = %s\n", f.key, formatValue(f))
+		}
+	}
+}
+
+// formatValue 隐藏密码、密钥和地址中的 API key, map 只隐藏值
+func formatValue(f field) string {
+	v := f.value
+	secret := setting.IsSecret(f.name)
+	switch {
+	case f.key == "Database.DbType":
+		switch v.Int() {
+		case db.PostgresType:
+			return "postgres"
+		case db.MysqlType:
+			return "mysql"
+		}
+	case v.Kind() == reflect.Map:
+		var pairs []string
+		iter := v.MapRange()
+		for iter.Next() {
+			value := fmt.Sprint(iter.Value().Interface())
+			if secret {
+				value = setting.Redacted
+			}
+			pairs = append(pairs, fmt.Sprintf("%v=%s", iter.Key().Interface(), value))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	case secret && !v.IsZero():
+		return setting.Redacted
+	case v.Kind() == reflect.String && setting.IsUrl(f.name):
+		return setting.RedactUrl(v.String())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// ListenAddr 设置了 PORT 环境变量时监听 :$PORT, 否则使用配置的地址
+func ListenAddr(addr string) string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return addr
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+)
+
+const baseConfig = `
+Database:
+  DbType: postgres
+  DbName: metago
+  Host: 127.0.0.1
+  Port: 5432
+  Username: postgres
+  Pwd: postgres
+BlockChain:
+  RpcUrl: https://mainnet.infura.io/v3/0123456789abcdef0123456789abcdef
+Metrics:
+  MaxLag:
+    default: 10
+Log:
+  Level: info
+`
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		sets    []string
+		wantErr []string
+		check   func(t *testing.T)
+	}{
+		{
+			name:    "file and defaults",
+			content: baseConfig,
+			check: func(t *testing.T) {
+				if DB.DbType != db.PostgresType || DB.Host != "127.0.0.1" || DB.Charset != "utf8" {
+					t.Errorf("DB = %+v", DB)
+				}
+				if Log.Mode != "console" || Log.Path != "logs" || Redis.Addr != "127.0.0.1:6379" || LevelDB.TapCache != "data/tap" {
+					t.Errorf("defaults not applied: %+v %+v %+v", Log, Redis, LevelDB)
+				}
+			},
+		},
+		{
+			name:    "env and flag overrides",
+			content: baseConfig,
+			env: map[string]string{
+				"METAGO_DATABASE_PWD":         "from-env",
+				"METAGO_DATABASE_DBTYPE":      "mysql",
+				"METAGO_ADMIN_KEYS":           "alice=k1,bob=k2",
+				"METAGO_METRICS_STALLTIMEOUT": "1m",
+				"METAGO_METRICS_MAXLAG":       "eth=3",
+				"METAGO_LOG_SERVICE_NAME":     "indexer",
+			},
+			sets: []string{"database.pwd=from-flag", "Bitcoin.Network=regtest"},
+			check: func(t *testing.T) {
+				if DB.Pwd != "from-flag" || DB.DbType != db.MysqlType {
+					t.Errorf("DB = %+v", DB)
+				}
+				if len(Admin.Keys) != 2 || Admin.Keys["bob"] != "k2" {
+					t.Errorf("Admin.Keys = %v", Admin.Keys)
+				}
+				if Metrics.StallTimeout != time.Minute || len(Metrics.MaxLag) != 1 || Metrics.MaxLag["eth"] != 3 {
+					t.Errorf("Metrics = %+v", Metrics)
+				}
+				if Log.ServiceName != "indexer" || Bitcoin.Network != "regtest" {
+					t.Errorf("Log = %+v, Bitcoin = %+v", Log, Bitcoin)
+				}
+			},
+		},
+		{
+			name:    "decode errors",
+			content: baseConfig + "Foo:\n  Bar: 1\nRedis:\n  Adr: 127.0.0.1:6379\n",
+			env:     map[string]string{"METAGO_SINK_REDISDB": "one"},
+			sets:    []string{"Log.Colour=red", "Log.Level"},
+			wantErr: []string{
+				"foo: unknown config section",
+				"Redis: ",
+				"adr",
+				"METAGO_SINK_REDISDB: ",
+				"-set Log.Colour=red: unknown config key",
+				"-set Log.Level: want Section.Field=value",
+			},
+		},
+		{
+			name:    "validation errors",
+			content: baseConfig,
+			sets: []string{
+				"Database.Port=postgres",
+				"BlockChain.WsUrl=https://example.com",
+				"Bitcoin.Network=mainnet3",
+				"Log.Level=debug",
+				"Server.Graphql=8080",
+				"Pricing.Enable=true",
+				"Pricing.Source=file",
+			},
+			wantErr: []string{
+				`Database.Port: invalid port "postgres"`,
+				`BlockChain.WsUrl: unsupported scheme "https"`,
+				`Bitcoin.Network: unknown bitcoin network "mainnet3"`,
+				`Log.Level: unknown level "debug"`,
+				`Server.Graphql: invalid address "8080"`,
+				"Pricing.File: required by the file source",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			DB = nil
+			path := writeConfig(t, t.TempDir(), tt.content)
+			err := Load(Options{Path: path, Sets: tt.sets})
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("Load succeeded, want error")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error missing %q:\n%v", want, err)
+					}
+				}
+				if DB != nil {
+					t.Error("globals replaced by an invalid config")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t)
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	t.Setenv("METAGO_CONFIG", "/etc/metago.yml")
+	if path, err := findConfig("custom.yml"); err != nil || path != "custom.yml" {
+		t.Errorf("findConfig(custom.yml) = %q, %v", path, err)
+	}
+	if path, err := findConfig(""); err != nil || path != "/etc/metago.yml" {
+		t.Errorf("findConfig with METAGO_CONFIG = %q, %v", path, err)
+	}
+
+	t.Setenv("METAGO_CONFIG", "")
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(t.TempDir())
+	if _, err := findConfig(""); err == nil || !strings.Contains(err.Error(), "config file not found") {
+		t.Errorf("findConfig without a config file = %v", err)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("METAGO_ADMIN_KEYS", "alice=secret-key")
+	t.Setenv("METAGO_PRICING_COINGECKOAPIKEY", "cg-key")
+	if err := Load(Options{Path: writeConfig(t, t.TempDir(), baseConfig)}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	Print(&buf)
+	out := buf.String()
+	for _, want := range []string{
+		"Database.DbType = postgres\n",
+		"Database.Pwd = ******\n",
+		"Admin.Keys = alice=******\n",
+		"Pricing.CoinGeckoApiKey = ******\n",
+		"Bitcoin.Pass = \n",
+		"BlockChain.RpcUrl = https://mainnet.infura.io/v3/******\n",
+		"Metrics.MaxLag = default=10\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	for _, secret := range []string{"postgres\nDatabase.Charset", "secret-key", "cg-key", "0123456789abcdef"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output leaks %q", secret)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, baseConfig)
+	if err := Load(Options{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	oldMetrics := Metrics
+
+	changedConfig := strings.Replace(baseConfig, "Level: info", "Level: error", 1)
+	changedConfig = strings.Replace(changedConfig, "Host: 127.0.0.1", "Host: 10.0.0.1", 1)
+	changedConfig = strings.Replace(changedConfig, "default: 10", "default: 2", 1)
+	writeConfig(t, dir, changedConfig)
+	// 保证修改时间变化
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan []string, 1)
+	go Watch(ctx, 10*time.Millisecond, func(changed []string) { done <- changed })
+
+	select {
+	case changed := <-done:
+		if strings.Join(changed, ",") != "Metrics.MaxLag,Log.Level" {
+			t.Errorf("changed = %v", changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+	if Log.Level != "error" || Metrics.MaxLag["default"] != 2 {
+		t.Errorf("reloadable settings not applied: %+v %+v", Log, Metrics)
+	}
+	if DB.Host != "127.0.0.1" {
+		t.Errorf("DB.Host = %s, structural settings must not reload", DB.Host)
+	}
+	if oldMetrics.MaxLag["default"] != 10 {
+		t.Error("reload modified the previous Metrics section in place")
+	}
+
+	// 检查失败的配置不会生效
+	writeConfig(t, dir, strings.Replace(changedConfig, "Level: error", "Level: debug", 1))
+	if _, _, err := reload(Options{Path: path}); err == nil {
+		t.Error("reload accepted an invalid config")
+	}
+	if Log.Level != "error" {
+		t.Errorf("Log.Level = %s after a failed reload", Log.Level)
+	}
+}
@@ -0,0 +1,77 @@
+package setting
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Redacted 打印配置时替换敏感值的占位符
+const Redacted = "******"
+
+// secretWords 名称中包含这些词的配置项视为敏感信息
+var secretWords = []string{"pwd", "pass", "secret", "apikey", "accesskey", "keys", "token"}
+
+// IsSecret 按配置项名称判断是否为密码、密钥等敏感信息
+func IsSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUrl 按配置项名称判断是否为地址, 地址中可能带有 API key
+func IsUrl(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, "url") || strings.HasSuffix(name, "endpoint")
+}
+
+// RedactUrl 隐藏地址中的密码、查询参数和像 API key 的路径段, 例如 https://mainnet.infura.io/v3/<key>
+func RedactUrl(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	var userinfo string
+	if _, ok := u.User.Password(); ok {
+		userinfo = url.User(u.User.Username()).String() + ":" + Redacted + "@"
+		u.User = nil
+	}
+	if u.RawQuery != "" {
+		var pairs []string
+		for key := range u.Query() {
+			pairs = append(pairs, key+"="+Redacted)
+		}
+		sort.Strings(pairs)
+		u.RawQuery = strings.Join(pairs, "&")
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, seg := range segments {
+		if looksLikeKey(seg) {
+			segments[i] = Redacted
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = u.Path
+	s := u.String()
+	if userinfo != "" {
+		s = strings.Replace(s, "://", "://"+userinfo, 1)
+	}
+	return s
+}
+
+// looksLikeKey 16位以上只有字母、数字、- 和 _ 的路径段
+func looksLikeKey(seg string) bool {
+	if len(seg) < 16 {
+		return false
+	}
+	for _, r := range seg {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,330 @@
 package setting
 
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/traitmeta/gotos/lib/db"
+
+	"github.com/traitmeta/metago/pkg/blog"
+)
+
+// ValidateDB 检查数据库配置, Host 为空时视为没有配置数据库
+func ValidateDB(c *db.DbConfig) error {
+	var errs []error
+	if c.DbType != db.PostgresType && c.DbType != db.MysqlType {
+		errs = append(errs, fmt.Errorf("Database.DbType: unknown database type %d, want postgres or mysql", c.DbType))
+	}
+	if c.Host != "" && c.DbName == "" {
+		errs = append(errs, errors.New("Database.DbName: required when Database.Host is set"))
+	}
+	if c.Port != "" {
+		if port, err := strconv.Atoi(c.Port); err != nil || port <= 0 || port > 65535 {
+			errs = append(errs, fmt.Errorf("Database.Port: invalid port %q", c.Port))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateLog 检查日志配置, 与 bzap.SetUp 的要求一致
+func ValidateLog(c *blog.LogConf) error {
+	var errs []error
+	if c.Path == "" {
+		errs = append(errs, errors.New("Log.Path: required"))
+	}
+	if c.Mode == "volume" && c.ServiceName == "" {
+		errs = append(errs, errors.New("Log.service_name: required in volume mode"))
+	}
+	switch c.Level {
+	case "", "info", "error", "severe":
+	default:
+		errs = append(errs, fmt.Errorf("Log.Level: unknown level %q, want info, error or severe", c.Level))
+	}
+	if c.KeepDays < 0 {
+		errs = append(errs, errors.New("Log.keep_days: must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateAddr 检查 host:port 格式的监听地址, 为空时不检查
+func validateAddr(key, addr string) error {
+	if addr == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return fmt.Errorf("%s: invalid address %q, want host:port", key, addr)
+	}
+	return nil
+}
+
+// validateUrl 检查地址的协议, 为空时不检查
+func validateUrl(key, raw string, schemes ...string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%s: invalid url: %v", key, err)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: unsupported scheme %q in %s", key, u.Scheme, RedactUrl(raw))
+}
+
+// validateNonNegative 检查时间间隔不为负数
+func validateNonNegative(key string, d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("%s: must not be negative", key)
+	}
+	return nil
+}
+
 // BlockChainConfig ...
 type BlockChainConfig struct {
 	RpcUrl string
+	// WsUrl 配置后订阅 newHeads, 为空且 RpcUrl 不是 websocket 地址时轮询
+	WsUrl string
+	// ChainId 为0时从节点读取
+	ChainId uint64
+	// Tracer 节点开启 debug 接口时通过 callTracer 读取失败交易的原因
+	Tracer bool
+}
+
+// Validate 检查节点地址, RpcUrl 不是 URL 时作为 IPC 路径
+func (c *BlockChainConfig) Validate() error {
+	var errs []error
+	if u, err := url.Parse(c.RpcUrl); err == nil && u.Scheme != "" {
+		errs = append(errs, validateUrl("BlockChain.RpcUrl", c.RpcUrl, "http", "https", "ws", "wss"))
+	}
+	errs = append(errs, validateUrl("BlockChain.WsUrl", c.WsUrl, "ws", "wss"))
+	return errors.Join(errs...)
+}
+
+// WebsocketUrl 优先使用 WsUrl, RpcUrl 本身为 websocket 地址时也可以订阅, 都没有时返回空
+func (c *BlockChainConfig) WebsocketUrl() string {
+	if c.WsUrl != "" {
+		return c.WsUrl
+	}
+	if u, err := url.Parse(c.RpcUrl); err == nil && (u.Scheme == "ws" || u.Scheme == "wss") {
+		return c.RpcUrl
+	}
+	return ""
+}
+
+// SinkConfig 区块数据输出配置, 为空的项不启用
+type SinkConfig struct {
+	File        string
+	RedisAddr   string
+	RedisPwd    string
+	RedisDB     int
+	RedisStream string
+	RedisMaxLen int64
+	StartHeight uint64
+}
+
+func (c *SinkConfig) Validate() error {
+	var errs []error
+	if c.RedisDB < 0 {
+		errs = append(errs, errors.New("Sink.RedisDB: must not be negative"))
+	}
+	if c.RedisMaxLen < 0 {
+		errs = append(errs, errors.New("Sink.RedisMaxLen: must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// MempoolConfig 交易池监听配置, 需要 websocket 地址
+type MempoolConfig struct {
+	Enable bool
+	// DropAfter 超过该时间仍未上链的交易标记为丢弃
+	DropAfter time.Duration
+}
+
+func (c *MempoolConfig) Validate() error {
+	return validateNonNegative("Mempool.DropAfter", c.DropAfter)
+}
+
+// AdminConfig 管理后台配置, Keys 为空时不启用
+type AdminConfig struct {
+	// Keys 操作人 -> API key, 请求头为 Authorization: Bearer <key>, 操作人记录到审计日志
+	Keys map[string]string
+	// IconStore 图标存储 local 或 s3, 为空时不能上传图标
+	IconStore string
+	// IconDir 本地存储的目录
+	IconDir string
+	// IconUrl 图标访问地址的前缀, 本地存储默认为 /icons/
+	IconUrl string
+	// S3 兼容的对象存储, 使用 path-style 地址 {S3Endpoint}/{S3Bucket}/{key}
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+func (c *AdminConfig) Validate() error {
+	var errs []error
+	for operator, key := range c.Keys {
+		if key == "" {
+			errs = append(errs, fmt.Errorf("Admin.Keys: empty key for operator %q", operator))
+		}
+	}
+	switch c.IconStore {
+	case "", "local":
+	case "s3":
+		if c.S3Endpoint == "" || c.S3Bucket == "" {
+			errs = append(errs, errors.New("Admin.IconStore: s3 needs S3Endpoint and S3Bucket"))
+		}
+		errs = append(errs, validateUrl("Admin.S3Endpoint", c.S3Endpoint, "http", "https"))
+	default:
+		errs = append(errs, fmt.Errorf("Admin.IconStore: unknown icon store %q, want local or s3", c.IconStore))
+	}
+	return errors.Join(errs...)
+}
+
+// PricingConfig Token 法币价格配置
+type PricingConfig struct {
+	Enable bool
+	// Source 价格来源 coingecko, file 或 dex
+	Source string
+	// Interval 更新价格的间隔
+	Interval time.Duration
+	// CoinGeckoUrl CoinGecko 兼容的接口地址, Platform 为链在 CoinGecko 中的 id
+	CoinGeckoUrl      string
+	CoinGeckoPlatform string
+	CoinGeckoApiKey   string
+	// File 与 CoinGecko simple/token_price 响应格式相同的本地文件, 用于离线测试
+	File string
+	// Anchors 从 DEX 成交推导价格时使用的锚定 Token, 例如 USDC
+	Anchors []PriceAnchor
+}
+
+// Validate 只在启用时检查价格来源
+func (c *PricingConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	var errs []error
+	switch c.Source {
+	case "coingecko":
+		errs = append(errs, validateUrl("Pricing.CoinGeckoUrl", c.CoinGeckoUrl, "http", "https"))
+	case "file":
+		if c.File == "" {
+			errs = append(errs, errors.New("Pricing.File: required by the file source"))
+		}
+	case "dex":
+		if len(c.Anchors) == 0 {
+			errs = append(errs, errors.New("Pricing.Anchors: required by the dex source"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("Pricing.Source: unknown source %q, want coingecko, file or dex", c.Source))
+	}
+	errs = append(errs, validateNonNegative("Pricing.Interval", c.Interval))
+	return errors.Join(errs...)
+}
+
+// PriceAnchor 价格已知的 Token
+type PriceAnchor struct {
+	Address  string
+	Decimals uint8
+	PriceUsd float64
+}
+
+// MetricsConfig 指标和健康检查服务配置, Addr 为空时不启动
+type MetricsConfig struct {
+	// Addr 服务监听地址, 例如 :9100, 提供 /metrics、/healthz 和 /readyz
+	Addr string
+	// MaxLag 索引器名称 -> 允许落后的区块数, default 对没有单独配置的索引器生效, 都没有时为10
+	MaxLag map[string]uint64
+	// StallTimeout 落后超过 MaxLag 且超过该时间没有进展时 /healthz 失败, 默认5分钟
+	StallTimeout time.Duration
+}
+
+func (c *MetricsConfig) Validate() error {
+	return errors.Join(
+		validateAddr("Metrics.Addr", c.Addr),
+		validateNonNegative("Metrics.StallTimeout", c.StallTimeout),
+	)
+}
+
+// BitcoinConfig bitcoind RPC 配置, 供 BTC 索引器、铭刻和 Runes 铸造使用
+type BitcoinConfig struct {
+	// Host bitcoind RPC 地址, 例如 127.0.0.1:8332
+	Host string
+	User string
+	Pass string
+	// Network mainnet、testnet3、signet 或 regtest
+	Network    string
+	DisableTLS bool
+}
+
+// Params 网络参数, Network 为空时为主网
+func (c *BitcoinConfig) Params() (*chaincfg.Params, error) {
+	switch c.Network {
+	case "", "mainnet":
+		return &chaincfg.MainNetParams, nil
+	case "testnet3":
+		return &chaincfg.TestNet3Params, nil
+	case "signet":
+		return &chaincfg.SigNetParams, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	}
+	return nil, fmt.Errorf("unknown bitcoin network %q", c.Network)
+}
+
+func (c *BitcoinConfig) Validate() error {
+	var errs []error
+	if _, err := c.Params(); err != nil {
+		errs = append(errs, fmt.Errorf("Bitcoin.Network: %v", err))
+	}
+	errs = append(errs, validateAddr("Bitcoin.Host", c.Host))
+	return errors.Join(errs...)
+}
+
+// RedisConfig Redis 连接配置, 供 TAP 索引器缓存元素
+type RedisConfig struct {
+	Addr string
+	Pwd  string
+	DB   int
+}
+
+func (c *RedisConfig) Validate() error {
+	var errs []error
+	errs = append(errs, validateAddr("Redis.Addr", c.Addr))
+	if c.DB < 0 {
+		errs = append(errs, errors.New("Redis.DB: must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// LevelDBConfig 本地 LevelDB 目录
+type LevelDBConfig struct {
+	// TapCache TAP DMT 索引器的缓存目录
+	TapCache string
+	// RunesWallet Runes 铸造钱包的缓存目录
+	RunesWallet string
+}
+
+// ServerConfig API 服务的监听地址, 设置 PORT 环境变量时优先使用 PORT
+type ServerConfig struct {
+	Explorer string
+	Graphql  string
+	JsonRpc  string
+}
+
+func (c *ServerConfig) Validate() error {
+	return errors.Join(
+		validateAddr("Server.Explorer", c.Explorer),
+		validateAddr("Server.Graphql", c.Graphql),
+		validateAddr("Server.JsonRpc", c.JsonRpc),
+	)
 }
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// reloadable 不需要重启就能生效的配置项, 使用方在 Watch 的回调中读取新值
+var reloadable = map[string]bool{
+	"Log.Level":            true,
+	"Metrics.MaxLag":       true,
+	"Metrics.StallTimeout": true,
+}
+
+// Watch 每隔 interval 检查 Load 读取的配置文件, 文件变更后重新读取, 只替换 reloadable 中的配置项,
+// 其它配置项的变更打印警告并忽略, 新配置检查失败时保持原配置. onReload 在新值生效后调用, ctx 结束时返回
+func Watch(ctx context.Context, interval time.Duration, onReload func(changed []string)) {
+	mu.Lock()
+	opts, modTime := loaded, loadedAt
+	mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		t := fileModTime(opts.Path)
+		if t.Equal(modTime) {
+			continue
+		}
+		modTime = t
+
+		changed, ignored, err := reload(opts)
+		if err != nil {
+			log.Printf("config reload failed, keeping the current config: %v", err)
+			continue
+		}
+		if len(ignored) > 0 {
+			log.Printf("config changes to %s need a restart", strings.Join(ignored, ", "))
+		}
+		if len(changed) > 0 {
+			log.Printf("config reloaded: %s", strings.Join(changed, ", "))
+			onReload(changed)
+		}
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reload 重新读取配置, 变更的 reloadable 配置项写入配置段的副本后替换全局变量, 原配置段不会被修改
+func reload(opts Options) (changed, ignored []string, err error) {
+	conf, err := OpenConfig(opts.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := conf.read(opts.Sets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, s := range sections {
+		current := reflect.ValueOf(s.ptr).Elem()
+		if current.IsNil() {
+			continue
+		}
+		var next reflect.Value
+		newFields := fields(s.name, values[i])
+		for j, f := range fields(s.name, current) {
+			if reflect.DeepEqual(f.value.Interface(), newFields[j].value.Interface()) {
+				continue
+			}
+			if !reloadable[f.key] {
+				ignored = append(ignored, f.key)
+				continue
+			}
+			if !next.IsValid() {
+				next = reflect.New(current.Type().Elem())
+				next.Elem().Set(current.Elem())
+			}
+			next.Elem().Field(j).Set(newFields[j].value)
+			changed = append(changed, f.key)
+		}
+		if next.IsValid() {
+			current.Set(next)
+		}
+	}
+	return changed, ignored, nil
+}
@@ -0,0 +1,172 @@
+/*
+## Overview
+admin 提供管理后台的 REST 接口, 用于人工整理 Token 和地址信息.
+
+  - 请求头 Authorization: Bearer <key> 鉴权, key 在配置 Admin.Keys 中按操作人配置
+  - 验证 Token, 覆盖 name/symbol/decimals, 覆盖后设置 skip_metadata 避免被重新读取的元信息覆盖
+  - 上传 Token 图标到本地目录或 S3 兼容的对象存储
+  - 标记垃圾 Token, 垃圾 Token 不出现在检索结果中
+  - 给地址打 exchange、bridge、scam 标签
+  - 每次修改在同一个事务中写入审计日志, 记录操作人和修改前后的内容
+*/
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/explorer"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/errcode"
+)
+
+const DefaultPrefix = "/admin/v1"
+
+// Store 管理后台读写数据的接口, 查询不到时返回 nil
+type Store interface {
+	WithTx(ctx context.Context, fn func(txCtx context.Context) error) error
+	Token(ctx context.Context, contractAddress string) (*models.Token, error)
+	UpdateToken(ctx context.Context, contractAddress string, updates map[string]interface{}) error
+	Labels(ctx context.Context, address string) ([]models.AddressLabel, error)
+	Label(ctx context.Context, address, label string) (*models.AddressLabel, error)
+	UpsertLabel(ctx context.Context, label *models.AddressLabel) error
+	DeleteLabel(ctx context.Context, address, label string) (bool, error)
+	InsertAuditLog(ctx context.Context, log *models.AdminAuditLog) error
+	AuditLogs(ctx context.Context, actor, target string, offset, limit int) ([]models.AdminAuditLog, error)
+}
+
+// dbStore 通过 dal 读写数据库
+type dbStore struct{}
+
+func (dbStore) WithTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	return dal.WithTx(ctx, fn)
+}
+
+func (dbStore) Token(ctx context.Context, contractAddress string) (*models.Token, error) {
+	return dal.IgnoreNotFound(dal.Token.GetByContractAddress(ctx, contractAddress))
+}
+
+func (dbStore) UpdateToken(ctx context.Context, contractAddress string, updates map[string]interface{}) error {
+	return dal.Token.Update(ctx, contractAddress, updates)
+}
+
+func (dbStore) Labels(ctx context.Context, address string) ([]models.AddressLabel, error) {
+	return dal.Admin.ListLabels(ctx, address)
+}
+
+func (dbStore) Label(ctx context.Context, address, label string) (*models.AddressLabel, error) {
+	return dal.IgnoreNotFound(dal.Admin.GetLabel(ctx, address, label))
+}
+
+func (dbStore) UpsertLabel(ctx context.Context, label *models.AddressLabel) error {
+	return dal.Admin.UpsertLabel(ctx, label)
+}
+
+func (dbStore) DeleteLabel(ctx context.Context, address, label string) (bool, error) {
+	n, err := dal.Admin.DeleteLabel(ctx, address, label)
+	return n > 0, err
+}
+
+func (dbStore) InsertAuditLog(ctx context.Context, log *models.AdminAuditLog) error {
+	return dal.Admin.InsertAuditLog(ctx, log)
+}
+
+func (dbStore) AuditLogs(ctx context.Context, actor, target string, offset, limit int) ([]models.AdminAuditLog, error) {
+	return dal.Admin.ListAuditLogs(ctx, actor, target, offset, limit)
+}
+
+// Server 管理后台服务, 实现 http.Handler, 鉴权通过后交给路由处理
+type Server struct {
+	router *explorer.Server
+	store  Store
+	icons  IconStore
+	keys   map[string]string
+}
+
+// NewServer 根据配置创建管理后台服务, 接口挂载在 prefix 下
+func NewServer(prefix string, conf *setting.AdminConfig) (*Server, error) {
+	icons, err := NewIconStore(conf)
+	if err != nil {
+		return nil, err
+	}
+	return newServer(prefix, dbStore{}, icons, conf.Keys), nil
+}
+
+func newServer(prefix string, store Store, icons IconStore, keys map[string]string) *Server {
+	s := &Server{router: explorer.NewRouter(prefix), store: store, icons: icons, keys: keys}
+	s.router.Register(s.routes()...)
+	return s
+}
+
+// Icons 图标存储, 未配置时为 nil
+func (s *Server) Icons() IconStore {
+	return s.icons
+}
+
+// OpenAPI 管理后台接口文档
+func (s *Server) OpenAPI() map[string]interface{} {
+	return s.router.OpenAPI()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	actor, ok := s.authenticate(r)
+	if !ok {
+		explorer.WriteError(w, errcode.ErrTokenVerify)
+		return
+	}
+	s.router.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), actorKey{}, actor)))
+}
+
+type actorKey struct{}
+
+// actorFrom 鉴权通过的操作人
+func actorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// authenticate 逐个比较所有 key, 比较耗时与 key 的内容无关
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	var actor string
+	for name, key := range s.keys {
+		if key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			actor = name
+		}
+	}
+	return actor, actor != ""
+}
+
+// audit 记录一次修改, 需要在修改所在的事务中调用
+func (s *Server) audit(ctx context.Context, action, targetType, target string, before, after interface{}) error {
+	log := &models.AdminAuditLog{
+		Actor:      actorFrom(ctx),
+		Action:     action,
+		TargetType: targetType,
+		Target:     target,
+	}
+	var err error
+	if log.Before, err = auditJSON(before); err != nil {
+		return err
+	}
+	if log.After, err = auditJSON(after); err != nil {
+		return err
+	}
+	return s.store.InsertAuditLog(ctx, log)
+}
+
+func auditJSON(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
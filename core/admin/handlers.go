@@ -0,0 +1,431 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/explorer"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/errcode"
+)
+
+const (
+	maxBodySize = 64 << 10
+	maxIconSize = 1 << 20
+
+	targetToken   = "token"
+	targetAddress = "address"
+)
+
+// iconTypes 允许上传的图标类型及保存的扩展名, svg 可以包含脚本, 不允许上传
+var iconTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// labelKinds 允许的地址标签
+var labelKinds = map[string]bool{
+	models.AddressLabelExchange: true,
+	models.AddressLabelBridge:   true,
+	models.AddressLabelScam:     true,
+}
+
+// Token 管理后台看到的 Token, 包含人工整理的字段
+type Token struct {
+	ContractAddress string `json:"contract_address"`
+	Name            string `json:"name"`
+	Symbol          string `json:"symbol"`
+	Decimals        uint8  `json:"decimals"`
+	Type            string `json:"type"`
+	IconUrl         string `json:"icon_url,omitempty"`
+	Verified        bool   `json:"verified"`
+	Spam            bool   `json:"spam"`
+	Cataloged       bool   `json:"cataloged"`
+	SkipMetadata    bool   `json:"skip_metadata"`
+}
+
+// TokenPatch 修改 Token, 只修改出现的字段
+type TokenPatch struct {
+	Name      *string `json:"name,omitempty"`
+	Symbol    *string `json:"symbol,omitempty"`
+	Decimals  *uint8  `json:"decimals,omitempty"`
+	Verified  *bool   `json:"verified,omitempty"`
+	Spam      *bool   `json:"spam,omitempty"`
+	Cataloged *bool   `json:"cataloged,omitempty"`
+	// SkipMetadata 覆盖 name/symbol/decimals 时默认设置为 true, 设置为 false 可以恢复读取链上元信息
+	SkipMetadata *bool `json:"skip_metadata,omitempty"`
+}
+
+// updates 转换为数据库字段
+func (p TokenPatch) updates() map[string]interface{} {
+	updates := map[string]interface{}{}
+	if p.Name != nil {
+		updates["name"] = *p.Name
+	}
+	if p.Symbol != nil {
+		updates["symbol"] = *p.Symbol
+	}
+	if p.Decimals != nil {
+		updates["decimals"] = *p.Decimals
+	}
+	if len(updates) > 0 {
+		updates["skip_metadata"] = true
+	}
+	if p.Verified != nil {
+		updates["is_verified_via_admin_panel"] = *p.Verified
+	}
+	if p.Spam != nil {
+		updates["spam"] = *p.Spam
+	}
+	if p.Cataloged != nil {
+		updates["cataloged"] = *p.Cataloged
+	}
+	if p.SkipMetadata != nil {
+		updates["skip_metadata"] = *p.SkipMetadata
+	}
+	return updates
+}
+
+type AddressLabel struct {
+	Address   string    `json:"address"`
+	Label     string    `json:"label"`
+	Name      string    `json:"name,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LabelInput 添加或修改地址标签
+type LabelInput struct {
+	Name string `json:"name,omitempty"`
+	Note string `json:"note,omitempty"`
+}
+
+// AuditLog 一条操作记录, Before 和 After 为修改前后的 JSON, 新增时 Before 为空, 删除时 After 为空
+type AuditLog struct {
+	Id         uint      `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	Target     string    `json:"target"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func toToken(t *models.Token) Token {
+	return Token{
+		ContractAddress: t.ContractAddress,
+		Name:            t.Name,
+		Symbol:          t.Symbol,
+		Decimals:        t.Decimals,
+		Type:            t.Type,
+		IconUrl:         t.IconUrl,
+		Verified:        t.IsVerifiedViaAdminPanel,
+		Spam:            t.Spam,
+		Cataloged:       t.Cataloged,
+		SkipMetadata:    t.SkipMetadata,
+	}
+}
+
+func toAddressLabel(l *models.AddressLabel) AddressLabel {
+	label := AddressLabel{Address: l.Address, Label: l.Label, Name: l.Name, Note: l.Note, CreatedBy: l.CreatedBy}
+	if l.Model != nil {
+		label.UpdatedAt = l.UpdatedAt
+	}
+	return label
+}
+
+func toAuditLog(l *models.AdminAuditLog) AuditLog {
+	log := AuditLog{Actor: l.Actor, Action: l.Action, TargetType: l.TargetType, Target: l.Target, Before: l.Before, After: l.After}
+	if l.Model != nil {
+		log.Id, log.CreatedAt = l.ID, l.CreatedAt
+	}
+	return log
+}
+
+func (s *Server) routes() []explorer.Route {
+	addressParam := explorer.Param{Name: "address", In: "path", Type: "string", Description: "token contract or account address", Required: true}
+	labelParam := explorer.Param{Name: "label", In: "path", Type: "string", Description: "exchange, bridge or scam", Required: true}
+
+	return []explorer.Route{
+		{
+			Method: http.MethodGet, Path: "/tokens/{address}", Name: "getToken", Tag: "tokens",
+			Summary: "Get a token with its curation fields", Params: []explorer.Param{addressParam},
+			Result: Token{}, Handle: s.getToken,
+		},
+		{
+			Method: http.MethodPatch, Path: "/tokens/{address}", Name: "updateToken", Tag: "tokens",
+			Summary: "Verify a token, mark it as spam or override its name, symbol and decimals",
+			Params:  []explorer.Param{addressParam}, Body: TokenPatch{},
+			Result: Token{}, Handle: s.updateToken,
+		},
+		{
+			Method: http.MethodPut, Path: "/tokens/{address}/icon", Name: "uploadTokenIcon", Tag: "tokens",
+			Summary: "Upload a token icon, the body is the raw png, jpeg, gif or webp image up to 1 MiB",
+			Params:  []explorer.Param{addressParam},
+			Result:  Token{}, Handle: s.uploadTokenIcon,
+		},
+		{
+			Method: http.MethodGet, Path: "/addresses/{address}/labels", Name: "listAddressLabels", Tag: "addresses",
+			Summary: "List the labels of an address", Params: []explorer.Param{addressParam},
+			Result: []AddressLabel{}, Handle: s.listLabels,
+		},
+		{
+			Method: http.MethodPut, Path: "/addresses/{address}/labels/{label}", Name: "putAddressLabel", Tag: "addresses",
+			Summary: "Attach a label to an address or update its name and note",
+			Params:  []explorer.Param{addressParam, labelParam}, Body: LabelInput{},
+			Result: AddressLabel{}, Handle: s.putLabel,
+		},
+		{
+			Method: http.MethodDelete, Path: "/addresses/{address}/labels/{label}", Name: "deleteAddressLabel", Tag: "addresses",
+			Summary: "Remove a label from an address", Params: []explorer.Param{addressParam, labelParam},
+			Handle: s.deleteLabel,
+		},
+		{
+			Method: http.MethodGet, Path: "/audit-logs", Name: "listAuditLogs", Tag: "audit",
+			Summary: "List admin changes, latest first",
+			Params: []explorer.Param{
+				{Name: "actor", In: "query", Type: "string", Description: "only changes made by this actor"},
+				{Name: "target", In: "query", Type: "string", Description: "only changes of this token or address"},
+				{Name: "offset", In: "query", Type: "integer", Description: "number of items to skip"},
+				{Name: "limit", In: "query", Type: "integer", Description: "page size, 1 to 100, defaults to 20"},
+			},
+			Result: explorer.Page[AuditLog]{}, Handle: s.listAuditLogs,
+		},
+	}
+}
+
+func decodeBody(r *explorer.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return explorer.InvalidParam("body")
+	}
+	return nil
+}
+
+func (s *Server) findToken(ctx context.Context, address string) (*models.Token, error) {
+	token, err := s.store.Token(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errcode.ErrRecordNotFound
+	}
+	return token, nil
+}
+
+func (s *Server) getToken(r *explorer.Request) (interface{}, error) {
+	address, err := explorer.AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.findToken(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	return toToken(token), nil
+}
+
+// changeToken 在一个事务中修改 Token 并记录审计日志
+func (s *Server) changeToken(ctx context.Context, action, address string, updates map[string]interface{}) (*Token, error) {
+	var result Token
+	err := s.store.WithTx(ctx, func(txCtx context.Context) error {
+		token, err := s.findToken(txCtx, address)
+		if err != nil {
+			return err
+		}
+		before := toToken(token)
+		if err := s.store.UpdateToken(txCtx, address, updates); err != nil {
+			return err
+		}
+		if token, err = s.findToken(txCtx, address); err != nil {
+			return err
+		}
+		result = toToken(token)
+		return s.audit(txCtx, action, targetToken, address, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *Server) updateToken(r *explorer.Request) (interface{}, error) {
+	address, err := explorer.AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	var patch TokenPatch
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+	updates := patch.updates()
+	if len(updates) == 0 {
+		return nil, explorer.InvalidParam("body")
+	}
+	return s.changeToken(r.Context(), "update_token", address, updates)
+}
+
+func (s *Server) uploadTokenIcon(r *explorer.Request) (interface{}, error) {
+	address, err := explorer.AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	if s.icons == nil {
+		return nil, errcode.NewErr(errcode.ErrFileLoadErr.Code(), "Icon store is not configured", http.StatusNotImplemented)
+	}
+	contentType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	contentType = strings.TrimSpace(contentType)
+	ext, ok := iconTypes[contentType]
+	if !ok {
+		return nil, errcode.NewErr(errcode.ErrFileUnknown.Code(), errcode.ErrFileUnknown.Error(), http.StatusUnsupportedMediaType)
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxIconSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data) > maxIconSize {
+		return nil, explorer.InvalidParam("body")
+	}
+	// 按内容校验类型, 不能把其他文件声明为图片上传
+	if http.DetectContentType(data) != contentType {
+		return nil, errcode.NewErr(errcode.ErrFileUnknown.Code(), errcode.ErrFileUnknown.Error(), http.StatusUnsupportedMediaType)
+	}
+	if _, err := s.findToken(r.Context(), address); err != nil {
+		return nil, err
+	}
+
+	url, err := s.icons.Put(r.Context(), strings.ToLower(address)+ext, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	return s.changeToken(r.Context(), "upload_token_icon", address, map[string]interface{}{"icon_url": url})
+}
+
+func (s *Server) listLabels(r *explorer.Request) (interface{}, error) {
+	address, err := explorer.AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.store.Labels(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]AddressLabel, 0, len(labels))
+	for i := range labels {
+		res = append(res, toAddressLabel(&labels[i]))
+	}
+	return res, nil
+}
+
+func labelVar(r *explorer.Request) (string, error) {
+	label := strings.ToLower(r.Var("label"))
+	if !labelKinds[label] {
+		return "", explorer.InvalidParam("label")
+	}
+	return label, nil
+}
+
+func (s *Server) putLabel(r *explorer.Request) (interface{}, error) {
+	address, err := explorer.AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	kind, err := labelVar(r)
+	if err != nil {
+		return nil, err
+	}
+	var input LabelInput
+	if err := decodeBody(r, &input); err != nil {
+		return nil, err
+	}
+
+	var result AddressLabel
+	err = s.store.WithTx(r.Context(), func(txCtx context.Context) error {
+		old, err := s.store.Label(txCtx, address, kind)
+		if err != nil {
+			return err
+		}
+		label := &models.AddressLabel{
+			Address:   address,
+			Label:     kind,
+			Name:      input.Name,
+			Note:      input.Note,
+			CreatedBy: actorFrom(txCtx),
+		}
+		if err := s.store.UpsertLabel(txCtx, label); err != nil {
+			return err
+		}
+		result = toAddressLabel(label)
+
+		var before interface{}
+		if old != nil {
+			before = toAddressLabel(old)
+		}
+		return s.audit(txCtx, "put_address_label", targetAddress, address, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Server) deleteLabel(r *explorer.Request) (interface{}, error) {
+	address, err := explorer.AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	kind, err := labelVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.WithTx(r.Context(), func(txCtx context.Context) error {
+		old, err := s.store.Label(txCtx, address, kind)
+		if err != nil {
+			return err
+		}
+		if old == nil {
+			return errcode.ErrRecordNotFound
+		}
+		if _, err := s.store.DeleteLabel(txCtx, address, kind); err != nil {
+			return err
+		}
+		return s.audit(txCtx, "delete_address_label", targetAddress, address, toAddressLabel(old), nil)
+	})
+	return nil, err
+}
+
+func (s *Server) listAuditLogs(r *explorer.Request) (interface{}, error) {
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+	target := r.Query("target")
+	if common.IsHexAddress(target) {
+		target = common.HexToAddress(target).Hex()
+	}
+	logs, err := s.store.AuditLogs(r.Context(), r.Query("actor"), target, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := explorer.Page[AuditLog]{Items: make([]AuditLog, 0, len(logs)), Offset: offset, Limit: limit}
+	if len(logs) > limit {
+		logs, page.HasMore = logs[:limit], true
+	}
+	for i := range logs {
+		page.Items = append(page.Items, toAuditLog(&logs[i]))
+	}
+	return page, nil
+}
@@ -0,0 +1,204 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+// IconStore 保存图标并返回访问地址
+type IconStore interface {
+	Put(ctx context.Context, name, contentType string, data []byte) (string, error)
+}
+
+// NewIconStore 根据配置创建图标存储, 没有配置时返回 nil
+func NewIconStore(conf *setting.AdminConfig) (IconStore, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	switch conf.IconStore {
+	case "":
+		return nil, nil
+	case "local":
+		return NewLocalIconStore(conf.IconDir, conf.IconUrl)
+	case "s3":
+		if conf.S3Endpoint == "" || conf.S3Bucket == "" {
+			return nil, fmt.Errorf("admin: s3 icon store needs S3Endpoint and S3Bucket")
+		}
+		return &S3IconStore{
+			Endpoint:  strings.TrimSuffix(conf.S3Endpoint, "/"),
+			Region:    conf.S3Region,
+			Bucket:    conf.S3Bucket,
+			AccessKey: conf.S3AccessKey,
+			SecretKey: conf.S3SecretKey,
+			BaseUrl:   conf.IconUrl,
+		}, nil
+	default:
+		return nil, fmt.Errorf("admin: unknown icon store %q", conf.IconStore)
+	}
+}
+
+// LocalIconStore 图标保存在本地目录, 由服务通过 BaseUrl 提供静态文件
+type LocalIconStore struct {
+	Dir     string
+	BaseUrl string
+}
+
+const defaultIconUrl = "/icons/"
+
+func NewLocalIconStore(dir, baseUrl string) (*LocalIconStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("admin: local icon store needs IconDir")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if baseUrl == "" {
+		baseUrl = defaultIconUrl
+	}
+	return &LocalIconStore{Dir: dir, BaseUrl: baseUrl}, nil
+}
+
+// Put 先写临时文件再重命名, 避免读到写了一半的图标
+func (s *LocalIconStore) Put(_ context.Context, name, _ string, data []byte) (string, error) {
+	tmp, err := os.CreateTemp(s.Dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.Dir, name)); err != nil {
+		return "", err
+	}
+	return joinUrl(s.BaseUrl, name), nil
+}
+
+// Handler 提供目录中的图标文件. 图标不可信, 禁止浏览器猜测类型, 并用 CSP sandbox 禁止文件中的脚本执行
+func (s *LocalIconStore) Handler() http.Handler {
+	files := http.FileServer(http.Dir(s.Dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "sandbox")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
+
+// S3IconStore 图标上传到 S3 兼容的对象存储, 请求使用 AWS Signature V4 签名
+type S3IconStore struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// BaseUrl 图标访问地址前缀, 为空时使用 {Endpoint}/{Bucket}/
+	BaseUrl string
+	Client  *http.Client
+}
+
+func (s *S3IconStore) Put(ctx context.Context, name, contentType string, data []byte) (string, error) {
+	objectUrl := s.Endpoint + "/" + s.Bucket + "/" + name
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectUrl, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	sum := sha256.Sum256(data)
+	signV4(req, hex.EncodeToString(sum[:]), s.Region, s.AccessKey, s.SecretKey, time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("admin: upload icon %s: %s %s", name, resp.Status, bytes.TrimSpace(body))
+	}
+
+	if s.BaseUrl != "" {
+		return joinUrl(s.BaseUrl, name), nil
+	}
+	return objectUrl, nil
+}
+
+func joinUrl(base, name string) string {
+	return strings.TrimSuffix(base, "/") + "/" + name
+}
+
+// signV4 按 AWS Signature V4 给请求签名, 签名覆盖 host 和请求中已经设置的所有请求头
+func signV4(req *http.Request, payloadHash, region, accessKey, secretKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,220 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+/*
+## Overview
+  授权只保存当前状态, 每个 key 只保留最后一次事件, 后写入的区块覆盖之前的结果.
+
+  | Event                                | Key                              | 状态                             |
+  |--------------------------------------|----------------------------------|----------------------------------|
+  | ERC20 `Approval`(3个topic)           | (token, owner, spender)          | `data` 中的额度, 0 表示撤销       |
+  | ERC721/ERC1155 `ApprovalForAll`      | (collection, owner, operator)    | `data` 中的 approved              |
+
+  ERC721 单个 tokenId 的 `Approval` 有4个topic, 转移后自动失效, 不记录.
+  额度不小于 2^255 时视为无限授权, 包括常见的 MaxUint256 和 MaxInt256.
+  transferFrom 消耗的额度只有合约同时发出 `Approval` 时才会更新.
+  重新索引区块时, 最后一次修改在该高度的授权恢复为之前最后一次日志的状态, 之前没有日志时删除.
+*/
+
+var unlimitedAllowance = new(big.Int).Lsh(big.NewInt(1), 255)
+
+type approvalKey struct {
+	contract string
+	owner    string
+	spender  string
+}
+
+// Approvals 一个区块中解析出的授权最新状态
+type Approvals struct {
+	TokenAllowances   []models.TokenAllowance
+	OperatorApprovals []models.OperatorApproval
+}
+
+// ParseApprovals 从区块日志中解析授权, 同一个 key 只返回最后一次的状态, 顺序为首次出现的顺序.
+// 格式不对的日志只记录警告并跳过, 同名事件可能来自不符合标准的合约
+func ParseApprovals(logs []models.Event) (Approvals, error) {
+	var (
+		result       Approvals
+		allowanceIdx = map[approvalKey]int{}
+		operatorIdx  = map[approvalKey]int{}
+	)
+
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		switch {
+		case log.FirstTopic == common.ApprovalSignature && log.ThirdTopic != "" && log.FourthTopic == "":
+			amount, err := parseWord(log, abi.ParseErc20ApprovalLog)
+			if err != nil {
+				warnSkipLog(log, err)
+				continue
+			}
+
+			allowance := models.TokenAllowance{
+				Token:           log.Address,
+				Owner:           ethcommon.HexToAddress(log.SecondTopic).Hex(),
+				Spender:         ethcommon.HexToAddress(log.ThirdTopic).Hex(),
+				Amount:          amount,
+				Unlimited:       amount.Cmp(unlimitedAllowance) >= 0,
+				TransactionHash: log.TxHash,
+				BlockNumber:     log.BlockNumber,
+				BlockHash:       log.BlockHash,
+				LogIndex:        log.LogIndex,
+			}
+			key := approvalKey{contract: allowance.Token, owner: allowance.Owner, spender: allowance.Spender}
+			if i, ok := allowanceIdx[key]; ok {
+				result.TokenAllowances[i] = allowance
+			} else {
+				allowanceIdx[key] = len(result.TokenAllowances)
+				result.TokenAllowances = append(result.TokenAllowances, allowance)
+			}
+
+		case log.FirstTopic == common.ApprovalForAllSignature && log.ThirdTopic != "" && log.FourthTopic == "":
+			approved, err := parseWord(log, abi.ParseApprovalForAllLog)
+			if err != nil {
+				warnSkipLog(log, err)
+				continue
+			}
+
+			approval := models.OperatorApproval{
+				Collection:      log.Address,
+				Owner:           ethcommon.HexToAddress(log.SecondTopic).Hex(),
+				Operator:        ethcommon.HexToAddress(log.ThirdTopic).Hex(),
+				Approved:        approved,
+				TransactionHash: log.TxHash,
+				BlockNumber:     log.BlockNumber,
+				BlockHash:       log.BlockHash,
+				LogIndex:        log.LogIndex,
+			}
+			key := approvalKey{contract: approval.Collection, owner: approval.Owner, spender: approval.Operator}
+			if i, ok := operatorIdx[key]; ok {
+				result.OperatorApprovals[i] = approval
+			} else {
+				operatorIdx[key] = len(result.OperatorApprovals)
+				result.OperatorApprovals = append(result.OperatorApprovals, approval)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parseWord data 只有一个32字节的字段时才解析, 多出或缺少的数据视为格式错误
+func parseWord[T any](log models.Event, parse func([]byte) (T, error)) (T, error) {
+	data := ethcommon.FromHex(log.Data)
+	if len(data) != 32 {
+		var zero T
+		return zero, fmt.Errorf("data length %d, want 32", len(data))
+	}
+	return parse(data)
+}
+
+// approvalStore 回滚授权时读写的数据, 默认使用 dal, 测试中替换为内存实现
+type approvalStore interface {
+	GetAllowancesByBlock(ctx context.Context, height uint64) ([]models.TokenAllowance, error)
+	GetOperatorApprovalsByBlock(ctx context.Context, height uint64) ([]models.OperatorApproval, error)
+	// GetApprovalLogs height 之前的授权日志, 按时间顺序返回
+	GetApprovalLogs(ctx context.Context, contract, signature, owner, spender string, height uint64) ([]models.Event, error)
+	ReplaceAllowance(ctx context.Context, allowance models.TokenAllowance) error
+	ReplaceOperatorApproval(ctx context.Context, approval models.OperatorApproval) error
+	DeleteAllowance(ctx context.Context, allowance models.TokenAllowance) error
+	DeleteOperatorApproval(ctx context.Context, approval models.OperatorApproval) error
+}
+
+type dalApprovalStore struct{}
+
+func (dalApprovalStore) GetAllowancesByBlock(ctx context.Context, height uint64) ([]models.TokenAllowance, error) {
+	return dal.Approval.GetAllowancesByBlock(ctx, height)
+}
+
+func (dalApprovalStore) GetOperatorApprovalsByBlock(ctx context.Context, height uint64) ([]models.OperatorApproval, error) {
+	return dal.Approval.GetOperatorApprovalsByBlock(ctx, height)
+}
+
+func (dalApprovalStore) GetApprovalLogs(ctx context.Context, contract, signature, owner, spender string, height uint64) ([]models.Event, error) {
+	return dal.Event.GetApprovalLogs(ctx, contract, signature, owner, spender, height)
+}
+
+func (dalApprovalStore) ReplaceAllowance(ctx context.Context, allowance models.TokenAllowance) error {
+	return dal.Approval.ReplaceAllowance(ctx, allowance)
+}
+
+func (dalApprovalStore) ReplaceOperatorApproval(ctx context.Context, approval models.OperatorApproval) error {
+	return dal.Approval.ReplaceOperatorApproval(ctx, approval)
+}
+
+func (dalApprovalStore) DeleteAllowance(ctx context.Context, allowance models.TokenAllowance) error {
+	return dal.Approval.DeleteAllowance(ctx, allowance)
+}
+
+func (dalApprovalStore) DeleteOperatorApproval(ctx context.Context, approval models.OperatorApproval) error {
+	return dal.Approval.DeleteOperatorApproval(ctx, approval)
+}
+
+var approvalState approvalStore = dalApprovalStore{}
+
+// revertApprovals 把最后一次修改在 height 的授权恢复为 height 之前最后一条有效日志的状态, 没有日志时删除.
+// 新区块的授权随后照常写入
+func revertApprovals(ctx context.Context, store approvalStore, height uint64) error {
+	allowances, err := store.GetAllowancesByBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+	for _, allowance := range allowances {
+		logs, err := store.GetApprovalLogs(ctx, allowance.Token, common.ApprovalSignature,
+			addressTopic(allowance.Owner), addressTopic(allowance.Spender), height)
+		if err != nil {
+			return err
+		}
+		prev, _ := ParseApprovals(logs)
+		if len(prev.TokenAllowances) == 0 {
+			err = store.DeleteAllowance(ctx, allowance)
+		} else {
+			err = store.ReplaceAllowance(ctx, prev.TokenAllowances[0])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	approvals, err := store.GetOperatorApprovalsByBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+	for _, approval := range approvals {
+		logs, err := store.GetApprovalLogs(ctx, approval.Collection, common.ApprovalForAllSignature,
+			addressTopic(approval.Owner), addressTopic(approval.Operator), height)
+		if err != nil {
+			return err
+		}
+		prev, _ := ParseApprovals(logs)
+		if len(prev.OperatorApprovals) == 0 {
+			err = store.DeleteOperatorApproval(ctx, approval)
+		} else {
+			err = store.ReplaceOperatorApproval(ctx, prev.OperatorApprovals[0])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addressTopic 地址在日志 topic 中的格式, 与 HandleTransactionEvent 写入的一致
+func addressTopic(address string) string {
+	return ethcommon.BytesToHash(ethcommon.HexToAddress(address).Bytes()).Hex()
+}
@@ -0,0 +1,262 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi/erc20"
+	"github.com/traitmeta/metago/pkg/abi/erc721"
+)
+
+func TestApprovalSignatures(t *testing.T) {
+	if got := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)")).Hex(); got != common.ApprovalSignature {
+		t.Errorf("ApprovalSignature = %v, want %v", common.ApprovalSignature, got)
+	}
+	if got := crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)")).Hex(); got != common.ApprovalForAllSignature {
+		t.Errorf("ApprovalForAllSignature = %v, want %v", common.ApprovalForAllSignature, got)
+	}
+}
+
+func TestParseApprovals(t *testing.T) {
+	usdc := "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	weth := "0x4200000000000000000000000000000000000006"
+	nft := "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4"
+	owner := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	router := "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+	txHash := "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020"
+	blockHash := "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3"
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	approval := func(token string, amount *big.Int, logIndex uint) models.Event {
+		return models.Event{
+			Address:     token,
+			FirstTopic:  common.ApprovalSignature,
+			SecondTopic: addressTopic(owner),
+			ThirdTopic:  addressTopic(router),
+			Data:        packPoolLog(t, erc20.Erc20ABI, "Approval", amount),
+			TxHash:      txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: logIndex,
+		}
+	}
+	approvalForAll := func(approved bool, logIndex uint) models.Event {
+		return models.Event{
+			Address:     nft,
+			FirstTopic:  common.ApprovalForAllSignature,
+			SecondTopic: addressTopic(owner),
+			ThirdTopic:  addressTopic(router),
+			Data:        packPoolLog(t, erc721.Erc721ABI, "ApprovalForAll", approved),
+			TxHash:      txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: logIndex,
+		}
+	}
+
+	logs := []models.Event{
+		approval(usdc, big.NewInt(1000), 1),
+		approval(weth, maxUint256, 2),
+		// 同一交易中先授权再撤销, 只保留最后的状态
+		approval(usdc, big.NewInt(0), 3),
+		approvalForAll(true, 4),
+		{
+			// ERC721 单个 tokenId 的 Approval 不记录
+			Address:     nft,
+			FirstTopic:  common.ApprovalSignature,
+			SecondTopic: addressTopic(owner),
+			ThirdTopic:  addressTopic(router),
+			FourthTopic: "0x0000000000000000000000000000000000000000000000000000000000000007",
+			TxHash:      txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: 5,
+		},
+		approvalForAll(false, 6),
+	}
+
+	got, err := ParseApprovals(logs)
+	if err != nil {
+		t.Fatalf("ParseApprovals() error = %v", err)
+	}
+
+	if len(got.TokenAllowances) != 2 {
+		t.Fatalf("ParseApprovals() got %d allowances, want 2", len(got.TokenAllowances))
+	}
+	revoked, unlimited := got.TokenAllowances[0], got.TokenAllowances[1]
+	if revoked.Token != usdc || revoked.Owner != owner || revoked.Spender != router ||
+		revoked.Amount.Sign() != 0 || revoked.Unlimited || revoked.LogIndex != 3 {
+		t.Errorf("ParseApprovals() revoked allowance = %+v", revoked)
+	}
+	if unlimited.Token != weth || unlimited.Amount.Cmp(maxUint256) != 0 || !unlimited.Unlimited ||
+		unlimited.TransactionHash != txHash || unlimited.BlockHash != blockHash || unlimited.BlockNumber != 100 {
+		t.Errorf("ParseApprovals() unlimited allowance = %+v", unlimited)
+	}
+
+	want := []models.OperatorApproval{{
+		Collection:      nft,
+		Owner:           owner,
+		Operator:        router,
+		Approved:        false,
+		TransactionHash: txHash,
+		BlockNumber:     100,
+		BlockHash:       blockHash,
+		LogIndex:        6,
+	}}
+	if len(got.OperatorApprovals) != 1 || got.OperatorApprovals[0] != want[0] {
+		t.Errorf("ParseApprovals() operator approvals = %+v, want %+v", got.OperatorApprovals, want)
+	}
+}
+
+func TestParseApprovalsSkipsMalformedLogs(t *testing.T) {
+	token := "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	owner := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	spender := "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+	word := func(v int64) string { return ethcommon.Bytes2Hex(ethcommon.LeftPadBytes(big.NewInt(v).Bytes(), 32)) }
+	event := func(topic, data string, logIndex uint) models.Event {
+		return models.Event{
+			Address: token, FirstTopic: topic, SecondTopic: addressTopic(owner), ThirdTopic: addressTopic(spender),
+			Data: data, TxHash: "0x01", BlockNumber: 100, LogIndex: logIndex,
+		}
+	}
+	withFourthTopic := event(common.ApprovalForAllSignature, word(1), 6)
+	withFourthTopic.FourthTopic = addressTopic(owner)
+
+	logs := []models.Event{
+		event(common.ApprovalSignature, "", 1),
+		event(common.ApprovalSignature, word(1)+word(2), 2),
+		event(common.ApprovalForAllSignature, "", 3),
+		// bool 只能是0或1
+		event(common.ApprovalForAllSignature, word(2), 4),
+		event(common.ApprovalForAllSignature, word(1)[:40], 5),
+		withFourthTopic,
+		event(common.ApprovalSignature, word(500), 7),
+		event(common.ApprovalForAllSignature, word(1), 8),
+	}
+
+	got, err := ParseApprovals(logs)
+	if err != nil {
+		t.Fatalf("ParseApprovals() error = %v, malformed logs must be skipped", err)
+	}
+	if len(got.TokenAllowances) != 1 || got.TokenAllowances[0].LogIndex != 7 || got.TokenAllowances[0].Amount.Int64() != 500 {
+		t.Errorf("ParseApprovals() allowances = %+v, want only log 7", got.TokenAllowances)
+	}
+	if len(got.OperatorApprovals) != 1 || got.OperatorApprovals[0].LogIndex != 8 || !got.OperatorApprovals[0].Approved {
+		t.Errorf("ParseApprovals() operator approvals = %+v, want only log 8", got.OperatorApprovals)
+	}
+}
+
+// memoryApprovals 内存中的授权表和日志表
+type memoryApprovals struct {
+	allowances map[approvalKey]models.TokenAllowance
+	operators  map[approvalKey]models.OperatorApproval
+	events     []models.Event
+}
+
+func (m *memoryApprovals) GetAllowancesByBlock(ctx context.Context, height uint64) ([]models.TokenAllowance, error) {
+	var result []models.TokenAllowance
+	for _, a := range m.allowances {
+		if a.BlockNumber == height {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryApprovals) GetOperatorApprovalsByBlock(ctx context.Context, height uint64) ([]models.OperatorApproval, error) {
+	var result []models.OperatorApproval
+	for _, a := range m.operators {
+		if a.BlockNumber == height {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryApprovals) GetApprovalLogs(ctx context.Context, contract, signature, owner, spender string, height uint64) ([]models.Event, error) {
+	var result []models.Event
+	for _, e := range m.events {
+		if e.Address == contract && e.FirstTopic == signature && e.SecondTopic == owner && e.ThirdTopic == spender &&
+			e.FourthTopic == "" && e.BlockNumber < height {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryApprovals) ReplaceAllowance(ctx context.Context, a models.TokenAllowance) error {
+	m.allowances[approvalKey{a.Token, a.Owner, a.Spender}] = a
+	return nil
+}
+
+func (m *memoryApprovals) ReplaceOperatorApproval(ctx context.Context, a models.OperatorApproval) error {
+	m.operators[approvalKey{a.Collection, a.Owner, a.Operator}] = a
+	return nil
+}
+
+func (m *memoryApprovals) DeleteAllowance(ctx context.Context, a models.TokenAllowance) error {
+	delete(m.allowances, approvalKey{a.Token, a.Owner, a.Spender})
+	return nil
+}
+
+func (m *memoryApprovals) DeleteOperatorApproval(ctx context.Context, a models.OperatorApproval) error {
+	delete(m.operators, approvalKey{a.Collection, a.Owner, a.Operator})
+	return nil
+}
+
+func TestRevertApprovals(t *testing.T) {
+	usdc := "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	weth := "0x4200000000000000000000000000000000000006"
+	nft := "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4"
+	owner := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	router := "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+	event := func(contract, topic, data string, height uint64, logIndex uint) models.Event {
+		return models.Event{
+			Address: contract, FirstTopic: topic, SecondTopic: addressTopic(owner), ThirdTopic: addressTopic(router),
+			Data: data, BlockNumber: height, LogIndex: logIndex,
+		}
+	}
+	approval := func(token string, amount int64, height uint64, logIndex uint) models.Event {
+		return event(token, common.ApprovalSignature, packPoolLog(t, erc20.Erc20ABI, "Approval", big.NewInt(amount)), height, logIndex)
+	}
+	approvalForAll := func(approved bool, height uint64, logIndex uint) models.Event {
+		return event(nft, common.ApprovalForAllSignature, packPoolLog(t, erc721.Erc721ABI, "ApprovalForAll", approved), height, logIndex)
+	}
+
+	// 被替换的区块 20 把 usdc 额度改为 0、第一次授权 weth、撤销 nft 的 ApprovalForAll
+	store := &memoryApprovals{
+		events: []models.Event{
+			approval(usdc, 100, 10, 1),
+			approval(usdc, 300, 11, 2),
+			approvalForAll(true, 12, 1),
+			// 格式错误的日志跳过, 以之前最后一条有效日志为准
+			event(nft, common.ApprovalForAllSignature, "", 13, 1),
+			approval(usdc, 0, 20, 1),
+			approval(weth, 50, 20, 2),
+			approvalForAll(false, 20, 3),
+		},
+		allowances: map[approvalKey]models.TokenAllowance{},
+		operators:  map[approvalKey]models.OperatorApproval{},
+	}
+	applied, err := ParseApprovals(store.events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range applied.TokenAllowances {
+		store.ReplaceAllowance(context.Background(), a)
+	}
+	for _, a := range applied.OperatorApprovals {
+		store.ReplaceOperatorApproval(context.Background(), a)
+	}
+
+	if err := revertApprovals(context.Background(), store, 20); err != nil {
+		t.Fatalf("revertApprovals() error = %v", err)
+	}
+
+	if len(store.allowances) != 1 {
+		t.Fatalf("allowances = %+v, want only usdc, weth was first approved in the replaced block", store.allowances)
+	}
+	if got := store.allowances[approvalKey{usdc, owner, router}]; got.Amount.Int64() != 300 || got.BlockNumber != 11 || got.LogIndex != 2 {
+		t.Errorf("usdc allowance = %+v, want 300 from block 11", got)
+	}
+	if got, ok := store.operators[approvalKey{nft, owner, router}]; !ok || !got.Approved || got.BlockNumber != 12 {
+		t.Errorf("nft operator approval = %+v, want approved from block 12", got)
+	}
+}
@@ -3,10 +3,13 @@ package chain
 import (
 	"context"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	log "github.com/sirupsen/logrus"
@@ -14,91 +17,146 @@ import (
 	"github.com/traitmeta/metago/config"
 	"github.com/traitmeta/metago/core/dal"
 	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/metrics"
+	"gorm.io/gorm"
 )
 
-// InitBlock 初始化第一个区块数据
-func InitBlock(ctx context.Context) {
-	block := models.Block{}
-	count, err := dal.Block.Counts()
+// initBlock 数据库中没有区块时写入节点的最新区块
+func initBlock(ctx context.Context) error {
+	next, err := dal.Block.GetNextHeight(ctx)
 	if err != nil {
-		log.Panic("InitBlock - DB blockcounts err : ", err)
+		return dbError("get next height", err)
+	}
+	if next > 0 {
+		return nil
 	}
 
-	if count == 0 {
-		lastBlockNumber, err := config.EthRpcClient.BlockNumber(context.Background())
-		if err != nil {
-			log.Panic("InitBlock - BlockNumber err : ", err)
-		}
-		lastBlock, err := config.EthRpcClient.BlockByNumber(context.Background(), big.NewInt(int64(lastBlockNumber)))
+	lastBlockNumber, err := config.EthRpcClient.BlockNumber(ctx)
+	if err != nil {
+		return rpcError("get block number", err)
+	}
+	lastBlock, err := config.EthRpcClient.BlockByNumber(ctx, new(big.Int).SetUint64(lastBlockNumber))
+	if err != nil {
+		return rpcError(fmt.Sprintf("fetch block %d", lastBlockNumber), err)
+	}
 
-		if err != nil {
-			log.Panic("InitBlock - BlockByNumber err : ", err)
-		}
-		block.BlockHash = lastBlock.Hash().Hex()
-		block.BlockHeight = lastBlock.NumberU64()
-		block.LatestBlockHeight = lastBlock.NumberU64()
-		block.ParentHash = lastBlock.ParentHash().Hex()
-		err = dal.Block.Insert(ctx, block)
-		if err != nil {
-			log.Panic("InitBlock - Insert block err : ", err)
-		}
+	block := models.Block{
+		BlockHash:         lastBlock.Hash().Hex(),
+		BlockHeight:       lastBlock.NumberU64(),
+		LatestBlockHeight: lastBlock.NumberU64(),
+		ParentHash:        lastBlock.ParentHash().Hex(),
 	}
+	if err := dal.Block.Insert(ctx, block); err != nil {
+		return dbError("insert init block", err)
+	}
+	return nil
 }
 
-func SyncTask(ctx context.Context) {
-	ticker := time.NewTicker(time.Second * 1)
-	defer ticker.Stop()
-	for range ticker.C {
-		latestBlockNumber, err := config.EthRpcClient.BlockNumber(context.Background())
-		if err != nil {
-			log.Panic("EthRpcClient.BlockNumber error : ", err)
-		}
-
-		latestBlock, err := dal.Block.GetLatest()
-		if err != nil {
-			log.Panic("blocks.GetLatest error : ", err)
-		}
-
-		if latestBlock.LatestBlockHeight > latestBlockNumber {
-			log.Printf("latestBlock.LatestBlockHeight : %v greater than latestBlockNumber : %v \n", latestBlock.LatestBlockHeight, latestBlockNumber)
-			continue
-		}
+// HandleBlock 处理区块信息, 返回的错误按 Classify 分类
+func HandleBlock(ctx context.Context, currentBlock *types.Block) error {
+	start := time.Now()
+	data, err := ParseBlock(ctx, currentBlock)
+	if err != nil {
+		return fatalError(fmt.Sprintf("parse block %d", currentBlock.NumberU64()), err)
+	}
+	syncMetrics.ObserveStage(metrics.StageParse, start)
 
-		currentBlock, err := config.EthRpcClient.BlockByNumber(context.Background(), big.NewInt(int64(latestBlock.LatestBlockHeight)))
-		if err != nil {
-			log.Panic("EthRpcClient.BlockByNumber error : ", err)
-		}
+	start = time.Now()
+	if err := SyncToDB(ctx, data); err != nil {
+		return dbError(fmt.Sprintf("persist block %d", currentBlock.NumberU64()), err)
+	}
+	syncMetrics.ObserveStage(metrics.StagePersist, start)
+	syncMetrics.SetIndexed(currentBlock.NumberU64())
+	return nil
+}
 
-		log.Printf("get currentBlock blockNumber : %v , blockHash : %v \n", currentBlock.Number(), currentBlock.Hash().Hex())
-		err = HandleBlock(ctx, currentBlock)
-		if err != nil {
-			log.Panic("HandleBlock error : ", err)
-		}
+// ReindexBlock 重新处理区块, 在同一个事务中替换该高度已经入库的数据, 返回的错误按 Classify 分类
+func ReindexBlock(ctx context.Context, currentBlock *types.Block) error {
+	data, err := ParseBlock(ctx, currentBlock)
+	if err != nil {
+		return fatalError(fmt.Sprintf("parse block %d", currentBlock.NumberU64()), err)
 	}
+	data.Reindexed = true
+	if err := SyncToDB(ctx, data); err != nil {
+		return dbError(fmt.Sprintf("persist block %d", currentBlock.NumberU64()), err)
+	}
+	return nil
 }
 
-// HandleBlock 处理区块信息
-func HandleBlock(ctx context.Context, currentBlock *types.Block) error {
+// ParseBlock 拉取交易回执并解析出区块需要入库的全部数据
+func ParseBlock(ctx context.Context, currentBlock *types.Block) (*BlockData, error) {
+	return ParseBlockWith(ctx, currentBlock, defaultPoolTokens)
+}
+
+// ParseBlockWith 使用指定的交易池代币缓存解析区块, 用于离线回放等需要隔离缓存的场景
+func ParseBlockWith(ctx context.Context, currentBlock *types.Block, pools *PoolTokenCache) (*BlockData, error) {
 	block := models.Block{
+		Consensus:         true,
+		Difficulty:        currentBlock.Difficulty(),
 		BlockHeight:       currentBlock.NumberU64(),
 		BlockHash:         currentBlock.Hash().Hex(),
 		ParentHash:        currentBlock.ParentHash().Hex(),
+		GasLimit:          currentBlock.GasLimit(),
+		GasUsed:           currentBlock.GasUsed(),
+		MinerHash:         currentBlock.Coinbase().Hex(),
+		Nonce:             hexutil.EncodeUint64(currentBlock.Nonce()),
+		Size:              int32(currentBlock.Size()),
+		Timestamp:         time.Unix(int64(currentBlock.Time()), 0).UTC(),
+		BaseFeePerGas:     currentBlock.BaseFee(),
 		LatestBlockHeight: currentBlock.NumberU64() + 1,
 	}
 
-	events, trxs, err := HandleTransaction(currentBlock)
+	events, trxs, err := HandleTransaction(ctx, currentBlock)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return SyncToDB(ctx, block, trxs, events)
+	tokenTransfers, err := doParse(events, TokenTransfers{})
+	if err != nil {
+		return nil, err
+	}
+
+	userOps, err := ParseUserOperations(events, trxs)
+	if err != nil {
+		return nil, err
+	}
+
+	dexTrades, err := ParseDexTrades(events, trxs, pools)
+	if err != nil {
+		return nil, err
+	}
+
+	nftSales, err := ParseNftSales(events, tokenTransfers.TokenTransfers)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := ParseApprovals(events)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockData{
+		Block:          block,
+		Transactions:   trxs,
+		Events:         events,
+		TokenTransfers: tokenTransfers.TokenTransfers,
+		UserOperations: userOps,
+		DexTrades:      dexTrades,
+		NftSales:       nftSales,
+
+		TokenAllowances:   approvals.TokenAllowances,
+		OperatorApprovals: approvals.OperatorApprovals,
+	}, nil
 }
 
-func SyncToDB(ctx context.Context, block models.Block, trxs []models.Transaction, events []models.Event) error {
+// SyncToDB 在一个事务中写入区块数据, 并执行注册的区块钩子, 钩子 panic 时回滚并返回不可恢复的错误
+func SyncToDB(ctx context.Context, data *BlockData) (err error) {
 	tx := db.DBEngine.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
+			err = fatalError(fmt.Sprintf("persist block %d", data.Block.BlockHeight), fmt.Errorf("panic: %v", r))
 		}
 	}()
 
@@ -107,38 +165,144 @@ func SyncToDB(ctx context.Context, block models.Block, trxs []models.Transaction
 	}
 
 	dbctx := context.WithValue(ctx, db.TxContext, tx)
-	err := dal.Block.Insert(dbctx, block)
+	if data.Reindexed {
+		if err := replaceHeight(dbctx, data); err != nil {
+			tx.Rollback()
+			log.Error("delete block data fail", "err", err)
+			return err
+		}
+	}
+
+	err = dal.Block.Insert(dbctx, data.Block)
 	if err != nil {
 		tx.Rollback()
 		log.Error("insert block fail", "err", err)
 		return err
 	}
-	err = dal.Transaction.Inserts(dbctx, trxs)
+	err = dal.Transaction.Inserts(dbctx, data.Transactions)
 	if err != nil {
 		tx.Rollback()
 		log.Error("insert transactions fail", "err", err)
 		return err
 	}
 
-	err = dal.Event.Inserts(dbctx, events)
+	err = dal.Event.Inserts(dbctx, data.Events)
 	if err != nil {
 		tx.Rollback()
 		log.Error("insert events fail", "err", err)
 		return err
 	}
 
-	return tx.Commit().Error
+	err = dal.TokenTransfer.Inserts(dbctx, data.TokenTransfers)
+	if err != nil {
+		tx.Rollback()
+		log.Error("insert token transfers fail", "err", err)
+		return err
+	}
+
+	err = dal.UserOperation.Inserts(dbctx, data.UserOperations)
+	if err != nil {
+		tx.Rollback()
+		log.Error("insert user operations fail", "err", err)
+		return err
+	}
+
+	err = dal.DexTrade.Inserts(dbctx, data.DexTrades)
+	if err != nil {
+		tx.Rollback()
+		log.Error("insert dex trades fail", "err", err)
+		return err
+	}
+
+	err = dal.NftSale.Inserts(dbctx, data.NftSales)
+	if err != nil {
+		tx.Rollback()
+		log.Error("insert nft sales fail", "err", err)
+		return err
+	}
+
+	err = dal.Approval.UpsertAllowances(dbctx, data.TokenAllowances)
+	if err != nil {
+		tx.Rollback()
+		log.Error("upsert token allowances fail", "err", err)
+		return err
+	}
+
+	err = dal.Approval.UpsertOperatorApprovals(dbctx, data.OperatorApprovals)
+	if err != nil {
+		tx.Rollback()
+		log.Error("upsert operator approvals fail", "err", err)
+		return err
+	}
+
+	err = runBlockHooks(dbctx, data)
+	if err != nil {
+		tx.Rollback()
+		log.Error("run block hooks fail", "err", err)
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	if data.Reorged() {
+		syncMetrics.Rollback()
+	}
+
+	runCommitHooks(ctx, data)
+	return nil
+}
+
+// replaceHeight 删除该高度已经入库的数据, 新区块沿用原区块的 ID 和同步进度,
+// 避免重新索引的区块被当作最新区块导致同步高度回退
+func replaceHeight(ctx context.Context, data *BlockData) error {
+	block := &data.Block
+	height := block.BlockHeight
+	old, err := dal.Block.GetByHeight(ctx, height)
+	switch {
+	case err == nil:
+		block.Model = &gorm.Model{ID: old.ID, CreatedAt: old.CreatedAt}
+		block.LatestBlockHeight = old.LatestBlockHeight
+		events, err := dal.Event.GetByBlockNumber(ctx, height)
+		if err != nil {
+			return err
+		}
+		data.Replaced = &ReplacedBlock{Block: *old, Events: events}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		next, err := dal.Block.GetNextHeight(ctx)
+		if err != nil {
+			return err
+		}
+		block.LatestBlockHeight = next
+	default:
+		return err
+	}
+
+	for _, del := range []func(context.Context, uint64) error{
+		dal.Block.DeleteByHeight,
+		dal.Transaction.DeleteByBlockNumber,
+		dal.Event.DeleteByBlockNumber,
+		dal.TokenTransfer.DeleteByBlockNumber,
+		dal.UserOperation.DeleteByBlockNumber,
+		dal.DexTrade.DeleteByBlockNumber,
+		dal.NftSale.DeleteByBlockNumber,
+	} {
+		if err := del(ctx, height); err != nil {
+			return err
+		}
+	}
+	return revertApprovals(ctx, approvalState, height)
 }
 
 // HandleTransaction 处理交易数据
-func HandleTransaction(block *types.Block) ([]models.Event, []models.Transaction, error) {
+func HandleTransaction(ctx context.Context, block *types.Block) ([]models.Event, []models.Transaction, error) {
 	events := []models.Event{}
 	trxs := []models.Transaction{}
 	for _, tx := range block.Transactions() {
-		receipt, err := config.EthRpcClient.TransactionReceipt(context.Background(), tx.Hash())
+		receipt, err := config.EthRpcClient.TransactionReceipt(ctx, tx.Hash())
 		if err != nil {
 			log.Error("get transaction fail", "err", err)
-			return nil, nil, err
+			return nil, nil, rpcError("get receipt "+tx.Hash().Hex(), err)
 		}
 
 		for _, rLog := range receipt.Logs {
@@ -156,6 +320,10 @@ func HandleTransaction(block *types.Block) ([]models.Event, []models.Transaction
 			log.Error("process transaction fail", "err", err)
 			return nil, nil, err
 		}
+		fillReceipt(trx, receipt)
+		if receipt.Status == types.ReceiptStatusFailed {
+			trx.RevertReason = RevertReason(ctx, tx, common.HexToAddress(trx.From), block.Number())
+		}
 
 		trxs = append(trxs, *trx)
 	}
@@ -166,7 +334,7 @@ func ProcessTransaction(tx *types.Transaction, blockNumber *big.Int, status uint
 	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
 	if err != nil {
 		log.Error("Failed to read the sender address", "TxHash", tx.Hash(), "err", err)
-		return nil, err
+		return nil, fatalError("recover sender "+tx.Hash().Hex(), err)
 	}
 
 	log.Info("hand transaction", "txHash", tx.Hash().String())
@@ -177,15 +345,20 @@ func ProcessTransaction(tx *types.Transaction, blockNumber *big.Int, status uint
 		Value:       tx.Value().String(),
 		Status:      status,
 		InputData:   hex.EncodeToString(tx.Data()),
+		Nonce:       tx.Nonce(),
+		Gas:         tx.Gas(),
+		GasPrice:    tx.GasPrice().String(),
+		GasTipCap:   tx.GasTipCap().String(),
 	}
 	if tx.To() == nil {
 		log.Info("Contract creation found", "Sender", transaction.From, "TxHash", transaction.TxHash)
 		toAddress := crypto.CreateAddress(from, tx.Nonce()).Hex()
 		transaction.Contract = toAddress
+		transaction.ContractCreation = true
 	} else {
 		isContract, err := isContractAddress(tx.To().Hex())
 		if err != nil {
-			return nil, err
+			return nil, rpcError("get code "+tx.To().Hex(), err)
 		}
 		if isContract {
 			transaction.Contract = tx.To().Hex()
@@ -197,6 +370,18 @@ func ProcessTransaction(tx *types.Transaction, blockNumber *big.Int, status uint
 	return transaction, nil
 }
 
+// fillReceipt 补充交易回执中的位置和 Gas 信息
+func fillReceipt(trx *models.Transaction, receipt *types.Receipt) {
+	trx.BlockHash = receipt.BlockHash.Hex()
+	trx.TxIndex = receipt.TransactionIndex
+	trx.Type = receipt.Type
+	trx.GasUsed = receipt.GasUsed
+	trx.CumulativeGasUsed = receipt.CumulativeGasUsed
+	if receipt.EffectiveGasPrice != nil {
+		trx.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+}
+
 func HandleTransactionEvent(rLog *types.Log, status uint64) (models.Event, error) {
 	log.Info("ProcessTransactionEvent", "address", rLog.Address, "data", rLog.Data)
 
@@ -0,0 +1,262 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	pkgerrors "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+/*
+## Overview
+  DEX 成交从交易池的 Swap 日志解析, 兼容 Uniswap V2/V3 及其分叉.
+
+  | Event     | Topics                | Data                                                      |
+  |-----------|-----------------------|-----------------------------------------------------------|
+  | V2 `Swap` | sender, to            | amount0In, amount1In, amount0Out, amount1Out              |
+  | V2 `Sync` |                       | reserve0, reserve1                                        |
+  | V3 `Swap` | sender, recipient     | amount0, amount1, sqrtPriceX96, liquidity, tick           |
+
+  日志中没有代币地址, 需要通过 eth_call 读取交易池的 token0/token1 并缓存.
+  V2 在 Swap 之前会先发出同一交易池的 Sync, 用来记录成交后的储备.
+  V3 的 amount 为交易池视角的变化量, 正数表示转入交易池, 即用户卖出.
+*/
+
+// PoolTokens 交易池的两个代币
+type PoolTokens struct {
+	Token0 string
+	Token1 string
+}
+
+// PoolTokenFetcher 读取交易池的代币
+type PoolTokenFetcher func(pool string) (PoolTokens, error)
+
+// ErrNotPool 合约没有 token0/token1, 不是交易池
+var ErrNotPool = errors.New("not a pool")
+
+// PoolTokenCache 缓存交易池的代币, 交易池创建之后 token0/token1 不会变化.
+// ErrNotPool 同样缓存, 避免发出同名事件的其它合约每个区块都重新读取
+type PoolTokenCache struct {
+	mu    sync.RWMutex
+	fetch PoolTokenFetcher
+	pools map[string]PoolTokens
+	// notPools 已经确认不是交易池的合约
+	notPools map[string]struct{}
+}
+
+func NewPoolTokenCache(fetch PoolTokenFetcher) *PoolTokenCache {
+	return &PoolTokenCache{
+		fetch:    fetch,
+		pools:    map[string]PoolTokens{},
+		notPools: map[string]struct{}{},
+	}
+}
+
+// Get 优先读取缓存, 网络等其它错误不缓存, 下次重新读取
+func (c *PoolTokenCache) Get(pool string) (PoolTokens, error) {
+	key := strings.ToLower(pool)
+	c.mu.RLock()
+	tokens, ok := c.pools[key]
+	_, notPool := c.notPools[key]
+	c.mu.RUnlock()
+	if ok {
+		return tokens, nil
+	}
+	if notPool {
+		return PoolTokens{}, ErrNotPool
+	}
+
+	tokens, err := c.fetch(pool)
+	if errors.Is(err, ErrNotPool) {
+		c.mu.Lock()
+		c.notPools[key] = struct{}{}
+		c.mu.Unlock()
+		return PoolTokens{}, err
+	}
+	if err != nil {
+		return PoolTokens{}, err
+	}
+
+	c.mu.Lock()
+	c.pools[key] = tokens
+	c.mu.Unlock()
+	return tokens, nil
+}
+
+// fetchPoolTokens 合约不存在、调用回滚或返回值无法解析时返回 ErrNotPool
+func fetchPoolTokens(pool string) (PoolTokens, error) {
+	token0, token1, err := abi.GetPoolTokens(pool)
+	if err != nil {
+		cause := pkgerrors.Cause(err)
+		if errors.Is(err, bind.ErrNoCode) || isExecutionReverted(err) || strings.HasPrefix(cause.Error(), "abi: ") {
+			return PoolTokens{}, fmt.Errorf("%w: %v", ErrNotPool, err)
+		}
+		return PoolTokens{}, err
+	}
+	return PoolTokens{Token0: token0.Hex(), Token1: token1.Hex()}, nil
+}
+
+// NewRpcPoolTokenCache 通过节点读取交易池代币的缓存
+func NewRpcPoolTokenCache() *PoolTokenCache {
+	return NewPoolTokenCache(fetchPoolTokens)
+}
+
+var defaultPoolTokens = NewRpcPoolTokenCache()
+
+type reserves struct {
+	reserve0 *big.Int
+	reserve1 *big.Int
+}
+
+// ParseDexTrades 从区块日志中解析 DEX 成交, 不是交易池的合约和无法解析的日志会被跳过,
+// 读取交易池代币失败时返回 rpcError
+func ParseDexTrades(logs []models.Event, trxs []models.Transaction, pools *PoolTokenCache) ([]models.DexTrade, error) {
+	traders := make(map[string]string, len(trxs))
+	for _, trx := range trxs {
+		traders[trx.TxHash] = trx.From
+	}
+
+	// 同一交易中同一交易池最近一次 Sync 的储备
+	syncs := make(map[string]reserves)
+	var trades []models.DexTrade
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		var (
+			trade *models.DexTrade
+			err   error
+		)
+		switch log.FirstTopic {
+		case common.UniswapV2SyncSignature:
+			reserve0, reserve1, err := abi.ParseUniswapV2SyncLog(ethcommon.FromHex(log.Data))
+			if err != nil {
+				warnSkipLog(log, err)
+				continue
+			}
+			syncs[log.TxHash+log.Address] = reserves{reserve0: reserve0, reserve1: reserve1}
+			continue
+		case common.UniswapV2SwapSignature:
+			trade, err = parseUniswapV2Swap(log, pools)
+			if err == nil && trade != nil {
+				if r, ok := syncs[log.TxHash+log.Address]; ok {
+					trade.Reserve0, trade.Reserve1 = r.reserve0, r.reserve1
+				}
+			}
+		case common.UniswapV3SwapSignature:
+			trade, err = parseUniswapV3Swap(log, pools)
+		default:
+			continue
+		}
+		if Classify(err) == KindRPC {
+			return nil, err
+		}
+		if err != nil {
+			warnSkipLog(log, err)
+			continue
+		}
+		if trade == nil {
+			continue
+		}
+
+		trade.Pool = log.Address
+		trade.Trader = traders[log.TxHash]
+		trade.Sender = ethcommon.HexToAddress(log.SecondTopic).Hex()
+		trade.Recipient = ethcommon.HexToAddress(log.ThirdTopic).Hex()
+		trade.Price = price(trade.AmountIn, trade.AmountOut)
+		trade.TransactionHash = log.TxHash
+		trade.BlockNumber = log.BlockNumber
+		trade.BlockHash = log.BlockHash
+		trade.LogIndex = log.LogIndex
+		trades = append(trades, *trade)
+	}
+
+	return trades, nil
+}
+
+func parseUniswapV2Swap(log models.Event, pools *PoolTokenCache) (*models.DexTrade, error) {
+	event, err := abi.ParseUniswapV2SwapLog(ethcommon.FromHex(log.Data))
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := poolTokens(log, pools)
+	if err != nil || tokens == nil {
+		return nil, err
+	}
+
+	// 按净流入判断方向, 兼容同时有两个方向输入的情况
+	net0 := new(big.Int).Sub(event.Amount0In, event.Amount0Out)
+	net1 := new(big.Int).Sub(event.Amount1In, event.Amount1Out)
+	return newTrade(common.UniswapV2, *tokens, net0, net1), nil
+}
+
+func parseUniswapV3Swap(log models.Event, pools *PoolTokenCache) (*models.DexTrade, error) {
+	event, err := abi.ParseUniswapV3SwapLog(ethcommon.FromHex(log.Data))
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := poolTokens(log, pools)
+	if err != nil || tokens == nil {
+		return nil, err
+	}
+
+	trade := newTrade(common.UniswapV3, *tokens, event.Amount0, event.Amount1)
+	if trade != nil {
+		trade.SqrtPriceX96 = event.SqrtPriceX96
+		trade.Liquidity = event.Liquidity
+		trade.Tick = int32(event.Tick.Int64())
+	}
+	return trade, nil
+}
+
+// poolTokens 不是交易池时返回 nil, 读取失败时返回 rpcError 让区块重试, 避免漏掉成交
+func poolTokens(log models.Event, pools *PoolTokenCache) (*PoolTokens, error) {
+	tokens, err := pools.Get(log.Address)
+	if errors.Is(err, ErrNotPool) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, rpcError("get pool tokens "+log.Address, err)
+	}
+	return &tokens, nil
+}
+
+// newTrade net0/net1 为交易池视角的净流入, 必须一正一负才是有效成交
+func newTrade(protocol string, tokens PoolTokens, net0, net1 *big.Int) *models.DexTrade {
+	trade := &models.DexTrade{Protocol: protocol}
+	switch {
+	case net0.Sign() > 0 && net1.Sign() < 0:
+		trade.TokenIn, trade.AmountIn = tokens.Token0, net0
+		trade.TokenOut, trade.AmountOut = tokens.Token1, new(big.Int).Neg(net1)
+	case net1.Sign() > 0 && net0.Sign() < 0:
+		trade.TokenIn, trade.AmountIn = tokens.Token1, net1
+		trade.TokenOut, trade.AmountOut = tokens.Token0, new(big.Int).Neg(net0)
+	default:
+		return nil
+	}
+	return trade
+}
+
+func price(amountIn, amountOut *big.Int) float64 {
+	if amountIn == nil || amountOut == nil || amountIn.Sign() == 0 {
+		return 0
+	}
+	p, _ := new(big.Float).Quo(new(big.Float).SetInt(amountOut), new(big.Float).SetInt(amountIn)).Float64()
+	return p
+}
+
+func warnSkipLog(event models.Event, err error) {
+	log.WithField("tx", event.TxHash).WithField("log_index", event.LogIndex).
+		WithField("address", event.Address).WithField("err", err).Warn("skip undecodable log")
+}
@@ -0,0 +1,215 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi/uniswapv2"
+	"github.com/traitmeta/metago/pkg/abi/uniswapv3"
+)
+
+func packPoolLog(t *testing.T, abiJSON, name string, args ...interface{}) string {
+	contractAbi, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := contractAbi.Events[name].Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ethcommon.Bytes2Hex(data)
+}
+
+func TestParseDexTrades(t *testing.T) {
+	v2Pool := "0x88A43bbDF9D098eEC7bCEda4e2494615dfD9bB9C"
+	v3Pool := "0xd0b53D9277642d899DF5C87A3966A349A798F224"
+	unknownPool := "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4"
+	weth := "0x4200000000000000000000000000000000000006"
+	usdc := "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	router := "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+	trader := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	txHash := "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020"
+	blockHash := "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3"
+
+	calls := 0
+	pools := NewPoolTokenCache(func(pool string) (PoolTokens, error) {
+		calls++
+		switch strings.ToLower(pool) {
+		case strings.ToLower(v2Pool), strings.ToLower(v3Pool):
+			return PoolTokens{Token0: weth, Token1: usdc}, nil
+		}
+		return PoolTokens{}, ErrNotPool
+	})
+
+	logs := []models.Event{
+		{
+			Address:    v2Pool,
+			FirstTopic: common.UniswapV2SyncSignature,
+			Data:       packPoolLog(t, uniswapv2.UniswapV2PairABI, "Sync", big.NewInt(101e6), big.NewInt(2e9)),
+			TxHash:     txHash, LogIndex: 1,
+		},
+		{
+			// 卖出 1e6 token0 换取 2e7 token1
+			Address:     v2Pool,
+			FirstTopic:  common.UniswapV2SwapSignature,
+			SecondTopic: addressTopic(router),
+			ThirdTopic:  addressTopic(trader),
+			Data:        packPoolLog(t, uniswapv2.UniswapV2PairABI, "Swap", big.NewInt(1e6), big.NewInt(0), big.NewInt(0), big.NewInt(2e7)),
+			TxHash:      txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: 2,
+		},
+		{
+			// token1 转入交易池 3000, token0 转出 1
+			Address:     v3Pool,
+			FirstTopic:  common.UniswapV3SwapSignature,
+			SecondTopic: addressTopic(router),
+			ThirdTopic:  addressTopic(trader),
+			Data: packPoolLog(t, uniswapv3.UniswapV3PoolABI, "Swap", big.NewInt(-1), big.NewInt(3000),
+				big.NewInt(1<<40), big.NewInt(5000), big.NewInt(-200)),
+			TxHash: txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: 3,
+		},
+		{
+			// 同一交易池再次成交, token0 走缓存
+			Address:     v3Pool,
+			FirstTopic:  common.UniswapV3SwapSignature,
+			SecondTopic: addressTopic(router),
+			ThirdTopic:  addressTopic(trader),
+			Data: packPoolLog(t, uniswapv3.UniswapV3PoolABI, "Swap", big.NewInt(10), big.NewInt(-20),
+				big.NewInt(1<<40), big.NewInt(5000), big.NewInt(100)),
+			TxHash: txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: 4,
+		},
+		{
+			// 读取不到 token0/token1 的合约需要跳过
+			Address:     unknownPool,
+			FirstTopic:  common.UniswapV2SwapSignature,
+			SecondTopic: addressTopic(router),
+			ThirdTopic:  addressTopic(trader),
+			Data:        packPoolLog(t, uniswapv2.UniswapV2PairABI, "Swap", big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(1)),
+			TxHash:      txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: 5,
+		},
+		{
+			// 不是交易池的结果被缓存, 不再重新读取
+			Address:     unknownPool,
+			FirstTopic:  common.UniswapV2SwapSignature,
+			SecondTopic: addressTopic(router),
+			ThirdTopic:  addressTopic(trader),
+			Data:        packPoolLog(t, uniswapv2.UniswapV2PairABI, "Swap", big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(1)),
+			TxHash:      txHash, BlockNumber: 100, BlockHash: blockHash, LogIndex: 6,
+		},
+	}
+	trxs := []models.Transaction{{TxHash: txHash, From: trader}}
+
+	got, err := ParseDexTrades(logs, trxs, pools)
+	if err != nil {
+		t.Fatalf("ParseDexTrades() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("ParseDexTrades() got %d trades, want 3", len(got))
+	}
+	if calls != 3 {
+		t.Errorf("pool token fetches = %d, want 3", calls)
+	}
+
+	tests := []struct {
+		name      string
+		trade     models.DexTrade
+		protocol  string
+		pool      string
+		tokenIn   string
+		tokenOut  string
+		amountIn  int64
+		amountOut int64
+		price     float64
+		logIndex  uint
+	}{
+		{"v2 token0 to token1", got[0], common.UniswapV2, v2Pool, weth, usdc, 1e6, 2e7, 20, 2},
+		{"v3 token1 to token0", got[1], common.UniswapV3, v3Pool, usdc, weth, 3000, 1, 1.0 / 3000, 3},
+		{"v3 token0 to token1", got[2], common.UniswapV3, v3Pool, weth, usdc, 10, 20, 2, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade := tt.trade
+			if trade.Protocol != tt.protocol || trade.Pool != tt.pool || trade.LogIndex != tt.logIndex {
+				t.Errorf("trade = %+v", trade)
+			}
+			if trade.TokenIn != tt.tokenIn || trade.TokenOut != tt.tokenOut {
+				t.Errorf("tokens = %s -> %s, want %s -> %s", trade.TokenIn, trade.TokenOut, tt.tokenIn, tt.tokenOut)
+			}
+			if trade.AmountIn.Int64() != tt.amountIn || trade.AmountOut.Int64() != tt.amountOut {
+				t.Errorf("amounts = %v -> %v, want %v -> %v", trade.AmountIn, trade.AmountOut, tt.amountIn, tt.amountOut)
+			}
+			if trade.Price != tt.price {
+				t.Errorf("price = %v, want %v", trade.Price, tt.price)
+			}
+			if trade.Trader != trader || trade.Sender != router || trade.Recipient != trader {
+				t.Errorf("addresses = %s %s %s", trade.Trader, trade.Sender, trade.Recipient)
+			}
+		})
+	}
+
+	if got[0].Reserve0.Int64() != 101e6 || got[0].Reserve1.Int64() != 2e9 {
+		t.Errorf("v2 reserves = %v %v", got[0].Reserve0, got[0].Reserve1)
+	}
+	if got[1].Tick != -200 || got[1].Liquidity.Int64() != 5000 {
+		t.Errorf("v3 state = %d %v", got[1].Tick, got[1].Liquidity)
+	}
+}
+
+func TestParseDexTradesPoolFetchError(t *testing.T) {
+	pool := "0x88A43bbDF9D098eEC7bCEda4e2494615dfD9bB9C"
+	calls := 0
+	pools := NewPoolTokenCache(func(string) (PoolTokens, error) {
+		calls++
+		return PoolTokens{}, errors.New("connection refused")
+	})
+	logs := []models.Event{{
+		Address:    pool,
+		FirstTopic: common.UniswapV2SwapSignature,
+		Data:       packPoolLog(t, uniswapv2.UniswapV2PairABI, "Swap", big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(1)),
+		TxHash:     "0x01",
+	}}
+
+	for i := 0; i < 2; i++ {
+		_, err := ParseDexTrades(logs, nil, pools)
+		if Classify(err) != KindRPC {
+			t.Fatalf("ParseDexTrades() error = %v, want rpc error so the block is retried", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("pool token fetches = %d, want 2, network errors must not be cached", calls)
+	}
+}
+
+type testRpcError struct {
+	code int
+	msg  string
+}
+
+func (e testRpcError) Error() string  { return e.msg }
+func (e testRpcError) ErrorCode() int { return e.code }
+
+func TestIsExecutionReverted(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"code 3", testRpcError{3, "execution reverted: STF"}, true},
+		{"message", testRpcError{-32000, "execution reverted"}, true},
+		{"wrapped", fmt.Errorf("Token0: %w", testRpcError{-32000, "execution reverted"}), true},
+		{"other rpc error", testRpcError{-32000, "header not found"}, false},
+		{"not an rpc error", errors.New("execution reverted"), false},
+	}
+	for _, tt := range tests {
+		if got := isExecutionReverted(tt.err); got != tt.want {
+			t.Errorf("%s: isExecutionReverted() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
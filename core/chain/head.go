@@ -0,0 +1,162 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+/*
+## Overview
+  HeadWatcher 在有新区块时唤醒同步流程, 通道中为节点的最新高度, 只保留最新的一个值, 不保证每个区块都会通知.
+  - 配置了 websocket 地址时订阅 newHeads, 订阅报错或超过 HeadTimeout 没有收到新区块时重新订阅,
+    重新订阅成功后立即通知一次当前高度, 补上断开期间的区块.
+  - 只有 HTTP 地址时按 PollInterval 轮询 eth_blockNumber, 高度变化时才通知.
+*/
+
+var errHeadTimeout = errors.New("no new head received before timeout")
+
+const (
+	defaultPollInterval     = time.Second
+	defaultHeadTimeout      = time.Minute
+	defaultResubscribeDelay = 5 * time.Second
+)
+
+type headSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	Close()
+}
+
+type HeadWatcher struct {
+	PollInterval     time.Duration
+	HeadTimeout      time.Duration
+	ResubscribeDelay time.Duration
+
+	// dial 为空时使用轮询
+	dial func(ctx context.Context) (headSubscriber, error)
+	poll func(ctx context.Context) (uint64, error)
+}
+
+// NewHeadWatcher poll 用于 HTTP 地址的轮询, 通常为 config.EthRpcClient.BlockNumber
+func NewHeadWatcher(conf *setting.BlockChainConfig, poll func(ctx context.Context) (uint64, error)) *HeadWatcher {
+	w := &HeadWatcher{
+		PollInterval:     defaultPollInterval,
+		HeadTimeout:      defaultHeadTimeout,
+		ResubscribeDelay: defaultResubscribeDelay,
+		poll:             poll,
+	}
+	if wsUrl := conf.WebsocketUrl(); wsUrl != "" {
+		w.dial = func(ctx context.Context) (headSubscriber, error) {
+			return ethclient.DialContext(ctx, wsUrl)
+		}
+	}
+	return w
+}
+
+// Watch 返回最新高度的通道, ctx 结束后关闭
+func (w *HeadWatcher) Watch(ctx context.Context) <-chan uint64 {
+	heads := make(chan uint64, 1)
+	go func() {
+		defer close(heads)
+		if w.dial == nil {
+			w.pollHeads(ctx, heads)
+			return
+		}
+		for ctx.Err() == nil {
+			if err := w.subscribeHeads(ctx, heads); err != nil && ctx.Err() == nil {
+				log.WithField("err", err).Warn("newHeads subscription dropped, resubscribing")
+				sleepCtx(ctx, w.ResubscribeDelay)
+			}
+		}
+	}()
+	return heads
+}
+
+// notify 只保留最新的高度, 同步流程繁忙时不会阻塞
+func notify(heads chan uint64, height uint64) {
+	select {
+	case <-heads:
+	default:
+	}
+	heads <- height
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+func (w *HeadWatcher) pollHeads(ctx context.Context, heads chan uint64) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		height, err := w.poll(ctx)
+		if err != nil {
+			log.WithField("err", err).Warn("poll block number fail")
+		} else if height != last {
+			last = height
+			notify(heads, height)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// subscribeHeads 订阅一次 newHeads, 直到订阅出错、超时或者 ctx 结束
+func (w *HeadWatcher) subscribeHeads(ctx context.Context, heads chan uint64) error {
+	client, err := w.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ch := make(chan *types.Header, 16)
+	sub, err := client.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	height, err := client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	notify(heads, height)
+
+	timer := time.NewTimer(w.HeadTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case <-timer.C:
+			return errHeadTimeout
+		case header := <-ch:
+			notify(heads, header.Number.Uint64())
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(w.HeadTimeout)
+		}
+	}
+}
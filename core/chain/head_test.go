@@ -0,0 +1,139 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+type fakeHeadSubscriber struct {
+	head  uint64
+	heads []uint64
+	// fail 发送完 heads 之后订阅报错
+	fail bool
+}
+
+func (s *fakeHeadSubscriber) SubscribeNewHead(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, height := range s.heads {
+			select {
+			case ch <- &types.Header{Number: new(big.Int).SetUint64(height)}:
+			case <-quit:
+				return nil
+			}
+		}
+		if s.fail {
+			return errors.New("connection reset")
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func (s *fakeHeadSubscriber) BlockNumber(context.Context) (uint64, error) { return s.head, nil }
+
+func (s *fakeHeadSubscriber) Close() {}
+
+// waitHead 读取通知直到 want, 通知只保留最新值, 中间的高度可能被跳过
+func waitHead(t *testing.T, heads <-chan uint64, want uint64) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case head := <-heads:
+			if head == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("head %d not received", want)
+		}
+	}
+}
+
+func TestHeadWatcher_Poll(t *testing.T) {
+	var calls atomic.Int32
+	heights := []uint64{5, 5, 5, 6}
+	w := NewHeadWatcher(&setting.BlockChainConfig{RpcUrl: "https://example.org"}, func(context.Context) (uint64, error) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(heights) {
+			return heights[len(heights)-1], nil
+		}
+		return heights[n], nil
+	})
+	w.PollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	heads := w.Watch(ctx)
+	if head := <-heads; head != 5 {
+		t.Errorf("Watch() first head = %d, want 5", head)
+	}
+	waitHead(t, heads, 6)
+
+	// 高度没有变化时不再通知
+	select {
+	case head := <-heads:
+		t.Errorf("Watch() unexpected head %d", head)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	for range heads {
+	}
+}
+
+func TestHeadWatcher_Subscribe(t *testing.T) {
+	subscribers := []*fakeHeadSubscriber{
+		{head: 10, heads: []uint64{11}, fail: true},
+		// 没有新区块, 超时后重新订阅
+		{head: 11},
+		{head: 12, heads: []uint64{13}},
+	}
+	var dials atomic.Int32
+	w := NewHeadWatcher(&setting.BlockChainConfig{RpcUrl: "wss://example.org"}, nil)
+	w.HeadTimeout = 20 * time.Millisecond
+	w.ResubscribeDelay = time.Millisecond
+	w.dial = func(context.Context) (headSubscriber, error) {
+		n := int(dials.Add(1)) - 1
+		if n >= len(subscribers) {
+			return subscribers[len(subscribers)-1], nil
+		}
+		return subscribers[n], nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	heads := w.Watch(ctx)
+	waitHead(t, heads, 11)
+	waitHead(t, heads, 13)
+	if n := dials.Load(); n < 3 {
+		t.Errorf("Watch() dialed %d times, want resubscribe after error and timeout", n)
+	}
+
+	cancel()
+	for range heads {
+	}
+}
+
+func TestBlockChainConfig_WebsocketUrl(t *testing.T) {
+	tests := []struct {
+		conf setting.BlockChainConfig
+		want string
+	}{
+		{conf: setting.BlockChainConfig{RpcUrl: "https://example.org"}, want: ""},
+		{conf: setting.BlockChainConfig{RpcUrl: "https://example.org", WsUrl: "wss://example.org/ws"}, want: "wss://example.org/ws"},
+		{conf: setting.BlockChainConfig{RpcUrl: "ws://127.0.0.1:8546"}, want: "ws://127.0.0.1:8546"},
+	}
+	for _, tt := range tests {
+		if got := tt.conf.WebsocketUrl(); got != tt.want {
+			t.Errorf("WebsocketUrl(%+v) = %q, want %q", tt.conf, got, tt.want)
+		}
+	}
+}
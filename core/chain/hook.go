@@ -0,0 +1,102 @@
+package chain
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+// BlockData 一个区块解析完成后需要入库的全部数据
+type BlockData struct {
+	Block          models.Block           `json:"block"`
+	Transactions   []models.Transaction   `json:"transactions"`
+	Events         []models.Event         `json:"events"`
+	TokenTransfers []models.TokenTransfer `json:"token_transfers"`
+	UserOperations []models.UserOperation `json:"user_operations,omitempty"`
+	DexTrades      []models.DexTrade      `json:"dex_trades,omitempty"`
+	NftSales       []models.NftSale       `json:"nft_sales,omitempty"`
+	// TokenAllowances 和 OperatorApprovals 为区块内每个 key 的最后状态
+	TokenAllowances   []models.TokenAllowance   `json:"token_allowances,omitempty"`
+	OperatorApprovals []models.OperatorApproval `json:"operator_approvals,omitempty"`
+	// Reindexed 为重新索引的区块, 入库前会删除该高度已有的数据
+	Reindexed bool `json:"reindexed,omitempty"`
+	// Replaced 重新索引时被删除的旧区块, 该高度之前没有数据时为 nil
+	Replaced *ReplacedBlock `json:"-"`
+}
+
+// ReplacedBlock 被替换的区块和日志, 钩子用来回滚旧区块对派生数据的修改
+type ReplacedBlock struct {
+	Block  models.Block
+	Events []models.Event
+}
+
+// Reorged 替换的旧区块和新区块哈希不同, 即该高度发生了链重组; 补录或重复处理同一个区块时为 false
+func (d *BlockData) Reorged() bool {
+	return d.Replaced != nil && d.Replaced.Block.BlockHash != d.Block.BlockHash
+}
+
+// BlockHook 在区块写库的事务中执行, ctx 中携带事务, 返回错误时整个区块回滚
+type BlockHook func(ctx context.Context, data *BlockData) error
+
+// CommitHook 在区块事务提交之后执行, 错误只记录日志
+type CommitHook func(ctx context.Context, data *BlockData) error
+
+var (
+	hookMu      sync.RWMutex
+	blockHooks  = map[string]BlockHook{}
+	commitHooks = map[string]CommitHook{}
+	hookOrder   []string
+)
+
+// RegisterBlockHook 注册区块事务内的钩子, 同名钩子会被覆盖
+func RegisterBlockHook(name string, hook BlockHook) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	if _, ok := blockHooks[name]; !ok {
+		hookOrder = append(hookOrder, name)
+	}
+	blockHooks[name] = hook
+}
+
+// RegisterCommitHook 注册区块提交之后的钩子, 同名钩子会被覆盖
+func RegisterCommitHook(name string, hook CommitHook) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	if _, ok := commitHooks[name]; !ok {
+		hookOrder = append(hookOrder, name)
+	}
+	commitHooks[name] = hook
+}
+
+func runBlockHooks(ctx context.Context, data *BlockData) error {
+	hookMu.RLock()
+	defer hookMu.RUnlock()
+	for _, name := range hookOrder {
+		hook, ok := blockHooks[name]
+		if !ok {
+			continue
+		}
+		if err := hook(ctx, data); err != nil {
+			log.WithField("hook", name).WithField("err", err).Error("block hook failed")
+			return err
+		}
+	}
+	return nil
+}
+
+func runCommitHooks(ctx context.Context, data *BlockData) {
+	hookMu.RLock()
+	defer hookMu.RUnlock()
+	for _, name := range hookOrder {
+		hook, ok := commitHooks[name]
+		if !ok {
+			continue
+		}
+		if err := hook(ctx, data); err != nil {
+			log.WithField("hook", name).WithField("err", err).Error("commit hook failed")
+		}
+	}
+}
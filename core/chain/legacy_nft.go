@@ -0,0 +1,153 @@
+package chain
+
+import (
+	"math/big"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+/*
+## Overview
+  CryptoPunks 和 MoonCatRescue 早于 ERC-721, 需要按合约地址单独解析.
+  WrappedPunks 是标准 ERC-721, 包装和解包时的 PunkTransfer 会作为普通转移记录.
+
+  | Contract      | Event          | Topics                              | Data      | Token Transfer          |
+  |---------------|----------------|-------------------------------------|-----------|-------------------------|
+  | CryptoPunks   | `Assign`       | to                                  | punkIndex | 零地址 -> to            |
+  | CryptoPunks   | `PunkTransfer` | from, to                            | punkIndex | from -> to              |
+  | CryptoPunks   | `PunkBought`   | punkIndex, fromAddress, toAddress   | value     | fromAddress -> toAddress |
+  | MoonCatRescue | `CatRescued`   | to, catId                           |           | 零地址 -> to            |
+  | MoonCatRescue | `CatAdopted`   | catId, from, to                     | price     | from -> to              |
+
+  CryptoPunks 每次转移还会发出 ERC-20 风格的 `Transfer(from, to, 1)`, 不能当作 ERC-20 转移.
+  acceptBidForPunk 在发出 PunkBought 之前清空了出价, 事件中的 toAddress 和 value 都为0,
+  买家需要从同一交易的 `Transfer` 中获取, 价格无法从事件得到.
+  MoonCat 的 catId 为 bytes5, 转换为整数作为 Token ID.
+*/
+
+// legacyNftType 按合约地址判断是否为 legacy NFT
+func legacyNftType(log models.Event) (string, bool) {
+	switch {
+	case strings.EqualFold(log.Address, common.CryptoPunksAddress):
+		return common.CryptoPunks, true
+	case strings.EqualFold(log.Address, common.MoonCatRescueAddress):
+		return common.MoonCats, true
+	}
+	return "", false
+}
+
+func doParseCryptoPunks(logs []models.Event, acc TokenTransfers) (TokenTransfers, error) {
+	// 同一交易中最近一次 ERC-20 风格 Transfer 的接收方, 用于修正 PunkBought 的 toAddress
+	lastTo := make(map[string]string)
+	for _, log := range logs {
+		var (
+			punkIndex *big.Int
+			from, to  string
+			err       error
+		)
+		switch log.FirstTopic {
+		case common.ERC20TokenTransferEventFuncSign:
+			lastTo[log.TxHash] = ethcommon.HexToAddress(log.ThirdTopic).String()
+			continue
+		case common.PunkAssignSignature:
+			punkIndex, err = abi.ParsePunkIndexLog("Assign", ethcommon.FromHex(log.Data))
+			from, to = common.ZeroAddress, ethcommon.HexToAddress(log.SecondTopic).String()
+		case common.PunkTransferSignature:
+			punkIndex, err = abi.ParsePunkIndexLog("PunkTransfer", ethcommon.FromHex(log.Data))
+			from, to = ethcommon.HexToAddress(log.SecondTopic).String(), ethcommon.HexToAddress(log.ThirdTopic).String()
+		case common.PunkBoughtSignature:
+			punkIndex = ethcommon.HexToHash(log.SecondTopic).Big()
+			from, to = ethcommon.HexToAddress(log.ThirdTopic).String(), ethcommon.HexToAddress(log.FourthTopic).String()
+			if to == common.ZeroAddress && lastTo[log.TxHash] != "" {
+				to = lastTo[log.TxHash]
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return acc, err
+		}
+
+		acc = appendLegacyNftTransfer(acc, log, punkIndex, from, to)
+	}
+
+	return acc, nil
+}
+
+func doParseMoonCats(logs []models.Event, acc TokenTransfers) TokenTransfers {
+	for _, log := range logs {
+		switch log.FirstTopic {
+		case common.CatRescuedSignature:
+			acc = appendLegacyNftTransfer(acc, log, catTokenId(log.ThirdTopic),
+				common.ZeroAddress, ethcommon.HexToAddress(log.SecondTopic).String())
+		case common.CatAdoptedSignature:
+			acc = appendLegacyNftTransfer(acc, log, catTokenId(log.SecondTopic),
+				ethcommon.HexToAddress(log.ThirdTopic).String(), ethcommon.HexToAddress(log.FourthTopic).String())
+		}
+	}
+
+	return acc
+}
+
+// catTokenId indexed 的 bytes5 在 topic 中左对齐
+func catTokenId(topic string) *big.Int {
+	return new(big.Int).SetBytes(ethcommon.HexToHash(topic).Bytes()[:5])
+}
+
+func appendLegacyNftTransfer(acc TokenTransfers, log models.Event, tokenId *big.Int, from, to string) TokenTransfers {
+	token, tokenTransfer := doParseBaseTokenTransfer(log)
+	tokenTransfer.TokenId = tokenId
+	tokenTransfer.FromAddress = from
+	tokenTransfer.ToAddress = to
+
+	// 没有单独的代币类型, 按不可分割的 ERC-721 处理
+	token.Type = common.ERC721
+
+	acc.Tokens = append(acc.Tokens, token)
+	acc.TokenTransfers = append(acc.TokenTransfers, tokenTransfer)
+	return acc
+}
+
+// parseLegacyNftSale PunkBought 和 CatAdopted 带有价格时记为成交, 合约不收取费用
+func parseLegacyNftSale(log models.Event) (*saleOrder, error) {
+	nftType, ok := legacyNftType(log)
+	if !ok {
+		return nil, nil
+	}
+
+	var (
+		tokenId *big.Int
+		price   *big.Int
+		err     error
+	)
+	switch {
+	case nftType == common.CryptoPunks && log.FirstTopic == common.PunkBoughtSignature:
+		tokenId = ethcommon.HexToHash(log.SecondTopic).Big()
+		price, err = abi.ParsePunkBoughtLog(ethcommon.FromHex(log.Data))
+	case nftType == common.MoonCats && log.FirstTopic == common.CatAdoptedSignature:
+		tokenId = catTokenId(log.SecondTopic)
+		price, err = abi.ParseCatAdoptedLog(ethcommon.FromHex(log.Data))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if price.Sign() == 0 {
+		return nil, nil
+	}
+
+	return &saleOrder{
+		marketplace: nftType,
+		items:       []saleItem{{collection: log.Address, tokenId: tokenId}},
+		price:       price,
+		currency:    common.ZeroAddress,
+		platformFee: new(big.Int),
+		royalty:     new(big.Int),
+	}, nil
+}
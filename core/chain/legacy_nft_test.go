@@ -0,0 +1,120 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi/cryptopunks"
+	"github.com/traitmeta/metago/pkg/abi/mooncats"
+)
+
+func TestParseLegacyNft(t *testing.T) {
+	seller := "0x8b736035BbDA71825e0219f5FE4DfB22C35FbDDC"
+	buyer := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	one := packPoolLog(t, cryptopunks.CryptoPunksMarketABI, "Transfer", big.NewInt(1))
+	catId := "0x00f9e8d7c6000000000000000000000000000000000000000000000000000000"
+	catTokenId, _ := new(big.Int).SetString("00f9e8d7c6", 16)
+
+	punkLog := func(tx string, logIndex uint, topics []string, data string) models.Event {
+		event := models.Event{Address: common.CryptoPunksAddress, TxHash: tx, LogIndex: logIndex, BlockNumber: 100, Data: data}
+		event.FirstTopic = topics[0]
+		if len(topics) > 1 {
+			event.SecondTopic = topics[1]
+		}
+		if len(topics) > 2 {
+			event.ThirdTopic = topics[2]
+		}
+		if len(topics) > 3 {
+			event.FourthTopic = topics[3]
+		}
+		return event
+	}
+	catLog := func(tx string, logIndex uint, topics []string, data string) models.Event {
+		event := punkLog(tx, logIndex, topics, data)
+		event.Address = common.MoonCatRescueAddress
+		return event
+	}
+
+	logs := []models.Event{
+		// getPunk 领取
+		punkLog("0xa", 0, []string{common.PunkAssignSignature, addressTopic(seller)},
+			packPoolLog(t, cryptopunks.CryptoPunksMarketABI, "Assign", big.NewInt(7))),
+		// transferPunk 赠送
+		punkLog("0xb", 1, []string{common.ERC20TokenTransferEventFuncSign, addressTopic(seller), addressTopic(buyer)}, one),
+		punkLog("0xb", 2, []string{common.PunkTransferSignature, addressTopic(seller), addressTopic(buyer)},
+			packPoolLog(t, cryptopunks.CryptoPunksMarketABI, "PunkTransfer", big.NewInt(7))),
+		// buyPunk 购买
+		punkLog("0xc", 3, []string{common.ERC20TokenTransferEventFuncSign, addressTopic(buyer), addressTopic(seller)}, one),
+		punkLog("0xc", 4, []string{common.PunkBoughtSignature, ethcommon.BigToHash(big.NewInt(7)).Hex(), addressTopic(buyer), addressTopic(seller)},
+			packPoolLog(t, cryptopunks.CryptoPunksMarketABI, "PunkBought", big.NewInt(5e18))),
+		// acceptBidForPunk, toAddress 和 value 为0
+		punkLog("0xd", 5, []string{common.ERC20TokenTransferEventFuncSign, addressTopic(seller), addressTopic(buyer)}, one),
+		punkLog("0xd", 6, []string{common.PunkBoughtSignature, ethcommon.BigToHash(big.NewInt(7)).Hex(), addressTopic(seller), addressTopic(common.ZeroAddress)},
+			packPoolLog(t, cryptopunks.CryptoPunksMarketABI, "PunkBought", big.NewInt(0))),
+		// MoonCat 救援和领养
+		catLog("0xe", 7, []string{common.CatRescuedSignature, addressTopic(seller), catId}, ""),
+		catLog("0xf", 8, []string{common.CatAdoptedSignature, catId, addressTopic(seller), addressTopic(buyer)},
+			packPoolLog(t, mooncats.MoonCatRescueABI, "CatAdopted", big.NewInt(1e17))),
+	}
+
+	got, err := doParse(logs, TokenTransfers{})
+	if err != nil {
+		t.Fatalf("doParse() error = %v", err)
+	}
+
+	wantTransfers := []struct {
+		tx       string
+		contract string
+		tokenId  *big.Int
+		from, to string
+	}{
+		{"0xa", common.CryptoPunksAddress, big.NewInt(7), common.ZeroAddress, seller},
+		{"0xb", common.CryptoPunksAddress, big.NewInt(7), seller, buyer},
+		{"0xc", common.CryptoPunksAddress, big.NewInt(7), buyer, seller},
+		{"0xd", common.CryptoPunksAddress, big.NewInt(7), seller, buyer},
+		{"0xe", common.MoonCatRescueAddress, catTokenId, common.ZeroAddress, seller},
+		{"0xf", common.MoonCatRescueAddress, catTokenId, seller, buyer},
+	}
+	if len(got.TokenTransfers) != len(wantTransfers) {
+		t.Fatalf("doParse() got %d transfers, want %d: %+v", len(got.TokenTransfers), len(wantTransfers), got.TokenTransfers)
+	}
+	for i, want := range wantTransfers {
+		transfer := got.TokenTransfers[i]
+		if transfer.TransactionHash != want.tx || transfer.TokenContractAddress != want.contract ||
+			transfer.TokenId.Cmp(want.tokenId) != 0 || transfer.FromAddress != want.from || transfer.ToAddress != want.to {
+			t.Errorf("transfer[%d] = %+v, want %+v", i, transfer, want)
+		}
+		if transfer.Amount != nil {
+			t.Errorf("transfer[%d] amount = %v, want nil", i, transfer.Amount)
+		}
+	}
+
+	sales, err := ParseNftSales(logs, got.TokenTransfers)
+	if err != nil {
+		t.Fatalf("ParseNftSales() error = %v", err)
+	}
+	wantSales := []struct {
+		marketplace string
+		tx          string
+		price       *big.Int
+		seller      string
+		buyer       string
+	}{
+		{common.CryptoPunks, "0xc", big.NewInt(5e18), buyer, seller},
+		{common.MoonCats, "0xf", big.NewInt(1e17), seller, buyer},
+	}
+	if len(sales) != len(wantSales) {
+		t.Fatalf("ParseNftSales() got %d sales, want %d", len(sales), len(wantSales))
+	}
+	for i, want := range wantSales {
+		sale := sales[i]
+		if sale.Marketplace != want.marketplace || sale.TransactionHash != want.tx || sale.Price.Cmp(want.price) != 0 ||
+			sale.Seller != want.seller || sale.Buyer != want.buyer || sale.Currency != common.ZeroAddress {
+			t.Errorf("sale[%d] = %+v, want %+v", i, sale, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/core/dal"
+)
+
+// LoadBlockData 从数据库中读取 [from, to] 高度区间内已经入库的区块数据
+func LoadBlockData(ctx context.Context, from, to uint64) ([]*BlockData, error) {
+	blocks, err := dal.Block.GetByHeightRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get blocks")
+	}
+	trxs, err := dal.Transaction.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get transactions")
+	}
+	events, err := dal.Event.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get events")
+	}
+	transfers, err := dal.TokenTransfer.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get token transfers")
+	}
+	userOps, err := dal.UserOperation.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get user operations")
+	}
+	trades, err := dal.DexTrade.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get dex trades")
+	}
+	sales, err := dal.NftSale.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get nft sales")
+	}
+
+	result := make([]*BlockData, 0, len(blocks))
+	byHeight := make(map[uint64]*BlockData, len(blocks))
+	for _, block := range blocks {
+		if block.BlockHash == "" {
+			continue
+		}
+		data := &BlockData{Block: block}
+		byHeight[block.BlockHeight] = data
+		result = append(result, data)
+	}
+	for _, trx := range trxs {
+		if data, ok := byHeight[trx.BlockNumber]; ok {
+			data.Transactions = append(data.Transactions, trx)
+		}
+	}
+	for _, event := range events {
+		if data, ok := byHeight[event.BlockNumber]; ok && event.BlockHash == data.Block.BlockHash {
+			data.Events = append(data.Events, event)
+		}
+	}
+	for _, transfer := range transfers {
+		if data, ok := byHeight[transfer.BlockNumber]; ok && transfer.BlockHash == data.Block.BlockHash {
+			data.TokenTransfers = append(data.TokenTransfers, transfer)
+		}
+	}
+	for _, userOp := range userOps {
+		if data, ok := byHeight[userOp.BlockNumber]; ok && userOp.BlockHash == data.Block.BlockHash {
+			data.UserOperations = append(data.UserOperations, userOp)
+		}
+	}
+	for _, trade := range trades {
+		if data, ok := byHeight[trade.BlockNumber]; ok && trade.BlockHash == data.Block.BlockHash {
+			data.DexTrades = append(data.DexTrades, trade)
+		}
+	}
+	for _, sale := range sales {
+		if data, ok := byHeight[sale.BlockNumber]; ok && sale.BlockHash == data.Block.BlockHash {
+			data.NftSales = append(data.NftSales, sale)
+		}
+	}
+
+	// 授权表只保存最新状态, 从区块日志重新解析
+	for _, data := range result {
+		approvals, err := ParseApprovals(data.Events)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse approvals")
+		}
+		data.TokenAllowances = approvals.TokenAllowances
+		data.OperatorApprovals = approvals.OperatorApprovals
+	}
+
+	return result, nil
+}
@@ -0,0 +1,414 @@
+package chain
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+/*
+## Overview
+  NFT 成交从交易市场的事件解析, 事件中的 NFT 必须能在同一交易的 token 转移中找到,
+  买卖双方以实际的 NFT 转移为准, 没有对应转移的订单不记录.
+
+  | Marketplace | Event                          | NFT 来源                               | 费用                              |
+  |-------------|--------------------------------|----------------------------------------|-----------------------------------|
+  | Seaport     | `OrderFulfilled`               | offer 或 consideration 中的 NFT        | consideration 中付给其他地址的货币 |
+  | Blur        | `OrdersMatched`                | sell 订单的 collection/tokenId         | sell 订单 fees, 按万分比计算版税   |
+  | LooksRare   | `TakerAsk` / `TakerBid`        | 事件中的 collection/tokenId            | 之前的 `RoyaltyPayment`            |
+  | X2Y2        | `EvInventory`                  | item.data 中编码的 (token, tokenId)    | detail.fees, 按百万分比计算        |
+
+  Seaport 挂单成交时 offer 为 NFT, 出价成交时 offer 为货币.
+  一个订单包含多个 NFT 时价格和费用按数量平分.
+  只解析各市场交易合约发出的事件, 见 marketplaceExchanges.
+  LooksRare v1 的协议费不在事件中体现, platform_fee 记为空.
+  CryptoPunks 和 MoonCats 合约自带的交易见 legacy_nft.go.
+*/
+
+// Seaport ItemType
+const (
+	seaportNative uint8 = iota
+	seaportERC20
+	seaportERC721
+	seaportERC1155
+	seaportERC721WithCriteria
+	seaportERC1155WithCriteria
+)
+
+// X2Y2 delegateType
+const x2y2ERC1155Delegate = 2
+
+var (
+	basisPoints    = big.NewInt(10000)
+	x2y2FeeDivisor = big.NewInt(1000000)
+)
+
+// marketplaceExchanges 交易市场合约地址(小写)对应的市场
+var marketplaceExchanges = map[string]string{
+	strings.ToLower(common.SeaportV10Address):        common.Seaport,
+	strings.ToLower(common.SeaportV11Address):        common.Seaport,
+	strings.ToLower(common.SeaportV13Address):        common.Seaport,
+	strings.ToLower(common.SeaportV14Address):        common.Seaport,
+	strings.ToLower(common.SeaportV15Address):        common.Seaport,
+	strings.ToLower(common.SeaportV16Address):        common.Seaport,
+	strings.ToLower(common.BlurExchangeAddress):      common.Blur,
+	strings.ToLower(common.LooksRareExchangeAddress): common.LooksRare,
+	strings.ToLower(common.X2Y2ExchangeAddress):      common.X2Y2,
+}
+
+// marketplaceEvents 交易市场事件对应的市场, 其他合约发出的同名事件不是成交
+var marketplaceEvents = map[string]string{
+	common.SeaportOrderFulfilledSignature:   common.Seaport,
+	common.BlurOrdersMatchedSignature:       common.Blur,
+	common.LooksRareRoyaltyPaymentSignature: common.LooksRare,
+	common.LooksRareTakerAskSignature:       common.LooksRare,
+	common.LooksRareTakerBidSignature:       common.LooksRare,
+	common.X2Y2InventorySignature:           common.X2Y2,
+}
+
+type saleItem struct {
+	collection string
+	tokenId    *big.Int
+}
+
+// saleOrder 市场事件解析出的订单, 价格和费用为整个订单的总额
+type saleOrder struct {
+	marketplace string
+	items       []saleItem
+	price       *big.Int
+	currency    string
+	platformFee *big.Int
+	royalty     *big.Int
+	orderHash   string
+}
+
+// ParseNftSales 从区块日志中解析 NFT 成交, 并与区块的 token 转移匹配
+func ParseNftSales(logs []models.Event, transfers []models.TokenTransfer) ([]models.NftSale, error) {
+	matcher := newTransferMatcher(transfers)
+	royalties := make(map[string]*big.Int)
+
+	var sales []models.NftSale
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		if marketplace, ok := marketplaceEvents[log.FirstTopic]; ok && marketplaceExchanges[strings.ToLower(log.Address)] != marketplace {
+			continue
+		}
+
+		var (
+			order *saleOrder
+			err   error
+		)
+		switch log.FirstTopic {
+		case common.SeaportOrderFulfilledSignature:
+			order, err = parseSeaportOrder(log)
+		case common.BlurOrdersMatchedSignature:
+			order, err = parseBlurOrder(log)
+		case common.LooksRareRoyaltyPaymentSignature:
+			_, amount, err := abi.ParseLooksRareRoyaltyPaymentLog(ethcommon.FromHex(log.Data))
+			if err != nil {
+				warnSkipLog(log, err)
+				continue
+			}
+			key := royaltyKey(log.TxHash, ethcommon.HexToAddress(log.SecondTopic).Hex(), ethcommon.HexToHash(log.ThirdTopic).Big())
+			if royalties[key] == nil {
+				royalties[key] = new(big.Int)
+			}
+			royalties[key].Add(royalties[key], amount)
+			continue
+		case common.LooksRareTakerAskSignature:
+			order, err = parseLooksRareOrder(log, "TakerAsk", royalties)
+		case common.LooksRareTakerBidSignature:
+			order, err = parseLooksRareOrder(log, "TakerBid", royalties)
+		case common.X2Y2InventorySignature:
+			order, err = parseX2Y2Order(log)
+		case common.PunkBoughtSignature, common.CatAdoptedSignature:
+			order, err = parseLegacyNftSale(log)
+		default:
+			continue
+		}
+		if err != nil {
+			warnSkipLog(log, err)
+			continue
+		}
+		if order == nil || len(order.items) == 0 {
+			continue
+		}
+
+		sales = append(sales, order.sales(log, matcher)...)
+	}
+
+	return sales, nil
+}
+
+// sales 把订单按 NFT 拆分为成交记录, 找不到对应转移的 NFT 会被忽略
+func (o *saleOrder) sales(log models.Event, matcher *transferMatcher) []models.NftSale {
+	n := big.NewInt(int64(len(o.items)))
+	var sales []models.NftSale
+	for _, item := range o.items {
+		transfer, amount, ok := matcher.match(log.TxHash, item.collection, item.tokenId)
+		if !ok {
+			continue
+		}
+
+		sales = append(sales, models.NftSale{
+			Marketplace:      o.marketplace,
+			Collection:       transfer.TokenContractAddress,
+			TokenId:          item.tokenId,
+			Amount:           amount,
+			Seller:           transfer.FromAddress,
+			Buyer:            transfer.ToAddress,
+			Price:            splitAmount(o.price, n),
+			Currency:         o.currency,
+			PlatformFee:      splitAmount(o.platformFee, n),
+			Royalty:          splitAmount(o.royalty, n),
+			OrderHash:        o.orderHash,
+			TransactionHash:  log.TxHash,
+			BlockNumber:      log.BlockNumber,
+			BlockHash:        log.BlockHash,
+			LogIndex:         log.LogIndex,
+			TransferLogIndex: transfer.LogIndex,
+		})
+	}
+	return sales
+}
+
+func parseSeaportOrder(log models.Event) (*saleOrder, error) {
+	event, err := abi.ParseSeaportOrderFulfilledLog(ethcommon.FromHex(log.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	offerer := ethcommon.HexToAddress(log.SecondTopic)
+	order := &saleOrder{
+		marketplace: common.Seaport,
+		price:       new(big.Int),
+		platformFee: new(big.Int),
+		royalty:     new(big.Int),
+		orderHash:   ethcommon.Hash(event.OrderHash).Hex(),
+	}
+
+	// 挂单成交: offerer 卖出 NFT, consideration 中付给 offerer 的是卖家收入, 其余为费用
+	// 出价成交: offerer 用 offer 中的货币买入 consideration 中的 NFT, 费用由卖家从货款中支付
+	for _, item := range event.Offer {
+		if isSeaportNft(item.ItemType) {
+			order.items = append(order.items, saleItem{collection: item.Token.Hex(), tokenId: item.Identifier})
+		}
+	}
+	listing := len(order.items) > 0
+	seller := offerer
+	if !listing {
+		seller = event.Recipient
+		for _, item := range event.Offer {
+			if isSeaportCurrency(item.ItemType) {
+				order.price.Add(order.price, item.Amount)
+				order.currency = item.Token.Hex()
+			}
+		}
+	}
+
+	for _, item := range event.Consideration {
+		switch {
+		case isSeaportNft(item.ItemType) && !listing:
+			order.items = append(order.items, saleItem{collection: item.Token.Hex(), tokenId: item.Identifier})
+		case isSeaportCurrency(item.ItemType):
+			if listing {
+				order.price.Add(order.price, item.Amount)
+				order.currency = item.Token.Hex()
+			}
+			switch {
+			case item.Recipient == seller:
+			case strings.EqualFold(item.Recipient.Hex(), common.OpenSeaFeeRecipient):
+				order.platformFee.Add(order.platformFee, item.Amount)
+			default:
+				order.royalty.Add(order.royalty, item.Amount)
+			}
+		}
+	}
+
+	// NFT 互换没有价格, 不算成交
+	if order.price.Sign() == 0 {
+		return nil, nil
+	}
+	return order, nil
+}
+
+func isSeaportNft(itemType uint8) bool {
+	switch itemType {
+	case seaportERC721, seaportERC1155, seaportERC721WithCriteria, seaportERC1155WithCriteria:
+		return true
+	}
+	return false
+}
+
+func isSeaportCurrency(itemType uint8) bool {
+	return itemType == seaportNative || itemType == seaportERC20
+}
+
+func parseBlurOrder(log models.Event) (*saleOrder, error) {
+	event, err := abi.ParseBlurOrdersMatchedLog(ethcommon.FromHex(log.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Blur 不收平台费, sell 订单中的 fees 为版税
+	sell := event.Sell
+	royalty := new(big.Int)
+	for _, fee := range sell.Fees {
+		amount := new(big.Int).Mul(sell.Price, big.NewInt(int64(fee.Rate)))
+		royalty.Add(royalty, amount.Quo(amount, basisPoints))
+	}
+
+	return &saleOrder{
+		marketplace: common.Blur,
+		items:       []saleItem{{collection: sell.Collection.Hex(), tokenId: sell.TokenId}},
+		price:       sell.Price,
+		currency:    sell.PaymentToken.Hex(),
+		platformFee: new(big.Int),
+		royalty:     royalty,
+		orderHash:   ethcommon.Hash(event.SellHash).Hex(),
+	}, nil
+}
+
+func parseLooksRareOrder(log models.Event, name string, royalties map[string]*big.Int) (*saleOrder, error) {
+	event, err := abi.ParseLooksRareTakerLog(name, ethcommon.FromHex(log.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	collection := event.Collection.Hex()
+	key := royaltyKey(log.TxHash, collection, event.TokenId)
+	royalty := royalties[key]
+	if royalty == nil {
+		royalty = new(big.Int)
+	}
+	delete(royalties, key)
+
+	return &saleOrder{
+		marketplace: common.LooksRare,
+		items:       []saleItem{{collection: collection, tokenId: event.TokenId}},
+		price:       event.Price,
+		currency:    event.Currency.Hex(),
+		royalty:     royalty,
+		orderHash:   ethcommon.Hash(event.OrderHash).Hex(),
+	}, nil
+}
+
+func royaltyKey(txHash, collection string, tokenId *big.Int) string {
+	return txHash + strings.ToLower(collection) + tokenId.String()
+}
+
+func parseX2Y2Order(log models.Event) (*saleOrder, error) {
+	event, err := abi.ParseX2Y2InventoryLog(ethcommon.FromHex(log.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := abi.ParseX2Y2ItemData(event.Item.Data, event.DelegateType.Int64() == x2y2ERC1155Delegate)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &saleOrder{
+		marketplace: common.X2Y2,
+		price:       event.Detail.Price,
+		currency:    event.Currency.Hex(),
+		platformFee: new(big.Int),
+		royalty:     new(big.Int),
+		orderHash:   log.SecondTopic,
+	}
+	for _, item := range items {
+		order.items = append(order.items, saleItem{collection: item.Token.Hex(), tokenId: item.TokenId})
+	}
+	for _, fee := range event.Detail.Fees {
+		amount := new(big.Int).Mul(event.Detail.Price, fee.Percentage)
+		amount.Quo(amount, x2y2FeeDivisor)
+		if strings.EqualFold(fee.To.Hex(), common.X2Y2FeeRecipient) {
+			order.platformFee.Add(order.platformFee, amount)
+		} else {
+			order.royalty.Add(order.royalty, amount)
+		}
+	}
+	return order, nil
+}
+
+func splitAmount(total, n *big.Int) *big.Int {
+	if total == nil {
+		return nil
+	}
+	return new(big.Int).Quo(total, n)
+}
+
+// transferMatcher 按交易查找 NFT 转移, 每个转移中的 Token ID 只能匹配一次
+type transferMatcher struct {
+	byTx map[string][]models.TokenTransfer
+	used map[string]bool
+}
+
+func newTransferMatcher(transfers []models.TokenTransfer) *transferMatcher {
+	m := &transferMatcher{
+		byTx: make(map[string][]models.TokenTransfer),
+		used: make(map[string]bool),
+	}
+	for _, transfer := range transfers {
+		if transfer.TokenId == nil && len(transfer.TokenIds) == 0 {
+			continue
+		}
+		m.byTx[transfer.TransactionHash] = append(m.byTx[transfer.TransactionHash], transfer)
+	}
+	return m
+}
+
+func (m *transferMatcher) match(txHash, collection string, tokenId *big.Int) (*models.TokenTransfer, *big.Int, bool) {
+	if tokenId == nil {
+		return nil, nil, false
+	}
+
+	transfers := m.byTx[txHash]
+	for i := range transfers {
+		transfer := &transfers[i]
+		if !strings.EqualFold(transfer.TokenContractAddress, collection) {
+			continue
+		}
+
+		key := txHash + ":" + strconv.FormatUint(uint64(transfer.LogIndex), 10) + ":" + tokenId.String()
+		if m.used[key] {
+			continue
+		}
+
+		amount, ok := transferAmount(transfer, tokenId)
+		if !ok {
+			continue
+		}
+		m.used[key] = true
+		return transfer, amount, true
+	}
+	return nil, nil, false
+}
+
+// transferAmount 返回转移中 tokenId 的数量, ERC721 没有数量记为1
+func transferAmount(transfer *models.TokenTransfer, tokenId *big.Int) (*big.Int, bool) {
+	if transfer.TokenId != nil {
+		if transfer.TokenId.Cmp(tokenId) != 0 {
+			return nil, false
+		}
+		if transfer.Amount == nil {
+			return big.NewInt(1), true
+		}
+		return transfer.Amount, true
+	}
+
+	for i, id := range transfer.TokenIds {
+		if id.Cmp(tokenId) == 0 && i < len(transfer.Amounts) {
+			return transfer.Amounts[i], true
+		}
+	}
+	return nil, false
+}
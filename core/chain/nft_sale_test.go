@@ -0,0 +1,229 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi/blur"
+	"github.com/traitmeta/metago/pkg/abi/looksrare"
+	"github.com/traitmeta/metago/pkg/abi/seaport"
+	"github.com/traitmeta/metago/pkg/abi/x2y2"
+)
+
+func packX2Y2Items(t *testing.T, token ethcommon.Address, tokenId *big.Int) []byte {
+	itemsType, err := abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := abi.Arguments{{Type: itemsType}}.Pack([]struct {
+		Token   ethcommon.Address
+		TokenId *big.Int
+	}{{token, tokenId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseNftSales(t *testing.T) {
+	seller := ethcommon.HexToAddress("0x8b736035BbDA71825e0219f5FE4DfB22C35FbDDC")
+	buyer := ethcommon.HexToAddress("0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e")
+	creator := ethcommon.HexToAddress("0x9406Cc6185a346906296840746125a0E44976454")
+	collection := ethcommon.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	weth := ethcommon.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	openSea := ethcommon.HexToAddress(common.OpenSeaFeeRecipient)
+	x2y2Fee := ethcommon.HexToAddress(common.X2Y2FeeRecipient)
+	zero := ethcommon.Address{}
+	ether := big.NewInt(1e18)
+	orderHash := ethcommon.HexToHash("0x01")
+
+	nftTransfer := func(tx string, logIndex uint, tokenId int64, from, to ethcommon.Address) models.TokenTransfer {
+		return models.TokenTransfer{
+			TransactionHash:      tx,
+			LogIndex:             logIndex,
+			FromAddress:          from.Hex(),
+			ToAddress:            to.Hex(),
+			TokenId:              big.NewInt(tokenId),
+			TokenContractAddress: collection.Hex(),
+		}
+	}
+	marketLog := func(address, tx string, logIndex uint, topic0 string, topics []string, data string) models.Event {
+		event := models.Event{Address: address, FirstTopic: topic0, TxHash: tx, LogIndex: logIndex, BlockNumber: 100}
+		if len(topics) > 0 {
+			event.SecondTopic = topics[0]
+		}
+		if len(topics) > 1 {
+			event.ThirdTopic = topics[1]
+		}
+		if len(topics) > 2 {
+			event.FourthTopic = topics[2]
+		}
+		event.Data = data
+		return event
+	}
+
+	tests := []struct {
+		name      string
+		logs      []models.Event
+		transfers []models.TokenTransfer
+		want      []models.NftSale
+	}{
+		{
+			name: "seaport listing paid in ETH",
+			logs: []models.Event{marketLog(common.SeaportV15Address, "0xa", 2, common.SeaportOrderFulfilledSignature,
+				[]string{addressTopic(seller.Hex()), addressTopic(zero.Hex())},
+				packPoolLog(t, seaport.SeaportABI, "OrderFulfilled", orderHash, buyer,
+					[]seaport.SpentItem{{ItemType: seaportERC721, Token: collection, Identifier: big.NewInt(1), Amount: big.NewInt(1)}},
+					[]seaport.ReceivedItem{
+						{ItemType: seaportNative, Token: zero, Identifier: big.NewInt(0), Amount: big.NewInt(9e17), Recipient: seller},
+						{ItemType: seaportNative, Token: zero, Identifier: big.NewInt(0), Amount: big.NewInt(25e15), Recipient: openSea},
+						{ItemType: seaportNative, Token: zero, Identifier: big.NewInt(0), Amount: big.NewInt(75e15), Recipient: creator},
+					}))},
+			transfers: []models.TokenTransfer{nftTransfer("0xa", 1, 1, seller, buyer)},
+			want: []models.NftSale{{
+				Marketplace: common.Seaport, TokenId: big.NewInt(1), Amount: big.NewInt(1),
+				Price: ether, Currency: zero.Hex(), PlatformFee: big.NewInt(25e15), Royalty: big.NewInt(75e15),
+				OrderHash: orderHash.Hex(), LogIndex: 2, TransferLogIndex: 1,
+			}},
+		},
+		{
+			name: "seaport accepted offer paid in WETH",
+			logs: []models.Event{marketLog(common.SeaportV15Address, "0xb", 3, common.SeaportOrderFulfilledSignature,
+				[]string{addressTopic(buyer.Hex()), addressTopic(zero.Hex())},
+				packPoolLog(t, seaport.SeaportABI, "OrderFulfilled", orderHash, seller,
+					[]seaport.SpentItem{{ItemType: seaportERC20, Token: weth, Identifier: big.NewInt(0), Amount: ether}},
+					[]seaport.ReceivedItem{
+						{ItemType: seaportERC721WithCriteria, Token: collection, Identifier: big.NewInt(2), Amount: big.NewInt(1), Recipient: buyer},
+						{ItemType: seaportERC20, Token: weth, Identifier: big.NewInt(0), Amount: big.NewInt(25e15), Recipient: openSea},
+					}))},
+			transfers: []models.TokenTransfer{nftTransfer("0xb", 1, 2, seller, buyer)},
+			want: []models.NftSale{{
+				Marketplace: common.Seaport, TokenId: big.NewInt(2), Amount: big.NewInt(1),
+				Price: ether, Currency: weth.Hex(), PlatformFee: big.NewInt(25e15), Royalty: big.NewInt(0),
+				OrderHash: orderHash.Hex(), LogIndex: 3, TransferLogIndex: 1,
+			}},
+		},
+		{
+			name: "blur royalties from sell order fees",
+			logs: []models.Event{marketLog(common.BlurExchangeAddress, "0xc", 5, common.BlurOrdersMatchedSignature,
+				[]string{addressTopic(seller.Hex()), addressTopic(buyer.Hex())},
+				packPoolLog(t, blur.BlurExchangeABI, "OrdersMatched",
+					blur.Order{Trader: seller, Collection: collection, TokenId: big.NewInt(3), Amount: big.NewInt(1), PaymentToken: zero,
+						Price: ether, ListingTime: big.NewInt(0), ExpirationTime: big.NewInt(0), Salt: big.NewInt(0),
+						Fees: []blur.Fee{{Rate: 50, Recipient: creator}}},
+					orderHash,
+					blur.Order{Trader: buyer, Side: 1, Collection: collection, TokenId: big.NewInt(3), Amount: big.NewInt(1), PaymentToken: zero,
+						Price: ether, ListingTime: big.NewInt(0), ExpirationTime: big.NewInt(0), Salt: big.NewInt(0)},
+					ethcommon.HexToHash("0x02")))},
+			transfers: []models.TokenTransfer{nftTransfer("0xc", 4, 3, seller, buyer)},
+			want: []models.NftSale{{
+				Marketplace: common.Blur, TokenId: big.NewInt(3), Amount: big.NewInt(1),
+				Price: ether, Currency: zero.Hex(), PlatformFee: big.NewInt(0), Royalty: big.NewInt(5e15),
+				OrderHash: orderHash.Hex(), LogIndex: 5, TransferLogIndex: 4,
+			}},
+		},
+		{
+			name: "looksrare taker bid with royalty payment",
+			logs: []models.Event{
+				marketLog(common.LooksRareExchangeAddress, "0xd", 1, common.LooksRareRoyaltyPaymentSignature,
+					[]string{addressTopic(collection.Hex()), ethcommon.BigToHash(big.NewInt(4)).Hex(), addressTopic(creator.Hex())},
+					packPoolLog(t, looksrare.LooksRareExchangeABI, "RoyaltyPayment", weth, big.NewInt(5e16))),
+				marketLog(common.LooksRareExchangeAddress, "0xd", 3, common.LooksRareTakerBidSignature,
+					[]string{addressTopic(buyer.Hex()), addressTopic(seller.Hex()), addressTopic(zero.Hex())},
+					packPoolLog(t, looksrare.LooksRareExchangeABI, "TakerBid", orderHash, big.NewInt(7), weth, collection,
+						big.NewInt(4), big.NewInt(1), ether)),
+			},
+			transfers: []models.TokenTransfer{nftTransfer("0xd", 2, 4, seller, buyer)},
+			want: []models.NftSale{{
+				Marketplace: common.LooksRare, TokenId: big.NewInt(4), Amount: big.NewInt(1),
+				Price: ether, Currency: weth.Hex(), Royalty: big.NewInt(5e16),
+				OrderHash: orderHash.Hex(), LogIndex: 3, TransferLogIndex: 2,
+			}},
+		},
+		{
+			name: "x2y2 item data and fees",
+			logs: []models.Event{marketLog(common.X2Y2ExchangeAddress, "0xe", 6, common.X2Y2InventorySignature,
+				[]string{orderHash.Hex()},
+				packPoolLog(t, x2y2.X2Y2ABI, "EvInventory", seller, buyer, big.NewInt(1), big.NewInt(2), big.NewInt(1), big.NewInt(1),
+					big.NewInt(0), weth, []byte{},
+					x2y2.MarketOrderItem{Price: ether, Data: packX2Y2Items(t, collection, big.NewInt(5))},
+					x2y2.MarketSettleDetail{Price: ether, OrderIdx: big.NewInt(0), ItemIdx: big.NewInt(0), DataReplacement: []byte{},
+						BidIncentivePct: big.NewInt(0), AucMinIncrementPct: big.NewInt(0), AucIncDurationSecs: big.NewInt(0),
+						Fees: []x2y2.MarketFee{{Percentage: big.NewInt(5000), To: x2y2Fee}, {Percentage: big.NewInt(50000), To: creator}}}))},
+			transfers: []models.TokenTransfer{nftTransfer("0xe", 5, 5, seller, buyer)},
+			want: []models.NftSale{{
+				Marketplace: common.X2Y2, TokenId: big.NewInt(5), Amount: big.NewInt(1),
+				Price: ether, Currency: weth.Hex(), PlatformFee: big.NewInt(5e15), Royalty: big.NewInt(5e16),
+				OrderHash: orderHash.Hex(), LogIndex: 6, TransferLogIndex: 5,
+			}},
+		},
+		{
+			name: "marketplace event from an unknown contract is ignored",
+			logs: []models.Event{marketLog(creator.Hex(), "0x10", 2, common.LooksRareTakerBidSignature,
+				[]string{addressTopic(buyer.Hex()), addressTopic(seller.Hex()), addressTopic(zero.Hex())},
+				packPoolLog(t, looksrare.LooksRareExchangeABI, "TakerBid", orderHash, big.NewInt(7), weth, collection,
+					big.NewInt(6), big.NewInt(1), ether))},
+			transfers: []models.TokenTransfer{nftTransfer("0x10", 1, 6, seller, buyer)},
+		},
+		{
+			name:      "transfer without marketplace event is a gift",
+			transfers: []models.TokenTransfer{nftTransfer("0xf", 1, 6, seller, buyer)},
+		},
+		{
+			name: "order without matching transfer is skipped",
+			logs: []models.Event{marketLog(common.BlurExchangeAddress, "0xf", 2, common.BlurOrdersMatchedSignature,
+				[]string{addressTopic(seller.Hex()), addressTopic(buyer.Hex())},
+				packPoolLog(t, blur.BlurExchangeABI, "OrdersMatched",
+					blur.Order{Trader: seller, Collection: collection, TokenId: big.NewInt(7), Amount: big.NewInt(1), PaymentToken: zero,
+						Price: ether, ListingTime: big.NewInt(0), ExpirationTime: big.NewInt(0), Salt: big.NewInt(0)},
+					orderHash,
+					blur.Order{Trader: buyer, Collection: collection, TokenId: big.NewInt(7), Amount: big.NewInt(1), PaymentToken: zero,
+						Price: ether, ListingTime: big.NewInt(0), ExpirationTime: big.NewInt(0), Salt: big.NewInt(0)},
+					orderHash))},
+			transfers: []models.TokenTransfer{nftTransfer("0xf", 1, 6, seller, buyer)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNftSales(tt.logs, tt.transfers)
+			if err != nil {
+				t.Fatalf("ParseNftSales() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseNftSales() got %d sales, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				sale := got[i]
+				if sale.Marketplace != want.Marketplace || sale.Collection != collection.Hex() || sale.Currency != want.Currency ||
+					sale.OrderHash != want.OrderHash || sale.LogIndex != want.LogIndex || sale.TransferLogIndex != want.TransferLogIndex {
+					t.Errorf("sale = %+v, want %+v", sale, want)
+				}
+				if sale.Seller != seller.Hex() || sale.Buyer != buyer.Hex() {
+					t.Errorf("seller/buyer = %s/%s", sale.Seller, sale.Buyer)
+				}
+				for _, pair := range []struct {
+					field     string
+					got, want *big.Int
+				}{
+					{"token_id", sale.TokenId, want.TokenId},
+					{"amount", sale.Amount, want.Amount},
+					{"price", sale.Price, want.Price},
+					{"platform_fee", sale.PlatformFee, want.PlatformFee},
+					{"royalty", sale.Royalty, want.Royalty},
+				} {
+					if (pair.got == nil) != (pair.want == nil) || (pair.got != nil && pair.got.Cmp(pair.want) != 0) {
+						t.Errorf("%s = %v, want %v", pair.field, pair.got, pair.want)
+					}
+				}
+			}
+		})
+	}
+}
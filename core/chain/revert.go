@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+/*
+## Overview
+  失败交易的原因有两种获取方式:
+  - 默认用 eth_call 在父区块的状态上重放交易, 同一区块中之前的交易改变了状态时结果可能不同,
+    重放成功时原因为空.
+  - BlockChain.Tracer 开启时用 debug_traceTransaction 的 callTracer 读取顶层调用的返回数据, 结果准确,
+    需要节点开启 debug 接口.
+  有返回数据时按 abi.DecodeRevertReason 解析. 没有返回数据时, tracer 记录顶层调用的错误信息, 例如 out of gas;
+  eth_call 只记录 execution reverted 的错误信息, 其它错误不能说明交易失败的原因, 原因留空.
+*/
+
+// callFrame callTracer 返回结果中需要的字段
+type callFrame struct {
+	Output hexutil.Bytes `json:"output"`
+	Error  string        `json:"error"`
+}
+
+// RevertReason 获取失败交易的原因, 获取失败时只记录日志并返回空字符串
+func RevertReason(ctx context.Context, tx *types.Transaction, from common.Address, blockNumber *big.Int) string {
+	var (
+		reason string
+		err    error
+	)
+	if config.BlockChain.Tracer {
+		reason, err = traceRevertReason(ctx, tx.Hash())
+	} else {
+		reason, err = callRevertReason(ctx, tx, from, blockNumber)
+	}
+	if err != nil {
+		log.WithField("txHash", tx.Hash().Hex()).WithField("err", err).Warn("get revert reason fail")
+		return ""
+	}
+	return reason
+}
+
+func traceRevertReason(ctx context.Context, txHash common.Hash) (string, error) {
+	var frame callFrame
+	err := config.EthRpcClient.Client().CallContext(ctx, &frame, "debug_traceTransaction", txHash,
+		map[string]interface{}{"tracer": "callTracer", "tracerConfig": map[string]interface{}{"onlyTopCall": true}})
+	if err != nil {
+		return "", err
+	}
+	if reason := abi.DecodeRevertReason(frame.Output); reason != "" {
+		return reason, nil
+	}
+	return frame.Error, nil
+}
+
+// callRevertReason 不设置 gas price, 避免父区块的 base fee 导致调用失败
+func callRevertReason(ctx context.Context, tx *types.Transaction, from common.Address, blockNumber *big.Int) (string, error) {
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	parent := new(big.Int).Sub(blockNumber, big.NewInt(1))
+	_, err := config.EthRpcClient.CallContract(ctx, msg, parent)
+	if err == nil {
+		return "", nil
+	}
+
+	// 只有执行回滚才是失败原因, 网络、out of gas 等其它错误需要返回, 原因留空
+	if !isExecutionReverted(err) {
+		return "", err
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if s, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(s); decodeErr == nil && len(data) > 0 {
+				return abi.DecodeRevertReason(data), nil
+			}
+		}
+	}
+	var rpcErr rpc.Error
+	errors.As(err, &rpcErr)
+	return rpcErr.Error(), nil
+}
@@ -0,0 +1,96 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/config/setting"
+)
+
+// errorStringData Error("insufficient balance")
+const errorStringData = "0x08c379a0" +
+	"0000000000000000000000000000000000000000000000000000000000000020" +
+	"0000000000000000000000000000000000000000000000000000000000000014" +
+	"696e73756666696369656e742062616c616e6365000000000000000000000000"
+
+type revertError struct {
+	code int
+	msg  string
+	data string
+}
+
+func (e *revertError) Error() string          { return e.msg }
+func (e *revertError) ErrorCode() int         { return e.code }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+type fakeEthService struct {
+	err   error
+	block string
+}
+
+func (s *fakeEthService) Call(_ context.Context, _ map[string]interface{}, block string) (hexutil.Bytes, error) {
+	s.block = block
+	return nil, s.err
+}
+
+type fakeDebugService struct{}
+
+func (fakeDebugService) TraceTransaction(_ context.Context, _ common.Hash, _ map[string]interface{}) (*callFrame, error) {
+	return &callFrame{Output: common.FromHex(errorStringData), Error: "execution reverted"}, nil
+}
+
+func TestRevertReason(t *testing.T) {
+	to := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, To: &to, Gas: 50000, Data: common.FromHex("0xa9059cbb")})
+	from := common.HexToAddress("0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e")
+
+	eth := &fakeEthService{}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", eth); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.RegisterName("debug", fakeDebugService{}); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	client, blockChain := config.EthRpcClient, config.BlockChain
+	defer func() { config.EthRpcClient, config.BlockChain = client, blockChain }()
+	config.EthRpcClient = ethclient.NewClient(rpc.DialInProc(srv))
+	config.BlockChain = &setting.BlockChainConfig{}
+
+	tests := []struct {
+		name   string
+		tracer bool
+		err    error
+		want   string
+	}{
+		{name: "revert data", err: &revertError{code: 3, msg: "execution reverted", data: errorStringData}, want: "insufficient balance"},
+		{name: "revert without data", err: &revertError{code: -32000, msg: "execution reverted"}, want: "execution reverted"},
+		{name: "not a revert", err: &revertError{code: -32000, msg: "out of gas"}, want: ""},
+		{name: "node error", err: &revertError{code: -32000, msg: "header not found"}, want: ""},
+		{name: "call succeeds at parent", want: ""},
+		{name: "tracer", tracer: true, want: "insufficient balance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth.err, eth.block = tt.err, ""
+			config.BlockChain.Tracer = tt.tracer
+
+			if got := RevertReason(context.Background(), tx, from, big.NewInt(100)); got != tt.want {
+				t.Errorf("RevertReason() = %q, want %q", got, tt.want)
+			}
+			if !tt.tracer && eth.block != "0x63" {
+				t.Errorf("RevertReason() called at block %s, want parent 0x63", eth.block)
+			}
+		})
+	}
+}
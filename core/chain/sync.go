@@ -0,0 +1,316 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/pkg/metrics"
+)
+
+/*
+## Overview
+  Syncer 是 EVM 的同步流程, 按 HeadWatcher 通知的最新高度逐个处理区块.
+  - 错误分为节点 RPC 错误、数据库冲突、其它数据库错误和不可恢复的错误(解析失败、钩子 panic 等)
+  - RPC 和数据库错误从 InitialBackoff 开始指数退避重试, 最长间隔 MaxBackoff, 成功后重置; 不可恢复的错误停止同步并返回
+  - ctx 结束后不再开始新的区块, 正在处理的区块不受 ctx 影响, 提交成功或出错回滚后返回
+  - Run 返回的 SyncState 为最终的同步进度, 用于退出时输出
+  - Backfill 按同样的错误处理重新处理指定区间的区块, 用于补齐同步起点之前的历史数据
+*/
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+)
+
+// syncMetrics EVM 同步流程的指标
+var syncMetrics = metrics.NewIndexer("eth")
+
+// ErrorKind 同步错误的分类
+type ErrorKind int
+
+const (
+	// KindFatal 解析失败等重试也无法恢复的错误
+	KindFatal ErrorKind = iota
+	// KindRPC 节点请求失败
+	KindRPC
+	// KindDBConflict 序列化失败、死锁或唯一键冲突, 通常是并发写入同一高度
+	KindDBConflict
+	// KindDB 其它数据库错误, 例如连接断开
+	KindDB
+)
+
+var errorKindNames = map[ErrorKind]string{KindFatal: "fatal", KindRPC: "rpc", KindDBConflict: "db conflict", KindDB: "db"}
+
+func (k ErrorKind) String() string {
+	return errorKindNames[k]
+}
+
+// Retryable 除不可恢复的错误外都可以重试
+func (k ErrorKind) Retryable() bool {
+	return k != KindFatal
+}
+
+// SyncError 带分类的同步错误
+type SyncError struct {
+	Kind ErrorKind
+	Op   string
+	Err  error
+}
+
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("%s: %s error: %v", e.Op, e.Kind, e.Err)
+}
+
+func (e *SyncError) Unwrap() error {
+	return e.Err
+}
+
+// Classify 返回错误的分类, 没有分类的错误视为不可恢复
+func Classify(err error) ErrorKind {
+	var se *SyncError
+	if errors.As(err, &se) {
+		return se.Kind
+	}
+	return KindFatal
+}
+
+// wrapError 给错误加上分类, 已经分类的错误保持原来的分类
+func wrapError(kind ErrorKind, op string, err error) error {
+	var se *SyncError
+	if errors.As(err, &se) {
+		return err
+	}
+	return &SyncError{Kind: kind, Op: op, Err: err}
+}
+
+// isExecutionReverted 节点执行合约调用时回滚, 错误码为3或错误信息以 execution reverted 开头, 区别于网络等错误
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	return rpcErr.ErrorCode() == 3 || strings.HasPrefix(rpcErr.Error(), "execution reverted")
+}
+
+func rpcError(op string, err error) error {
+	return wrapError(KindRPC, op, err)
+}
+
+func fatalError(op string, err error) error {
+	return wrapError(KindFatal, op, err)
+}
+
+// dbError 按 Postgres 错误码区分冲突和其它数据库错误
+func dbError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", "40P01", "23505", "55P03":
+			return wrapError(KindDBConflict, op, err)
+		}
+	}
+	return wrapError(KindDB, op, err)
+}
+
+// SyncState 同步进度
+type SyncState struct {
+	// Head 最后收到的节点高度
+	Head uint64
+	// Next 下一个待同步的高度
+	Next uint64
+	// Blocks 本次运行同步的区块数
+	Blocks  int
+	Retries int
+	// Err 停止同步的错误, ctx 结束正常退出时为空
+	Err error
+}
+
+func (s SyncState) String() string {
+	state := fmt.Sprintf("next height %d, head %d, %d blocks synced, %d retries", s.Next, s.Head, s.Blocks, s.Retries)
+	if s.Err != nil {
+		state += ", stopped by " + s.Err.Error()
+	}
+	return state
+}
+
+// Syncer 同步到节点最新高度, 依赖均可替换以便测试
+type Syncer struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	Heads func(ctx context.Context) <-chan uint64
+	// Next 下一个待同步的高度
+	Next   func(ctx context.Context) (uint64, error)
+	Fetch  func(ctx context.Context, height uint64) (*types.Block, error)
+	Handle func(ctx context.Context, block *types.Block) error
+
+	state SyncState
+}
+
+func NewSyncer() *Syncer {
+	return &Syncer{
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Heads: func(ctx context.Context) <-chan uint64 {
+			poll := func(ctx context.Context) (uint64, error) {
+				head, err := config.EthRpcClient.BlockNumber(ctx)
+				if err != nil {
+					syncMetrics.RPCError()
+				}
+				return head, err
+			}
+			return NewHeadWatcher(config.BlockChain, poll).Watch(ctx)
+		},
+		Next: dal.Block.GetNextHeight,
+		Fetch: func(ctx context.Context, height uint64) (*types.Block, error) {
+			return config.EthRpcClient.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+		},
+		Handle: HandleBlock,
+	}
+}
+
+// SyncTask 有新区块时同步到节点的最新高度, 直到 ctx 结束或遇到不可恢复的错误
+func SyncTask(ctx context.Context) (SyncState, error) {
+	return NewSyncer().Run(ctx)
+}
+
+// Run 处理 Heads 通知的每个高度, ctx 结束时返回 nil, 遇到不可恢复的错误时返回该错误
+func (s *Syncer) Run(ctx context.Context) (SyncState, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for head := range s.Heads(ctx) {
+		s.state.Head = head
+		syncMetrics.SetHead(head)
+		if err := s.syncToHead(ctx, head); err != nil && ctx.Err() == nil {
+			s.state.Err = err
+			return s.state, err
+		}
+	}
+	return s.state, nil
+}
+
+// syncToHead 逐个处理区块直到 head
+func (s *Syncer) syncToHead(ctx context.Context, head uint64) error {
+	for ctx.Err() == nil {
+		done := false
+		err := s.retry(ctx, func() (err error) {
+			done, err = s.syncNext(ctx, head)
+			return err
+		})
+		if err != nil || done {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncNext 处理下一个区块, 已经同步到 head 时返回 true
+func (s *Syncer) syncNext(ctx context.Context, head uint64) (bool, error) {
+	next, err := s.Next(ctx)
+	if err != nil {
+		return false, dbError("get next height", err)
+	}
+	s.state.Next = next
+	if next > 0 {
+		syncMetrics.SetIndexed(next - 1)
+	}
+	if next > head {
+		return true, nil
+	}
+
+	start := time.Now()
+	block, err := s.Fetch(ctx, next)
+	if err != nil {
+		syncMetrics.RPCError()
+		return false, rpcError(fmt.Sprintf("fetch block %d", next), err)
+	}
+	syncMetrics.ObserveStage(metrics.StageFetch, start)
+
+	log.WithField("height", block.NumberU64()).WithField("hash", block.Hash().Hex()).Info("sync block")
+	// 已经开始处理的区块不随 ctx 中断, 完成或者回滚
+	if err := s.Handle(context.WithoutCancel(ctx), block); err != nil {
+		return false, err
+	}
+	s.state.Blocks++
+	s.state.Next = next + 1
+	return false, nil
+}
+
+// Backfill 使用 ReindexBlock 处理 [from, to] 区间的区块, 已经入库的高度在同一个事务中替换
+func Backfill(ctx context.Context, from, to uint64) (SyncState, error) {
+	s := NewSyncer()
+	s.Handle = ReindexBlock
+	return s.Backfill(ctx, from, to)
+}
+
+// Backfill 依次处理 [from, to] 区间的区块, ctx 结束时返回 nil, 遇到不可恢复的错误时返回该错误
+func (s *Syncer) Backfill(ctx context.Context, from, to uint64) (SyncState, error) {
+	s.state.Head = to
+	for height := from; height <= to && ctx.Err() == nil; height++ {
+		s.state.Next = height
+		err := s.retry(ctx, func() error {
+			block, err := s.Fetch(ctx, height)
+			if err != nil {
+				syncMetrics.RPCError()
+				return rpcError(fmt.Sprintf("fetch block %d", height), err)
+			}
+			return s.Handle(context.WithoutCancel(ctx), block)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			s.state.Err = err
+			return s.state, err
+		}
+		s.state.Blocks++
+		s.state.Next = height + 1
+	}
+	return s.state, nil
+}
+
+// retry 可重试的错误按指数退避重试, 返回不可恢复的错误或 ctx 的错误
+func (s *Syncer) retry(ctx context.Context, fn func() error) error {
+	backoff := s.InitialBackoff
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		kind := Classify(err)
+		if !kind.Retryable() {
+			log.WithField("err", err).Error("sync stopped by fatal error")
+			return err
+		}
+
+		s.state.Retries++
+		log.WithField("err", err).WithField("kind", kind).WithField("retry_in", backoff).Warn("sync failed, retrying")
+		sleepCtx(ctx, backoff)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, s.MaxBackoff)
+	}
+}
+
+// InitBlock 数据库中没有区块时写入节点的最新区块作为同步起点, RPC 和数据库错误会退避重试
+func InitBlock(ctx context.Context) error {
+	return NewSyncer().retry(ctx, func() error {
+		return initBlock(ctx)
+	})
+}
@@ -0,0 +1,208 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"unclassified", errors.New("bad log"), KindFatal},
+		{"rpc", rpcError("fetch", errors.New("timeout")), KindRPC},
+		{"wrapped rpc", fmt.Errorf("parse: %w", rpcError("receipt", errors.New("eof"))), KindRPC},
+		{"serialization", dbError("persist", &pgconn.PgError{Code: "40001"}), KindDBConflict},
+		{"unique", dbError("persist", fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505"})), KindDBConflict},
+		{"db", dbError("persist", &pgconn.PgError{Code: "08006"}), KindDB},
+		{"keep kind", dbError("persist", fatalError("hook", errors.New("panic"))), KindFatal},
+		{"decode", fatalError("parse", errors.New("invalid sender")), KindFatal},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("%s: Classify = %s, want %s", tt.name, got, tt.want)
+		}
+		if tt.want.Retryable() == (tt.want == KindFatal) {
+			t.Errorf("%s: Retryable = %v", tt.name, tt.want.Retryable())
+		}
+	}
+}
+
+// fakeChain 内存中的同步进度, failures 为每个高度依次返回的错误
+type fakeChain struct {
+	next     uint64
+	handled  []uint64
+	fetchErr map[uint64][]error
+	failures map[uint64][]error
+	// onHandle 处理区块时调用
+	onHandle func(ctx context.Context, height uint64)
+}
+
+func pop(errs map[uint64][]error, height uint64) error {
+	if len(errs[height]) == 0 {
+		return nil
+	}
+	err := errs[height][0]
+	errs[height] = errs[height][1:]
+	return err
+}
+
+func (c *fakeChain) syncer(heads ...uint64) *Syncer {
+	return &Syncer{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     4 * time.Millisecond,
+		Heads: func(ctx context.Context) <-chan uint64 {
+			ch := make(chan uint64, len(heads))
+			for _, h := range heads {
+				ch <- h
+			}
+			close(ch)
+			return ch
+		},
+		Next: func(context.Context) (uint64, error) { return c.next, nil },
+		Fetch: func(_ context.Context, height uint64) (*types.Block, error) {
+			if err := pop(c.fetchErr, height); err != nil {
+				return nil, err
+			}
+			return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(height)}), nil
+		},
+		Handle: func(ctx context.Context, block *types.Block) error {
+			height := block.NumberU64()
+			if c.onHandle != nil {
+				c.onHandle(ctx, height)
+			}
+			if err := pop(c.failures, height); err != nil {
+				return err
+			}
+			c.handled = append(c.handled, height)
+			c.next = height + 1
+			return nil
+		},
+	}
+}
+
+func TestSyncerRetry(t *testing.T) {
+	c := &fakeChain{
+		next:     1,
+		fetchErr: map[uint64][]error{2: {errors.New("connection refused"), errors.New("timeout")}},
+		failures: map[uint64][]error{3: {dbError("persist", &pgconn.PgError{Code: "40P01"})}},
+	}
+	state, err := c.syncer(3, 5).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(c.handled) != "[1 2 3 4 5]" {
+		t.Errorf("handled = %v", c.handled)
+	}
+	if state.Head != 5 || state.Next != 6 || state.Blocks != 5 || state.Retries != 3 || state.Err != nil {
+		t.Errorf("state = %+v", state)
+	}
+}
+
+func TestSyncerFatal(t *testing.T) {
+	c := &fakeChain{
+		next:     1,
+		failures: map[uint64][]error{3: {fatalError("parse block 3", errors.New("abi: cannot unmarshal"))}},
+	}
+	state, err := c.syncer(5).Run(context.Background())
+	if Classify(err) != KindFatal || err == nil {
+		t.Fatalf("err = %v", err)
+	}
+	if fmt.Sprint(c.handled) != "[1 2]" || state.Next != 3 || state.Blocks != 2 || state.Retries != 0 || state.Err != err {
+		t.Errorf("handled = %v, state = %+v", c.handled, state)
+	}
+}
+
+func TestSyncerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := &fakeChain{next: 1}
+	c.onHandle = func(hctx context.Context, height uint64) {
+		if height == 2 {
+			cancel()
+		}
+		if hctx.Err() != nil {
+			t.Errorf("block %d handled with canceled context", height)
+		}
+	}
+	state, err := c.syncer(5).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 取消时正在处理的区块完成后停止
+	if fmt.Sprint(c.handled) != "[1 2]" || state.Next != 3 || state.Err != nil {
+		t.Errorf("handled = %v, state = %+v", c.handled, state)
+	}
+
+	// 退避等待中取消时立即返回
+	ctx, cancel = context.WithCancel(context.Background())
+	c = &fakeChain{next: 1, fetchErr: map[uint64][]error{1: {errors.New("timeout")}}}
+	s := c.syncer(5)
+	s.InitialBackoff = time.Hour
+	time.AfterFunc(10*time.Millisecond, cancel)
+	done := make(chan struct{})
+	go func() {
+		state, err = s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	if err != nil || state.Retries != 1 || len(c.handled) != 0 {
+		t.Errorf("err = %v, state = %+v", err, state)
+	}
+}
+
+func TestSyncerBackfill(t *testing.T) {
+	c := &fakeChain{
+		next:     100,
+		fetchErr: map[uint64][]error{3: {errors.New("timeout")}},
+		failures: map[uint64][]error{4: {dbError("persist", &pgconn.PgError{Code: "23505"})}},
+	}
+	state, err := c.syncer().Backfill(context.Background(), 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(c.handled) != "[2 3 4 5]" || state.Blocks != 4 || state.Next != 6 || state.Retries != 2 {
+		t.Errorf("handled = %v, state = %+v", c.handled, state)
+	}
+
+	c = &fakeChain{failures: map[uint64][]error{3: {fatalError("parse block 3", errors.New("bad log"))}}}
+	state, err = c.syncer().Backfill(context.Background(), 2, 5)
+	if err == nil || fmt.Sprint(c.handled) != "[2]" || state.Next != 3 || state.Err != err {
+		t.Errorf("err = %v, handled = %v, state = %+v", err, c.handled, state)
+	}
+}
+
+func TestBlockDataReorged(t *testing.T) {
+	tests := []struct {
+		name     string
+		replaced *ReplacedBlock
+		want     bool
+	}{
+		{name: "new height", replaced: nil, want: false},
+		{name: "backfill of the same block", replaced: &ReplacedBlock{Block: models.Block{BlockHash: "0xa"}}, want: false},
+		{name: "reorg", replaced: &ReplacedBlock{Block: models.Block{BlockHash: "0xb"}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &BlockData{Block: models.Block{BlockHash: "0xa"}, Reindexed: true, Replaced: tt.replaced}
+			if got := data.Reorged(); got != tt.want {
+				t.Errorf("Reorged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
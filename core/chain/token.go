@@ -2,6 +2,7 @@ package chain
 
 import (
 	"math/big"
+	"sort"
 	"strings"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -86,27 +87,58 @@ func ParseTokenTransfers(logs []models.Event) (tokenTransfers TokenTransfers, er
 	return
 }
 
+// parseOrder 按固定顺序解析, 保证结果稳定
+var parseOrder = []string{common.ERC20, common.WETH, common.ERC721, common.ERC1155, common.CryptoPunks, common.MoonCats}
+
+// doParse 解析日志中所有类型的 token 转移, 同一交易可以同时包含 ERC20 和 NFT 的转移,
+// Tokens 按 parseOrder 排列, TokenTransfers 按区块和日志顺序排列
 func doParse(logs []models.Event, acc TokenTransfers) (TokenTransfers, error) {
+	var err error
 	filteredLogs := filterLogs(logs)
-	for tokenType, val := range filteredLogs {
+	for _, tokenType := range parseOrder {
+		val, ok := filteredLogs[tokenType]
+		if !ok {
+			continue
+		}
+
 		switch tokenType {
 		case common.ERC20:
-			return doParseErc20(val, acc)
+			acc, err = doParseErc20(val, acc)
 		case common.WETH:
-			return doParseWTH(val, acc)
+			acc, err = doParseWTH(val, acc)
 		case common.ERC721:
-			return doParseErc721(val, acc), nil
+			acc = doParseErc721(val, acc)
 		case common.ERC1155:
-			return doParseErc1155(val, acc)
+			acc, err = doParseErc1155(val, acc)
+		case common.CryptoPunks:
+			acc, err = doParseCryptoPunks(val, acc)
+		case common.MoonCats:
+			acc = doParseMoonCats(val, acc)
+		}
+		if err != nil {
+			return acc, err
 		}
 	}
 
+	sort.SliceStable(acc.TokenTransfers, func(i, j int) bool {
+		a, b := acc.TokenTransfers[i], acc.TokenTransfers[j]
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		return a.LogIndex < b.LogIndex
+	})
+
 	return acc, nil
 }
 
 func filterLogs(logs []models.Event) map[string][]models.Event {
 	filteredLogs := map[string][]models.Event{}
 	for _, log := range logs {
+		if nftType, ok := legacyNftType(log); ok {
+			filteredLogs[nftType] = append(filteredLogs[nftType], log)
+			continue
+		}
+
 		if log.FirstTopic == common.ERC20TokenTransferEventFuncSign {
 			if log.FourthTopic == "" {
 				filteredLogs[common.ERC20] = append(filteredLogs[common.ERC20], log)
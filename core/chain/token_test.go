@@ -289,6 +289,72 @@ func Test_doParse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "test parse 20 and 721 in one tx",
+			args: args{
+				logs: []models.Event{
+					{
+						Address:     "0xC6CA7be41Ba10a3645988B77a523231666540b82",
+						FirstTopic:  common.ERC20TokenTransferEventFuncSign,
+						SecondTopic: "0x000000000000000000000000c904a40ed8656ea828f13d3720bcb1f8aff46098",
+						ThirdTopic:  "0x0000000000000000000000006c8f9a46294f7e279f23cf2d7900e07f98be0aee",
+						FourthTopic: "0x0000000000000000000000000000000000000000000000000000000000045ece",
+						Data:        "0x",
+						BlockNumber: 10010,
+						TxHash:      "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020",
+						BlockHash:   "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3",
+						LogIndex:    0,
+					},
+					{
+						Address:     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+						FirstTopic:  common.ERC20TokenTransferEventFuncSign,
+						SecondTopic: "0x0000000000000000000000006c8f9a46294f7e279f23cf2d7900e07f98be0aee",
+						ThirdTopic:  "0x000000000000000000000000c904a40ed8656ea828f13d3720bcb1f8aff46098",
+						Data:        "0x00000000000000000000000000000000000000000000000000000000000a2c2a",
+						BlockNumber: 10010,
+						TxHash:      "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020",
+						BlockHash:   "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3",
+						LogIndex:    1,
+					},
+				},
+				acc: TokenTransfers{},
+			},
+			want: TokenTransfers{
+				Tokens: []models.Token{
+					{
+						Type:            common.ERC20,
+						ContractAddress: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+					},
+					{
+						Type:            common.ERC721,
+						ContractAddress: "0xC6CA7be41Ba10a3645988B77a523231666540b82",
+					},
+				},
+				TokenTransfers: []models.TokenTransfer{
+					{
+						TransactionHash:      "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020",
+						LogIndex:             0,
+						FromAddress:          "0xc904a40eD8656EA828F13D3720bcB1F8Aff46098",
+						ToAddress:            "0x6C8f9A46294f7e279F23cF2D7900E07F98be0Aee",
+						TokenContractAddress: "0xC6CA7be41Ba10a3645988B77a523231666540b82",
+						BlockNumber:          10010,
+						BlockHash:            "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3",
+						TokenId:              big.NewInt(286414),
+					},
+					{
+						TransactionHash:      "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020",
+						LogIndex:             1,
+						FromAddress:          "0x6C8f9A46294f7e279F23cF2D7900E07F98be0Aee",
+						ToAddress:            "0xc904a40eD8656EA828F13D3720bcB1F8Aff46098",
+						TokenContractAddress: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+						BlockNumber:          10010,
+						BlockHash:            "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3",
+						Amount:               big.NewInt(666666),
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "test parse token unkown",
 			args: args{
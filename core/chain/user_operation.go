@@ -0,0 +1,96 @@
+package chain
+
+import (
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+/*
+## Overview
+  ERC-4337 的 UserOperation 由 bundler 打包在 EntryPoint.handleOps 交易中执行,
+  链上只能看到 bundler 的交易, 每个 UserOperation 的结果通过 EntryPoint 的日志体现.
+
+  | Event                       | Topics                              | Data                                              |
+  |-----------------------------|-------------------------------------|---------------------------------------------------|
+  | `UserOperationEvent`        | userOpHash, sender, paymaster       | nonce, success, actualGasCost, actualGasUsed      |
+  | `AccountDeployed`           | userOpHash, sender                  | factory, paymaster                                |
+  | `UserOperationRevertReason` | userOpHash, sender                  | nonce, revertReason                               |
+
+  v0.6 和 v0.7 的这几个事件签名一致, 通过合约地址区分版本.
+*/
+
+var entryPointVersions = map[string]string{
+	strings.ToLower(common.EntryPointV06Address): common.EntryPointV06,
+	strings.ToLower(common.EntryPointV07Address): common.EntryPointV07,
+}
+
+// ParseUserOperations 从区块日志中解析 UserOperation, 并关联所属的 bundler 交易
+func ParseUserOperations(logs []models.Event, trxs []models.Transaction) ([]models.UserOperation, error) {
+	factories := make(map[string]string)
+	revertReasons := make(map[string]string)
+	var opLogs []models.Event
+	for _, log := range logs {
+		if _, ok := entryPointVersions[strings.ToLower(log.Address)]; !ok || log.Removed {
+			continue
+		}
+
+		switch log.FirstTopic {
+		case common.UserOperationEventSignature:
+			opLogs = append(opLogs, log)
+		case common.AccountDeployedSignature:
+			factory, _, err := abi.ParseAccountDeployedLog(ethcommon.FromHex(log.Data))
+			if err != nil {
+				warnSkipLog(log, err)
+				continue
+			}
+			factories[log.SecondTopic] = factory.Hex()
+		case common.UserOperationRevertReasonSignature:
+			_, reason, err := abi.ParseUserOperationRevertReasonLog(ethcommon.FromHex(log.Data))
+			if err != nil {
+				warnSkipLog(log, err)
+				continue
+			}
+			revertReasons[log.SecondTopic] = ethcommon.Bytes2Hex(reason)
+		}
+	}
+
+	bundlers := make(map[string]string, len(trxs))
+	for _, trx := range trxs {
+		bundlers[trx.TxHash] = trx.From
+	}
+
+	userOps := make([]models.UserOperation, 0, len(opLogs))
+	for _, log := range opLogs {
+		event, err := abi.ParseUserOperationEventLog(ethcommon.FromHex(log.Data))
+		if err != nil {
+			warnSkipLog(log, err)
+			continue
+		}
+
+		userOps = append(userOps, models.UserOperation{
+			UserOpHash:        log.SecondTopic,
+			Sender:            ethcommon.HexToAddress(log.ThirdTopic).String(),
+			Paymaster:         ethcommon.HexToAddress(log.FourthTopic).String(),
+			Nonce:             event.Nonce,
+			Success:           event.Success,
+			ActualGasCost:     event.ActualGasCost,
+			ActualGasUsed:     event.ActualGasUsed,
+			Factory:           factories[log.SecondTopic],
+			RevertReason:      revertReasons[log.SecondTopic],
+			EntryPoint:        log.Address,
+			EntryPointVersion: entryPointVersions[strings.ToLower(log.Address)],
+			Bundler:           bundlers[log.TxHash],
+			TransactionHash:   log.TxHash,
+			BlockNumber:       log.BlockNumber,
+			BlockHash:         log.BlockHash,
+			LogIndex:          log.LogIndex,
+		})
+	}
+
+	return userOps, nil
+}
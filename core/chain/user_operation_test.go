@@ -0,0 +1,177 @@
+package chain
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi/entrypoint"
+)
+
+func packEntryPointLog(t *testing.T, name string, args ...interface{}) string {
+	contractAbi, err := abi.JSON(strings.NewReader(entrypoint.EntryPointABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := contractAbi.Events[name].Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ethcommon.Bytes2Hex(data)
+}
+
+func TestParseUserOperations(t *testing.T) {
+	userOpHash := "0x6d1f3b6e1c5bb00d5d7bdd5e0a3aa7e6a1a0b0c4a0f6d8f0c8b3e1a3b3c4d5e6"
+	failedOpHash := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	sender := "0x8b736035BbDA71825e0219f5FE4DfB22C35FbDDC"
+	paymaster := "0x4D8834907CEE521D08A3fC77478A0fbc7c94FD0a"
+	factory := "0x9406Cc6185a346906296840746125a0E44976454"
+	bundler := "0x2C0e7e53FBB9d1aB31f2E39F7E4C2E3AF1b24d8e"
+	txHash := "0xdc10d88baa62afce2005b3423875a7c6c5a73003e0513e505025a56258870020"
+	blockHash := "0x350479050cc11e6cf26a65d3b43dfd1a68194eeb1f6128d74901447b83770ad3"
+	senderTopic := ethcommon.BytesToHash(ethcommon.HexToAddress(sender).Bytes()).Hex()
+	paymasterTopic := ethcommon.BytesToHash(ethcommon.HexToAddress(paymaster).Bytes()).Hex()
+	zeroTopic := ethcommon.Hash{}.Hex()
+
+	logs := []models.Event{
+		{
+			Address:     common.EntryPointV06Address,
+			FirstTopic:  common.AccountDeployedSignature,
+			SecondTopic: userOpHash,
+			ThirdTopic:  senderTopic,
+			Data:        packEntryPointLog(t, "AccountDeployed", ethcommon.HexToAddress(factory), ethcommon.HexToAddress(paymaster)),
+			BlockNumber: 100, TxHash: txHash, BlockHash: blockHash, LogIndex: 1,
+		},
+		{
+			Address:     common.EntryPointV06Address,
+			FirstTopic:  common.UserOperationEventSignature,
+			SecondTopic: userOpHash,
+			ThirdTopic:  senderTopic,
+			FourthTopic: paymasterTopic,
+			Data:        packEntryPointLog(t, "UserOperationEvent", big.NewInt(0), true, big.NewInt(123456), big.NewInt(7890)),
+			BlockNumber: 100, TxHash: txHash, BlockHash: blockHash, LogIndex: 2,
+		},
+		{
+			Address:     common.EntryPointV07Address,
+			FirstTopic:  common.UserOperationRevertReasonSignature,
+			SecondTopic: failedOpHash,
+			ThirdTopic:  senderTopic,
+			Data:        packEntryPointLog(t, "UserOperationRevertReason", big.NewInt(1), []byte{0xde, 0xad}),
+			BlockNumber: 100, TxHash: txHash, BlockHash: blockHash, LogIndex: 3,
+		},
+		{
+			Address:     common.EntryPointV07Address,
+			FirstTopic:  common.UserOperationEventSignature,
+			SecondTopic: failedOpHash,
+			ThirdTopic:  senderTopic,
+			FourthTopic: zeroTopic,
+			Data:        packEntryPointLog(t, "UserOperationEvent", big.NewInt(1), false, big.NewInt(100), big.NewInt(10)),
+			BlockNumber: 100, TxHash: txHash, BlockHash: blockHash, LogIndex: 4,
+		},
+		{
+			// 不是 EntryPoint 合约发出的同名事件需要忽略
+			Address:     "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4",
+			FirstTopic:  common.UserOperationEventSignature,
+			SecondTopic: userOpHash,
+			ThirdTopic:  senderTopic,
+			FourthTopic: zeroTopic,
+			Data:        packEntryPointLog(t, "UserOperationEvent", big.NewInt(5), true, big.NewInt(1), big.NewInt(1)),
+			BlockNumber: 100, TxHash: txHash, BlockHash: blockHash, LogIndex: 5,
+		},
+	}
+	trxs := []models.Transaction{{TxHash: txHash, From: bundler, Contract: common.EntryPointV06Address}}
+
+	want := []models.UserOperation{
+		{
+			UserOpHash:        userOpHash,
+			Sender:            sender,
+			Paymaster:         paymaster,
+			Nonce:             big.NewInt(0),
+			Success:           true,
+			ActualGasCost:     big.NewInt(123456),
+			ActualGasUsed:     big.NewInt(7890),
+			Factory:           factory,
+			EntryPoint:        common.EntryPointV06Address,
+			EntryPointVersion: common.EntryPointV06,
+			Bundler:           bundler,
+			TransactionHash:   txHash,
+			BlockNumber:       100,
+			BlockHash:         blockHash,
+			LogIndex:          2,
+		},
+		{
+			UserOpHash:        failedOpHash,
+			Sender:            sender,
+			Paymaster:         common.ZeroAddress,
+			Nonce:             big.NewInt(1),
+			Success:           false,
+			ActualGasCost:     big.NewInt(100),
+			ActualGasUsed:     big.NewInt(10),
+			RevertReason:      "dead",
+			EntryPoint:        common.EntryPointV07Address,
+			EntryPointVersion: common.EntryPointV07,
+			Bundler:           bundler,
+			TransactionHash:   txHash,
+			BlockNumber:       100,
+			BlockHash:         blockHash,
+			LogIndex:          4,
+		},
+	}
+
+	got, err := ParseUserOperations(logs, trxs)
+	if err != nil {
+		t.Fatalf("ParseUserOperations() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseUserOperations() got %d ops, want %d", len(got), len(want))
+	}
+	for i := range got {
+		// big.Int 的内部表示可能不同, 数值单独比较
+		for _, pair := range [][2]*big.Int{
+			{got[i].Nonce, want[i].Nonce},
+			{got[i].ActualGasCost, want[i].ActualGasCost},
+			{got[i].ActualGasUsed, want[i].ActualGasUsed},
+		} {
+			if pair[0].Cmp(pair[1]) != 0 {
+				t.Errorf("ParseUserOperations()[%d] got = %v, want %v", i, pair[0], pair[1])
+			}
+		}
+		got[i].Nonce, got[i].ActualGasCost, got[i].ActualGasUsed = nil, nil, nil
+		want[i].Nonce, want[i].ActualGasCost, want[i].ActualGasUsed = nil, nil, nil
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("ParseUserOperations()[%d] got = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseUserOperationsSkipsMalformedLogs(t *testing.T) {
+	userOpHash := "0x6d1f3b6e1c5bb00d5d7bdd5e0a3aa7e6a1a0b0c4a0f6d8f0c8b3e1a3b3c4d5e6"
+	senderTopic := ethcommon.BytesToHash(ethcommon.HexToAddress("0x8b736035BbDA71825e0219f5FE4DfB22C35FbDDC").Bytes()).Hex()
+	event := func(topic, data string, logIndex uint) models.Event {
+		return models.Event{
+			Address: common.EntryPointV06Address, FirstTopic: topic, SecondTopic: userOpHash, ThirdTopic: senderTopic,
+			FourthTopic: ethcommon.Hash{}.Hex(), Data: data, TxHash: "0x01", BlockNumber: 100, LogIndex: logIndex,
+		}
+	}
+
+	logs := []models.Event{
+		event(common.AccountDeployedSignature, "", 1),
+		event(common.UserOperationRevertReasonSignature, "dead", 2),
+		event(common.UserOperationEventSignature, packEntryPointLog(t, "UserOperationEvent", big.NewInt(0), true, big.NewInt(1), big.NewInt(1))[:64], 3),
+		event(common.UserOperationEventSignature, packEntryPointLog(t, "UserOperationEvent", big.NewInt(0), true, big.NewInt(500), big.NewInt(1)), 4),
+	}
+
+	got, err := ParseUserOperations(logs, nil)
+	if err != nil {
+		t.Fatalf("ParseUserOperations() error = %v, malformed logs must be skipped", err)
+	}
+	if len(got) != 1 || got[0].LogIndex != 4 || got[0].ActualGasCost.Int64() != 500 || got[0].Factory != "" || got[0].RevertReason != "" {
+		t.Errorf("ParseUserOperations() = %+v, want only log 4", got)
+	}
+}
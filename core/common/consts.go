@@ -20,4 +20,99 @@ const (
 	ERC1155SingleTransferSignature  = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
 	ERC1155BatchTransferSignature   = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
 	TransferFunctionSignature       = "0xa9059cbb"
+
+	// ERC20 和 ERC721 的 Approval 签名相同, ERC721 的 tokenId 为第四个 topic
+	ApprovalSignature = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+	// ERC721 和 ERC1155 共用
+	ApprovalForAllSignature = "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31"
+)
+
+// ERC-4337 EntryPoint
+const (
+	EntryPointV06Address = "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+	EntryPointV07Address = "0x0000000071727De22E5E9d8BAf0edAc6f37da032"
+
+	EntryPointV06 = "v0.6"
+	EntryPointV07 = "v0.7"
+
+	UserOperationEventSignature        = "0x49628fd1471006c1482da88028e9ce4dbb080b815c9b0344d39e5a8e6ec1419f"
+	AccountDeployedSignature           = "0xd51a9c61267aa6196961883ecf5ff2da6619c37dac0fa92122513fb32c032d2d"
+	UserOperationRevertReasonSignature = "0x1c4fada7374c0a9ee8841fc38afe82932dc0f8e69012e927f061a8bae611a201"
+)
+
+// DEX
+const (
+	UniswapV2 = "uniswap_v2"
+	UniswapV3 = "uniswap_v3"
+
+	UniswapV2SwapSignature = "0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822"
+	UniswapV2SyncSignature = "0x1c411e9a96e071241c2f21f7726b17ae89e3cab4c78be50e062b03a9fffbbad1"
+	UniswapV3SwapSignature = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
+)
+
+// NFT marketplaces
+const (
+	Seaport   = "seaport"
+	Blur      = "blur"
+	LooksRare = "looksrare"
+	X2Y2      = "x2y2"
+
+	SeaportOrderFulfilledSignature   = "0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31"
+	BlurOrdersMatchedSignature       = "0x61cbb2a3dee0b6064c2e681aadd61677fb4ef319f0b547508d495626f5a62f64"
+	LooksRareTakerAskSignature       = "0x68cd251d4d267c6e2034ff0088b990352b97b2002c0476587d0c4da889c11330"
+	LooksRareTakerBidSignature       = "0x95fb6205e23ff6bda16a2d1dba56b9ad7c783f67c96fa149785052f47696f2be"
+	LooksRareRoyaltyPaymentSignature = "0x27c4f0403323142b599832f26acd21c74a9e5b809f2215726e244a4ac588cd7d"
+	X2Y2InventorySignature           = "0x3cbb63f144840e5b1b0a38a7c19211d2e89de4d7c5faf8b2d3c1776c302d1d33"
+
+	// 交易市场合约地址, 只解析这些合约发出的事件
+	SeaportV10Address        = "0x00000000006CEE72100D161c57ADA5bb2be1CA79"
+	SeaportV11Address        = "0x00000000006c3852cbEf3e08E8dF289169EdE581"
+	SeaportV13Address        = "0x0000000000000aD24e80fd803C6ac37206a45f15"
+	SeaportV14Address        = "0x00000000000001ad428e4906aE43D8F9852d0dD6"
+	SeaportV15Address        = "0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"
+	SeaportV16Address        = "0x0000000000000068F116a894984e2DB1123eB395"
+	BlurExchangeAddress      = "0x000000000000Ad05Ccc4F10045630fb830B95127"
+	LooksRareExchangeAddress = "0x59728544B08AB483533076417FbBB2fD0B17CE3a"
+	X2Y2ExchangeAddress      = "0x74312363e45DCaBA76c59ec49a7Aa8A65a67EeD3"
+
+	// OpenSea 在 Seaport 订单中收取平台费的地址
+	OpenSeaFeeRecipient = "0x0000a26b00c1F0DF003000390027140000fAa719"
+	// X2Y2 平台费接收地址
+	X2Y2FeeRecipient = "0xD823C605807cC5E6Bd6fC0d7e4Eea50d3e2d66cd"
+)
+
+// Legacy NFT, 早于 ERC-721 的合约, 转移不使用标准 Transfer 事件
+const (
+	CryptoPunks = "cryptopunks"
+	MoonCats    = "mooncats"
+
+	CryptoPunksAddress   = "0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB"
+	MoonCatRescueAddress = "0x60cd862c9C687A9dE49aecdC3A99b74A4fc54aB6"
+
+	PunkAssignSignature   = "0x8a0e37b73a0d9c82e205d4d1a3ff3d0b57ce5f4d7bccf6bac03336dc101cb7ba"
+	PunkTransferSignature = "0x05af636b70da6819000c49f85b21fa82081c632069bb626f30932034099107d8"
+	PunkBoughtSignature   = "0x58e5d5a525e3b40bc15abaa38b5882678db1ee68befd2f60bafe3a7fd06db9e3"
+	CatRescuedSignature   = "0x80d2c1a6c75f471130a64fd71b80dc7208f721037766fb7decf53e10f82211cd"
+	CatAdoptedSignature   = "0x1d9becf52be84ecb1e1e8de532c6cea871c0903fdcc9675123ca5c3c2cb43625"
+)
+
+// ENS
+const (
+	// ENSRegistryWithFallback, 主网和测试网地址相同
+	EnsRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+	EnsNewOwnerSignature       = "0xce0457fe73731f824cc272376169235128c118b49d344817417c6d108d155e82"
+	EnsNewResolverSignature    = "0x335721b01866dc23fbee8b6b2c7b1e14d6f05c28cd35a2c934239f94095602a0"
+	EnsTransferSignature       = "0xd4735d920b0f87494915f556dd9b54c8f309026070caea5c737245152564d266"
+	EnsAddrChangedSignature    = "0x52d7d861f09ab3d26239d492e8968629f95e9e318cf0b73bfddc441522a15fd2"
+	EnsNameChangedSignature    = "0xb7d29e911041e8d9b843369e890bcb72c9388692ba48b65ac54e7214c4c348f7"
+	EnsReverseClaimedSignature = "0x6ada868dd3058cf77a48a74489fd7963688e5464b2b0fa957ace976243270e92"
+)
+
+// Pending transaction status
+const (
+	PendingStatusPending  = "pending"
+	PendingStatusMined    = "mined"
+	PendingStatusReplaced = "replaced"
+	PendingStatusDropped  = "dropped"
 )
@@ -0,0 +1,86 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Admin *adminDal
+
+type adminDal struct{}
+
+func InitAdminDal() {
+	Admin = &adminDal{}
+}
+
+// ListLabels 获取地址的所有标签
+func (a *adminDal) ListLabels(ctx context.Context, address string) ([]models.AddressLabel, error) {
+	var labels []models.AddressLabel
+	if err := db.DBEngine.WithContext(ctx).Where("address = ?", address).
+		Order("label").Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// ListLabelsByAddresses 批量获取地址的标签
+func (a *adminDal) ListLabelsByAddresses(ctx context.Context, addresses []string) ([]models.AddressLabel, error) {
+	var labels []models.AddressLabel
+	if len(addresses) == 0 {
+		return labels, nil
+	}
+	if err := db.DBEngine.WithContext(ctx).Where("address IN ?", addresses).
+		Order("address, label").Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// GetLabel 获取地址的一个标签
+func (a *adminDal) GetLabel(ctx context.Context, address, label string) (*models.AddressLabel, error) {
+	var l models.AddressLabel
+	if err := db.DBEngine.WithContext(ctx).Where("address = ? AND label = ?", address, label).
+		Take(&l).Error; err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// UpsertLabel 添加标签, 已存在时更新名称和备注
+func (a *adminDal) UpsertLabel(ctx context.Context, label *models.AddressLabel) error {
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}, {Name: "label"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "note", "created_by", "updated_at"}),
+	}).Create(label).Error
+}
+
+// DeleteLabel 删除标签, 物理删除以便之后重新添加
+func (a *adminDal) DeleteLabel(ctx context.Context, address, label string) (int64, error) {
+	res := db.DBEngine.WithContext(ctx).Unscoped().
+		Where("address = ? AND label = ?", address, label).Delete(&models.AddressLabel{})
+	return res.RowsAffected, res.Error
+}
+
+func (a *adminDal) InsertAuditLog(ctx context.Context, log *models.AdminAuditLog) error {
+	return db.DBEngine.WithContext(ctx).Create(log).Error
+}
+
+// ListAuditLogs 按时间倒序查询操作记录, actor 和 target 为空时不过滤
+func (a *adminDal) ListAuditLogs(ctx context.Context, actor, target string, offset, limit int) ([]models.AdminAuditLog, error) {
+	tx := db.DBEngine.WithContext(ctx)
+	if actor != "" {
+		tx = tx.Where("actor = ?", actor)
+	}
+	if target != "" {
+		tx = tx.Where("target = ?", target)
+	}
+	var logs []models.AdminAuditLog
+	if err := tx.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,157 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Approval *approvalDal
+
+type approvalDal struct{}
+
+func InitApprovalDal() {
+	Approval = &approvalDal{}
+}
+
+// newerThan 只有更新的日志才能覆盖已有状态, 同一位置重新写入(重新同步区块)时覆盖
+func newerThan(table string) clause.Where {
+	return clause.Where{Exprs: []clause.Expression{gorm.Expr(
+		"excluded.block_number > " + table + ".block_number OR " +
+			"(excluded.block_number = " + table + ".block_number AND excluded.log_index >= " + table + ".log_index)",
+	)}}
+}
+
+// UpsertAllowances 更新授权额度, 同一批次中每个 (token, owner, spender) 只能出现一次
+func (a *approvalDal) UpsertAllowances(ctx context.Context, allowances []models.TokenAllowance) error {
+	if len(allowances) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token"}, {Name: "owner"}, {Name: "spender"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "unlimited", "transaction_hash",
+			"block_number", "block_hash", "log_index", "updated_at"}),
+		Where: newerThan("token_allowances"),
+	}).CreateInBatches(allowances, common.BatchSize).Error
+}
+
+// UpsertOperatorApprovals 更新 ApprovalForAll 状态, 同一批次中每个 (collection, owner, operator) 只能出现一次
+func (a *approvalDal) UpsertOperatorApprovals(ctx context.Context, approvals []models.OperatorApproval) error {
+	if len(approvals) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "collection"}, {Name: "owner"}, {Name: "operator"}},
+		DoUpdates: clause.AssignmentColumns([]string{"approved", "transaction_hash",
+			"block_number", "block_hash", "log_index", "updated_at"}),
+		Where: newerThan("operator_approvals"),
+	}).CreateInBatches(approvals, common.BatchSize).Error
+}
+
+// GetAllowancesByBlock 查询最后一次修改在指定高度的授权额度
+func (a *approvalDal) GetAllowancesByBlock(ctx context.Context, height uint64) ([]models.TokenAllowance, error) {
+	var allowances []models.TokenAllowance
+	if err := db.DBEngine.WithContext(ctx).Where("block_number = ?", height).
+		Order("log_index ASC").Find(&allowances).Error; err != nil {
+		return nil, err
+	}
+	return allowances, nil
+}
+
+// GetOperatorApprovalsByBlock 查询最后一次修改在指定高度的 ApprovalForAll 状态
+func (a *approvalDal) GetOperatorApprovalsByBlock(ctx context.Context, height uint64) ([]models.OperatorApproval, error) {
+	var approvals []models.OperatorApproval
+	if err := db.DBEngine.WithContext(ctx).Where("block_number = ?", height).
+		Order("log_index ASC").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// ReplaceAllowance 不比较新旧直接覆盖授权额度, 用于回滚到更早的状态
+func (a *approvalDal) ReplaceAllowance(ctx context.Context, allowance models.TokenAllowance) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.TokenAllowance{}).
+		Where("token = ? AND owner = ? AND spender = ?", allowance.Token, allowance.Owner, allowance.Spender).
+		Select("amount", "unlimited", "transaction_hash", "block_number", "block_hash", "log_index").
+		Updates(&allowance).Error
+}
+
+// ReplaceOperatorApproval 不比较新旧直接覆盖 ApprovalForAll 状态, 用于回滚到更早的状态
+func (a *approvalDal) ReplaceOperatorApproval(ctx context.Context, approval models.OperatorApproval) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.OperatorApproval{}).
+		Where("collection = ? AND owner = ? AND operator = ?", approval.Collection, approval.Owner, approval.Operator).
+		Select("approved", "transaction_hash", "block_number", "block_hash", "log_index").
+		Updates(&approval).Error
+}
+
+// DeleteAllowance 物理删除授权额度, 软删除的行仍然占用唯一索引
+func (a *approvalDal) DeleteAllowance(ctx context.Context, allowance models.TokenAllowance) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("token = ? AND owner = ? AND spender = ?", allowance.Token, allowance.Owner, allowance.Spender).
+		Delete(&models.TokenAllowance{}).Error
+}
+
+// DeleteOperatorApproval 物理删除 ApprovalForAll 状态
+func (a *approvalDal) DeleteOperatorApproval(ctx context.Context, approval models.OperatorApproval) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("collection = ? AND owner = ? AND operator = ?", approval.Collection, approval.Owner, approval.Operator).
+		Delete(&models.OperatorApproval{}).Error
+}
+
+// ListAllowances 查询授权额度, 参数为空时不过滤, 默认不返回已撤销的授权
+func (a *approvalDal) ListAllowances(ctx context.Context, owner, spender, token string, unlimitedOnly, includeRevoked bool, offset, limit int) ([]models.TokenAllowance, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.TokenAllowance{})
+	if owner != "" {
+		query = query.Where("owner = ?", owner)
+	}
+	if spender != "" {
+		query = query.Where("spender = ?", spender)
+	}
+	if token != "" {
+		query = query.Where("token = ?", token)
+	}
+	if unlimitedOnly {
+		query = query.Where("unlimited = ?", true)
+	}
+	if !includeRevoked {
+		query = query.Where("amount > 0")
+	}
+
+	var allowances []models.TokenAllowance
+	if err := query.Order("block_number DESC, log_index DESC").
+		Offset(offset).Limit(limit).Find(&allowances).Error; err != nil {
+		return nil, err
+	}
+	return allowances, nil
+}
+
+// ListOperatorApprovals 查询 ApprovalForAll 授权, 参数为空时不过滤, 默认不返回已撤销的授权
+func (a *approvalDal) ListOperatorApprovals(ctx context.Context, owner, operator, collection string, includeRevoked bool, offset, limit int) ([]models.OperatorApproval, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.OperatorApproval{})
+	if owner != "" {
+		query = query.Where("owner = ?", owner)
+	}
+	if operator != "" {
+		query = query.Where("operator = ?", operator)
+	}
+	if collection != "" {
+		query = query.Where("collection = ?", collection)
+	}
+	if !includeRevoked {
+		query = query.Where("approved = ?", true)
+	}
+
+	var approvals []models.OperatorApproval
+	if err := query.Order("block_number DESC, log_index DESC").
+		Offset(offset).Limit(limit).Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
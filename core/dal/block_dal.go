@@ -2,10 +2,13 @@ package dal
 
 import (
 	"context"
+	"math"
+	"time"
 
 	"github.com/traitmeta/gotos/lib/db"
 	"github.com/traitmeta/metago/core/common"
 	"github.com/traitmeta/metago/core/models"
+	"gorm.io/gorm"
 )
 
 var Block *blockDal
@@ -46,3 +49,126 @@ func (b *blockDal) GetLatest() (*models.Block, error) {
 	}
 	return block, nil
 }
+
+// GetByHeightRange 获取 [from, to] 高度区间内的区块, 同一高度重复写入时以最后一次为准
+func (b *blockDal) GetByHeightRange(ctx context.Context, from, to uint64) ([]models.Block, error) {
+	var blocks []models.Block
+	if err := db.DBEngine.WithContext(ctx).
+		Where("id IN (?)", latestBlockIds(from, to)).
+		Order("block_height ASC").Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// latestBlockIds [from, to] 区间内每个高度最后一次写入的区块 ID
+func latestBlockIds(from, to uint64) *gorm.DB {
+	return db.DBEngine.Model(&models.Block{}).Select("MAX(id)").
+		Where("block_height BETWEEN ? AND ?", from, to).Group("block_height")
+}
+
+// GetHeightRangeByTime 获取时间区间 [start, end) 内区块的最小和最大高度
+func (b *blockDal) GetHeightRangeByTime(ctx context.Context, start, end time.Time) (uint64, uint64, bool, error) {
+	var result struct {
+		MinHeight *uint64
+		MaxHeight *uint64
+	}
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Block{}).
+		Select("MIN(block_height) AS min_height, MAX(block_height) AS max_height").
+		Where("timestamp >= ? AND timestamp < ?", start, end).Scan(&result).Error; err != nil {
+		return 0, 0, false, err
+	}
+	if result.MinHeight == nil || result.MaxHeight == nil {
+		return 0, 0, false, nil
+	}
+	return *result.MinHeight, *result.MaxHeight, true, nil
+}
+
+// GetMaxHeight 获取已经入库的最大区块高度
+func (b *blockDal) GetMaxHeight(ctx context.Context) (uint64, error) {
+	return b.aggregateHeight(ctx, "MAX(block_height)")
+}
+
+// GetMinHeight 获取已经入库的最小区块高度
+func (b *blockDal) GetMinHeight(ctx context.Context) (uint64, error) {
+	return b.aggregateHeight(ctx, "MIN(block_height)")
+}
+
+func (b *blockDal) aggregateHeight(ctx context.Context, expr string) (uint64, error) {
+	var height *uint64
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Block{}).
+		Select(expr).Where("block_hash <> ''").Scan(&height).Error; err != nil {
+		return 0, err
+	}
+	if height == nil {
+		return 0, nil
+	}
+	return *height, nil
+}
+
+// canonicalHashes [from, to] 高度区间内以最后一次写入为准的区块哈希子查询, 用于排除被替换区块留下的数据
+func (b *blockDal) canonicalHashes(from, to uint64) *gorm.DB {
+	return db.DBEngine.Model(&models.Block{}).Select("block_hash").
+		Where("id IN (?)", latestBlockIds(from, to))
+}
+
+// GetByHeight 获取指定高度的区块, 同一高度重复写入时以最后一次为准
+func (b *blockDal) GetByHeight(ctx context.Context, height uint64) (*models.Block, error) {
+	var block models.Block
+	if err := db.DBEngine.WithContext(ctx).Where("block_height = ? AND block_hash <> ''", height).
+		Order("id DESC").Take(&block).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (b *blockDal) GetByHash(ctx context.Context, hash string) (*models.Block, error) {
+	var block models.Block
+	if err := db.DBEngine.WithContext(ctx).Where("block_hash = ?", hash).
+		Order("id DESC").Take(&block).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// GetNextHeight 获取下一个待同步的区块高度
+func (b *blockDal) GetNextHeight(ctx context.Context) (uint64, error) {
+	return b.aggregateHeight(ctx, "MAX(latest_block_height)")
+}
+
+// MarkRefetchNeeded 标记指定高度的区块需要重新拉取
+func (b *blockDal) MarkRefetchNeeded(ctx context.Context, heights []uint64) error {
+	if len(heights) == 0 {
+		return nil
+	}
+	return db.DBEngine.WithContext(ctx).Model(&models.Block{}).
+		Where("block_height IN ?", heights).Update("refetch_needed", true).Error
+}
+
+// GetRefetchNeeded 获取 [from, to] 高度区间内被标记为需要重新拉取的高度
+func (b *blockDal) GetRefetchNeeded(ctx context.Context, from, to uint64) ([]uint64, error) {
+	var heights []uint64
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Block{}).Distinct("block_height").
+		Where("block_height BETWEEN ? AND ? AND refetch_needed = ?", from, to, true).
+		Order("block_height ASC").Pluck("block_height", &heights).Error; err != nil {
+		return nil, err
+	}
+	return heights, nil
+}
+
+// DeleteByHeight 物理删除指定高度的全部区块记录
+func (b *blockDal) DeleteByHeight(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_height = ?", height).Delete(&models.Block{}).Error
+}
+
+// List 按高度倒序分页获取区块, 同一高度重复写入时以最后一次为准
+func (b *blockDal) List(ctx context.Context, offset, limit int) ([]models.Block, error) {
+	var blocks []models.Block
+	if err := db.DBEngine.WithContext(ctx).
+		Where("id IN (?) AND block_hash <> ''", latestBlockIds(0, math.MaxInt64)).
+		Order("block_height DESC").Offset(offset).Limit(limit).Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
@@ -0,0 +1,48 @@
+package dal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Cursor *cursorDal
+
+// NoBlockHash 游标推进到没有区块的区间时记录的占位哈希, 用于和尚未初始化的游标(空哈希)区分
+const NoBlockHash = "-"
+
+type cursorDal struct{}
+
+func InitCursorDal() {
+	Cursor = &cursorDal{}
+}
+
+// Get 获取任务游标, 不存在时返回高度为0的游标
+func (c *cursorDal) Get(ctx context.Context, name string) (*models.SyncCursor, error) {
+	var cursor models.SyncCursor
+	err := db.DBEngine.WithContext(ctx).Where("name = ?", name).Take(&cursor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.SyncCursor{Name: name}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (c *cursorDal) Upsert(ctx context.Context, name string, blockHeight uint64, blockHash string) error {
+	cursor := models.SyncCursor{
+		Name:        name,
+		BlockHeight: blockHeight,
+		BlockHash:   blockHash,
+	}
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_height", "block_hash", "updated_at"}),
+	}).Create(&cursor).Error
+}
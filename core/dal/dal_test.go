@@ -0,0 +1,265 @@
+package dal
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+// sqlRecorder 记录 DryRun 模式下生成的 SQL
+type sqlRecorder struct {
+	logger.Interface
+	sqls []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface { return r }
+
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.sqls = append(r.sqls, sql)
+}
+
+// dryRun 使用 postgres 方言的 DryRun 连接替换 db.DBEngine, 只生成 SQL 不访问数据库
+func dryRun(t *testing.T) *sqlRecorder {
+	t.Helper()
+	rec := &sqlRecorder{Interface: logger.Discard}
+	engine, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 rec,
+	})
+	if err != nil {
+		t.Fatalf("open dry run db: %v", err)
+	}
+	old := db.DBEngine
+	db.DBEngine = db.WarpDB{DB: engine}
+	t.Cleanup(func() { db.DBEngine = old })
+	return rec
+}
+
+func (r *sqlRecorder) last(t *testing.T) string {
+	t.Helper()
+	if len(r.sqls) == 0 {
+		t.Fatal("no sql recorded")
+	}
+	return r.sqls[len(r.sqls)-1]
+}
+
+func assertSQL(t *testing.T, sql string, parts ...string) {
+	t.Helper()
+	for _, part := range parts {
+		if !strings.Contains(sql, part) {
+			t.Errorf("sql %s\nmissing %s", sql, part)
+		}
+	}
+}
+
+func TestGetByBlockRangeUsesLatestBlocks(t *testing.T) {
+	ctx := context.Background()
+	latest := `block_hash IN (SELECT "block_hash" FROM "blocks" WHERE id IN (SELECT MAX(id) FROM "blocks" WHERE (block_height BETWEEN 10 AND 20) AND "blocks"."deleted_at" IS NULL GROUP BY "block_height")`
+	tests := []struct {
+		name  string
+		query func() error
+		parts []string
+	}{
+		{
+			name: "transactions",
+			query: func() error {
+				_, err := Transaction.GetByBlockRange(ctx, 10, 20)
+				return err
+			},
+			parts: []string{`SELECT MAX(id) FROM "transactions"`, latest, `GROUP BY block_hash, tx_index`},
+		},
+		{
+			name: "events",
+			query: func() error {
+				_, err := Event.GetByBlockRange(ctx, 10, 20)
+				return err
+			},
+			parts: []string{`SELECT MAX(id) FROM "events"`, latest, `GROUP BY block_hash, log_index`},
+		},
+		{
+			name: "user operations",
+			query: func() error {
+				_, err := UserOperation.GetByBlockRange(ctx, 10, 20)
+				return err
+			},
+			parts: []string{`SELECT MAX(id) FROM "user_operations"`, latest, `GROUP BY block_hash, log_index`},
+		},
+	}
+	InitTransactionDal()
+	InitEventDal()
+	InitUserOperationDal()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := dryRun(t)
+			if err := tt.query(); err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			assertSQL(t, rec.last(t), tt.parts...)
+		})
+	}
+}
+
+func TestWithTxJoinsContextTx(t *testing.T) {
+	dryRun(t)
+	tx := db.DBEngine.DB
+	ctx := context.WithValue(context.Background(), db.TxContext, tx)
+	called := false
+	err := WithTx(ctx, func(txCtx context.Context) error {
+		called = true
+		if got, _ := txCtx.Value(db.TxContext).(*gorm.DB); got != tx {
+			t.Error("WithTx() started a new tx instead of joining the one in ctx")
+		}
+		return nil
+	})
+	if err != nil || !called {
+		t.Fatalf("WithTx() = %v, called %v", err, called)
+	}
+}
+
+func TestRevertSQL(t *testing.T) {
+	ctx := context.Background()
+	const (
+		token = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+		owner = "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	)
+	tests := []struct {
+		name  string
+		query func() error
+		parts []string
+	}{
+		{
+			name: "replace allowance writes zero values",
+			query: func() error {
+				return Approval.ReplaceAllowance(ctx, models.TokenAllowance{Token: token, Owner: owner, Spender: owner, Amount: big.NewInt(0), BlockNumber: 9})
+			},
+			parts: []string{`UPDATE "token_allowances" SET`, `"amount"='0',"unlimited"=false,"transaction_hash"='',"block_number"=9,"block_hash"='',"log_index"=0`,
+				`WHERE (token = '` + token + `' AND owner = '` + owner + `' AND spender = '` + owner + `')`},
+		},
+		{
+			name: "replace operator approval writes revoked",
+			query: func() error {
+				return Approval.ReplaceOperatorApproval(ctx, models.OperatorApproval{Collection: token, Owner: owner, Operator: owner, BlockNumber: 9})
+			},
+			parts: []string{`UPDATE "operator_approvals" SET`, `"approved"=false`},
+		},
+		{
+			name: "delete allowance is physical",
+			query: func() error {
+				return Approval.DeleteAllowance(ctx, models.TokenAllowance{Token: token, Owner: owner, Spender: owner})
+			},
+			parts: []string{`DELETE FROM "token_allowances" WHERE token = '` + token + `'`},
+		},
+		{
+			name: "approval logs before height",
+			query: func() error {
+				_, err := Event.GetApprovalLogs(ctx, token, "0xsig", "0xowner", "0xspender", 20)
+				return err
+			},
+			parts: []string{`address = '` + token + `' AND first_topic = '0xsig' AND second_topic = '0xowner' AND third_topic = '0xspender' AND fourth_topic = ''`,
+				`block_number < 20`, `ORDER BY block_number ASC, log_index ASC`},
+		},
+		{
+			name: "reset ens names keeps plaintext",
+			query: func() error {
+				return Ens.ResetNames(ctx, []string{"0xnode"})
+			},
+			parts: []string{`UPDATE "ens_names" SET "address"='',"block_number"=0,"label_hash"='',"owner"='',"parent_node"='',"resolver"='',"transaction_hash"=''`,
+				`WHERE node IN ('0xnode')`},
+		},
+		{
+			name: "delete ens reverse records is physical",
+			query: func() error {
+				return Ens.DeleteReverses(ctx, []string{owner})
+			},
+			parts: []string{`DELETE FROM "ens_reverse_records" WHERE address IN ('` + owner + `')`},
+		},
+	}
+	InitApprovalDal()
+	InitEventDal()
+	InitEnsDal()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := dryRun(t)
+			if err := tt.query(); err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			sql := rec.last(t)
+			assertSQL(t, sql, tt.parts...)
+			if strings.Contains(sql, "deleted_at") && strings.HasPrefix(sql, "DELETE") {
+				t.Errorf("sql %s must not be a soft delete", sql)
+			}
+		})
+	}
+}
+
+// numericColumns 迁移脚本中表的 numeric 列
+func numericColumns(t *testing.T, migration, table string) map[string]bool {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("..", "..", "migrations", migration))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, body, ok := strings.Cut(string(content), `CREATE TABLE "`+table+`" (`)
+	if !ok {
+		t.Fatalf("table %s not found in %s", table, migration)
+	}
+	body, _, _ = strings.Cut(body, "\n)")
+	columns := map[string]bool{}
+	for _, m := range regexp.MustCompile(`"(\w+)" numeric`).FindAllStringSubmatch(body, -1) {
+		columns[m[1]] = true
+	}
+	return columns
+}
+
+func TestUpdatePriceNumericColumns(t *testing.T) {
+	numeric := numericColumns(t, "4.address_token.up.sql", "tokens")
+	if !numeric["fiat_value"] || !numeric["circulating_market_cap"] {
+		t.Fatalf("numeric columns of tokens = %v", numeric)
+	}
+	assignment := regexp.MustCompile(`"(\w+)"=('[^']*'|\w+)`)
+
+	tests := []struct {
+		name                 string
+		fiatValue, marketCap string
+		want                 []string
+	}{
+		{"priced", "1.0002", "25000000000", []string{`"fiat_value"='1.0002'`, `"circulating_market_cap"='25000000000'`}},
+		{"no market cap", "0.5", "", []string{`"fiat_value"='0.5'`, `"circulating_market_cap"=NULL`}},
+		{"unknown", "", "", []string{`"fiat_value"=NULL`, `"circulating_market_cap"=NULL`}},
+	}
+	InitTokenDal()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := dryRun(t)
+			if err := Token.UpdatePrice(context.Background(), "0xa0b8", tt.fiatValue, tt.marketCap); err != nil {
+				t.Fatal(err)
+			}
+			sql := rec.last(t)
+			assertSQL(t, sql, tt.want...)
+			set, _, _ := strings.Cut(sql, " WHERE ")
+			for _, m := range assignment.FindAllStringSubmatch(set, -1) {
+				if !numeric[m[1]] || m[2] == "NULL" {
+					continue
+				}
+				if _, ok := new(big.Float).SetString(strings.Trim(m[2], "'")); !ok {
+					t.Errorf("numeric column %s = %s, want a number or NULL", m[1], m[2])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var DexTrade *dexTradeDal
+
+type dexTradeDal struct{}
+
+func InitDexTradeDal() {
+	DexTrade = &dexTradeDal{}
+}
+
+func (d *dexTradeDal) Inserts(ctx context.Context, trades []models.DexTrade) error {
+	if err := db.DBEngine.WithContext(ctx).CreateInBatches(trades, common.BatchSize).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List 按交易池或发起地址查询, 参数为空时不过滤, 按区块倒序
+func (d *dexTradeDal) List(ctx context.Context, pool, trader string, offset, limit int) ([]models.DexTrade, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.DexTrade{})
+	if pool != "" {
+		query = query.Where("pool = ?", pool)
+	}
+	if trader != "" {
+		query = query.Where("trader = ?", trader)
+	}
+
+	var trades []models.DexTrade
+	if err := query.Order("block_number DESC, log_index DESC").
+		Offset(offset).Limit(limit).Find(&trades).Error; err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+func (d *dexTradeDal) GetByBlockRange(ctx context.Context, from, to uint64) ([]models.DexTrade, error) {
+	var trades []models.DexTrade
+	if err := db.DBEngine.WithContext(ctx).Where("block_number BETWEEN ? AND ?", from, to).
+		Order("block_number, log_index").Find(&trades).Error; err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// DeleteByBlockNumber 物理删除指定高度的全部DEX成交
+func (d *dexTradeDal) DeleteByBlockNumber(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_number = ?", height).Delete(&models.DexTrade{}).Error
+}
+
+// GetLatestBetween 获取两个代币之间最新的一笔成交, 不区分买卖方向
+func (d *dexTradeDal) GetLatestBetween(ctx context.Context, tokenA, tokenB string) (*models.DexTrade, error) {
+	var trade models.DexTrade
+	if err := db.DBEngine.WithContext(ctx).
+		Where("(token_in = ? AND token_out = ?) OR (token_in = ? AND token_out = ?)", tokenA, tokenB, tokenB, tokenA).
+		Order("block_number DESC, log_index DESC").Take(&trade).Error; err != nil {
+		return nil, err
+	}
+	return &trade, nil
+}
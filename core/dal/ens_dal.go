@@ -0,0 +1,105 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Ens *ensDal
+
+type ensDal struct{}
+
+func InitEnsDal() {
+	Ens = &ensDal{}
+}
+
+// GetName 按 namehash 查询节点
+func (e *ensDal) GetName(ctx context.Context, node string) (*models.EnsName, error) {
+	var name models.EnsName
+	if err := db.DBEngine.WithContext(ctx).Where("node = ?", node).Take(&name).Error; err != nil {
+		return nil, err
+	}
+	return &name, nil
+}
+
+// UpsertName 写入节点, 已存在时只更新 columns 中的字段
+func (e *ensDal) UpsertName(ctx context.Context, name *models.EnsName, columns ...string) error {
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "node"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, "updated_at")),
+	}).Create(name).Error
+}
+
+// GetReverse 查询地址的反向记录
+func (e *ensDal) GetReverse(ctx context.Context, address string) (*models.EnsReverseRecord, error) {
+	var record models.EnsReverseRecord
+	if err := db.DBEngine.WithContext(ctx).Where("address = ?", address).Take(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// UpsertReverse 写入反向记录, 已存在时只更新 columns 中的字段
+func (e *ensDal) UpsertReverse(ctx context.Context, record *models.EnsReverseRecord, columns ...string) error {
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, "updated_at")),
+	}).Create(record).Error
+}
+
+// UpdateReverseByNode 按反向节点更新反向记录, 返回更新的行数, 节点没有反向记录时为0
+func (e *ensDal) UpdateReverseByNode(ctx context.Context, node string, updates map[string]interface{}) (int64, error) {
+	result := db.DBEngine.WithContext(ctx).Model(&models.EnsReverseRecord{}).
+		Where("node = ?", node).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// ListReverseByNode 按反向节点查询反向记录
+func (e *ensDal) ListReverseByNode(ctx context.Context, node string) ([]models.EnsReverseRecord, error) {
+	var records []models.EnsReverseRecord
+	if err := db.DBEngine.WithContext(ctx).Where("node = ?", node).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ResetNames 清空节点的注册表和解析器状态, 名称明文只由 namehash 决定, 保留
+func (e *ensDal) ResetNames(ctx context.Context, nodes []string) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return db.DBEngine.WithContext(ctx).Model(&models.EnsName{}).Where("node IN ?", nodes).
+		Updates(map[string]interface{}{
+			"parent_node": "", "label_hash": "", "owner": "", "resolver": "", "address": "",
+			"transaction_hash": "", "block_number": 0,
+		}).Error
+}
+
+// DeleteReverses 物理删除地址的反向记录, 软删除的行仍然占用唯一索引
+func (e *ensDal) DeleteReverses(ctx context.Context, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("address IN ?", addresses).Delete(&models.EnsReverseRecord{}).Error
+}
+
+// ListPrimaryNames 批量查询地址的主名称, 只返回正向解析也指向该地址的反向记录
+func (e *ensDal) ListPrimaryNames(ctx context.Context, addresses []string) ([]models.EnsReverseRecord, error) {
+	var records []models.EnsReverseRecord
+	if len(addresses) == 0 {
+		return records, nil
+	}
+	if err := db.DBEngine.WithContext(ctx).Table("ens_reverse_records r").
+		Select("r.*").
+		Joins("JOIN ens_names n ON n.node = r.forward_node AND n.address = r.address AND n.deleted_at IS NULL").
+		Where("r.address IN ? AND r.name <> '' AND r.deleted_at IS NULL", addresses).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
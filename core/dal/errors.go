@@ -0,0 +1,15 @@
+package dal
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// IgnoreNotFound 把记录不存在转换为 nil, nil, 其余错误原样返回
+func IgnoreNotFound[T any](v *T, err error) (*T, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return v, err
+}
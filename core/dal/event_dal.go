@@ -38,3 +38,142 @@ func (e *eventDal) GetEventByTxHash(txHash string) (*models.Event, error) {
 	}
 	return &event, nil
 }
+
+// GetByBlockRange 获取 [from, to] 区块高度区间内的日志, 同一高度重复写入时以最后一次为准
+func (e *eventDal) GetByBlockRange(ctx context.Context, from, to uint64) ([]models.Event, error) {
+	var events []models.Event
+	if err := db.DBEngine.WithContext(ctx).
+		Where("id IN (?)", db.DBEngine.Model(&models.Event{}).Select("MAX(id)").
+			Where("block_number BETWEEN ? AND ?", from, to).
+			Where("block_hash IN (?)", Block.canonicalHashes(from, to)).
+			Group("block_hash, log_index")).
+		Order("block_number ASC, log_index ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetByTxHash 获取交易在指定区块中的日志
+func (e *eventDal) GetByTxHash(ctx context.Context, txHash, blockHash string) ([]models.Event, error) {
+	var events []models.Event
+	if err := db.DBEngine.WithContext(ctx).Where("tx_hash = ? AND block_hash = ?", txHash, blockHash).
+		Order("log_index ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LogFilter eth_getLogs 的查询条件, 指定 BlockHash 时忽略区块范围
+type LogFilter struct {
+	FromBlock uint64
+	ToBlock   uint64
+	BlockHash string
+	Addresses []string
+	// Topics 按位置匹配, 同一位置内为 OR, 空表示任意
+	Topics [][]string
+}
+
+var topicColumns = []string{"first_topic", "second_topic", "third_topic", "fourth_topic"}
+
+// GetLogs 按 eth_getLogs 语义查询日志, 区块范围内只返回最后一次写入的区块中的日志, limit 小于0时不限制条数
+func (e *eventDal) GetLogs(ctx context.Context, filter LogFilter, limit int) ([]models.Event, error) {
+	return e.ListLogs(ctx, filter, 0, limit)
+}
+
+// ListLogs 分页查询日志, 条件和 GetLogs 相同
+func (e *eventDal) ListLogs(ctx context.Context, filter LogFilter, offset, limit int) ([]models.Event, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.Event{}).Where("removed = ?", false)
+	if filter.BlockHash != "" {
+		query = query.Where("block_hash = ?", filter.BlockHash)
+	} else {
+		query = query.Where("block_number BETWEEN ? AND ?", filter.FromBlock, filter.ToBlock).
+			Where("block_hash IN (?)", Block.canonicalHashes(filter.FromBlock, filter.ToBlock))
+	}
+	if len(filter.Addresses) > 0 {
+		query = query.Where("address IN ?", filter.Addresses)
+	}
+	for i, topics := range filter.Topics {
+		if i < len(topicColumns) && len(topics) > 0 {
+			query = query.Where(topicColumns[i]+" IN ?", topics)
+		}
+	}
+
+	var events []models.Event
+	if err := query.Order("block_number ASC, log_index ASC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetByBlockNumber 获取指定高度已写入的全部日志
+func (e *eventDal) GetByBlockNumber(ctx context.Context, height uint64) ([]models.Event, error) {
+	var events []models.Event
+	if err := db.DBEngine.WithContext(ctx).Where("block_number = ?", height).
+		Order("log_index ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetApprovalLogs 获取 height 之前合约发出的某个 (owner, spender) 的三个 topic 的授权日志, 按时间顺序返回
+func (e *eventDal) GetApprovalLogs(ctx context.Context, contract, signature, owner, spender string, height uint64) ([]models.Event, error) {
+	var events []models.Event
+	if err := db.DBEngine.WithContext(ctx).
+		Where("address = ? AND first_topic = ? AND second_topic = ? AND third_topic = ? AND fourth_topic = ''",
+			contract, signature, owner, spender).
+		Where("block_number < ?", height).
+		Order("block_number ASC, log_index ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetLogsBySecondTopic 获取 height 之前事件签名在 signatures 中、第一个 indexed 参数在 topics 中的日志, 按时间顺序返回
+func (e *eventDal) GetLogsBySecondTopic(ctx context.Context, signatures, topics []string, height uint64) ([]models.Event, error) {
+	var events []models.Event
+	if len(signatures) == 0 || len(topics) == 0 {
+		return events, nil
+	}
+	if err := db.DBEngine.WithContext(ctx).
+		Where("first_topic IN ? AND second_topic IN ? AND block_number < ?", signatures, topics, height).
+		Order("block_number ASC, log_index ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetLogsByTopics 获取 height 之前前三个 topic 都匹配的日志, 按时间顺序返回
+func (e *eventDal) GetLogsByTopics(ctx context.Context, signature, second, third string, height uint64) ([]models.Event, error) {
+	var events []models.Event
+	if err := db.DBEngine.WithContext(ctx).
+		Where("first_topic = ? AND second_topic = ? AND third_topic = ? AND block_number < ?", signature, second, third, height).
+		Order("block_number ASC, log_index ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DeleteByBlockNumber 物理删除指定高度的全部日志
+func (e *eventDal) DeleteByBlockNumber(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_number = ?", height).Delete(&models.Event{}).Error
+}
+
+// CountByBlockRange 按区块哈希统计 [from, to] 高度区间内的日志数量
+func (e *eventDal) CountByBlockRange(ctx context.Context, from, to uint64) (map[string]int, error) {
+	var rows []struct {
+		BlockHash string
+		Count     int
+	}
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Event{}).
+		Select("block_hash, COUNT(*) AS count").
+		Where("block_number BETWEEN ? AND ?", from, to).
+		Group("block_hash").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.BlockHash] = row.Count
+	}
+	return counts, nil
+}
@@ -4,4 +4,17 @@ func Init() {
 	InitBlockDal()
 	InitTransactionDal()
 	InitEventDal()
+	InitTokenDal()
+	InitTokenTransferDal()
+	InitCursorDal()
+	InitStatsDal()
+	InitWatchlistDal()
+	InitUserOperationDal()
+	InitDexTradeDal()
+	InitNftSaleDal()
+	InitApprovalDal()
+	InitPendingTransactionDal()
+	InitAdminDal()
+	InitPriceDal()
+	InitEnsDal()
 }
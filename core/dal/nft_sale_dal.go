@@ -0,0 +1,58 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var NftSale *nftSaleDal
+
+type nftSaleDal struct{}
+
+func InitNftSaleDal() {
+	NftSale = &nftSaleDal{}
+}
+
+func (d *nftSaleDal) Inserts(ctx context.Context, sales []models.NftSale) error {
+	if err := db.DBEngine.WithContext(ctx).CreateInBatches(sales, common.BatchSize).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List 按 NFT 合约或买卖方查询, 参数为空时不过滤, 按区块倒序
+func (d *nftSaleDal) List(ctx context.Context, collection, account string, offset, limit int) ([]models.NftSale, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.NftSale{})
+	if collection != "" {
+		query = query.Where("collection = ?", collection)
+	}
+	if account != "" {
+		query = query.Where("seller = ? OR buyer = ?", account, account)
+	}
+
+	var sales []models.NftSale
+	if err := query.Order("block_number DESC, transfer_log_index DESC").
+		Offset(offset).Limit(limit).Find(&sales).Error; err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+func (d *nftSaleDal) GetByBlockRange(ctx context.Context, from, to uint64) ([]models.NftSale, error) {
+	var sales []models.NftSale
+	if err := db.DBEngine.WithContext(ctx).Where("block_number BETWEEN ? AND ?", from, to).
+		Order("block_number, transfer_log_index").Find(&sales).Error; err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// DeleteByBlockNumber 物理删除指定高度的全部NFT成交
+func (d *nftSaleDal) DeleteByBlockNumber(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_number = ?", height).Delete(&models.NftSale{}).Error
+}
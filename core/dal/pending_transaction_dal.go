@@ -0,0 +1,102 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var PendingTransaction *pendingTransactionDal
+
+type pendingTransactionDal struct{}
+
+func InitPendingTransactionDal() {
+	PendingTransaction = &pendingTransactionDal{}
+}
+
+// Inserts 已经存在的交易忽略, 保留第一次看到的时间
+func (p *pendingTransactionDal) Inserts(ctx context.Context, txs []models.PendingTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}},
+		DoNothing: true,
+	}).CreateInBatches(txs, common.BatchSize).Error
+}
+
+// MarkMined 标记上链的交易, 不在交易池表中的哈希忽略
+func (p *pendingTransactionDal) MarkMined(ctx context.Context, blockNumber uint64, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Model(&models.PendingTransaction{}).
+		Where("tx_hash IN ?", hashes).
+		Updates(map[string]interface{}{"status": common.PendingStatusMined, "block_number": blockNumber}).Error
+}
+
+// ListPendingBySenders 查询这些地址仍处于 pending 状态的交易
+func (p *pendingTransactionDal) ListPendingBySenders(ctx context.Context, senders []string) ([]models.PendingTransaction, error) {
+	if len(senders) == 0 {
+		return nil, nil
+	}
+
+	var txs []models.PendingTransaction
+	if err := db.DBEngine.WithContext(ctx).
+		Where("status = ? AND from_address IN ?", common.PendingStatusPending, senders).
+		Find(&txs).Error; err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// UpdateStatus 更新 pending 状态交易的状态, replacedBy 只在被替换时设置
+func (p *pendingTransactionDal) UpdateStatus(ctx context.Context, hashes []string, status, replacedBy string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Model(&models.PendingTransaction{}).
+		Where("status = ? AND tx_hash IN ?", common.PendingStatusPending, hashes).
+		Updates(map[string]interface{}{"status": status, "replaced_by": replacedBy}).Error
+}
+
+// MarkDropped 第一次看到的时间早于 before 且仍未上链的交易标记为丢弃
+func (p *pendingTransactionDal) MarkDropped(ctx context.Context, before time.Time) (int64, error) {
+	result := db.DBEngine.WithContext(ctx).Model(&models.PendingTransaction{}).
+		Where("status = ? AND first_seen < ?", common.PendingStatusPending, before).
+		Update("status", common.PendingStatusDropped)
+	return result.RowsAffected, result.Error
+}
+
+func (p *pendingTransactionDal) GetByHash(ctx context.Context, txHash string) (*models.PendingTransaction, error) {
+	var tx models.PendingTransaction
+	if err := db.DBEngine.WithContext(ctx).Where("tx_hash = ?", txHash).Take(&tx).Error; err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// List 按发送方和状态查询, 参数为空时不过滤, 按第一次看到的时间倒序
+func (p *pendingTransactionDal) List(ctx context.Context, from, status string, offset, limit int) ([]models.PendingTransaction, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.PendingTransaction{})
+	if from != "" {
+		query = query.Where("from_address = ?", from)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var txs []models.PendingTransaction
+	if err := query.Order("first_seen DESC, id DESC").Offset(offset).Limit(limit).Find(&txs).Error; err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
@@ -0,0 +1,36 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Price *priceDal
+
+type priceDal struct{}
+
+func InitPriceDal() {
+	Price = &priceDal{}
+}
+
+func (p *priceDal) Inserts(ctx context.Context, prices []models.TokenPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	return db.DBEngine.WithContext(ctx).CreateInBatches(prices, common.BatchSize).Error
+}
+
+// ListHistory 获取 Token 在 [from, to] 时间区间内的历史价格, 按时间倒序
+func (p *priceDal) ListHistory(ctx context.Context, token string, from, to time.Time, limit int) ([]models.TokenPrice, error) {
+	var prices []models.TokenPrice
+	if err := db.DBEngine.WithContext(ctx).Where("contract_address = ? AND fetched_at BETWEEN ? AND ?", token, from, to).
+		Order("fetched_at DESC").Limit(limit).Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
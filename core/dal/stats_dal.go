@@ -0,0 +1,105 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Stats *statsDal
+
+type statsDal struct{}
+
+func InitStatsDal() {
+	Stats = &statsDal{}
+}
+
+// AddDailyChainStats 以增量方式累加每日统计, 活跃地址数需要之后调用 RefreshActiveAddressCount
+func (s *statsDal) AddDailyChainStats(ctx context.Context, stats []models.DailyChainStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"block_count":          gorm.Expr("daily_chain_stats.block_count + excluded.block_count"),
+			"transaction_count":    gorm.Expr("daily_chain_stats.transaction_count + excluded.transaction_count"),
+			"new_contract_count":   gorm.Expr("daily_chain_stats.new_contract_count + excluded.new_contract_count"),
+			"token_transfer_count": gorm.Expr("daily_chain_stats.token_transfer_count + excluded.token_transfer_count"),
+			"gas_used":             gorm.Expr("daily_chain_stats.gas_used + excluded.gas_used"),
+			"updated_at":           gorm.Expr("excluded.updated_at"),
+		}),
+	}).CreateInBatches(stats, common.BatchSize).Error
+}
+
+// AddDailyTokenStats 以增量方式累加每日Token统计
+func (s *statsDal) AddDailyTokenStats(ctx context.Context, stats []models.DailyTokenStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}, {Name: "token_contract_address"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"transfer_count": gorm.Expr("daily_token_stats.transfer_count + excluded.transfer_count"),
+			"volume":         gorm.Expr("daily_token_stats.volume + excluded.volume"),
+			"updated_at":     gorm.Expr("excluded.updated_at"),
+		}),
+	}).CreateInBatches(stats, common.BatchSize).Error
+}
+
+// InsertActiveAddresses 记录每日出现过的地址, 已存在的忽略
+func (s *statsDal) InsertActiveAddresses(ctx context.Context, addresses []models.DailyActiveAddress) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(addresses, common.BatchSize).Error
+}
+
+// RefreshActiveAddressCount 根据 daily_active_addresses 重新计算某天的活跃地址数
+func (s *statsDal) RefreshActiveAddressCount(ctx context.Context, date time.Time) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.DailyChainStat{}).
+		Where("date = ?", date).
+		Update("active_address_count", db.DBEngine.Model(&models.DailyActiveAddress{}).
+			Select("COUNT(*)").Where("date = ?", date)).Error
+}
+
+// DeleteByDateRange 删除 [start, end) 区间内的所有统计数据, 用于重建
+func (s *statsDal) DeleteByDateRange(ctx context.Context, start, end time.Time) error {
+	tx := db.DBEngine.WithContext(ctx)
+	if err := tx.Unscoped().Where("date >= ? AND date < ?", start, end).Delete(&models.DailyChainStat{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("date >= ? AND date < ?", start, end).Delete(&models.DailyTokenStat{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("date >= ? AND date < ?", start, end).Delete(&models.DailyActiveAddress{}).Error
+}
+
+func (s *statsDal) GetDailyChainStats(ctx context.Context, start, end time.Time) ([]models.DailyChainStat, error) {
+	var stats []models.DailyChainStat
+	if err := db.DBEngine.WithContext(ctx).
+		Where("date >= ? AND date < ?", start, end).Order("date ASC").Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *statsDal) GetDailyTokenStats(ctx context.Context, tokenContractAddress string, start, end time.Time) ([]models.DailyTokenStat, error) {
+	var stats []models.DailyTokenStat
+	if err := db.DBEngine.WithContext(ctx).
+		Where("token_contract_address = ? AND date >= ? AND date < ?", tokenContractAddress, start, end).
+		Order("date ASC").Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 
 	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/common"
 	"github.com/traitmeta/metago/core/models"
 )
 
@@ -39,3 +42,72 @@ func (e *tokenDal) GetByContractHash(contractHash string) (*models.Token, error)
 	}
 	return &token, nil
 }
+
+// GetByContractAddress 获取 Token 元信息
+func (e *tokenDal) GetByContractAddress(ctx context.Context, contractAddress string) (*models.Token, error) {
+	var token models.Token
+	if err := db.DBEngine.WithContext(ctx).Where("contract_address = ?", contractAddress).Take(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Update 按合约地址更新 Token 的指定字段
+func (e *tokenDal) Update(ctx context.Context, contractAddress string, updates map[string]interface{}) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.Token{}).
+		Where("contract_address = ?", contractAddress).Updates(updates).Error
+}
+
+// GetByContractAddresses 批量获取 Token 元信息
+func (e *tokenDal) GetByContractAddresses(ctx context.Context, contractAddresses []string) ([]models.Token, error) {
+	var tokens []models.Token
+	if len(contractAddresses) == 0 {
+		return tokens, nil
+	}
+	if err := db.DBEngine.WithContext(ctx).Where("contract_address IN ?", contractAddresses).Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// ListPriceable 按 id 分批获取需要更新价格的 ERC-20 Token, 排除垃圾 Token
+func (e *tokenDal) ListPriceable(ctx context.Context, afterId uint, limit int) ([]models.Token, error) {
+	var tokens []models.Token
+	if err := db.DBEngine.WithContext(ctx).Where("id > ? AND type = ? AND spam = ?", afterId, common.ERC20, false).
+		Order("id").Limit(limit).Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// UpdatePrice 更新 Token 的美元价格和市值, 空字符串表示未知, 写为 NULL
+func (e *tokenDal) UpdatePrice(ctx context.Context, contractAddress, fiatValue, marketCap string) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.Token{}).Where("contract_address = ?", contractAddress).
+		Updates(map[string]interface{}{"fiat_value": nullIfEmpty(fiatValue), "circulating_market_cap": nullIfEmpty(marketCap)}).Error
+}
+
+// nullIfEmpty numeric 列不接受空字符串, 未知的值写为 NULL
+func nullIfEmpty(v string) interface{} {
+	if v == "" {
+		return gorm.Expr("NULL")
+	}
+	return v
+}
+
+// TokenMatch 全文检索命中的 Token 及其文本匹配度
+type TokenMatch struct {
+	models.Token
+	Rank float64
+}
+
+// Search 按全文检索匹配 Token 的 symbol 和 name, query 为 to_tsquery 语法, 按匹配度和持有人数排序, 排除垃圾 Token
+func (e *tokenDal) Search(ctx context.Context, query string, limit int) ([]TokenMatch, error) {
+	var matches []TokenMatch
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Token{}).
+		Select("tokens.*, ts_rank("+models.TokenSearchDocument+", to_tsquery('english', ?)) AS rank", query).
+		Where(models.TokenSearchDocument+" @@ to_tsquery('english', ?)", query).Where("spam = ?", false).
+		Order("rank DESC, holder_count DESC").Limit(limit).Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,117 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var TokenTransfer *tokenTransferDal
+
+type tokenTransferDal struct{}
+
+func InitTokenTransferDal() {
+	TokenTransfer = &tokenTransferDal{}
+}
+
+func (t *tokenTransferDal) Inserts(ctx context.Context, transfers []models.TokenTransfer) error {
+	if err := db.DBEngine.WithContext(ctx).CreateInBatches(transfers, common.BatchSize).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetByBlockRange 获取 [from, to] 区块高度区间内的Token转移
+func (t *tokenTransferDal) GetByBlockRange(ctx context.Context, from, to uint64) ([]models.TokenTransfer, error) {
+	var transfers []models.TokenTransfer
+	if err := db.DBEngine.WithContext(ctx).
+		Where("block_number BETWEEN ? AND ?", from, to).
+		Order("block_number ASC, log_index ASC").Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// FindByTokenInBatches 分批读取某个 Token 在 height 及之前的全部转移, 同一高度重复写入时只读取最后一次写入的区块
+func (t *tokenTransferDal) FindByTokenInBatches(ctx context.Context, token string, height uint64, fn func([]models.TokenTransfer) error) error {
+	var transfers []models.TokenTransfer
+	return db.DBEngine.WithContext(ctx).
+		Where("token_contract_address = ? AND block_number <= ?", token, height).
+		Where("block_hash IN (?)", Block.canonicalHashes(0, height)).
+		FindInBatches(&transfers, common.BatchSize, func(tx *gorm.DB, batch int) error {
+			return fn(transfers)
+		}).Error
+}
+
+// DeleteByBlockNumber 物理删除指定高度的全部Token转移
+func (t *tokenTransferDal) DeleteByBlockNumber(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_number = ?", height).Delete(&models.TokenTransfer{}).Error
+}
+
+// GetByTxHash 获取交易在指定区块中的Token转移
+func (t *tokenTransferDal) GetByTxHash(ctx context.Context, txHash, blockHash string) ([]models.TokenTransfer, error) {
+	var transfers []models.TokenTransfer
+	if err := db.DBEngine.WithContext(ctx).Where("transaction_hash = ? AND block_hash = ?", txHash, blockHash).
+		Order("log_index ASC").Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// ListByToken 分页获取 Token 的转移, 按区块高度倒序
+func (t *tokenTransferDal) ListByToken(ctx context.Context, token string, offset, limit int) ([]models.TokenTransfer, error) {
+	var transfers []models.TokenTransfer
+	if err := db.DBEngine.WithContext(ctx).Where("token_contract_address = ?", token).
+		Order("block_number DESC, log_index DESC").Offset(offset).Limit(limit).Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// ListByAddress 分页获取地址转出或转入的 Token 转移, 按区块高度倒序
+func (t *tokenTransferDal) ListByAddress(ctx context.Context, address string, offset, limit int) ([]models.TokenTransfer, error) {
+	var transfers []models.TokenTransfer
+	if err := db.DBEngine.WithContext(ctx).Where("from_address = ? OR to_address = ?", address, address).
+		Order("block_number DESC, log_index DESC").Offset(offset).Limit(limit).Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// CountByAddress 统计地址转出或转入的 Token 转移数量
+func (t *tokenTransferDal) CountByAddress(ctx context.Context, address string) (int64, error) {
+	var count int64
+	if err := db.DBEngine.WithContext(ctx).Model(&models.TokenTransfer{}).
+		Where("from_address = ? OR to_address = ?", address, address).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// TokenBalance 由转移记录累加得到的 ERC-20 余额
+type TokenBalance struct {
+	Token   string
+	Balance string
+}
+
+// SumBalancesByAddress 按 Token 汇总地址转入减转出的 ERC-20 数量, 余额为0的 Token 不返回
+func (t *tokenTransferDal) SumBalancesByAddress(ctx context.Context, address string) ([]TokenBalance, error) {
+	var balances []TokenBalance
+	if err := db.DBEngine.WithContext(ctx).Model(&models.TokenTransfer{}).
+		Select("token_contract_address AS token, "+
+			"CAST(SUM(CASE WHEN to_address = ? THEN amount ELSE 0 END) - SUM(CASE WHEN from_address = ? THEN amount ELSE 0 END) AS text) AS balance",
+			address, address).
+		Where("(from_address = ? OR to_address = ?) AND amount IS NOT NULL AND token_id IS NULL", address, address).
+		Group("token_contract_address").
+		Having("SUM(CASE WHEN to_address = ? THEN amount ELSE 0 END) <> SUM(CASE WHEN from_address = ? THEN amount ELSE 0 END)", address, address).
+		Order("token_contract_address").Scan(&balances).Error; err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
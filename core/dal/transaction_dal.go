@@ -30,3 +30,112 @@ func (t *transactionDal) Inserts(ctx context.Context, tranactions []models.Trans
 
 	return nil
 }
+
+// GetByBlockRange 获取 [from, to] 区块高度区间内的交易, 同一高度重复写入时以最后一次为准
+func (t *transactionDal) GetByBlockRange(ctx context.Context, from, to uint64) ([]models.Transaction, error) {
+	var trxs []models.Transaction
+	if err := db.DBEngine.WithContext(ctx).
+		Where("id IN (?)", db.DBEngine.Model(&models.Transaction{}).Select("MAX(id)").
+			Where("block_number BETWEEN ? AND ?", from, to).
+			Where("block_hash IN (?)", Block.canonicalHashes(from, to)).
+			Group("block_hash, tx_index")).
+		Order("block_number ASC, tx_index ASC").Find(&trxs).Error; err != nil {
+		return nil, err
+	}
+	return trxs, nil
+}
+
+// GetByHash 同一交易重复写入时以最后一次为准
+func (t *transactionDal) GetByHash(ctx context.Context, txHash string) (*models.Transaction, error) {
+	var trx models.Transaction
+	if err := db.DBEngine.WithContext(ctx).Where("tx_hash = ?", txHash).
+		Order("id DESC").Take(&trx).Error; err != nil {
+		return nil, err
+	}
+	return &trx, nil
+}
+
+// GetByBlockHash 获取区块内的交易, 按交易索引排序
+func (t *transactionDal) GetByBlockHash(ctx context.Context, blockHash string) ([]models.Transaction, error) {
+	var trxs []models.Transaction
+	if err := db.DBEngine.WithContext(ctx).Where("block_hash = ?", blockHash).
+		Order("tx_index ASC").Find(&trxs).Error; err != nil {
+		return nil, err
+	}
+	return trxs, nil
+}
+
+// DeleteByBlockNumber 物理删除指定高度的全部交易
+func (t *transactionDal) DeleteByBlockNumber(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_number = ?", height).Delete(&models.Transaction{}).Error
+}
+
+// CountByBlockRange 按区块哈希统计 [from, to] 高度区间内的交易数量
+func (t *transactionDal) CountByBlockRange(ctx context.Context, from, to uint64) (map[string]int, error) {
+	var rows []struct {
+		BlockHash string
+		Count     int
+	}
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Transaction{}).
+		Select("block_hash, COUNT(*) AS count").
+		Where("block_number BETWEEN ? AND ?", from, to).
+		Group("block_hash").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.BlockHash] = row.Count
+	}
+	return counts, nil
+}
+
+// List 按区块高度倒序分页获取交易
+func (t *transactionDal) List(ctx context.Context, offset, limit int) ([]models.Transaction, error) {
+	var trxs []models.Transaction
+	if err := db.DBEngine.WithContext(ctx).Order("block_number DESC, tx_index DESC").
+		Offset(offset).Limit(limit).Find(&trxs).Error; err != nil {
+		return nil, err
+	}
+	return trxs, nil
+}
+
+// ListByAddress 分页获取地址发出、接收或调用的交易, 按区块高度倒序
+func (t *transactionDal) ListByAddress(ctx context.Context, address string, offset, limit int) ([]models.Transaction, error) {
+	var trxs []models.Transaction
+	if err := db.DBEngine.WithContext(ctx).
+		Where(`"from" = ? OR "to" = ? OR contract = ?`, address, address, address).
+		Order("block_number DESC, tx_index DESC").Offset(offset).Limit(limit).Find(&trxs).Error; err != nil {
+		return nil, err
+	}
+	return trxs, nil
+}
+
+// AddressStats 地址相关交易的统计
+type AddressStats struct {
+	Count      int64
+	FirstBlock uint64
+	LastBlock  uint64
+}
+
+// GetAddressStats 统计地址发出、接收或调用的交易数量和首末区块
+func (t *transactionDal) GetAddressStats(ctx context.Context, address string) (*AddressStats, error) {
+	var stats AddressStats
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Transaction{}).
+		Select("COUNT(*) AS count, COALESCE(MIN(block_number), 0) AS first_block, COALESCE(MAX(block_number), 0) AS last_block").
+		Where(`"from" = ? OR "to" = ? OR contract = ?`, address, address, address).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// IsContract 地址是否作为合约被调用或创建过
+func (t *transactionDal) IsContract(ctx context.Context, address string) (bool, error) {
+	var ids []uint
+	if err := db.DBEngine.WithContext(ctx).Model(&models.Transaction{}).
+		Where("contract = ?", address).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
@@ -0,0 +1,33 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm"
+)
+
+// WithTx 在一个数据库事务中执行 fn, fn 中的 dal 调用需要使用传入的 txCtx.
+// ctx 中已经携带事务时(例如区块钩子中)直接加入该事务, 由外层提交或回滚
+func WithTx(ctx context.Context, fn func(txCtx context.Context) error) (err error) {
+	if _, ok := ctx.Value(db.TxContext).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	tx := db.DBEngine.Begin()
+	if err = tx.Error; err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, db.TxContext, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
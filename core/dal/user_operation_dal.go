@@ -0,0 +1,71 @@
+package dal
+
+import (
+	"context"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var UserOperation *userOperationDal
+
+type userOperationDal struct{}
+
+func InitUserOperationDal() {
+	UserOperation = &userOperationDal{}
+}
+
+func (u *userOperationDal) Inserts(ctx context.Context, userOps []models.UserOperation) error {
+	if err := db.DBEngine.WithContext(ctx).CreateInBatches(userOps, common.BatchSize).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userOperationDal) GetByHash(ctx context.Context, userOpHash string) (*models.UserOperation, error) {
+	var userOp models.UserOperation
+	if err := db.DBEngine.WithContext(ctx).Where("user_op_hash = ?", userOpHash).Take(&userOp).Error; err != nil {
+		return nil, err
+	}
+	return &userOp, nil
+}
+
+// List 按 sender 或交易哈希查询, 参数为空时不过滤, 按区块倒序
+func (u *userOperationDal) List(ctx context.Context, sender, transactionHash string, offset, limit int) ([]models.UserOperation, error) {
+	query := db.DBEngine.WithContext(ctx).Model(&models.UserOperation{})
+	if sender != "" {
+		query = query.Where("sender = ?", sender)
+	}
+	if transactionHash != "" {
+		query = query.Where("transaction_hash = ?", transactionHash)
+	}
+
+	var userOps []models.UserOperation
+	if err := query.Order("block_number DESC, log_index DESC").
+		Offset(offset).Limit(limit).Find(&userOps).Error; err != nil {
+		return nil, err
+	}
+	return userOps, nil
+}
+
+// GetByBlockRange 获取 [from, to] 区块高度区间内的UserOperation, 同一高度重复写入时以最后一次为准
+func (u *userOperationDal) GetByBlockRange(ctx context.Context, from, to uint64) ([]models.UserOperation, error) {
+	var userOps []models.UserOperation
+	if err := db.DBEngine.WithContext(ctx).
+		Where("id IN (?)", db.DBEngine.Model(&models.UserOperation{}).Select("MAX(id)").
+			Where("block_number BETWEEN ? AND ?", from, to).
+			Where("block_hash IN (?)", Block.canonicalHashes(from, to)).
+			Group("block_hash, log_index")).
+		Order("block_number ASC, log_index ASC").Find(&userOps).Error; err != nil {
+		return nil, err
+	}
+	return userOps, nil
+}
+
+// DeleteByBlockNumber 物理删除指定高度的全部UserOperation
+func (u *userOperationDal) DeleteByBlockNumber(ctx context.Context, height uint64) error {
+	return db.DBEngine.WithContext(ctx).Unscoped().
+		Where("block_number = ?", height).Delete(&models.UserOperation{}).Error
+}
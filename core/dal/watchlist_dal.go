@@ -0,0 +1,147 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"gorm.io/gorm/clause"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+var Watchlist *watchlistDal
+
+type watchlistDal struct{}
+
+func InitWatchlistDal() {
+	Watchlist = &watchlistDal{}
+}
+
+func (w *watchlistDal) Insert(ctx context.Context, watchlist *models.Watchlist) error {
+	return db.DBEngine.WithContext(ctx).Create(watchlist).Error
+}
+
+func (w *watchlistDal) Delete(ctx context.Context, id uint) error {
+	return db.DBEngine.WithContext(ctx).Delete(&models.Watchlist{}, id).Error
+}
+
+func (w *watchlistDal) SetEnabled(ctx context.Context, id uint, enabled bool) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.Watchlist{}).
+		Where("id = ?", id).Update("enabled", enabled).Error
+}
+
+func (w *watchlistDal) GetById(ctx context.Context, id uint) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	if err := db.DBEngine.WithContext(ctx).Where("id = ?", id).Take(&watchlist).Error; err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+func (w *watchlistDal) ListEnabled(ctx context.Context) ([]models.Watchlist, error) {
+	var watchlists []models.Watchlist
+	if err := db.DBEngine.WithContext(ctx).Where("enabled = ?", true).Find(&watchlists).Error; err != nil {
+		return nil, err
+	}
+	return watchlists, nil
+}
+
+// InsertDeliveries 写入待推送消息, 同一个事件重复写入时忽略
+func (w *watchlistDal) InsertDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.DBEngine.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(deliveries, common.BatchSize).Error
+}
+
+// GetDueDeliveries 获取到了推送时间的待推送消息
+func (w *watchlistDal) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	if err := db.DBEngine.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookStatusPending, now).
+		Order("next_attempt_at ASC, id ASC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (w *watchlistDal) MarkDelivered(ctx context.Context, id uint, attempts int, deliveredAt time.Time) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.WebhookStatusDelivered,
+			"attempts":     attempts,
+			"delivered_at": deliveredAt,
+			"last_error":   "",
+		}).Error
+}
+
+func (w *watchlistDal) MarkRetry(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return db.DBEngine.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
+
+// MoveToDeadLetter 将推送标记为失败并写入死信表, 需要在事务中调用
+func (w *watchlistDal) MoveToDeadLetter(ctx context.Context, delivery models.WebhookDelivery, webhookUrl string) error {
+	tx := db.DBEngine.WithContext(ctx)
+	if err := tx.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":     models.WebhookStatusDead,
+			"attempts":   delivery.Attempts,
+			"last_error": delivery.LastError,
+		}).Error; err != nil {
+		return err
+	}
+
+	deadLetter := models.WebhookDeadLetter{
+		DeliveryId:  delivery.ID,
+		WatchlistId: delivery.WatchlistId,
+		WebhookUrl:  webhookUrl,
+		Payload:     delivery.Payload,
+		Attempts:    delivery.Attempts,
+		LastError:   delivery.LastError,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "delivery_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"attempts", "last_error", "replayed_at", "updated_at"}),
+	}).Create(&deadLetter).Error
+}
+
+func (w *watchlistDal) ListDeadLetters(ctx context.Context, offset, limit int) ([]models.WebhookDeadLetter, error) {
+	var deadLetters []models.WebhookDeadLetter
+	if err := db.DBEngine.WithContext(ctx).Where("replayed_at IS NULL").
+		Order("id DESC").Offset(offset).Limit(limit).Find(&deadLetters).Error; err != nil {
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+func (w *watchlistDal) GetDeadLetter(ctx context.Context, id uint) (*models.WebhookDeadLetter, error) {
+	var deadLetter models.WebhookDeadLetter
+	if err := db.DBEngine.WithContext(ctx).Where("id = ?", id).Take(&deadLetter).Error; err != nil {
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+// ReplayDeadLetter 将死信重新放回待推送队列, 需要在事务中调用
+func (w *watchlistDal) ReplayDeadLetter(ctx context.Context, deadLetter models.WebhookDeadLetter, now time.Time) error {
+	tx := db.DBEngine.WithContext(ctx)
+	if err := tx.Model(&models.WebhookDelivery{}).Where("id = ?", deadLetter.DeliveryId).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.WebhookDeadLetter{}).Where("id = ?", deadLetter.ID).
+		Update("replayed_at", now).Error
+}
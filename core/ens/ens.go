@@ -0,0 +1,128 @@
+/*
+## Overview
+ens 从区块日志中解析 ENS 注册表、解析器和反向注册事件, 在区块事务中更新 ens_names 和 ens_reverse_records.
+
+	| Contract         | Event            | Topics        | Data     | 更新                                       |
+	|------------------|------------------|---------------|----------|--------------------------------------------|
+	| Registry         | `NewOwner`       | node, label   | owner    | 子节点 keccak(node, label) 的所有者        |
+	| Registry         | `Transfer`       | node          | owner    | 节点所有者                                 |
+	| Registry         | `NewResolver`    | node          | resolver | 节点解析器, 解析器变化时清空 addr 和主名称 |
+	| Resolver         | `AddrChanged`    | node          | a        | 节点的 addr 记录                           |
+	| Resolver         | `NameChanged`    | node          | name     | 反向节点对应地址的主名称                   |
+	| ReverseRegistrar | `ReverseClaimed` | addr, node    |          | 地址的反向节点                             |
+
+	- 注册表事件只接受 ENS 注册表合约发出的日志
+	- 解析器事件任何合约都可以发出, 只有发出者是节点当前的解析器时才生效
+	- ReverseClaimed 的节点必须等于 namehash(<addr>.addr.reverse), 因此不限制反向注册合约的地址
+	- 主名称需要正向解析回同一个地址才返回
+	- 名称只做小写和去除首尾空白, 没有实现完整的 ENSIP-15 规范化
+	- 重新索引区块时先回滚被替换区块的修改: 受影响的节点清空注册表和解析器状态(保留名称明文), 反向记录删除,
+	  再按时间顺序重放该高度之前与它们相关的日志, 之后照常应用新区块的日志
+*/
+package ens
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// HookName 在 chain 中注册的区块钩子名称
+const HookName = "ens"
+
+// reverseSuffix 反向解析的顶级名称
+const reverseSuffix = "addr.reverse"
+
+// Normalize 小写并去掉首尾空白和末尾的点
+func Normalize(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// LabelHash 单个标签的 keccak256
+func LabelHash(label string) ethcommon.Hash {
+	return crypto.Keccak256Hash([]byte(label))
+}
+
+// Namehash EIP-137 namehash, 空名称为全0
+func Namehash(name string) ethcommon.Hash {
+	var node ethcommon.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := LabelHash(labels[i])
+		node = crypto.Keccak256Hash(node.Bytes(), label.Bytes())
+	}
+	return node
+}
+
+// ReverseName 地址的反向名称, 例如 d8da6bf26964af9d7eed9e03e53415d37aa96045.addr.reverse
+func ReverseName(address ethcommon.Address) string {
+	return strings.ToLower(strings.TrimPrefix(address.Hex(), "0x")) + "." + reverseSuffix
+}
+
+// ReverseNode 地址反向名称的 namehash
+func ReverseNode(address ethcommon.Address) ethcommon.Hash {
+	return Namehash(ReverseName(address))
+}
+
+// Resolve 正向解析名称, 名称不存在或没有 addr 记录时返回 nil
+func Resolve(ctx context.Context, name string) (*models.EnsName, error) {
+	record, err := dal.Ens.GetName(ctx, Namehash(Normalize(name)).Hex())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.Address == "" {
+		return nil, nil
+	}
+	return record, nil
+}
+
+// PrimaryNames 批量反向解析地址的主名称, 按地址索引, 没有主名称的地址不返回
+func PrimaryNames(ctx context.Context, addresses []string) (map[string]string, error) {
+	records, err := dal.Ens.ListPrimaryNames(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(records))
+	for _, r := range records {
+		names[r.Address] = r.Name
+	}
+	return names, nil
+}
+
+// BlockHook 在区块写库的事务中按日志顺序更新 ENS 状态, 重新索引的区块先回滚旧区块的修改
+func BlockHook(ctx context.Context, data *chain.BlockData) error {
+	if data.Replaced != nil {
+		if err := Revert(ctx, data.Replaced.Events, data.Block.BlockHeight); err != nil {
+			return err
+		}
+	}
+	changes, err := Parse(data.Events)
+	if err != nil {
+		return err
+	}
+	for i := range changes {
+		if err := Apply(ctx, &changes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Setup 注册区块钩子
+func Setup() {
+	chain.RegisterBlockHook(HookName, BlockHook)
+}
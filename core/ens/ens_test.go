@@ -0,0 +1,111 @@
+package ens
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	vitalik  = "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+	resolver = "0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"
+)
+
+func TestNamehash(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{"eth", "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{"foo.eth", "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+		{"addr.reverse", "0x91d1777781884d03a6757a803996e38de2a42967fb37eeaca72729271025a9e2"},
+	}
+	for _, tt := range tests {
+		if got := Namehash(tt.name).Hex(); got != tt.want {
+			t.Errorf("Namehash(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if Normalize(" Vitalik.ETH. ") != "vitalik.eth" {
+		t.Errorf("Normalize = %q", Normalize(" Vitalik.ETH. "))
+	}
+}
+
+func TestReverseNode(t *testing.T) {
+	address := ethcommon.HexToAddress(vitalik)
+	if got := ReverseName(address); got != "d8da6bf26964af9d7eed9e03e53415d37aa96045.addr.reverse" {
+		t.Errorf("ReverseName = %s", got)
+	}
+	want := crypto.Keccak256Hash(Namehash(reverseSuffix).Bytes(),
+		LabelHash("d8da6bf26964af9d7eed9e03e53415d37aa96045").Bytes())
+	if ReverseNode(address) != want {
+		t.Errorf("ReverseNode = %s, want %s", ReverseNode(address).Hex(), want.Hex())
+	}
+}
+
+func word(b []byte) string {
+	return hex.EncodeToString(ethcommon.LeftPadBytes(b, 32))
+}
+
+func addressData(address string) string {
+	return word(ethcommon.HexToAddress(address).Bytes())
+}
+
+func stringData(s string) string {
+	padded := make([]byte, (len(s)+31)/32*32)
+	copy(padded, s)
+	return word([]byte{0x20}) + word([]byte{byte(len(s))}) + hex.EncodeToString(padded)
+}
+
+func TestParse(t *testing.T) {
+	ethNode := Namehash("eth").Hex()
+	vitalikNode := Namehash("vitalik.eth").Hex()
+	reverseNode := ReverseNode(ethcommon.HexToAddress(vitalik)).Hex()
+	label := LabelHash("vitalik").Hex()
+	const tx = "0x1f1b0b1e0a6d2b2c5b71f2ff35a3c6d3f3c1b3d1e5f1a2b3c4d5e6f708192a3b"
+
+	logs := []models.Event{
+		{Address: common.EnsRegistryAddress, FirstTopic: common.EnsNewOwnerSignature, SecondTopic: ethNode, ThirdTopic: label,
+			Data: addressData(vitalik), TxHash: tx, BlockNumber: 10},
+		// 不是注册表合约发出的 NewOwner
+		{Address: resolver, FirstTopic: common.EnsNewOwnerSignature, SecondTopic: ethNode, ThirdTopic: label, Data: addressData(resolver)},
+		{Address: strings.ToLower(common.EnsRegistryAddress), FirstTopic: common.EnsNewResolverSignature, SecondTopic: vitalikNode,
+			Data: addressData(resolver)},
+		{Address: common.EnsRegistryAddress, FirstTopic: common.EnsTransferSignature, SecondTopic: vitalikNode, Data: addressData(resolver)},
+		{Address: resolver, FirstTopic: common.EnsAddrChangedSignature, SecondTopic: vitalikNode, Data: addressData(vitalik)},
+		{Address: resolver, FirstTopic: common.EnsAddrChangedSignature, SecondTopic: vitalikNode, Data: "00", LogIndex: 5},
+		{Address: resolver, FirstTopic: common.EnsAddrChangedSignature, SecondTopic: vitalikNode, Data: addressData(vitalik), Removed: true},
+		{Address: resolver, FirstTopic: common.EnsReverseClaimedSignature, SecondTopic: word(ethcommon.HexToAddress(vitalik).Bytes()),
+			ThirdTopic: reverseNode},
+		{Address: resolver, FirstTopic: common.EnsNameChangedSignature, SecondTopic: reverseNode, Data: stringData("vitalik.eth")},
+		{Address: resolver, FirstTopic: common.ERC20TokenTransferEventFuncSign, SecondTopic: vitalikNode},
+	}
+
+	changes, err := Parse(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{Event: EventNewOwner, Emitter: common.EnsRegistryAddress, Node: vitalikNode, ParentNode: ethNode, LabelHash: label,
+			Address: vitalik, TransactionHash: tx, BlockNumber: 10},
+		{Event: EventNewResolver, Emitter: common.EnsRegistryAddress, Node: vitalikNode, Address: resolver},
+		{Event: EventTransfer, Emitter: common.EnsRegistryAddress, Node: vitalikNode, Address: resolver},
+		{Event: EventAddrChanged, Emitter: resolver, Node: vitalikNode, Address: vitalik},
+		{Event: EventReverseClaimed, Emitter: resolver, Node: reverseNode, Address: vitalik},
+		{Event: EventNameChanged, Emitter: resolver, Node: reverseNode, Name: "vitalik.eth"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package ens
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+const (
+	EventNewOwner       = "NewOwner"
+	EventTransfer       = "Transfer"
+	EventNewResolver    = "NewResolver"
+	EventAddrChanged    = "AddrChanged"
+	EventNameChanged    = "NameChanged"
+	EventReverseClaimed = "ReverseClaimed"
+)
+
+// Change 一条 ENS 日志对节点或反向记录的修改
+type Change struct {
+	Event string
+	// Emitter 发出日志的合约, 解析器事件用来校验是否为节点当前的解析器
+	Emitter string
+	Node    string
+	// ParentNode 和 LabelHash 只有 NewOwner 有值
+	ParentNode string
+	LabelHash  string
+	// Address NewOwner/Transfer 为所有者, NewResolver 为解析器, AddrChanged 为 addr 记录, ReverseClaimed 为地址
+	Address string
+	// Name NameChanged 中的名称
+	Name            string
+	TransactionHash string
+	BlockNumber     uint64
+}
+
+func topicHash(topic string) string {
+	return ethcommon.HexToHash(topic).Hex()
+}
+
+// Parse 按日志顺序解析 ENS 修改, 数据格式不正确的日志跳过
+func Parse(logs []models.Event) ([]Change, error) {
+	var changes []Change
+	for _, l := range logs {
+		if l.Removed || l.SecondTopic == "" {
+			continue
+		}
+
+		c := Change{
+			Emitter:         ethcommon.HexToAddress(l.Address).Hex(),
+			Node:            topicHash(l.SecondTopic),
+			TransactionHash: l.TxHash,
+			BlockNumber:     l.BlockNumber,
+		}
+		fromRegistry := strings.EqualFold(l.Address, common.EnsRegistryAddress)
+		data := ethcommon.FromHex(l.Data)
+
+		var (
+			address ethcommon.Address
+			err     error
+		)
+		switch {
+		case l.FirstTopic == common.EnsNewOwnerSignature && fromRegistry && l.ThirdTopic != "":
+			c.Event = EventNewOwner
+			c.ParentNode, c.LabelHash = c.Node, topicHash(l.ThirdTopic)
+			c.Node = crypto.Keccak256Hash(ethcommon.FromHex(c.ParentNode), ethcommon.FromHex(c.LabelHash)).Hex()
+			address, err = abi.ParseEnsAddressLog(EventNewOwner, data)
+		case l.FirstTopic == common.EnsTransferSignature && fromRegistry:
+			c.Event = EventTransfer
+			address, err = abi.ParseEnsAddressLog(EventTransfer, data)
+		case l.FirstTopic == common.EnsNewResolverSignature && fromRegistry:
+			c.Event = EventNewResolver
+			address, err = abi.ParseEnsAddressLog(EventNewResolver, data)
+		case l.FirstTopic == common.EnsAddrChangedSignature:
+			c.Event = EventAddrChanged
+			address, err = abi.ParseEnsAddressLog(EventAddrChanged, data)
+		case l.FirstTopic == common.EnsNameChangedSignature:
+			c.Event = EventNameChanged
+			c.Name, err = abi.ParseEnsNameChangedLog(data)
+		case l.FirstTopic == common.EnsReverseClaimedSignature && l.ThirdTopic != "":
+			c.Event = EventReverseClaimed
+			address = ethcommon.HexToAddress(l.SecondTopic)
+			c.Node = topicHash(l.ThirdTopic)
+		default:
+			continue
+		}
+		if err != nil {
+			log.WithField("tx", l.TxHash).WithField("log_index", l.LogIndex).WithField("event", c.Event).
+				WithField("err", err).Warn("skip malformed ens log")
+			continue
+		}
+		if c.Event != EventNameChanged {
+			c.Address = address.Hex()
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func getName(ctx context.Context, node string) (*models.EnsName, error) {
+	name, err := store.GetName(ctx, node)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return name, err
+}
+
+// fromResolver 解析器事件只有发出者是节点当前的解析器时才生效
+func fromResolver(ctx context.Context, c *Change) (bool, error) {
+	current, err := getName(ctx, c.Node)
+	if err != nil {
+		return false, err
+	}
+	return current != nil && current.Resolver == c.Emitter, nil
+}
+
+// Apply 把一条修改写入数据库, ctx 中携带区块事务
+func Apply(ctx context.Context, c *Change) error {
+	record := &models.EnsName{Node: c.Node, TransactionHash: c.TransactionHash, BlockNumber: c.BlockNumber}
+	switch c.Event {
+	case EventNewOwner:
+		record.ParentNode, record.LabelHash, record.Owner = c.ParentNode, c.LabelHash, c.Address
+		return store.UpsertName(ctx, record, "parent_node", "label_hash", "owner", "transaction_hash", "block_number")
+
+	case EventTransfer:
+		record.Owner = c.Address
+		return store.UpsertName(ctx, record, "owner", "transaction_hash", "block_number")
+
+	case EventNewResolver:
+		current, err := getName(ctx, c.Node)
+		if err != nil {
+			return err
+		}
+		record.Resolver = c.Address
+		if current == nil || current.Resolver == c.Address {
+			return store.UpsertName(ctx, record, "resolver", "transaction_hash", "block_number")
+		}
+		// 记录属于解析器, 换了解析器之后旧的 addr 和主名称都不再有效
+		if _, err := store.UpdateReverseByNode(ctx, c.Node, map[string]interface{}{"name": "", "forward_node": ""}); err != nil {
+			return err
+		}
+		return store.UpsertName(ctx, record, "resolver", "address", "transaction_hash", "block_number")
+
+	case EventAddrChanged:
+		ok, err := fromResolver(ctx, c)
+		if err != nil || !ok {
+			return err
+		}
+		address := c.Address
+		if address == common.ZeroAddress {
+			address = ""
+		}
+		record.Address = address
+		return store.UpsertName(ctx, record, "address", "transaction_hash", "block_number")
+
+	case EventNameChanged:
+		ok, err := fromResolver(ctx, c)
+		if err != nil || !ok {
+			return err
+		}
+		name := Normalize(c.Name)
+		forwardNode := ""
+		if name != "" {
+			forwardNode = Namehash(name).Hex()
+		}
+		updated, err := store.UpdateReverseByNode(ctx, c.Node, map[string]interface{}{
+			"name": name, "forward_node": forwardNode,
+			"transaction_hash": c.TransactionHash, "block_number": c.BlockNumber,
+		})
+		if err != nil || updated == 0 || name == "" {
+			return err
+		}
+		// 主名称的明文也是正向节点的名称
+		return store.UpsertName(ctx, &models.EnsName{Node: forwardNode, Name: name,
+			TransactionHash: c.TransactionHash, BlockNumber: c.BlockNumber}, "name")
+
+	case EventReverseClaimed:
+		address := ethcommon.HexToAddress(c.Address)
+		if ReverseNode(address).Hex() != c.Node {
+			return nil
+		}
+		err := store.UpsertReverse(ctx, &models.EnsReverseRecord{Address: c.Address, Node: c.Node,
+			TransactionHash: c.TransactionHash, BlockNumber: c.BlockNumber}, "node", "transaction_hash", "block_number")
+		if err != nil {
+			return err
+		}
+		record.Name = ReverseName(address)
+		return store.UpsertName(ctx, record, "name")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package ens
+
+import (
+	"context"
+	"sort"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// nodeSignatures 第一个 indexed 参数为节点的事件
+var nodeSignatures = []string{
+	common.EnsTransferSignature,
+	common.EnsNewResolverSignature,
+	common.EnsAddrChangedSignature,
+	common.EnsNameChangedSignature,
+}
+
+// Revert 回滚被替换区块的日志对 ENS 状态的修改, ctx 中携带区块事务.
+// 受影响的节点清空后按时间顺序重放 height 之前与它们相关的日志, 反向记录同样删除后重放
+func Revert(ctx context.Context, replaced []models.Event, height uint64) error {
+	changes, err := Parse(replaced)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	nodes := map[string]struct{}{}
+	addresses := map[string]struct{}{}
+	owners := map[[2]string]struct{}{}
+	for _, c := range changes {
+		nodes[c.Node] = struct{}{}
+		switch c.Event {
+		case EventNewOwner:
+			owners[[2]string{c.ParentNode, c.LabelHash}] = struct{}{}
+		case EventReverseClaimed:
+			addresses[c.Address] = struct{}{}
+		case EventNewResolver, EventNameChanged:
+			// 两者都会按节点修改反向记录
+			records, err := store.ListReverseByNode(ctx, c.Node)
+			if err != nil {
+				return err
+			}
+			for _, r := range records {
+				addresses[r.Address] = struct{}{}
+			}
+		}
+	}
+	// 反向记录的主名称来自反向节点上的 NameChanged
+	for address := range addresses {
+		nodes[ReverseNode(ethcommon.HexToAddress(address)).Hex()] = struct{}{}
+	}
+	// NewOwner 的 topic 为父节点和标签, 从节点当前的状态中取
+	for node := range nodes {
+		name, err := getName(ctx, node)
+		if err != nil {
+			return err
+		}
+		if name != nil && name.ParentNode != "" {
+			owners[[2]string{name.ParentNode, name.LabelHash}] = struct{}{}
+		}
+	}
+
+	nodeList, addressList := sortedKeys(nodes), sortedKeys(addresses)
+	if err := store.ResetNames(ctx, nodeList); err != nil {
+		return err
+	}
+	if err := store.DeleteReverses(ctx, addressList); err != nil {
+		return err
+	}
+
+	logs, err := store.GetLogsBySecondTopic(ctx, nodeSignatures, nodeList, height)
+	if err != nil {
+		return err
+	}
+	addressTopics := make([]string, 0, len(addressList))
+	for _, address := range addressList {
+		addressTopics = append(addressTopics, ethcommon.BytesToHash(ethcommon.HexToAddress(address).Bytes()).Hex())
+	}
+	claims, err := store.GetLogsBySecondTopic(ctx, []string{common.EnsReverseClaimedSignature}, addressTopics, height)
+	if err != nil {
+		return err
+	}
+	logs = append(logs, claims...)
+	for owner := range owners {
+		newOwners, err := store.GetLogsByTopics(ctx, common.EnsNewOwnerSignature, owner[0], owner[1], height)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, newOwners...)
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].LogIndex < logs[j].LogIndex
+	})
+
+	replay, err := Parse(logs)
+	if err != nil {
+		return err
+	}
+	for i := range replay {
+		if err := Apply(ctx, &replay[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
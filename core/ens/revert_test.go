@@ -0,0 +1,233 @@
+package ens
+
+import (
+	"context"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// memoryStore 内存中的 ens_names、ens_reverse_records 和日志表
+type memoryStore struct {
+	names    map[string]models.EnsName
+	reverses map[string]models.EnsReverseRecord
+	events   []models.Event
+}
+
+func setName(n *models.EnsName, from *models.EnsName, column string) {
+	switch column {
+	case "parent_node":
+		n.ParentNode = from.ParentNode
+	case "label_hash":
+		n.LabelHash = from.LabelHash
+	case "owner":
+		n.Owner = from.Owner
+	case "resolver":
+		n.Resolver = from.Resolver
+	case "address":
+		n.Address = from.Address
+	case "name":
+		n.Name = from.Name
+	case "transaction_hash":
+		n.TransactionHash = from.TransactionHash
+	case "block_number":
+		n.BlockNumber = from.BlockNumber
+	}
+}
+
+func (m *memoryStore) GetName(ctx context.Context, node string) (*models.EnsName, error) {
+	n, ok := m.names[node]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &n, nil
+}
+
+func (m *memoryStore) UpsertName(ctx context.Context, name *models.EnsName, columns ...string) error {
+	n, ok := m.names[name.Node]
+	if !ok {
+		m.names[name.Node] = *name
+		return nil
+	}
+	for _, column := range columns {
+		setName(&n, name, column)
+	}
+	m.names[name.Node] = n
+	return nil
+}
+
+func (m *memoryStore) UpsertReverse(ctx context.Context, record *models.EnsReverseRecord, columns ...string) error {
+	r, ok := m.reverses[record.Address]
+	if !ok {
+		m.reverses[record.Address] = *record
+		return nil
+	}
+	for _, column := range columns {
+		switch column {
+		case "node":
+			r.Node = record.Node
+		case "transaction_hash":
+			r.TransactionHash = record.TransactionHash
+		case "block_number":
+			r.BlockNumber = record.BlockNumber
+		}
+	}
+	m.reverses[record.Address] = r
+	return nil
+}
+
+func (m *memoryStore) UpdateReverseByNode(ctx context.Context, node string, updates map[string]interface{}) (int64, error) {
+	var updated int64
+	for address, r := range m.reverses {
+		if r.Node != node {
+			continue
+		}
+		for column, v := range updates {
+			switch column {
+			case "name":
+				r.Name = v.(string)
+			case "forward_node":
+				r.ForwardNode = v.(string)
+			case "transaction_hash":
+				r.TransactionHash = v.(string)
+			case "block_number":
+				r.BlockNumber = v.(uint64)
+			}
+		}
+		m.reverses[address] = r
+		updated++
+	}
+	return updated, nil
+}
+
+func (m *memoryStore) ListReverseByNode(ctx context.Context, node string) ([]models.EnsReverseRecord, error) {
+	var records []models.EnsReverseRecord
+	for _, r := range m.reverses {
+		if r.Node == node {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+func (m *memoryStore) ResetNames(ctx context.Context, nodes []string) error {
+	for _, node := range nodes {
+		if n, ok := m.names[node]; ok {
+			m.names[node] = models.EnsName{Node: node, Name: n.Name}
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) DeleteReverses(ctx context.Context, addresses []string) error {
+	for _, address := range addresses {
+		delete(m.reverses, address)
+	}
+	return nil
+}
+
+func (m *memoryStore) GetLogsBySecondTopic(ctx context.Context, signatures, topics []string, height uint64) ([]models.Event, error) {
+	contains := func(list []string, s string) bool {
+		for _, v := range list {
+			if v == s {
+				return true
+			}
+		}
+		return false
+	}
+	var logs []models.Event
+	for _, e := range m.events {
+		if contains(signatures, e.FirstTopic) && contains(topics, e.SecondTopic) && e.BlockNumber < height {
+			logs = append(logs, e)
+		}
+	}
+	return logs, nil
+}
+
+func (m *memoryStore) GetLogsByTopics(ctx context.Context, signature, second, third string, height uint64) ([]models.Event, error) {
+	var logs []models.Event
+	for _, e := range m.events {
+		if e.FirstTopic == signature && e.SecondTopic == second && e.ThirdTopic == third && e.BlockNumber < height {
+			logs = append(logs, e)
+		}
+	}
+	return logs, nil
+}
+
+func useMemoryStore(t *testing.T) *memoryStore {
+	m := &memoryStore{names: map[string]models.EnsName{}, reverses: map[string]models.EnsReverseRecord{}}
+	old := store
+	store = m
+	t.Cleanup(func() { store = old })
+	return m
+}
+
+func TestBlockHookRevertsReplacedBlock(t *testing.T) {
+	ctx := context.Background()
+	m := useMemoryStore(t)
+
+	const attacker = "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+	ethNode := Namehash("eth").Hex()
+	vitalikNode := Namehash("vitalik.eth").Hex()
+	newNode := Namehash("new.eth").Hex()
+	reverseNode := ReverseNode(ethcommon.HexToAddress(vitalik)).Hex()
+	addressTopic := ethcommon.BytesToHash(ethcommon.HexToAddress(vitalik).Bytes()).Hex()
+
+	logIndex := uint(0)
+	event := func(height uint64, emitter, signature, second, third, data string) models.Event {
+		logIndex++
+		return models.Event{Address: emitter, FirstTopic: signature, SecondTopic: second, ThirdTopic: third,
+			Data: data, BlockNumber: height, LogIndex: logIndex}
+	}
+	history := []models.Event{
+		event(10, common.EnsRegistryAddress, common.EnsNewOwnerSignature, ethNode, LabelHash("vitalik").Hex(), addressData(vitalik)),
+		event(10, common.EnsRegistryAddress, common.EnsNewResolverSignature, vitalikNode, "", addressData(resolver)),
+		event(11, resolver, common.EnsAddrChangedSignature, vitalikNode, "", addressData(vitalik)),
+		event(11, resolver, common.EnsReverseClaimedSignature, addressTopic, reverseNode, ""),
+		event(11, common.EnsRegistryAddress, common.EnsNewResolverSignature, reverseNode, "", addressData(resolver)),
+		event(11, resolver, common.EnsNameChangedSignature, reverseNode, "", stringData("vitalik.eth")),
+	}
+	replaced := []models.Event{
+		event(20, common.EnsRegistryAddress, common.EnsTransferSignature, vitalikNode, "", addressData(attacker)),
+		event(20, resolver, common.EnsAddrChangedSignature, vitalikNode, "", addressData(attacker)),
+		event(20, resolver, common.EnsNameChangedSignature, reverseNode, "", stringData("other.eth")),
+		event(20, common.EnsRegistryAddress, common.EnsNewOwnerSignature, ethNode, LabelHash("new").Hex(), addressData(attacker)),
+	}
+	for i, logs := range [][]models.Event{history, replaced} {
+		m.events = append(m.events, logs...)
+		if err := BlockHook(ctx, &chain.BlockData{Block: models.Block{BlockHeight: uint64(10 + i)}, Events: logs}); err != nil {
+			t.Fatalf("BlockHook() error = %v", err)
+		}
+	}
+	if m.names[vitalikNode].Owner != attacker || m.reverses[vitalik].Name != "other.eth" {
+		t.Fatalf("replaced block not applied: %+v %+v", m.names[vitalikNode], m.reverses[vitalik])
+	}
+
+	// 区块 20 被替换为没有 ENS 日志的区块, 旧日志已从日志表中删除
+	m.events = history
+	data := &chain.BlockData{
+		Block:     models.Block{BlockHeight: 20},
+		Reindexed: true,
+		Replaced:  &chain.ReplacedBlock{Block: models.Block{BlockHeight: 20}, Events: replaced},
+	}
+	if err := BlockHook(ctx, data); err != nil {
+		t.Fatalf("BlockHook() reindex error = %v", err)
+	}
+
+	got := m.names[vitalikNode]
+	if got.Owner != vitalik || got.Resolver != resolver || got.Address != vitalik || got.ParentNode != ethNode || got.BlockNumber != 11 {
+		t.Errorf("vitalik.eth = %+v, want the state before block 20", got)
+	}
+	if got := m.names[newNode]; got.Owner != "" || got.BlockNumber != 0 {
+		t.Errorf("new.eth = %+v, want no owner, it was only registered in the replaced block", got)
+	}
+	reverse := m.reverses[vitalik]
+	if reverse.Node != reverseNode || reverse.Name != "vitalik.eth" || reverse.ForwardNode != vitalikNode {
+		t.Errorf("reverse record = %+v, want vitalik.eth", reverse)
+	}
+}
@@ -0,0 +1,63 @@
+package ens
+
+import (
+	"context"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Store ENS 状态和日志的读写, 默认使用 dal, 测试中替换为内存实现
+type Store interface {
+	GetName(ctx context.Context, node string) (*models.EnsName, error)
+	UpsertName(ctx context.Context, name *models.EnsName, columns ...string) error
+	UpsertReverse(ctx context.Context, record *models.EnsReverseRecord, columns ...string) error
+	UpdateReverseByNode(ctx context.Context, node string, updates map[string]interface{}) (int64, error)
+	ListReverseByNode(ctx context.Context, node string) ([]models.EnsReverseRecord, error)
+	// ResetNames 清空节点的注册表和解析器状态, 保留名称明文
+	ResetNames(ctx context.Context, nodes []string) error
+	DeleteReverses(ctx context.Context, addresses []string) error
+	// GetLogsBySecondTopic 和 GetLogsByTopics 查询 height 之前的日志, 按时间顺序返回
+	GetLogsBySecondTopic(ctx context.Context, signatures, topics []string, height uint64) ([]models.Event, error)
+	GetLogsByTopics(ctx context.Context, signature, second, third string, height uint64) ([]models.Event, error)
+}
+
+type dalStore struct{}
+
+func (dalStore) GetName(ctx context.Context, node string) (*models.EnsName, error) {
+	return dal.Ens.GetName(ctx, node)
+}
+
+func (dalStore) UpsertName(ctx context.Context, name *models.EnsName, columns ...string) error {
+	return dal.Ens.UpsertName(ctx, name, columns...)
+}
+
+func (dalStore) UpsertReverse(ctx context.Context, record *models.EnsReverseRecord, columns ...string) error {
+	return dal.Ens.UpsertReverse(ctx, record, columns...)
+}
+
+func (dalStore) UpdateReverseByNode(ctx context.Context, node string, updates map[string]interface{}) (int64, error) {
+	return dal.Ens.UpdateReverseByNode(ctx, node, updates)
+}
+
+func (dalStore) ListReverseByNode(ctx context.Context, node string) ([]models.EnsReverseRecord, error) {
+	return dal.Ens.ListReverseByNode(ctx, node)
+}
+
+func (dalStore) ResetNames(ctx context.Context, nodes []string) error {
+	return dal.Ens.ResetNames(ctx, nodes)
+}
+
+func (dalStore) DeleteReverses(ctx context.Context, addresses []string) error {
+	return dal.Ens.DeleteReverses(ctx, addresses)
+}
+
+func (dalStore) GetLogsBySecondTopic(ctx context.Context, signatures, topics []string, height uint64) ([]models.Event, error) {
+	return dal.Event.GetLogsBySecondTopic(ctx, signatures, topics, height)
+}
+
+func (dalStore) GetLogsByTopics(ctx context.Context, signature, second, third string, height uint64) ([]models.Event, error) {
+	return dal.Event.GetLogsByTopics(ctx, signature, second, third, height)
+}
+
+var store Store = dalStore{}
@@ -0,0 +1,186 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	DefaultMaxBlockRange = 10000
+	DefaultMaxLogs       = 10000
+)
+
+// rpcError 带 JSON-RPC 错误码的错误
+type rpcError struct {
+	code int
+	msg  string
+}
+
+func (e *rpcError) Error() string  { return e.msg }
+func (e *rpcError) ErrorCode() int { return e.code }
+
+func invalidParams(err error) error {
+	return &rpcError{code: -32602, msg: err.Error()}
+}
+
+func limitExceeded(format string, args ...interface{}) error {
+	return &rpcError{code: -32005, msg: fmt.Sprintf(format, args...)}
+}
+
+// EthAPI 用数据库中已经入库的数据实现 eth 命名空间的部分只读方法
+type EthAPI struct {
+	// MaxBlockRange eth_getLogs 单次查询的最大区块数
+	MaxBlockRange uint64
+	// MaxLogs eth_getLogs 单次返回的最大日志数, 超出时返回错误
+	MaxLogs int
+	ChainID *big.Int
+
+	backend Backend
+}
+
+func NewEthAPI(chainId *big.Int) *EthAPI {
+	return &EthAPI{
+		MaxBlockRange: DefaultMaxBlockRange,
+		MaxLogs:       DefaultMaxLogs,
+		ChainID:       chainId,
+		backend:       dbBackend{},
+	}
+}
+
+// NewServer 返回注册了 eth 命名空间的 JSON-RPC 服务, 同时实现 http.Handler
+func NewServer(api *EthAPI) (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", api); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// ChainId eth_chainId
+func (api *EthAPI) ChainId() (*hexutil.Big, error) {
+	if api.ChainID == nil {
+		return nil, fmt.Errorf("chain id not configured")
+	}
+	return (*hexutil.Big)(api.ChainID), nil
+}
+
+// BlockNumber eth_blockNumber, 返回已经入库的最新高度
+func (api *EthAPI) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	latest, err := api.backend.LatestHeight(ctx)
+	return hexutil.Uint64(latest), err
+}
+
+// GetLogs eth_getLogs
+func (api *EthAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	if crit.BlockHash != nil {
+		block, err := api.backend.BlockByHash(ctx, crit.BlockHash.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, invalidParams(fmt.Errorf("unknown block"))
+		}
+	}
+
+	latest, err := api.backend.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := crit.toLogFilter(latest)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+	if filter.BlockHash == "" {
+		if filter.FromBlock > filter.ToBlock {
+			return []*types.Log{}, nil
+		}
+		if api.MaxBlockRange > 0 && filter.ToBlock-filter.FromBlock+1 > api.MaxBlockRange {
+			return nil, limitExceeded("block range greater than %d max", api.MaxBlockRange)
+		}
+	}
+
+	// 多查一条用于判断是否超出 MaxLogs, -1 表示不限制
+	limit := -1
+	if api.MaxLogs > 0 {
+		limit = api.MaxLogs + 1
+	}
+	events, err := api.backend.Logs(ctx, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+	if api.MaxLogs > 0 && len(events) > api.MaxLogs {
+		return nil, limitExceeded("query returned more than %d results", api.MaxLogs)
+	}
+	return toLogs(events), nil
+}
+
+// GetBlockByNumber eth_getBlockByNumber, 区块不存在时返回 null
+func (api *EthAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	latest, err := api.backend.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	height := resolveBlockNumber(&number, latest)
+	block, err := api.backend.BlockByHeight(ctx, height)
+	if err != nil || block == nil {
+		return nil, err
+	}
+
+	trxs, err := api.backend.TransactionsByBlock(ctx, block.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return formatBlock(block, trxs, fullTx, api.ChainID), nil
+}
+
+// GetTransactionByHash eth_getTransactionByHash, 交易不存在时返回 null
+func (api *EthAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	trx, blockHash, err := api.transaction(ctx, hash)
+	if err != nil || trx == nil {
+		return nil, err
+	}
+	return formatTransaction(trx, blockHash, api.ChainID), nil
+}
+
+// GetTransactionReceipt eth_getTransactionReceipt, 交易不存在时返回 null
+func (api *EthAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	trx, blockHash, err := api.transaction(ctx, hash)
+	if err != nil || trx == nil {
+		return nil, err
+	}
+
+	events, err := api.backend.LogsByTransaction(ctx, trx.TxHash, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return formatReceipt(trx, blockHash, events), nil
+}
+
+// transaction 查询交易和所在区块哈希, 早期入库的交易没有区块哈希时按高度查询
+func (api *EthAPI) transaction(ctx context.Context, hash common.Hash) (*models.Transaction, string, error) {
+	trx, err := api.backend.TransactionByHash(ctx, hash.Hex())
+	if err != nil || trx == nil {
+		return nil, "", err
+	}
+	if trx.BlockHash != "" {
+		return trx, trx.BlockHash, nil
+	}
+
+	block, err := api.backend.BlockByHeight(ctx, trx.BlockNumber)
+	if err != nil {
+		return nil, "", err
+	}
+	if block == nil {
+		return trx, "", nil
+	}
+	return trx, block.BlockHash, nil
+}
@@ -0,0 +1,274 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+type fakeBackend struct {
+	blocks []*models.Block
+	trxs   []models.Transaction
+	events []models.Event
+}
+
+func (b *fakeBackend) LatestHeight(context.Context) (uint64, error) {
+	return b.blocks[len(b.blocks)-1].BlockHeight, nil
+}
+
+func (b *fakeBackend) BlockByHeight(_ context.Context, height uint64) (*models.Block, error) {
+	for _, block := range b.blocks {
+		if block.BlockHeight == height {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) BlockByHash(_ context.Context, hash string) (*models.Block, error) {
+	for _, block := range b.blocks {
+		if block.BlockHash == hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) TransactionsByBlock(_ context.Context, blockHash string) ([]models.Transaction, error) {
+	var trxs []models.Transaction
+	for _, trx := range b.trxs {
+		if trx.BlockHash == blockHash {
+			trxs = append(trxs, trx)
+		}
+	}
+	return trxs, nil
+}
+
+func (b *fakeBackend) TransactionByHash(_ context.Context, hash string) (*models.Transaction, error) {
+	for i := range b.trxs {
+		if b.trxs[i].TxHash == hash {
+			return &b.trxs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) LogsByTransaction(_ context.Context, txHash, blockHash string) ([]models.Event, error) {
+	var events []models.Event
+	for _, event := range b.events {
+		if event.TxHash == txHash && event.BlockHash == blockHash {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (b *fakeBackend) Logs(_ context.Context, filter dal.LogFilter, limit int) ([]models.Event, error) {
+	var events []models.Event
+	for _, event := range b.events {
+		if event.BlockNumber < filter.FromBlock || event.BlockNumber > filter.ToBlock {
+			continue
+		}
+		if len(filter.Addresses) > 0 && filter.Addresses[0] != event.Address {
+			continue
+		}
+		events = append(events, event)
+	}
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func newTestClient(t *testing.T, backend Backend, maxLogs int) *ethclient.Client {
+	api := NewEthAPI(big.NewInt(8453))
+	api.backend = backend
+	api.MaxBlockRange = 5
+	api.MaxLogs = maxLogs
+
+	srv, err := NewServer(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Stop)
+	client := ethclient.NewClient(rpc.DialInProc(srv))
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestEthAPI(t *testing.T) {
+	blockHash := "0x1f2d3c4b5a69788796a5b4c3d2e1f00112233445566778899aabbccddeeff001"
+	txHash := "0x9a8b7c6d5e4f30211203f4e5d6c7b8a99a8b7c6d5e4f30211203f4e5d6c7b8a9"
+	token := "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	from := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	transferTopic := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+	backend := &fakeBackend{
+		blocks: []*models.Block{
+			{BlockHeight: 9, BlockHash: "0x0000000000000000000000000000000000000000000000000000000000000009", Timestamp: time.Unix(1700000000, 0)},
+			{
+				BlockHeight:   10,
+				BlockHash:     blockHash,
+				ParentHash:    "0x0000000000000000000000000000000000000000000000000000000000000009",
+				GasLimit:      30000000,
+				GasUsed:       50000,
+				Difficulty:    big.NewInt(0),
+				BaseFeePerGas: big.NewInt(1000),
+				Timestamp:     time.Unix(1700000002, 0),
+			},
+		},
+		trxs: []models.Transaction{{
+			BlockNumber:       10,
+			BlockHash:         blockHash,
+			TxHash:            txHash,
+			From:              from,
+			To:                token,
+			Value:             "0",
+			Status:            1,
+			InputData:         "0xa9059cbb",
+			Type:              2,
+			Nonce:             7,
+			Gas:               60000,
+			GasPrice:          "2000",
+			GasTipCap:         "100",
+			GasUsed:           50000,
+			CumulativeGasUsed: 50000,
+			EffectiveGasPrice: "1100",
+		}},
+		events: []models.Event{{
+			Address:     token,
+			FirstTopic:  transferTopic,
+			SecondTopic: "0x0000000000000000000000002c0e7e53fbb9d1ab31f2e39f7e4c2e3af1b24d8e",
+			Data:        "0x0000000000000000000000000000000000000000000000000000000000000064",
+			BlockNumber: 10,
+			BlockHash:   blockHash,
+			TxHash:      txHash,
+		}},
+	}
+	ctx := context.Background()
+	client := newTestClient(t, backend, 10)
+
+	t.Run("FilterLogs", func(t *testing.T) {
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: big.NewInt(8),
+			Addresses: []common.Address{common.HexToAddress(token)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != 1 {
+			t.Fatalf("FilterLogs() got %d logs, want 1", len(logs))
+		}
+		log := logs[0]
+		if log.Address.Hex() != token || log.TxHash.Hex() != txHash || len(log.Topics) != 2 || log.Topics[0].Hex() != transferTopic {
+			t.Errorf("FilterLogs() got = %+v", log)
+		}
+		if new(big.Int).SetBytes(log.Data).Int64() != 100 {
+			t.Errorf("FilterLogs() data = %x", log.Data)
+		}
+
+		if _, err := client.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(20), ToBlock: big.NewInt(10)}); err == nil {
+			t.Error("FilterLogs() expected invalid range error")
+		}
+		_, err = client.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(0), ToBlock: big.NewInt(1000)})
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32005 {
+			t.Errorf("FilterLogs() error = %v, want block range limit", err)
+		}
+		logs, err = client.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(50)})
+		if err != nil || len(logs) != 0 {
+			t.Errorf("FilterLogs() beyond latest got = %v, %v", logs, err)
+		}
+	})
+
+	t.Run("MaxLogs", func(t *testing.T) {
+		unlimited := newTestClient(t, backend, 0)
+		api := NewEthAPI(nil)
+		api.backend = backend
+		api.MaxLogs = 1
+		backend.events = append(backend.events, backend.events[0])
+		defer func() { backend.events = backend.events[:1] }()
+
+		_, err := api.GetLogs(ctx, FilterCriteria{FromBlock: blockNumber(8)})
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32005 {
+			t.Errorf("GetLogs() error = %v, want result limit", err)
+		}
+		if _, err := unlimited.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(8)}); err != nil {
+			t.Errorf("FilterLogs() without limit error = %v", err)
+		}
+	})
+
+	t.Run("BlockByNumber", func(t *testing.T) {
+		block, err := client.BlockByNumber(ctx, big.NewInt(10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if block.NumberU64() != 10 || block.GasUsed() != 50000 || block.BaseFee().Int64() != 1000 || block.Time() != 1700000002 {
+			t.Errorf("BlockByNumber() got header = %+v", block.Header())
+		}
+		if len(block.Transactions()) != 1 || block.Transactions()[0].Nonce() != 7 {
+			t.Errorf("BlockByNumber() got %d transactions", len(block.Transactions()))
+		}
+
+		empty, err := client.BlockByNumber(ctx, big.NewInt(9))
+		if err != nil || len(empty.Transactions()) != 0 {
+			t.Errorf("BlockByNumber() empty block got = %v, %v", empty, err)
+		}
+		if _, err := client.BlockByNumber(ctx, big.NewInt(11)); !errors.Is(err, ethereum.NotFound) {
+			t.Errorf("BlockByNumber() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("TransactionByHash", func(t *testing.T) {
+		tx, pending, err := client.TransactionByHash(ctx, common.HexToHash(txHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pending || tx.Type() != types.DynamicFeeTxType || tx.Nonce() != 7 || tx.Gas() != 60000 ||
+			tx.GasFeeCap().Int64() != 2000 || tx.GasTipCap().Int64() != 100 || tx.To().Hex() != token ||
+			tx.ChainId().Int64() != 8453 {
+			t.Errorf("TransactionByHash() got = %+v", tx)
+		}
+		if _, _, err := client.TransactionByHash(ctx, common.HexToHash("0x01")); !errors.Is(err, ethereum.NotFound) {
+			t.Errorf("TransactionByHash() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("TransactionReceipt", func(t *testing.T) {
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(txHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful || receipt.GasUsed != 50000 ||
+			receipt.EffectiveGasPrice.Int64() != 1100 || receipt.BlockHash.Hex() != blockHash ||
+			receipt.BlockNumber.Int64() != 10 || len(receipt.Logs) != 1 {
+			t.Errorf("TransactionReceipt() got = %+v", receipt)
+		}
+		if !receipt.Bloom.Test(common.HexToAddress(token).Bytes()) {
+			t.Error("TransactionReceipt() bloom does not contain log address")
+		}
+	})
+
+	t.Run("ChainID", func(t *testing.T) {
+		id, err := client.ChainID(ctx)
+		if err != nil || id.Int64() != 8453 {
+			t.Errorf("ChainID() got = %v, %v", id, err)
+		}
+		number, err := client.BlockNumber(ctx)
+		if err != nil || number != 10 {
+			t.Errorf("BlockNumber() got = %v, %v", number, err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package ethrpc
+
+import (
+	"context"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Backend JSON-RPC 读取数据的接口, 查询不到时返回 nil
+type Backend interface {
+	LatestHeight(ctx context.Context) (uint64, error)
+	BlockByHeight(ctx context.Context, height uint64) (*models.Block, error)
+	BlockByHash(ctx context.Context, hash string) (*models.Block, error)
+	TransactionsByBlock(ctx context.Context, blockHash string) ([]models.Transaction, error)
+	TransactionByHash(ctx context.Context, hash string) (*models.Transaction, error)
+	LogsByTransaction(ctx context.Context, txHash, blockHash string) ([]models.Event, error)
+	Logs(ctx context.Context, filter dal.LogFilter, limit int) ([]models.Event, error)
+}
+
+// dbBackend 通过 dal 读取已经入库的数据
+type dbBackend struct{}
+
+func (dbBackend) LatestHeight(ctx context.Context) (uint64, error) {
+	return dal.Block.GetMaxHeight(ctx)
+}
+
+func (dbBackend) BlockByHeight(ctx context.Context, height uint64) (*models.Block, error) {
+	return dal.IgnoreNotFound(dal.Block.GetByHeight(ctx, height))
+}
+
+func (dbBackend) BlockByHash(ctx context.Context, hash string) (*models.Block, error) {
+	return dal.IgnoreNotFound(dal.Block.GetByHash(ctx, hash))
+}
+
+func (dbBackend) TransactionsByBlock(ctx context.Context, blockHash string) ([]models.Transaction, error) {
+	return dal.Transaction.GetByBlockHash(ctx, blockHash)
+}
+
+func (dbBackend) TransactionByHash(ctx context.Context, hash string) (*models.Transaction, error) {
+	return dal.IgnoreNotFound(dal.Transaction.GetByHash(ctx, hash))
+}
+
+func (dbBackend) LogsByTransaction(ctx context.Context, txHash, blockHash string) ([]models.Event, error) {
+	return dal.Event.GetByTxHash(ctx, txHash, blockHash)
+}
+
+func (dbBackend) Logs(ctx context.Context, filter dal.LogFilter, limit int) ([]models.Event, error) {
+	return dal.Event.GetLogs(ctx, filter, limit)
+}
@@ -0,0 +1,142 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/traitmeta/metago/core/dal"
+)
+
+const maxTopics = 4
+
+var (
+	errBlockHashWithRange = errors.New("cannot specify both BlockHash and FromBlock/ToBlock, choose one or the other")
+	errExceedMaxTopics    = errors.New("exceed max topics")
+	errInvalidBlockRange  = errors.New("invalid block range params")
+)
+
+// FilterCriteria eth_getLogs 的参数, 与 go-ethereum 的 filters.FilterCriteria 格式一致
+type FilterCriteria struct {
+	BlockHash *common.Hash
+	FromBlock *rpc.BlockNumber
+	ToBlock   *rpc.BlockNumber
+	Addresses []common.Address
+	// Topics 按位置匹配, 同一位置内为 OR, nil 表示任意
+	Topics [][]common.Hash
+}
+
+// UnmarshalJSON address 可以是单个地址或数组, topics 的每个位置可以是 null、单个哈希或数组
+func (f *FilterCriteria) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash *common.Hash     `json:"blockHash"`
+		FromBlock *rpc.BlockNumber `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber `json:"toBlock"`
+		Addresses json.RawMessage  `json:"address"`
+		Topics    []interface{}    `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.BlockHash != nil && (raw.FromBlock != nil || raw.ToBlock != nil) {
+		return errBlockHashWithRange
+	}
+	f.BlockHash, f.FromBlock, f.ToBlock = raw.BlockHash, raw.FromBlock, raw.ToBlock
+
+	if len(raw.Addresses) > 0 && string(raw.Addresses) != "null" {
+		var single common.Address
+		if err := json.Unmarshal(raw.Addresses, &single); err == nil {
+			f.Addresses = []common.Address{single}
+		} else if err := json.Unmarshal(raw.Addresses, &f.Addresses); err != nil {
+			return fmt.Errorf("invalid addresses in query")
+		}
+	}
+
+	if len(raw.Topics) > maxTopics {
+		return errExceedMaxTopics
+	}
+	f.Topics = make([][]common.Hash, len(raw.Topics))
+	for i, t := range raw.Topics {
+		switch topic := t.(type) {
+		case nil:
+		case string:
+			hash, err := decodeTopic(topic)
+			if err != nil {
+				return err
+			}
+			f.Topics[i] = []common.Hash{hash}
+		case []interface{}:
+			for _, item := range topic {
+				// 数组中出现 null 时该位置匹配任意值
+				if item == nil {
+					f.Topics[i] = nil
+					break
+				}
+				s, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("invalid topic(s)")
+				}
+				hash, err := decodeTopic(s)
+				if err != nil {
+					return err
+				}
+				f.Topics[i] = append(f.Topics[i], hash)
+			}
+		default:
+			return fmt.Errorf("invalid topic(s)")
+		}
+	}
+	return nil
+}
+
+func decodeTopic(s string) (common.Hash, error) {
+	var hash common.Hash
+	if err := hash.UnmarshalText([]byte(s)); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid topic %q: %v", s, err)
+	}
+	return hash, nil
+}
+
+// toLogFilter 把参数转换为数据库查询条件, latest 等标签按已入库的最新高度处理
+func (f FilterCriteria) toLogFilter(latest uint64) (dal.LogFilter, error) {
+	filter := dal.LogFilter{
+		Addresses: make([]string, 0, len(f.Addresses)),
+		Topics:    make([][]string, len(f.Topics)),
+	}
+	for _, address := range f.Addresses {
+		filter.Addresses = append(filter.Addresses, address.Hex())
+	}
+	for i, topics := range f.Topics {
+		for _, topic := range topics {
+			filter.Topics[i] = append(filter.Topics[i], topic.Hex())
+		}
+	}
+
+	if f.BlockHash != nil {
+		filter.BlockHash = f.BlockHash.Hex()
+		return filter, nil
+	}
+
+	if f.FromBlock != nil && f.ToBlock != nil && *f.FromBlock >= 0 && *f.ToBlock >= 0 && *f.FromBlock > *f.ToBlock {
+		return filter, errInvalidBlockRange
+	}
+	// 超出最新高度的部分没有数据, FromBlock 大于 ToBlock 时结果为空
+	filter.FromBlock = resolveBlockNumber(f.FromBlock, latest)
+	filter.ToBlock = resolveBlockNumber(f.ToBlock, latest)
+	if filter.ToBlock > latest {
+		filter.ToBlock = latest
+	}
+	return filter, nil
+}
+
+// resolveBlockNumber 未指定和 latest/pending/safe/finalized 都按最新高度处理
+func resolveBlockNumber(number *rpc.BlockNumber, latest uint64) uint64 {
+	if number == nil || *number < 0 {
+		return latest
+	}
+	return uint64(*number)
+}
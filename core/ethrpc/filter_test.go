@@ -0,0 +1,139 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/traitmeta/metago/core/dal"
+)
+
+func blockNumber(n int64) *rpc.BlockNumber {
+	number := rpc.BlockNumber(n)
+	return &number
+}
+
+func TestFilterCriteria_UnmarshalJSON(t *testing.T) {
+	addr1 := common.HexToAddress("0x4200000000000000000000000000000000000006")
+	addr2 := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	topic1 := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	topic2 := common.HexToHash("0x0000000000000000000000002c0e7e53fbb9d1ab31f2e39f7e4c2e3af1b24d8e")
+	blockHash := common.HexToHash("0x1f2d3c4b5a69788796a5b4c3d2e1f00112233445566778899aabbccddeeff001")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    FilterCriteria
+		wantErr error
+	}{
+		{
+			name:  "range with single address",
+			input: `{"fromBlock":"0x10","toBlock":"latest","address":"` + addr1.Hex() + `"}`,
+			want: FilterCriteria{
+				FromBlock: blockNumber(16),
+				ToBlock:   blockNumber(int64(rpc.LatestBlockNumber)),
+				Addresses: []common.Address{addr1},
+				Topics:    [][]common.Hash{},
+			},
+		},
+		{
+			name:  "address array and topic positions",
+			input: `{"address":["` + addr1.Hex() + `","` + addr2.Hex() + `"],"topics":["` + topic1.Hex() + `",null,["` + topic1.Hex() + `","` + topic2.Hex() + `"]]}`,
+			want: FilterCriteria{
+				Addresses: []common.Address{addr1, addr2},
+				Topics:    [][]common.Hash{{topic1}, nil, {topic1, topic2}},
+			},
+		},
+		{
+			name:  "null inside topic array matches anything",
+			input: `{"topics":[["` + topic1.Hex() + `",null]]}`,
+			want:  FilterCriteria{Topics: [][]common.Hash{nil}},
+		},
+		{
+			name:  "block hash",
+			input: `{"blockHash":"` + blockHash.Hex() + `"}`,
+			want:  FilterCriteria{BlockHash: &blockHash, Topics: [][]common.Hash{}},
+		},
+		{
+			name:    "block hash with range",
+			input:   `{"blockHash":"` + blockHash.Hex() + `","fromBlock":"0x1"}`,
+			wantErr: errBlockHashWithRange,
+		},
+		{
+			name:    "too many topics",
+			input:   `{"topics":[null,null,null,null,null]}`,
+			wantErr: errExceedMaxTopics,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FilterCriteria
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalJSON() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterCriteria_toLogFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		crit     FilterCriteria
+		wantFrom uint64
+		wantTo   uint64
+		wantErr  error
+	}{
+		{name: "defaults to latest", crit: FilterCriteria{}, wantFrom: 100, wantTo: 100},
+		{name: "explicit range", crit: FilterCriteria{FromBlock: blockNumber(10), ToBlock: blockNumber(20)}, wantFrom: 10, wantTo: 20},
+		{name: "to clamped to latest", crit: FilterCriteria{FromBlock: blockNumber(90), ToBlock: blockNumber(200)}, wantFrom: 90, wantTo: 100},
+		{name: "from beyond latest", crit: FilterCriteria{FromBlock: blockNumber(150)}, wantFrom: 150, wantTo: 100},
+		{name: "pending tag", crit: FilterCriteria{FromBlock: blockNumber(1), ToBlock: blockNumber(int64(rpc.PendingBlockNumber))}, wantFrom: 1, wantTo: 100},
+		{name: "reversed range", crit: FilterCriteria{FromBlock: blockNumber(20), ToBlock: blockNumber(10)}, wantErr: errInvalidBlockRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.crit.toLogFilter(100)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("toLogFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.FromBlock != tt.wantFrom || got.ToBlock != tt.wantTo {
+				t.Errorf("toLogFilter() got = [%d, %d], want [%d, %d]", got.FromBlock, got.ToBlock, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+
+	topic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	got, err := FilterCriteria{
+		Addresses: []common.Address{common.HexToAddress("0x4200000000000000000000000000000000000006")},
+		Topics:    [][]common.Hash{nil, {topic}},
+	}.toLogFilter(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dal.LogFilter{
+		FromBlock: 100,
+		ToBlock:   100,
+		Addresses: []string{"0x4200000000000000000000000000000000000006"},
+		Topics:    [][]string{nil, {topic.Hex()}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toLogFilter() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+package ethrpc
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+/*
+## Overview
+  返回格式与 go-ethereum 节点一致, 但数据库中没有保存的字段使用默认值:
+  - 区块的 stateRoot/logsBloom/mixHash 为零值, 没有交易时 transactionsRoot/receiptsRoot 为空树哈希,
+    有交易时为零值, 因此客户端重新计算的区块哈希与返回的 hash 不同.
+  - 交易的签名 v/r/s 为0, 发送方通过 from 返回, typed 交易不包含 accessList.
+*/
+
+func toLog(event models.Event) *types.Log {
+	log := &types.Log{
+		Address:     common.HexToAddress(event.Address),
+		Data:        common.FromHex(event.Data),
+		BlockNumber: event.BlockNumber,
+		TxHash:      common.HexToHash(event.TxHash),
+		TxIndex:     event.TxIndex,
+		BlockHash:   common.HexToHash(event.BlockHash),
+		Index:       event.LogIndex,
+		Removed:     event.Removed,
+		Topics:      []common.Hash{},
+	}
+	for _, topic := range []string{event.FirstTopic, event.SecondTopic, event.ThirdTopic, event.FourthTopic} {
+		if topic == "" {
+			break
+		}
+		log.Topics = append(log.Topics, common.HexToHash(topic))
+	}
+	return log
+}
+
+func toLogs(events []models.Event) []*types.Log {
+	logs := make([]*types.Log, 0, len(events))
+	for _, event := range events {
+		logs = append(logs, toLog(event))
+	}
+	return logs
+}
+
+func decimalToBig(s string) *hexutil.Big {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		v = new(big.Int)
+	}
+	return (*hexutil.Big)(v)
+}
+
+// toAddress 合约创建交易没有 to
+func toAddress(trx *models.Transaction) *common.Address {
+	if trx.ContractCreation {
+		return nil
+	}
+	to := trx.To
+	if to == "" {
+		to = trx.Contract
+	}
+	if to == "" {
+		return nil
+	}
+	address := common.HexToAddress(to)
+	return &address
+}
+
+func formatTransaction(trx *models.Transaction, blockHash string, chainId *big.Int) map[string]interface{} {
+	fields := map[string]interface{}{
+		"blockHash":        common.HexToHash(blockHash),
+		"blockNumber":      hexutil.Uint64(trx.BlockNumber),
+		"from":             common.HexToAddress(trx.From),
+		"gas":              hexutil.Uint64(trx.Gas),
+		"gasPrice":         decimalToBig(trx.GasPrice),
+		"hash":             common.HexToHash(trx.TxHash),
+		"input":            hexutil.Bytes(common.FromHex(trx.InputData)),
+		"nonce":            hexutil.Uint64(trx.Nonce),
+		"to":               toAddress(trx),
+		"transactionIndex": hexutil.Uint64(trx.TxIndex),
+		"value":            decimalToBig(trx.Value),
+		"type":             hexutil.Uint64(trx.Type),
+		"v":                (*hexutil.Big)(new(big.Int)),
+		"r":                (*hexutil.Big)(new(big.Int)),
+		"s":                (*hexutil.Big)(new(big.Int)),
+	}
+	if trx.Type != types.LegacyTxType && chainId != nil {
+		fields["chainId"] = (*hexutil.Big)(chainId)
+	}
+	if trx.Type >= types.DynamicFeeTxType {
+		fields["maxFeePerGas"] = decimalToBig(trx.GasPrice)
+		fields["maxPriorityFeePerGas"] = decimalToBig(trx.GasTipCap)
+	}
+	return fields
+}
+
+func formatReceipt(trx *models.Transaction, blockHash string, events []models.Event) map[string]interface{} {
+	logs := toLogs(events)
+	fields := map[string]interface{}{
+		"blockHash":         common.HexToHash(blockHash),
+		"blockNumber":       hexutil.Uint64(trx.BlockNumber),
+		"transactionHash":   common.HexToHash(trx.TxHash),
+		"transactionIndex":  hexutil.Uint64(trx.TxIndex),
+		"from":              common.HexToAddress(trx.From),
+		"to":                toAddress(trx),
+		"gasUsed":           hexutil.Uint64(trx.GasUsed),
+		"cumulativeGasUsed": hexutil.Uint64(trx.CumulativeGasUsed),
+		"effectiveGasPrice": decimalToBig(trx.EffectiveGasPrice),
+		"contractAddress":   nil,
+		"logs":              logs,
+		"logsBloom":         types.CreateBloom(types.Receipts{{Logs: logs}}),
+		"status":            hexutil.Uint64(trx.Status),
+		"type":              hexutil.Uint64(trx.Type),
+	}
+	if trx.ContractCreation {
+		fields["contractAddress"] = common.HexToAddress(trx.Contract)
+	}
+	return fields
+}
+
+func formatBlock(block *models.Block, trxs []models.Transaction, fullTx bool, chainId *big.Int) map[string]interface{} {
+	nonce, _ := hexutil.DecodeUint64(block.Nonce)
+	txsRoot, receiptsRoot := types.EmptyTxsHash, types.EmptyReceiptsHash
+	if len(trxs) > 0 {
+		txsRoot, receiptsRoot = common.Hash{}, common.Hash{}
+	}
+	difficulty := block.Difficulty
+	if difficulty == nil {
+		difficulty = new(big.Int)
+	}
+
+	fields := map[string]interface{}{
+		"number":           hexutil.Uint64(block.BlockHeight),
+		"hash":             common.HexToHash(block.BlockHash),
+		"parentHash":       common.HexToHash(block.ParentHash),
+		"nonce":            types.EncodeNonce(nonce),
+		"mixHash":          common.Hash{},
+		"sha3Uncles":       types.EmptyUncleHash,
+		"logsBloom":        types.Bloom{},
+		"stateRoot":        common.Hash{},
+		"transactionsRoot": txsRoot,
+		"receiptsRoot":     receiptsRoot,
+		"miner":            common.HexToAddress(block.MinerHash),
+		"difficulty":       (*hexutil.Big)(difficulty),
+		"extraData":        hexutil.Bytes{},
+		"size":             hexutil.Uint64(block.Size),
+		"gasLimit":         hexutil.Uint64(block.GasLimit),
+		"gasUsed":          hexutil.Uint64(block.GasUsed),
+		"timestamp":        hexutil.Uint64(block.Timestamp.Unix()),
+		"uncles":           []common.Hash{},
+	}
+	if block.BaseFeePerGas != nil {
+		fields["baseFeePerGas"] = (*hexutil.Big)(block.BaseFeePerGas)
+	}
+
+	if fullTx {
+		txs := make([]map[string]interface{}, 0, len(trxs))
+		for i := range trxs {
+			txs = append(txs, formatTransaction(&trxs[i], block.BlockHash, chainId))
+		}
+		fields["transactions"] = txs
+	} else {
+		hashes := make([]common.Hash, 0, len(trxs))
+		for _, trx := range trxs {
+			hashes = append(hashes, common.HexToHash(trx.TxHash))
+		}
+		fields["transactions"] = hashes
+	}
+	return fields
+}
@@ -0,0 +1,142 @@
+/*
+## Overview
+explorer 基于 core/dal 提供区块浏览器的 REST 接口.
+
+  - 区块、交易、日志、地址概况、Token 元信息和 Token 转移, 地址概况包含管理后台添加的标签
+  - 交易和 Token 转移中的地址附带 ENS 主名称, /names/{name} 从已索引的 ENS 记录正向解析
+  - 有价格的 ERC-20 Token 转移和余额附带美元价值, 价格由 core/pricing 定时更新, 列表接口统一使用 offset/limit 分页
+  - 响应统一为 {"code", "msg", "data"}, 错误使用 pkg/errcode.Err 的错误码和 HTTP 状态码
+  - 路由表同时用于请求分发和生成 OpenAPI 文档, 文档通过 /openapi.json 提供
+*/
+package explorer
+
+import (
+	"context"
+	"time"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/ens"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Backend 浏览器读取数据的接口, 查询不到时返回 nil
+type Backend interface {
+	Blocks(ctx context.Context, offset, limit int) ([]models.Block, error)
+	BlockByHeight(ctx context.Context, height uint64) (*models.Block, error)
+	BlockByHash(ctx context.Context, hash string) (*models.Block, error)
+	Transactions(ctx context.Context, offset, limit int) ([]models.Transaction, error)
+	TransactionsByBlock(ctx context.Context, blockHash string) ([]models.Transaction, error)
+	TransactionByHash(ctx context.Context, hash string) (*models.Transaction, error)
+	TransactionsByAddress(ctx context.Context, address string, offset, limit int) ([]models.Transaction, error)
+	LogsByTransaction(ctx context.Context, txHash, blockHash string) ([]models.Event, error)
+	Logs(ctx context.Context, filter dal.LogFilter, offset, limit int) ([]models.Event, error)
+	LatestHeight(ctx context.Context) (uint64, error)
+	AddressStats(ctx context.Context, address string) (*dal.AddressStats, error)
+	IsContract(ctx context.Context, address string) (bool, error)
+	TokenTransferCount(ctx context.Context, address string) (int64, error)
+	Token(ctx context.Context, contractAddress string) (*models.Token, error)
+	AddressLabels(ctx context.Context, address string) ([]models.AddressLabel, error)
+	PrimaryNames(ctx context.Context, addresses []string) (map[string]string, error)
+	ResolveName(ctx context.Context, name string) (*models.EnsName, error)
+	Tokens(ctx context.Context, contractAddresses []string) ([]models.Token, error)
+	TokenBalances(ctx context.Context, address string) ([]dal.TokenBalance, error)
+	PriceHistory(ctx context.Context, token string, from, to time.Time, limit int) ([]models.TokenPrice, error)
+	TokenTransfersByTransaction(ctx context.Context, txHash, blockHash string) ([]models.TokenTransfer, error)
+	TokenTransfersByToken(ctx context.Context, token string, offset, limit int) ([]models.TokenTransfer, error)
+	TokenTransfersByAddress(ctx context.Context, address string, offset, limit int) ([]models.TokenTransfer, error)
+}
+
+// dbBackend 通过 dal 读取已经入库的数据
+type dbBackend struct{}
+
+func (dbBackend) Blocks(ctx context.Context, offset, limit int) ([]models.Block, error) {
+	return dal.Block.List(ctx, offset, limit)
+}
+
+func (dbBackend) BlockByHeight(ctx context.Context, height uint64) (*models.Block, error) {
+	return dal.IgnoreNotFound(dal.Block.GetByHeight(ctx, height))
+}
+
+func (dbBackend) BlockByHash(ctx context.Context, hash string) (*models.Block, error) {
+	return dal.IgnoreNotFound(dal.Block.GetByHash(ctx, hash))
+}
+
+func (dbBackend) Transactions(ctx context.Context, offset, limit int) ([]models.Transaction, error) {
+	return dal.Transaction.List(ctx, offset, limit)
+}
+
+func (dbBackend) TransactionsByBlock(ctx context.Context, blockHash string) ([]models.Transaction, error) {
+	return dal.Transaction.GetByBlockHash(ctx, blockHash)
+}
+
+func (dbBackend) TransactionByHash(ctx context.Context, hash string) (*models.Transaction, error) {
+	return dal.IgnoreNotFound(dal.Transaction.GetByHash(ctx, hash))
+}
+
+func (dbBackend) TransactionsByAddress(ctx context.Context, address string, offset, limit int) ([]models.Transaction, error) {
+	return dal.Transaction.ListByAddress(ctx, address, offset, limit)
+}
+
+func (dbBackend) LogsByTransaction(ctx context.Context, txHash, blockHash string) ([]models.Event, error) {
+	return dal.Event.GetByTxHash(ctx, txHash, blockHash)
+}
+
+func (dbBackend) Logs(ctx context.Context, filter dal.LogFilter, offset, limit int) ([]models.Event, error) {
+	return dal.Event.ListLogs(ctx, filter, offset, limit)
+}
+
+func (dbBackend) LatestHeight(ctx context.Context) (uint64, error) {
+	return dal.Block.GetMaxHeight(ctx)
+}
+
+func (dbBackend) AddressStats(ctx context.Context, address string) (*dal.AddressStats, error) {
+	return dal.Transaction.GetAddressStats(ctx, address)
+}
+
+func (dbBackend) IsContract(ctx context.Context, address string) (bool, error) {
+	return dal.Transaction.IsContract(ctx, address)
+}
+
+func (dbBackend) AddressLabels(ctx context.Context, address string) ([]models.AddressLabel, error) {
+	return dal.Admin.ListLabels(ctx, address)
+}
+
+func (dbBackend) PrimaryNames(ctx context.Context, addresses []string) (map[string]string, error) {
+	return ens.PrimaryNames(ctx, addresses)
+}
+
+func (dbBackend) ResolveName(ctx context.Context, name string) (*models.EnsName, error) {
+	return ens.Resolve(ctx, name)
+}
+
+func (dbBackend) TokenTransferCount(ctx context.Context, address string) (int64, error) {
+	return dal.TokenTransfer.CountByAddress(ctx, address)
+}
+
+func (dbBackend) Token(ctx context.Context, contractAddress string) (*models.Token, error) {
+	return dal.IgnoreNotFound(dal.Token.GetByContractAddress(ctx, contractAddress))
+}
+
+func (dbBackend) Tokens(ctx context.Context, contractAddresses []string) ([]models.Token, error) {
+	return dal.Token.GetByContractAddresses(ctx, contractAddresses)
+}
+
+func (dbBackend) TokenBalances(ctx context.Context, address string) ([]dal.TokenBalance, error) {
+	return dal.TokenTransfer.SumBalancesByAddress(ctx, address)
+}
+
+func (dbBackend) PriceHistory(ctx context.Context, token string, from, to time.Time, limit int) ([]models.TokenPrice, error) {
+	return dal.Price.ListHistory(ctx, token, from, to, limit)
+}
+
+func (dbBackend) TokenTransfersByTransaction(ctx context.Context, txHash, blockHash string) ([]models.TokenTransfer, error) {
+	return dal.TokenTransfer.GetByTxHash(ctx, txHash, blockHash)
+}
+
+func (dbBackend) TokenTransfersByToken(ctx context.Context, token string, offset, limit int) ([]models.TokenTransfer, error) {
+	return dal.TokenTransfer.ListByToken(ctx, token, offset, limit)
+}
+
+func (dbBackend) TokenTransfersByAddress(ctx context.Context, address string, offset, limit int) ([]models.TokenTransfer, error) {
+	return dal.TokenTransfer.ListByAddress(ctx, address, offset, limit)
+}
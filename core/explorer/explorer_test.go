@@ -0,0 +1,423 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	blockHash = "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6"
+	txHash    = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	holder    = "0x28C6c06298d514Db089934071355E5743bf21d60"
+	usdc      = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+)
+
+type fakeBackend struct {
+	blocks    []models.Block
+	trxs      []models.Transaction
+	events    []models.Event
+	transfers []models.TokenTransfer
+	tokens    map[string]*models.Token
+	labels    []models.AddressLabel
+	balances  []dal.TokenBalance
+	prices    []models.TokenPrice
+	names     map[string]string
+	ensNames  map[string]*models.EnsName
+	filter    dal.LogFilter
+	err       error
+}
+
+func window[T any](rows []T, offset, limit int) []T {
+	if offset >= len(rows) {
+		return nil
+	}
+	return rows[offset:min(offset+limit, len(rows))]
+}
+
+func (f *fakeBackend) Blocks(_ context.Context, offset, limit int) ([]models.Block, error) {
+	return window(f.blocks, offset, limit), f.err
+}
+
+func (f *fakeBackend) BlockByHeight(_ context.Context, height uint64) (*models.Block, error) {
+	for i := range f.blocks {
+		if f.blocks[i].BlockHeight == height {
+			return &f.blocks[i], nil
+		}
+	}
+	return nil, f.err
+}
+
+func (f *fakeBackend) BlockByHash(_ context.Context, hash string) (*models.Block, error) {
+	for i := range f.blocks {
+		if f.blocks[i].BlockHash == hash {
+			return &f.blocks[i], nil
+		}
+	}
+	return nil, f.err
+}
+
+func (f *fakeBackend) Transactions(_ context.Context, offset, limit int) ([]models.Transaction, error) {
+	return window(f.trxs, offset, limit), f.err
+}
+
+func (f *fakeBackend) TransactionsByBlock(_ context.Context, hash string) ([]models.Transaction, error) {
+	var out []models.Transaction
+	for _, trx := range f.trxs {
+		if trx.BlockHash == hash {
+			out = append(out, trx)
+		}
+	}
+	return out, f.err
+}
+
+func (f *fakeBackend) TransactionByHash(_ context.Context, hash string) (*models.Transaction, error) {
+	for i := range f.trxs {
+		if f.trxs[i].TxHash == hash {
+			return &f.trxs[i], nil
+		}
+	}
+	return nil, f.err
+}
+
+func (f *fakeBackend) TransactionsByAddress(_ context.Context, address string, offset, limit int) ([]models.Transaction, error) {
+	var out []models.Transaction
+	for _, trx := range f.trxs {
+		if trx.From == address || trx.To == address || trx.Contract == address {
+			out = append(out, trx)
+		}
+	}
+	return window(out, offset, limit), f.err
+}
+
+func (f *fakeBackend) LogsByTransaction(_ context.Context, txHash, _ string) ([]models.Event, error) {
+	var out []models.Event
+	for _, e := range f.events {
+		if e.TxHash == txHash {
+			out = append(out, e)
+		}
+	}
+	return out, f.err
+}
+
+func (f *fakeBackend) Logs(_ context.Context, filter dal.LogFilter, offset, limit int) ([]models.Event, error) {
+	f.filter = filter
+	return window(f.events, offset, limit), f.err
+}
+
+func (f *fakeBackend) LatestHeight(context.Context) (uint64, error) {
+	return 100, f.err
+}
+
+func (f *fakeBackend) AddressStats(_ context.Context, address string) (*dal.AddressStats, error) {
+	trxs, _ := f.TransactionsByAddress(context.Background(), address, 0, len(f.trxs))
+	stats := &dal.AddressStats{Count: int64(len(trxs))}
+	if len(trxs) > 0 {
+		stats.FirstBlock, stats.LastBlock = trxs[0].BlockNumber, trxs[len(trxs)-1].BlockNumber
+	}
+	return stats, f.err
+}
+
+func (f *fakeBackend) IsContract(_ context.Context, address string) (bool, error) {
+	for _, trx := range f.trxs {
+		if trx.Contract == address {
+			return true, f.err
+		}
+	}
+	return false, f.err
+}
+
+func (f *fakeBackend) TokenTransferCount(_ context.Context, address string) (int64, error) {
+	transfers, _ := f.TokenTransfersByAddress(context.Background(), address, 0, len(f.transfers))
+	return int64(len(transfers)), f.err
+}
+
+func (f *fakeBackend) Token(_ context.Context, address string) (*models.Token, error) {
+	return f.tokens[address], f.err
+}
+
+func (f *fakeBackend) AddressLabels(_ context.Context, address string) ([]models.AddressLabel, error) {
+	var out []models.AddressLabel
+	for _, l := range f.labels {
+		if l.Address == address {
+			out = append(out, l)
+		}
+	}
+	return out, f.err
+}
+
+func (f *fakeBackend) PrimaryNames(_ context.Context, addresses []string) (map[string]string, error) {
+	names := map[string]string{}
+	for _, address := range addresses {
+		if name, ok := f.names[address]; ok {
+			names[address] = name
+		}
+	}
+	return names, f.err
+}
+
+func (f *fakeBackend) ResolveName(_ context.Context, name string) (*models.EnsName, error) {
+	return f.ensNames[name], f.err
+}
+
+func (f *fakeBackend) Tokens(_ context.Context, addresses []string) ([]models.Token, error) {
+	var out []models.Token
+	for _, address := range addresses {
+		if t := f.tokens[address]; t != nil {
+			out = append(out, *t)
+		}
+	}
+	return out, f.err
+}
+
+func (f *fakeBackend) TokenBalances(_ context.Context, address string) ([]dal.TokenBalance, error) {
+	if address != holder {
+		return nil, f.err
+	}
+	return f.balances, f.err
+}
+
+func (f *fakeBackend) PriceHistory(_ context.Context, token string, from, to time.Time, limit int) ([]models.TokenPrice, error) {
+	var out []models.TokenPrice
+	for _, p := range f.prices {
+		if p.ContractAddress == token && !p.FetchedAt.Before(from) && !p.FetchedAt.After(to) {
+			out = append(out, p)
+		}
+	}
+	return window(out, 0, limit), f.err
+}
+
+func (f *fakeBackend) TokenTransfersByTransaction(_ context.Context, txHash, _ string) ([]models.TokenTransfer, error) {
+	var out []models.TokenTransfer
+	for _, t := range f.transfers {
+		if t.TransactionHash == txHash {
+			out = append(out, t)
+		}
+	}
+	return out, f.err
+}
+
+func (f *fakeBackend) TokenTransfersByToken(_ context.Context, token string, offset, limit int) ([]models.TokenTransfer, error) {
+	var out []models.TokenTransfer
+	for _, t := range f.transfers {
+		if t.TokenContractAddress == token {
+			out = append(out, t)
+		}
+	}
+	return window(out, offset, limit), f.err
+}
+
+func (f *fakeBackend) TokenTransfersByAddress(_ context.Context, address string, offset, limit int) ([]models.TokenTransfer, error) {
+	var out []models.TokenTransfer
+	for _, t := range f.transfers {
+		if t.FromAddress == address || t.ToAddress == address {
+			out = append(out, t)
+		}
+	}
+	return window(out, offset, limit), f.err
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		blocks: []models.Block{
+			{BlockHeight: 11, BlockHash: "0x" + strings.Repeat("1", 64)},
+			{BlockHeight: 10, BlockHash: blockHash, Difficulty: big.NewInt(2)},
+		},
+		trxs: []models.Transaction{{
+			TxHash: txHash, BlockHash: blockHash, BlockNumber: 10, From: holder, Contract: usdc,
+			Value: "0", Status: 1, InputData: "a9059cbb",
+		}},
+		events: []models.Event{{
+			Address: usdc, TxHash: txHash, BlockHash: blockHash, BlockNumber: 10,
+			FirstTopic: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", Data: "01",
+		}},
+		transfers: []models.TokenTransfer{{
+			TransactionHash: txHash, BlockHash: blockHash, BlockNumber: 10, TokenContractAddress: usdc,
+			FromAddress: holder, ToAddress: usdc, Amount: big.NewInt(1),
+		}},
+		tokens: map[string]*models.Token{usdc: {ContractAddress: usdc, Name: "USD Coin", Symbol: "USDC", Decimals: 6, Type: "ERC-20", FiatValue: "2"}},
+		labels: []models.AddressLabel{{Address: holder, Label: models.AddressLabelExchange, Name: "Binance 14"}},
+		balances: []dal.TokenBalance{
+			{Token: "0x0000000000000000000000000000000000000001", Balance: "5"},
+			{Token: usdc, Balance: "3000000"},
+		},
+		prices: []models.TokenPrice{
+			{ContractAddress: usdc, Source: "file", PriceUsd: 2, FetchedAt: time.Now().Add(-time.Hour)},
+			{ContractAddress: usdc, Source: "file", PriceUsd: 1, FetchedAt: time.Now().Add(-30 * 24 * time.Hour)},
+		},
+		names: map[string]string{holder: "binance14.eth"},
+		ensNames: map[string]*models.EnsName{"binance14.eth": {
+			Node: "0x" + strings.Repeat("ab", 32), Name: "binance14.eth", Address: holder, Resolver: usdc,
+		}},
+	}
+}
+
+func get(t *testing.T, srv http.Handler, path string) (int, Response, json.RawMessage) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body struct {
+		Response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: %v: %s", path, err, rec.Body.String())
+	}
+	return rec.Code, body.Response, body.Data
+}
+
+func TestServer(t *testing.T) {
+	srv := newServer(DefaultPrefix, newFakeBackend())
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		code     uint32
+		contains string
+	}{
+		{"block by height", "/api/v1/blocks/10", 200, 200, `"hash":"` + blockHash},
+		{"malformed block hash", "/api/v1/blocks/0x" + blockHash[3:], 400, 20002, ""},
+		{"block by hash", "/api/v1/blocks/0x" + strings.ToUpper(blockHash[2:]), 200, 200, `"height":10`},
+		{"block not found", "/api/v1/blocks/12", 404, 22000, ""},
+		{"invalid block id", "/api/v1/blocks/latest", 400, 20002, ""},
+		{"block transactions", "/api/v1/blocks/10/transactions", 200, 200, `"hash":"` + txHash},
+		{"blocks page", "/api/v1/blocks?limit=1", 200, 200, `"has_more":true`},
+		{"last blocks page", "/api/v1/blocks?offset=1&limit=1", 200, 200, `"has_more":false`},
+		{"invalid limit", "/api/v1/blocks?limit=ten", 400, 20002, ""},
+		{"transaction detail", "/api/v1/transactions/" + txHash, 200, 200, `"topics":["0xddf252ad`},
+		{"transaction token transfers", "/api/v1/transactions/" + txHash + "/token-transfers", 200, 200, `"amount":"1"`},
+		{"transaction not found", "/api/v1/transactions/0x" + strings.Repeat("0", 64), 404, 22000, ""},
+		{"address summary", "/api/v1/addresses/" + strings.ToLower(holder), 200, 200, `"transaction_count":1`},
+		{"address labels", "/api/v1/addresses/" + holder, 200, 200, `"labels":[{"label":"exchange","name":"Binance 14"}]`},
+		{"contract summary", "/api/v1/addresses/" + usdc, 200, 200, `"symbol":"USDC"`},
+		{"invalid address", "/api/v1/addresses/0x1234", 400, 20002, ""},
+		{"address transfers", "/api/v1/addresses/" + holder + "/token-transfers", 200, 200, `"token":"` + usdc},
+		{"token", "/api/v1/tokens/" + usdc, 200, 200, `"decimals":6`},
+		{"token not found", "/api/v1/tokens/" + holder, 404, 22000, ""},
+		{"token transfers", "/api/v1/tokens/" + usdc + "/transfers", 200, 200, `"from":"` + holder},
+		{"token price", "/api/v1/tokens/" + usdc, 200, 200, `"price_usd":"2"`},
+		{"address ens name", "/api/v1/addresses/" + holder, 200, 200, `"ens_name":"binance14.eth"`},
+		{"transaction ens names", "/api/v1/transactions/" + txHash, 200, 200, `"from":"` + holder + `","from_name":"binance14.eth"`},
+		{"transfer ens names", "/api/v1/tokens/" + usdc + "/transfers", 200, 200, `"from_name":"binance14.eth","to":"` + usdc + `","amount"`},
+		{"address transactions ens names", "/api/v1/addresses/" + holder + "/transactions", 200, 200, `"from_name":"binance14.eth"`},
+		{"resolve name", "/api/v1/names/Binance14.ETH", 200, 200, `"name":"binance14.eth","node":"0x` + strings.Repeat("ab", 32) + `","address":"` + holder + `"`},
+		{"unknown name", "/api/v1/names/nobody.eth", 404, 22000, ""},
+		{"transfer usd value", "/api/v1/transactions/" + txHash + "/token-transfers", 200, 200, `"amount_usd":"0.000002"`},
+		{"address transfers usd value", "/api/v1/addresses/" + holder + "/token-transfers", 200, 200, `"amount_usd":"0.000002"`},
+		{"token balances", "/api/v1/addresses/" + holder + "/token-balances", 200, 200,
+			`[{"token":"` + usdc + `","name":"USD Coin","symbol":"USDC","decimals":6,"balance":"3000000","price_usd":"2","value_usd":"6"},` +
+				`{"token":"0x0000000000000000000000000000000000000001","decimals":0,"balance":"5"}]`},
+		{"token prices", "/api/v1/tokens/" + usdc + "/prices", 200, 200, `[{"price_usd":2,"source":"file"`},
+		{"token prices window", "/api/v1/tokens/" + usdc + "/prices?from=0", 200, 200, `"price_usd":1,`},
+		{"invalid price window", "/api/v1/tokens/" + usdc + "/prices?from=10&to=5", 400, 20002, ""},
+		{"prices of unknown token", "/api/v1/tokens/" + holder + "/prices", 404, 22000, ""},
+		{"unknown path", "/api/v1/nothing", 404, 20000, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp, data := get(t, srv, tt.path)
+			if status != tt.status || resp.Code != tt.code {
+				t.Fatalf("GET %s = %d %+v, want %d code %d", tt.path, status, resp, tt.status, tt.code)
+			}
+			if !strings.Contains(string(data), tt.contains) {
+				t.Fatalf("GET %s data = %s, want %s", tt.path, data, tt.contains)
+			}
+		})
+	}
+}
+
+func TestListLogs(t *testing.T) {
+	backend := newFakeBackend()
+	srv := newServer(DefaultPrefix, backend)
+
+	topic := "0xDDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF"
+	status, _, data := get(t, srv, "/api/v1/logs?address="+strings.ToLower(usdc)+"&from_block=5&topic0="+topic)
+	if status != 200 || !strings.Contains(string(data), `"log_index":0`) {
+		t.Fatalf("GET /logs = %d %s", status, data)
+	}
+	f := backend.filter
+	if f.FromBlock != 5 || f.ToBlock != 100 || f.Addresses[0] != usdc || f.Topics[0][0] != strings.ToLower(topic) {
+		t.Fatalf("filter = %+v", f)
+	}
+
+	if status, _, _ := get(t, srv, "/api/v1/logs?from_block=5&to_block=4"); status != 400 {
+		t.Fatalf("GET /logs with to_block < from_block = %d, want 400", status)
+	}
+}
+
+func TestBackendError(t *testing.T) {
+	backend := newFakeBackend()
+	backend.err = errors.New("connection refused")
+	status, resp, _ := get(t, newServer(DefaultPrefix, backend), "/api/v1/blocks")
+	if status != 500 || resp.Code != 16999 {
+		t.Fatalf("GET /blocks = %d %+v, want 500", status, resp)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	srv := newServer(DefaultPrefix, newFakeBackend())
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	var doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Paths) != len(srv.Routes()) {
+		t.Fatalf("openapi has %d paths, want %d", len(doc.Paths), len(srv.Routes()))
+	}
+	if _, ok := doc.Paths["/transactions/{hash}"]["get"]; !ok {
+		t.Fatal("openapi is missing GET /transactions/{hash}")
+	}
+	detail := doc.Components.Schemas["TransactionDetail"].Properties
+	for _, field := range []string{"hash", "logs", "token_transfers"} {
+		if _, ok := detail[field]; !ok {
+			t.Errorf("TransactionDetail schema is missing %s", field)
+		}
+	}
+	if _, ok := doc.Components.Schemas["Block"].Properties["timestamp"]; !ok {
+		t.Error("Block schema is missing timestamp")
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          map[string]string
+	}{
+		{"/blocks/{id}", "/blocks/10", map[string]string{"id": "10"}},
+		{"/blocks/{id}", "/blocks/10/transactions", nil},
+		{"/blocks", "/blocks/", map[string]string{}},
+		{"/tokens/{address}/transfers", "/tokens/0xab/holders", nil},
+	}
+	for _, tt := range tests {
+		got, ok := matchPath(tt.pattern, tt.path)
+		if ok != (tt.want != nil) || len(got) != len(tt.want) {
+			t.Errorf("matchPath(%s, %s) = %v %v", tt.pattern, tt.path, got, ok)
+			continue
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("matchPath(%s, %s)[%s] = %s, want %s", tt.pattern, tt.path, k, got[k], v)
+			}
+		}
+	}
+}
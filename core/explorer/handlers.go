@@ -0,0 +1,438 @@
+package explorer
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/errcode"
+)
+
+var hashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// AddressVar 地址按 checksum 格式入库, 参数统一转换
+func AddressVar(r *Request, name string) (string, error) {
+	v := r.Var(name)
+	if !common.IsHexAddress(v) {
+		return "", InvalidParam(name)
+	}
+	return common.HexToAddress(v).Hex(), nil
+}
+
+// hashVar 区块和交易哈希按小写入库
+func hashVar(r *Request, name string) (string, error) {
+	v := r.Var(name)
+	if !hashPattern.MatchString(v) {
+		return "", InvalidParam(name)
+	}
+	return strings.ToLower(v), nil
+}
+
+func (s *Server) explorerRoutes() []Route {
+	addressParam := Param{Name: "address", In: "path", Type: "string", Description: "account or contract address", Required: true}
+	hashParam := Param{Name: "hash", In: "path", Type: "string", Description: "transaction hash", Required: true}
+	blockParam := Param{Name: "id", In: "path", Type: "string", Description: "block height or block hash", Required: true}
+
+	return []Route{
+		{
+			Method: http.MethodGet, Path: "/blocks", Name: "listBlocks", Tag: "blocks",
+			Summary: "List blocks, latest first", Params: pageParams,
+			Result: Page[Block]{}, Handle: s.listBlocks,
+		},
+		{
+			Method: http.MethodGet, Path: "/blocks/{id}", Name: "getBlock", Tag: "blocks",
+			Summary: "Get a block by height or hash", Params: []Param{blockParam},
+			Result: Block{}, Handle: s.getBlock,
+		},
+		{
+			Method: http.MethodGet, Path: "/blocks/{id}/transactions", Name: "listBlockTransactions", Tag: "blocks",
+			Summary: "List the transactions of a block", Params: []Param{blockParam},
+			Result: []Transaction{}, Handle: s.listBlockTransactions,
+		},
+		{
+			Method: http.MethodGet, Path: "/transactions", Name: "listTransactions", Tag: "transactions",
+			Summary: "List transactions, latest first", Params: pageParams,
+			Result: Page[Transaction]{}, Handle: s.listTransactions,
+		},
+		{
+			Method: http.MethodGet, Path: "/transactions/{hash}", Name: "getTransaction", Tag: "transactions",
+			Summary: "Get transaction details with logs and token transfers", Params: []Param{hashParam},
+			Result: TransactionDetail{}, Handle: s.getTransaction,
+		},
+		{
+			Method: http.MethodGet, Path: "/transactions/{hash}/logs", Name: "listTransactionLogs", Tag: "transactions",
+			Summary: "List the logs of a transaction", Params: []Param{hashParam},
+			Result: []Log{}, Handle: s.listTransactionLogs,
+		},
+		{
+			Method: http.MethodGet, Path: "/transactions/{hash}/token-transfers", Name: "listTransactionTokenTransfers", Tag: "transactions",
+			Summary: "List the token transfers of a transaction", Params: []Param{hashParam},
+			Result: []TokenTransfer{}, Handle: s.listTransactionTokenTransfers,
+		},
+		{
+			Method: http.MethodGet, Path: "/logs", Name: "listLogs", Tag: "logs",
+			Summary: "Query logs of canonical blocks, oldest first",
+			Params: append([]Param{
+				{Name: "address", In: "query", Type: "string", Description: "emitting contract address"},
+				{Name: "from_block", In: "query", Type: "integer", Description: "first block height, defaults to 0"},
+				{Name: "to_block", In: "query", Type: "integer", Description: "last block height, defaults to the latest block"},
+				{Name: "topic0", In: "query", Type: "string", Description: "comma separated topics, any of them matches"},
+				{Name: "topic1", In: "query", Type: "string", Description: "comma separated topics, any of them matches"},
+				{Name: "topic2", In: "query", Type: "string", Description: "comma separated topics, any of them matches"},
+				{Name: "topic3", In: "query", Type: "string", Description: "comma separated topics, any of them matches"},
+			}, pageParams...),
+			Result: Page[Log]{}, Handle: s.listLogs,
+		},
+		{
+			Method: http.MethodGet, Path: "/addresses/{address}", Name: "getAddress", Tag: "addresses",
+			Summary: "Get an address summary", Params: []Param{addressParam},
+			Result: AddressSummary{}, Handle: s.getAddress,
+		},
+		{
+			Method: http.MethodGet, Path: "/addresses/{address}/transactions", Name: "listAddressTransactions", Tag: "addresses",
+			Summary: "List transactions sent, received or called by an address", Params: append([]Param{addressParam}, pageParams...),
+			Result: Page[Transaction]{}, Handle: s.listAddressTransactions,
+		},
+		{
+			Method: http.MethodGet, Path: "/addresses/{address}/token-transfers", Name: "listAddressTokenTransfers", Tag: "addresses",
+			Summary: "List token transfers from or to an address", Params: append([]Param{addressParam}, pageParams...),
+			Result: Page[TokenTransfer]{}, Handle: s.listAddressTokenTransfers,
+		},
+		{
+			Method: http.MethodGet, Path: "/addresses/{address}/token-balances", Name: "listAddressTokenBalances", Tag: "addresses",
+			Summary: "List ERC-20 balances of an address with USD values, highest value first", Params: []Param{addressParam},
+			Result: []TokenBalance{}, Handle: s.listAddressTokenBalances,
+		},
+		{
+			Method: http.MethodGet, Path: "/tokens/{address}", Name: "getToken", Tag: "tokens",
+			Summary: "Get token metadata", Params: []Param{addressParam},
+			Result: Token{}, Handle: s.getToken,
+		},
+		{
+			Method: http.MethodGet, Path: "/tokens/{address}/transfers", Name: "listTokenTransfers", Tag: "tokens",
+			Summary: "List transfers of a token", Params: append([]Param{addressParam}, pageParams...),
+			Result: Page[TokenTransfer]{}, Handle: s.listTokenTransfers,
+		},
+		{
+			Method: http.MethodGet, Path: "/tokens/{address}/prices", Name: "listTokenPrices", Tag: "tokens",
+			Summary: "List the USD price history of a token, latest first",
+			Params: []Param{addressParam,
+				{Name: "from", In: "query", Type: "integer", Description: "unix seconds, defaults to 7 days ago"},
+				{Name: "to", In: "query", Type: "integer", Description: "unix seconds, defaults to now"},
+				{Name: "limit", In: "query", Type: "integer", Description: "max prices, 1 to 1000, defaults to 500"},
+			},
+			Result: []TokenPrice{}, Handle: s.listTokenPrices,
+		},
+		{
+			Method: http.MethodGet, Path: "/names/{name}", Name: "resolveName", Tag: "names",
+			Summary: "Resolve an ENS name to its address",
+			Params:  []Param{{Name: "name", In: "path", Type: "string", Description: "ENS name, e.g. vitalik.eth", Required: true}},
+			Result:  EnsName{}, Handle: s.resolveName,
+		},
+	}
+}
+
+func (s *Server) listBlocks(r *Request) (interface{}, error) {
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.backend.Blocks(r.Context(), offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(blocks, offset, limit, toBlock), nil
+}
+
+func (s *Server) findBlock(r *Request) (*Block, error) {
+	id := r.Var("id")
+	var b *models.Block
+	if hashPattern.MatchString(id) {
+		var err error
+		if b, err = s.backend.BlockByHash(r.Context(), strings.ToLower(id)); err != nil {
+			return nil, err
+		}
+	} else {
+		height, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, InvalidParam("id")
+		}
+		if b, err = s.backend.BlockByHeight(r.Context(), height); err != nil {
+			return nil, err
+		}
+	}
+	if b == nil {
+		return nil, errcode.ErrRecordNotFound
+	}
+	result := toBlock(b)
+	return &result, nil
+}
+
+func (s *Server) getBlock(r *Request) (interface{}, error) {
+	return s.findBlock(r)
+}
+
+func (s *Server) listBlockTransactions(r *Request) (interface{}, error) {
+	block, err := s.findBlock(r)
+	if err != nil {
+		return nil, err
+	}
+	trxs, err := s.backend.TransactionsByBlock(r.Context(), block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	items := convertAll(trxs, toTransaction)
+	return items, s.withNames(r.Context(), items, nil)
+}
+
+func (s *Server) listTransactions(r *Request) (interface{}, error) {
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+	trxs, err := s.backend.Transactions(r.Context(), offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := newPage(trxs, offset, limit, toTransaction)
+	return page, s.withNames(r.Context(), page.Items, nil)
+}
+
+func (s *Server) getTransaction(r *Request) (interface{}, error) {
+	hash, err := hashVar(r, "hash")
+	if err != nil {
+		return nil, err
+	}
+	trx, err := s.backend.TransactionByHash(r.Context(), hash)
+	if err != nil {
+		return nil, err
+	}
+	if trx == nil {
+		return nil, errcode.ErrRecordNotFound
+	}
+	logs, err := s.backend.LogsByTransaction(r.Context(), trx.TxHash, trx.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.backend.TokenTransfersByTransaction(r.Context(), trx.TxHash, trx.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	detail := TransactionDetail{
+		Transaction:    toTransaction(trx),
+		Logs:           convertAll(logs, toLog),
+		TokenTransfers: convertAll(transfers, toTokenTransfer),
+	}
+	if err := s.withUsd(r.Context(), detail.TokenTransfers); err != nil {
+		return nil, err
+	}
+	trxs := []Transaction{detail.Transaction}
+	if err := s.withNames(r.Context(), trxs, detail.TokenTransfers); err != nil {
+		return nil, err
+	}
+	detail.Transaction = trxs[0]
+	return detail, nil
+}
+
+func (s *Server) listTransactionLogs(r *Request) (interface{}, error) {
+	detail, err := s.getTransaction(r)
+	if err != nil {
+		return nil, err
+	}
+	return detail.(TransactionDetail).Logs, nil
+}
+
+func (s *Server) listTransactionTokenTransfers(r *Request) (interface{}, error) {
+	detail, err := s.getTransaction(r)
+	if err != nil {
+		return nil, err
+	}
+	return detail.(TransactionDetail).TokenTransfers, nil
+}
+
+func (s *Server) listLogs(r *Request) (interface{}, error) {
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+
+	var filter dal.LogFilter
+	if address := r.Query("address"); address != "" {
+		if !common.IsHexAddress(address) {
+			return nil, InvalidParam("address")
+		}
+		filter.Addresses = []string{common.HexToAddress(address).Hex()}
+	}
+	from, err := r.IntQuery("from_block", 0)
+	if err != nil || from < 0 {
+		return nil, InvalidParam("from_block")
+	}
+	filter.FromBlock = uint64(from)
+	if r.Query("to_block") == "" {
+		if filter.ToBlock, err = s.backend.LatestHeight(r.Context()); err != nil {
+			return nil, err
+		}
+	} else {
+		to, err := r.IntQuery("to_block", 0)
+		if err != nil || to < from {
+			return nil, InvalidParam("to_block")
+		}
+		filter.ToBlock = uint64(to)
+	}
+	for i := 0; i < 4; i++ {
+		name := "topic" + strconv.Itoa(i)
+		var topics []string
+		for _, topic := range strings.Split(r.Query(name), ",") {
+			if topic == "" {
+				continue
+			}
+			if !hashPattern.MatchString(topic) {
+				return nil, InvalidParam(name)
+			}
+			topics = append(topics, strings.ToLower(topic))
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+
+	logs, err := s.backend.Logs(r.Context(), filter, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(logs, offset, limit, toLog), nil
+}
+
+func (s *Server) getAddress(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.backend.AddressStats(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	isContract, err := s.backend.IsContract(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.backend.TokenTransferCount(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.backend.AddressLabels(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.backend.PrimaryNames(r.Context(), []string{address})
+	if err != nil {
+		return nil, err
+	}
+	summary := AddressSummary{
+		Address:            address,
+		EnsName:            names[address],
+		IsContract:         isContract,
+		TransactionCount:   stats.Count,
+		TokenTransferCount: transfers,
+		FirstBlock:         stats.FirstBlock,
+		LastBlock:          stats.LastBlock,
+		Labels:             make([]AddressLabel, 0, len(labels)),
+	}
+	for _, l := range labels {
+		summary.Labels = append(summary.Labels, AddressLabel{Label: l.Label, Name: l.Name})
+	}
+	if isContract {
+		token, err := s.backend.Token(r.Context(), address)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			t := toToken(token)
+			summary.Token = &t
+		}
+	}
+	return summary, nil
+}
+
+func (s *Server) listAddressTransactions(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+	trxs, err := s.backend.TransactionsByAddress(r.Context(), address, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := newPage(trxs, offset, limit, toTransaction)
+	return page, s.withNames(r.Context(), page.Items, nil)
+}
+
+func (s *Server) listAddressTokenTransfers(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.backend.TokenTransfersByAddress(r.Context(), address, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := newPage(transfers, offset, limit, toTokenTransfer)
+	if err := s.withNames(r.Context(), nil, page.Items); err != nil {
+		return nil, err
+	}
+	return page, s.withUsd(r.Context(), page.Items)
+}
+
+func (s *Server) getToken(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.backend.Token(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errcode.ErrRecordNotFound
+	}
+	return toToken(token), nil
+}
+
+func (s *Server) listTokenTransfers(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, err := r.Page()
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.backend.TokenTransfersByToken(r.Context(), address, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := newPage(transfers, offset, limit, toTokenTransfer)
+	if err := s.withNames(r.Context(), nil, page.Items); err != nil {
+		return nil, err
+	}
+	return page, s.withUsd(r.Context(), page.Items)
+}
+
+func convertAll[M any, T any](rows []M, convert func(*M) T) []T {
+	out := make([]T, 0, len(rows))
+	for i := range rows {
+		out = append(out, convert(&rows[i]))
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package explorer
+
+import (
+	"context"
+
+	"github.com/traitmeta/metago/core/ens"
+	"github.com/traitmeta/metago/pkg/errcode"
+)
+
+// withNames 给交易和 Token 转移中的地址填充 ENS 主名称, 一次查询所有地址
+func (s *Server) withNames(ctx context.Context, trxs []Transaction, transfers []TokenTransfer) error {
+	var addresses []string
+	seen := map[string]bool{}
+	add := func(address string) {
+		if address != "" && !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	for _, t := range trxs {
+		add(t.From)
+		add(t.To)
+		add(t.Contract)
+	}
+	for _, t := range transfers {
+		add(t.From)
+		add(t.To)
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	names, err := s.backend.PrimaryNames(ctx, addresses)
+	if err != nil {
+		return err
+	}
+	for i := range trxs {
+		trxs[i].FromName, trxs[i].ToName, trxs[i].ContractName = names[trxs[i].From], names[trxs[i].To], names[trxs[i].Contract]
+	}
+	for i := range transfers {
+		transfers[i].FromName, transfers[i].ToName = names[transfers[i].From], names[transfers[i].To]
+	}
+	return nil
+}
+
+func (s *Server) resolveName(r *Request) (interface{}, error) {
+	name := ens.Normalize(r.Var("name"))
+	if name == "" {
+		return nil, InvalidParam("name")
+	}
+	record, err := s.backend.ResolveName(r.Context(), name)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errcode.ErrRecordNotFound
+	}
+	return EnsName{
+		Name:     name,
+		Node:     record.Node,
+		Address:  record.Address,
+		Owner:    record.Owner,
+		Resolver: record.Resolver,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package explorer
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const schemaRef = "#/components/schemas/"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// OpenAPI 根据路由表生成 OpenAPI 3 文档
+func (s *Server) OpenAPI() map[string]interface{} {
+	b := &schemaBuilder{defs: map[string]interface{}{}}
+	b.defs["Error"] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"code": map[string]interface{}{"type": "integer"},
+			"msg":  map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"code", "msg"},
+	}
+
+	paths := map[string]interface{}{}
+	for _, route := range s.routes {
+		item, _ := paths[route.Path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = b.operation(route)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Metago Explorer API",
+			"version": "1.0",
+		},
+		"servers":    []interface{}{map[string]interface{}{"url": s.prefix}},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": b.defs},
+	}
+}
+
+type schemaBuilder struct {
+	defs map[string]interface{}
+}
+
+func (b *schemaBuilder) operation(route Route) map[string]interface{} {
+	params := make([]interface{}, 0, len(route.Params))
+	for _, p := range route.Params {
+		params = append(params, map[string]interface{}{
+			"name":        p.Name,
+			"in":          p.In,
+			"required":    p.Required || p.In == "path",
+			"description": p.Description,
+			"schema":      map[string]interface{}{"type": p.Type},
+		})
+	}
+
+	data := map[string]interface{}{}
+	if route.Result != nil {
+		data = b.schema(reflect.TypeOf(route.Result))
+	}
+	envelope := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"code": map[string]interface{}{"type": "integer"},
+			"msg":  map[string]interface{}{"type": "string"},
+			"data": data,
+		},
+	}
+
+	op := map[string]interface{}{
+		"operationId": route.Name,
+		"summary":     route.Summary,
+		"parameters":  params,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "OK",
+				"content":     jsonContent(envelope),
+			},
+			"default": map[string]interface{}{
+				"description": "Error, code is a pkg/errcode error code",
+				"content":     jsonContent(map[string]interface{}{"$ref": schemaRef + "Error"}),
+			},
+		},
+	}
+	if route.Body != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(b.schema(reflect.TypeOf(route.Body))),
+		}
+	}
+	if route.Tag != "" {
+		op["tags"] = []string{route.Tag}
+	}
+	return op
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// schema 命名的结构体放入 components 中引用, 泛型实例化的结构体直接展开
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.schema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" || strings.Contains(name, "[") {
+			return b.object(t)
+		}
+		if _, ok := b.defs[name]; !ok {
+			// 先占位, 避免递归引用的类型无限展开
+			b.defs[name] = map[string]interface{}{}
+			b.defs[name] = b.object(t)
+		}
+		return map[string]interface{}{"$ref": schemaRef + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func (b *schemaBuilder) object(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	var required []string
+	b.fields(t, props, &required)
+	obj := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+// fields 按 json 标签收集字段, 匿名嵌入的结构体字段展开到同一层
+func (b *schemaBuilder) fields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.fields(f.Type, props, required)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = b.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
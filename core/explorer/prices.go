@@ -0,0 +1,149 @@
+package explorer
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/core/pricing"
+	"github.com/traitmeta/metago/pkg/errcode"
+)
+
+const (
+	defaultPriceWindow = 7 * 24 * time.Hour
+	defaultPriceLimit  = 500
+	maxPriceLimit      = 1000
+)
+
+// tokensByAddress 批量读取 Token, 按合约地址索引
+func (s *Server) tokensByAddress(ctx context.Context, addresses []string) (map[string]*models.Token, error) {
+	tokens, err := s.backend.Tokens(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	byAddress := make(map[string]*models.Token, len(tokens))
+	for i := range tokens {
+		byAddress[tokens[i].ContractAddress] = &tokens[i]
+	}
+	return byAddress, nil
+}
+
+// withUsd 给有价格的 ERC-20 转移填充美元价值
+func (s *Server) withUsd(ctx context.Context, transfers []TokenTransfer) error {
+	var addresses []string
+	seen := map[string]bool{}
+	for _, t := range transfers {
+		if t.Amount != "" && t.TokenId == "" && !seen[t.Token] {
+			seen[t.Token] = true
+			addresses = append(addresses, t.Token)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	tokens, err := s.tokensByAddress(ctx, addresses)
+	if err != nil {
+		return err
+	}
+	for i := range transfers {
+		token := tokens[transfers[i].Token]
+		if token == nil || transfers[i].TokenId != "" {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(transfers[i].Amount, 10)
+		if !ok {
+			continue
+		}
+		transfers[i].AmountUsd = pricing.UsdValue(amount, token.Decimals, token.FiatValue)
+	}
+	return nil
+}
+
+func (s *Server) listAddressTokenBalances(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.backend.TokenBalances(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(rows))
+	for _, row := range rows {
+		addresses = append(addresses, row.Token)
+	}
+	tokens, err := s.tokensByAddress(r.Context(), addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]TokenBalance, 0, len(rows))
+	values := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		balance := TokenBalance{Token: row.Token, Balance: row.Balance}
+		var value float64
+		if token := tokens[row.Token]; token != nil {
+			balance.Name, balance.Symbol, balance.Decimals = token.Name, token.Symbol, token.Decimals
+			balance.PriceUsd = token.FiatValue
+			if amount, ok := new(big.Int).SetString(row.Balance, 10); ok {
+				balance.ValueUsd = pricing.UsdValue(amount, token.Decimals, token.FiatValue)
+				value, _ = strconv.ParseFloat(balance.ValueUsd, 64)
+			}
+		}
+		balances = append(balances, balance)
+		values = append(values, value)
+	}
+
+	// 按美元价值倒序, 没有价格的排在最后
+	order := make([]int, len(balances))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] > values[order[j]]
+	})
+	sorted := make([]TokenBalance, 0, len(balances))
+	for _, i := range order {
+		sorted = append(sorted, balances[i])
+	}
+	return sorted, nil
+}
+
+func (s *Server) listTokenPrices(r *Request) (interface{}, error) {
+	address, err := AddressVar(r, "address")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	from, err := r.IntQuery("from", int(now.Add(-defaultPriceWindow).Unix()))
+	if err != nil || from < 0 {
+		return nil, InvalidParam("from")
+	}
+	to, err := r.IntQuery("to", int(now.Unix()))
+	if err != nil || to < from {
+		return nil, InvalidParam("to")
+	}
+	limit, err := r.IntQuery("limit", defaultPriceLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxPriceLimit {
+		limit = defaultPriceLimit
+	}
+
+	token, err := s.backend.Token(r.Context(), address)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errcode.ErrRecordNotFound
+	}
+	prices, err := s.backend.PriceHistory(r.Context(), address, time.Unix(int64(from), 0), time.Unix(int64(to), 0), limit)
+	if err != nil {
+		return nil, err
+	}
+	return convertAll(prices, toTokenPrice), nil
+}
@@ -0,0 +1,220 @@
+package explorer
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/pkg/errcode"
+)
+
+const (
+	DefaultPrefix = "/api/v1"
+
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Param 接口参数, 同时用于解析请求和生成 OpenAPI 文档
+type Param struct {
+	Name        string
+	In          string // path 或 query
+	Type        string // string, integer 或 boolean
+	Description string
+	Required    bool
+}
+
+// Route 一个接口, Body 和 Result 为 JSON 请求体和响应 data 的零值, 用于生成文档中的 schema
+type Route struct {
+	Method  string
+	Path    string
+	Name    string
+	Summary string
+	Tag     string
+	Params  []Param
+	Body    interface{}
+	Result  interface{}
+	Handle  func(r *Request) (interface{}, error)
+}
+
+// Request 带路径参数的请求
+type Request struct {
+	*http.Request
+	vars map[string]string
+}
+
+// Var 路径参数
+func (r *Request) Var(name string) string {
+	return r.vars[name]
+}
+
+// Query 查询参数
+func (r *Request) Query(name string) string {
+	return r.URL.Query().Get(name)
+}
+
+// Page 解析 offset/limit 分页参数, 超出范围时使用默认值
+func (r *Request) Page() (int, int, error) {
+	offset, err := r.IntQuery("offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err := r.IntQuery("limit", defaultLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	return offset, limit, nil
+}
+
+// IntQuery 整数查询参数, 为空时返回 def
+func (r *Request) IntQuery(name string, def int) (int, error) {
+	v := r.Query(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, InvalidParam(name)
+	}
+	return n, nil
+}
+
+// pageParams 分页接口共用的参数
+var pageParams = []Param{
+	{Name: "offset", In: "query", Type: "integer", Description: "number of items to skip"},
+	{Name: "limit", In: "query", Type: "integer", Description: "page size, 1 to 100, defaults to 20"},
+}
+
+// Response 统一的响应结构
+type Response struct {
+	Code uint32      `json:"code"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// InvalidParam 参数错误沿用 ErrInvalidParams 的错误码, HTTP 状态码为 400
+func InvalidParam(name string) *errcode.Err {
+	return errcode.NewErr(errcode.ErrInvalidParams.Code(), errcode.ErrInvalidParams.Error()+": "+name, http.StatusBadRequest)
+}
+
+// Server 浏览器 REST 服务, 实现 http.Handler
+type Server struct {
+	prefix  string
+	backend Backend
+	routes  []Route
+}
+
+// NewServer 返回读取数据库的浏览器服务, 接口挂载在 prefix 下
+func NewServer(prefix string) *Server {
+	return newServer(prefix, dbBackend{})
+}
+
+// NewRouter 返回没有注册任何接口的服务, 用于在独立的前缀下挂载其他模块的接口
+func NewRouter(prefix string) *Server {
+	return &Server{prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+func newServer(prefix string, backend Backend) *Server {
+	s := NewRouter(prefix)
+	s.backend = backend
+	s.Register(s.explorerRoutes()...)
+	return s
+}
+
+// Register 注册额外的接口, 其他模块可以在同一个服务上扩展
+func (s *Server) Register(routes ...Route) {
+	s.routes = append(s.routes, routes...)
+}
+
+// Routes 已注册的接口
+func (s *Server) Routes() []Route {
+	return s.routes
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == s.prefix+"/openapi.json" {
+		writeJSON(w, http.StatusOK, s.OpenAPI())
+		return
+	}
+	if !strings.HasPrefix(path, s.prefix+"/") {
+		WriteError(w, errcode.NewErr(errcode.ErrInvalidUrl.Code(), errcode.ErrInvalidUrl.Error(), http.StatusNotFound))
+		return
+	}
+	path = strings.TrimPrefix(path, s.prefix)
+
+	methodAllowed := true
+	for _, route := range s.routes {
+		vars, ok := matchPath(route.Path, path)
+		if !ok {
+			continue
+		}
+		if route.Method != r.Method {
+			methodAllowed = false
+			continue
+		}
+		data, err := route.Handle(&Request{Request: r, vars: vars})
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, Response{Code: errcode.NoErr.Code(), Msg: errcode.NoErr.Error(), Data: data})
+		return
+	}
+
+	if !methodAllowed {
+		WriteError(w, errcode.NewErr(errcode.ErrInvalidUrl.Code(), "Method not allowed", http.StatusMethodNotAllowed))
+		return
+	}
+	WriteError(w, errcode.NewErr(errcode.ErrInvalidUrl.Code(), errcode.ErrInvalidUrl.Error(), http.StatusNotFound))
+}
+
+// matchPath 按 / 分段匹配, {name} 匹配任意非空的一段
+func matchPath(pattern, path string) (map[string]string, bool) {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+	vars := map[string]string{}
+	for i, seg := range want {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if got[i] == "" {
+				return nil, false
+			}
+			vars[seg[1:len(seg)-1]] = got[i]
+			continue
+		}
+		if seg != got[i] {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+// WriteError 按统一的响应结构输出错误, 非 errcode.Err 的错误记录日志后返回 ErrUnexpected
+func WriteError(w http.ResponseWriter, err error) {
+	e, ok := err.(*errcode.Err)
+	if !ok {
+		log.Errorf("explorer: %v", err)
+		e = errcode.ErrUnexpected
+	}
+	writeJSON(w, e.HTTPCode(), Response{Code: e.Code(), Msg: e.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warnf("explorer: write response: %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package explorer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Page 分页列表, 多读取一条判断是否还有下一页
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Offset  int  `json:"offset"`
+	Limit   int  `json:"limit"`
+	HasMore bool `json:"has_more"`
+}
+
+func newPage[M any, T any](rows []M, offset, limit int, convert func(*M) T) Page[T] {
+	page := Page[T]{Items: make([]T, 0, len(rows)), Offset: offset, Limit: limit}
+	if len(rows) > limit {
+		rows, page.HasMore = rows[:limit], true
+	}
+	for i := range rows {
+		page.Items = append(page.Items, convert(&rows[i]))
+	}
+	return page
+}
+
+type Block struct {
+	Height        uint64    `json:"height"`
+	Hash          string    `json:"hash"`
+	ParentHash    string    `json:"parent_hash"`
+	Timestamp     time.Time `json:"timestamp"`
+	Miner         string    `json:"miner"`
+	GasLimit      uint64    `json:"gas_limit"`
+	GasUsed       uint64    `json:"gas_used"`
+	BaseFeePerGas string    `json:"base_fee_per_gas,omitempty"`
+	Difficulty    string    `json:"difficulty"`
+	Nonce         string    `json:"nonce"`
+	Size          int32     `json:"size"`
+	RefetchNeeded bool      `json:"refetch_needed"`
+}
+
+type Transaction struct {
+	Hash        string `json:"hash"`
+	BlockNumber uint64 `json:"block_number"`
+	BlockHash   string `json:"block_hash"`
+	Index       uint   `json:"index"`
+	Type        uint8  `json:"type"`
+	From        string `json:"from"`
+	FromName    string `json:"from_name,omitempty"`
+	// To 普通转账的接收地址, 调用合约时为空, 见 Contract
+	To                string `json:"to,omitempty"`
+	ToName            string `json:"to_name,omitempty"`
+	Contract          string `json:"contract,omitempty"`
+	ContractName      string `json:"contract_name,omitempty"`
+	ContractCreation  bool   `json:"contract_creation"`
+	Value             string `json:"value"`
+	Nonce             uint64 `json:"nonce"`
+	Status            uint64 `json:"status"`
+	RevertReason      string `json:"revert_reason,omitempty"`
+	Input             string `json:"input"`
+	Gas               uint64 `json:"gas"`
+	GasPrice          string `json:"gas_price"`
+	GasTipCap         string `json:"gas_tip_cap"`
+	GasUsed           uint64 `json:"gas_used"`
+	EffectiveGasPrice string `json:"effective_gas_price,omitempty"`
+}
+
+// TransactionDetail 交易详情, 包含日志和 Token 转移
+type TransactionDetail struct {
+	Transaction
+	Logs           []Log           `json:"logs"`
+	TokenTransfers []TokenTransfer `json:"token_transfers"`
+}
+
+type Log struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     uint64   `json:"block_number"`
+	BlockHash       string   `json:"block_hash"`
+	TransactionHash string   `json:"transaction_hash"`
+	TxIndex         uint     `json:"transaction_index"`
+	LogIndex        uint     `json:"log_index"`
+}
+
+type TokenTransfer struct {
+	TransactionHash string   `json:"transaction_hash"`
+	LogIndex        uint     `json:"log_index"`
+	BlockNumber     uint64   `json:"block_number"`
+	Token           string   `json:"token"`
+	From            string   `json:"from"`
+	FromName        string   `json:"from_name,omitempty"`
+	To              string   `json:"to"`
+	ToName          string   `json:"to_name,omitempty"`
+	Amount          string   `json:"amount,omitempty"`
+	TokenId         string   `json:"token_id,omitempty"`
+	Amounts         []string `json:"amounts,omitempty"`
+	TokenIds        []string `json:"token_ids,omitempty"`
+	// AmountUsd 按当前价格计算的美元价值, 只有 ERC-20 且有价格时返回
+	AmountUsd string `json:"amount_usd,omitempty"`
+}
+
+type Token struct {
+	ContractAddress string `json:"contract_address"`
+	Name            string `json:"name"`
+	Symbol          string `json:"symbol"`
+	Decimals        uint8  `json:"decimals"`
+	Type            string `json:"type"`
+	TotalSupply     string `json:"total_supply,omitempty"`
+	HolderCount     int32  `json:"holder_count"`
+	IconUrl         string `json:"icon_url,omitempty"`
+	Verified        bool   `json:"verified"`
+	Spam            bool   `json:"spam"`
+	PriceUsd        string `json:"price_usd,omitempty"`
+	MarketCapUsd    string `json:"market_cap_usd,omitempty"`
+}
+
+// TokenBalance 由转移记录累加得到的 ERC-20 余额
+type TokenBalance struct {
+	Token    string `json:"token"`
+	Name     string `json:"name,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals uint8  `json:"decimals"`
+	Balance  string `json:"balance"`
+	PriceUsd string `json:"price_usd,omitempty"`
+	ValueUsd string `json:"value_usd,omitempty"`
+}
+
+// TokenPrice 一条历史价格
+type TokenPrice struct {
+	PriceUsd     float64   `json:"price_usd"`
+	MarketCapUsd float64   `json:"market_cap_usd,omitempty"`
+	Source       string    `json:"source"`
+	Time         time.Time `json:"time"`
+}
+
+// EnsName 正向解析结果
+type EnsName struct {
+	Name     string `json:"name"`
+	Node     string `json:"node"`
+	Address  string `json:"address"`
+	Owner    string `json:"owner,omitempty"`
+	Resolver string `json:"resolver,omitempty"`
+}
+
+// AddressLabel 管理后台给地址添加的标签
+type AddressLabel struct {
+	Label string `json:"label"`
+	Name  string `json:"name,omitempty"`
+}
+
+// AddressSummary 地址概况, 由已经入库的交易和 Token 转移统计
+type AddressSummary struct {
+	Address            string         `json:"address"`
+	EnsName            string         `json:"ens_name,omitempty"`
+	IsContract         bool           `json:"is_contract"`
+	TransactionCount   int64          `json:"transaction_count"`
+	TokenTransferCount int64          `json:"token_transfer_count"`
+	FirstBlock         uint64         `json:"first_block,omitempty"`
+	LastBlock          uint64         `json:"last_block,omitempty"`
+	Labels             []AddressLabel `json:"labels"`
+	// Token 地址为 Token 合约时的元信息
+	Token *Token `json:"token,omitempty"`
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func bigStrings(vs []*big.Int) []string {
+	if len(vs) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, bigString(v))
+	}
+	return out
+}
+
+func toBlock(b *models.Block) Block {
+	return Block{
+		Height:        b.BlockHeight,
+		Hash:          b.BlockHash,
+		ParentHash:    b.ParentHash,
+		Timestamp:     b.Timestamp,
+		Miner:         b.MinerHash,
+		GasLimit:      b.GasLimit,
+		GasUsed:       b.GasUsed,
+		BaseFeePerGas: bigString(b.BaseFeePerGas),
+		Difficulty:    bigString(b.Difficulty),
+		Nonce:         b.Nonce,
+		Size:          b.Size,
+		RefetchNeeded: b.RefetchNeeded,
+	}
+}
+
+func toTransaction(t *models.Transaction) Transaction {
+	return Transaction{
+		Hash:              t.TxHash,
+		BlockNumber:       t.BlockNumber,
+		BlockHash:         t.BlockHash,
+		Index:             t.TxIndex,
+		Type:              t.Type,
+		From:              t.From,
+		To:                t.To,
+		Contract:          t.Contract,
+		ContractCreation:  t.ContractCreation,
+		Value:             t.Value,
+		Nonce:             t.Nonce,
+		Status:            t.Status,
+		RevertReason:      t.RevertReason,
+		Input:             "0x" + t.InputData,
+		Gas:               t.Gas,
+		GasPrice:          t.GasPrice,
+		GasTipCap:         t.GasTipCap,
+		GasUsed:           t.GasUsed,
+		EffectiveGasPrice: t.EffectiveGasPrice,
+	}
+}
+
+func toLog(e *models.Event) Log {
+	topics := make([]string, 0, 4)
+	for _, topic := range []string{e.FirstTopic, e.SecondTopic, e.ThirdTopic, e.FourthTopic} {
+		if topic == "" {
+			break
+		}
+		topics = append(topics, topic)
+	}
+	return Log{
+		Address:         e.Address,
+		Topics:          topics,
+		Data:            "0x" + e.Data,
+		BlockNumber:     e.BlockNumber,
+		BlockHash:       e.BlockHash,
+		TransactionHash: e.TxHash,
+		TxIndex:         e.TxIndex,
+		LogIndex:        e.LogIndex,
+	}
+}
+
+func toTokenTransfer(t *models.TokenTransfer) TokenTransfer {
+	return TokenTransfer{
+		TransactionHash: t.TransactionHash,
+		LogIndex:        t.LogIndex,
+		BlockNumber:     t.BlockNumber,
+		Token:           t.TokenContractAddress,
+		From:            t.FromAddress,
+		To:              t.ToAddress,
+		Amount:          bigString(t.Amount),
+		TokenId:         bigString(t.TokenId),
+		Amounts:         bigStrings(t.Amounts),
+		TokenIds:        bigStrings(t.TokenIds),
+	}
+}
+
+func toToken(t *models.Token) Token {
+	return Token{
+		ContractAddress: t.ContractAddress,
+		Name:            t.Name,
+		Symbol:          t.Symbol,
+		Decimals:        t.Decimals,
+		Type:            t.Type,
+		TotalSupply:     bigString(t.TotalSupply),
+		HolderCount:     t.HolderCount,
+		IconUrl:         t.IconUrl,
+		Verified:        t.IsVerifiedViaAdminPanel,
+		Spam:            t.Spam,
+		PriceUsd:        t.FiatValue,
+		MarketCapUsd:    t.CirculatingMarketCap,
+	}
+}
+
+func toTokenPrice(p *models.TokenPrice) TokenPrice {
+	return TokenPrice{PriceUsd: p.PriceUsd, MarketCapUsd: p.MarketCapUsd, Source: p.Source, Time: p.FetchedAt}
+}
@@ -0,0 +1,399 @@
+/*
+## Overview
+export 把已入库的区块数据按区块高度分区导出为 Parquet 或 CSV 文件, 供 DuckDB/Spark 等离线分析使用.
+
+  - 每张表每个分区一个文件, 路径为 <dir>/<table>/<from>-<to>.<format>, 高度补零到12位, 分区按 PartitionSize 对齐
+  - 分区内按 ChunkSize 个区块分批读取, Parquet 每批一个 row group, 读取时只使用每个高度最后写入的区块
+  - 只导出完整的分区, 分区内缺少区块时报错, 需要先补齐或重新索引
+  - 文件先写入临时文件再改名, 所有表写完后更新 manifest.json 的 checkpoint, 中断后从 checkpoint 继续
+  - manifest 记录每张表的 schema, 与当前 schema 不一致时拒绝继续导出, 需要导出到新目录
+  - Start 持续运行, 落后最新区块 Confirmations 个区块导出, 避免导出之后发生重组
+*/
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+)
+
+// Format 导出文件格式
+type Format string
+
+const (
+	FormatParquet Format = "parquet"
+	FormatCSV     Format = "csv"
+)
+
+// ManifestVersion manifest.json 的格式版本
+const ManifestVersion = 1
+
+const manifestFile = "manifest.json"
+
+// Partition 一张表的一个分区文件, File 为相对导出目录的路径
+type Partition struct {
+	From   uint64 `json:"from"`
+	To     uint64 `json:"to"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	Bytes  int64  `json:"bytes"`
+	Sha256 string `json:"sha256"`
+}
+
+// TableManifest 一张表的 schema 和已导出的分区
+type TableManifest struct {
+	Columns    []Column    `json:"columns"`
+	Partitions []Partition `json:"partitions"`
+}
+
+// Manifest 导出目录的清单, Checkpoint 为下一个要导出的区块高度
+type Manifest struct {
+	Version       int                       `json:"version"`
+	Format        Format                    `json:"format"`
+	PartitionSize uint64                    `json:"partition_size"`
+	Checkpoint    uint64                    `json:"checkpoint"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+	Tables        map[string]*TableManifest `json:"tables"`
+}
+
+// ReadManifest 读取导出目录的清单, 不存在时返回 nil
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "parse manifest")
+	}
+	return &m, nil
+}
+
+// writeFileAtomic 先写入同目录的临时文件再改名, 避免中断时留下不完整的文件
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (m *Manifest) save(dir string) error {
+	m.UpdatedAt = time.Now().UTC()
+	return writeFileAtomic(filepath.Join(dir, manifestFile), func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	})
+}
+
+// rowWriter 一个分区文件的写入器, 每次 Write 写入一批行
+type rowWriter interface {
+	Write(rows [][]interface{}) error
+	Close() error
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.Name)
+	}
+	return &csvWriter{w: writer}, writer.Write(header)
+}
+
+func (c *csvWriter) Write(rows [][]interface{}) error {
+	record := make([]string, 0)
+	for _, row := range rows {
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, formatValue(v))
+		}
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Loader 读取 [from, to] 高度区间内已入库的区块数据, 按高度升序
+type Loader func(ctx context.Context, from, to uint64) ([]*chain.BlockData, error)
+
+// Exporter 按分区导出区块数据
+type Exporter struct {
+	Dir    string
+	Format Format
+	// Codec Parquet 页面的压缩方式, CSV 不压缩
+	Codec  Codec
+	Tables []Table
+	// From 目录中还没有 manifest 时开始导出的高度
+	From          uint64
+	PartitionSize uint64
+	ChunkSize     uint64
+	Confirmations uint64
+	Interval      time.Duration
+
+	Load Loader
+	// Tip 已入库的最新区块高度
+	Tip func(ctx context.Context) (uint64, error)
+}
+
+func NewExporter(dir string, format Format) *Exporter {
+	return &Exporter{
+		Dir:           dir,
+		Format:        format,
+		Codec:         CodecSnappy,
+		Tables:        Tables,
+		PartitionSize: 10000,
+		ChunkSize:     100,
+		Confirmations: 12,
+		Interval:      time.Minute,
+		Load:          chain.LoadBlockData,
+		Tip:           dal.Block.GetMaxHeight,
+	}
+}
+
+// open 读取或创建 manifest, 并检查格式、分区大小和 schema 与当前配置一致
+func (e *Exporter) open() (*Manifest, error) {
+	if e.Format != FormatParquet && e.Format != FormatCSV {
+		return nil, fmt.Errorf("export: unknown format %q", e.Format)
+	}
+	if e.PartitionSize == 0 || e.ChunkSize == 0 {
+		return nil, errors.New("export: partition size and chunk size must be positive")
+	}
+	if err := os.MkdirAll(e.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	m, err := ReadManifest(e.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = &Manifest{Version: ManifestVersion, Format: e.Format, PartitionSize: e.PartitionSize, Checkpoint: e.From,
+			Tables: map[string]*TableManifest{}}
+		for _, t := range e.Tables {
+			m.Tables[t.Name] = &TableManifest{Columns: t.Columns, Partitions: []Partition{}}
+		}
+	}
+	if m.Version != ManifestVersion || m.Format != e.Format || m.PartitionSize != e.PartitionSize {
+		return nil, fmt.Errorf("export: %s was exported as version %d %s with partition size %d",
+			e.Dir, m.Version, m.Format, m.PartitionSize)
+	}
+	// 同一个目录中所有表的分区范围相同, 不能中途增减表
+	if len(m.Tables) != len(e.Tables) {
+		return nil, fmt.Errorf("export: %s was exported with %d tables", e.Dir, len(m.Tables))
+	}
+	for _, t := range e.Tables {
+		tm, ok := m.Tables[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("export: table %s was not exported to %s", t.Name, e.Dir)
+		}
+		if !reflect.DeepEqual(tm.Columns, t.Columns) {
+			return nil, fmt.Errorf("export: schema of table %s changed, export to a new directory", t.Name)
+		}
+	}
+	for _, t := range e.Tables {
+		if err := os.MkdirAll(filepath.Join(e.Dir, t.Name), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// partitionEnd from 所在分区的最后一个高度
+func (e *Exporter) partitionEnd(from uint64) uint64 {
+	return (from/e.PartitionSize+1)*e.PartitionSize - 1
+}
+
+// RunOnce 导出 checkpoint 之后到 to 为止所有完整的分区, 返回导出的分区数量
+func (e *Exporter) RunOnce(ctx context.Context, to uint64) (int, error) {
+	m, err := e.open()
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	for {
+		from := m.Checkpoint
+		end := e.partitionEnd(from)
+		if end > to {
+			return exported, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+
+		partitions, err := e.exportPartition(ctx, from, end)
+		if err != nil {
+			return exported, errors.Wrapf(err, "export blocks %d to %d", from, end)
+		}
+		// 在更新 checkpoint 之前中断时, 下次会重新导出并覆盖该分区的文件
+		for name, p := range partitions {
+			m.Tables[name].Partitions = append(m.Tables[name].Partitions, p)
+		}
+		m.Checkpoint = end + 1
+		if err := m.save(e.Dir); err != nil {
+			return exported, errors.Wrap(err, "save manifest")
+		}
+		exported++
+		log.WithField("from", from).WithField("to", end).WithField("dir", e.Dir).Info("exported partition")
+	}
+}
+
+func (e *Exporter) newWriter(w io.Writer, columns []Column) (rowWriter, error) {
+	if e.Format == FormatCSV {
+		return newCSVWriter(w, columns)
+	}
+	return newParquetWriter(w, columns, e.Codec)
+}
+
+// exportPartition 写入一个分区的所有表, 所有表共用一次区块数据读取
+func (e *Exporter) exportPartition(ctx context.Context, from, to uint64) (map[string]Partition, error) {
+	type tableFile struct {
+		table  Table
+		path   string
+		file   *os.File
+		writer rowWriter
+		rows   int64
+	}
+	files := make([]*tableFile, 0, len(e.Tables))
+	defer func() {
+		for _, f := range files {
+			f.file.Close()
+			os.Remove(f.file.Name())
+		}
+	}()
+	for _, t := range e.Tables {
+		path := filepath.Join(e.Dir, t.Name, fmt.Sprintf("%012d-%012d.%s", from, to, e.Format))
+		file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+		if err != nil {
+			return nil, err
+		}
+		f := &tableFile{table: t, path: path, file: file}
+		files = append(files, f)
+		if f.writer, err = e.newWriter(file, t.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	next := from
+	for start := from; start <= to; start += e.ChunkSize {
+		end := min(start+e.ChunkSize-1, to)
+		blocks, err := e.Load(ctx, start, end)
+		if err != nil {
+			return nil, errors.Wrap(err, "load blocks")
+		}
+		for _, data := range blocks {
+			if data.Block.BlockHeight != next {
+				return nil, fmt.Errorf("block %d is missing", next)
+			}
+			next++
+		}
+		if next != end+1 {
+			return nil, fmt.Errorf("block %d is missing", next)
+		}
+
+		for _, f := range files {
+			var rows [][]interface{}
+			for _, data := range blocks {
+				rows = append(rows, f.table.Rows(data)...)
+			}
+			if err := f.writer.Write(rows); err != nil {
+				return nil, errors.Wrapf(err, "write %s", f.table.Name)
+			}
+			f.rows += int64(len(rows))
+		}
+	}
+
+	partitions := make(map[string]Partition, len(files))
+	for _, f := range files {
+		if err := f.writer.Close(); err != nil {
+			return nil, err
+		}
+		if err := f.file.Close(); err != nil {
+			return nil, err
+		}
+		size, sum, err := fileDigest(f.file.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Rename(f.file.Name(), f.path); err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(e.Dir, f.path)
+		partitions[f.table.Name] = Partition{From: from, To: to, File: filepath.ToSlash(rel), Rows: f.rows, Bytes: size, Sha256: sum}
+	}
+	return partitions, nil
+}
+
+func fileDigest(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Start 持续导出落后最新区块 Confirmations 个区块的完整分区, 直到 ctx 结束
+func (e *Exporter) Start(ctx context.Context) {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		tip, err := e.Tip(ctx)
+		if err != nil {
+			log.WithField("err", err).Error("export get tip failed")
+		} else if tip >= e.Confirmations {
+			if _, err := e.RunOnce(ctx, tip-e.Confirmations); err != nil && ctx.Err() == nil {
+				log.WithField("err", err).Error("export run failed")
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+/*
+## Parquet
+  只实现导出需要的最小子集, 不依赖第三方 Parquet 库:
+
+  - 扁平 schema, 列为 REQUIRED 或 OPTIONAL, 不支持嵌套和重复字段
+  - 每次 Write 写入一个 row group, 每列一个 v1 data page, 值使用 PLAIN 编码
+  - OPTIONAL 列的 definition level 使用 RLE 编码, REQUIRED 列没有 level
+  - 页面压缩支持 SNAPPY、GZIP 和不压缩, 元数据使用 Thrift compact protocol
+  - 不写统计信息和字典页
+  - testdata 中的 golden 文件用 arrow 和 parquet-go 读取校验过, 见 testdata/README.md
+
+  | ColumnType | Physical   | Converted        |
+  |------------|------------|------------------|
+  | Int64      | INT64      |                  |
+  | String     | BYTE_ARRAY | UTF8             |
+  | Bool       | BOOLEAN    |                  |
+  | Timestamp  | INT64      | TIMESTAMP_MILLIS |
+*/
+
+const parquetMagic = "PAR1"
+
+// Parquet 元数据中的枚举值, 见 parquet-format 的 parquet.thrift
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUtf8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRle   = 3
+
+	pageTypeData = 0
+)
+
+// Codec Parquet 页面的压缩方式
+type Codec string
+
+const (
+	CodecNone   Codec = "none"
+	CodecSnappy Codec = "snappy"
+	CodecGzip   Codec = "gzip"
+)
+
+func (c Codec) id() (int32, error) {
+	switch c {
+	case CodecNone:
+		return 0, nil
+	case CodecSnappy, "":
+		return 1, nil
+	case CodecGzip:
+		return 2, nil
+	}
+	return 0, fmt.Errorf("export: unknown parquet codec %q", c)
+}
+
+func (c Codec) compress(data []byte) ([]byte, error) {
+	switch c {
+	case CodecNone:
+		return data, nil
+	case CodecGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return snappy.Encode(nil, data), nil
+	}
+}
+
+type columnChunk struct {
+	offset           int64
+	uncompressedSize int64
+	compressedSize   int64
+	numValues        int64
+}
+
+type rowGroup struct {
+	numRows   int64
+	totalSize int64
+	columns   []columnChunk
+}
+
+// parquetWriter 把行写入 Parquet 文件, Close 时写入文件尾的元数据
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []Column
+	codec     Codec
+	codecId   int32
+	rowGroups []rowGroup
+	numRows   int64
+}
+
+func newParquetWriter(w io.Writer, columns []Column, codec Codec) (*parquetWriter, error) {
+	codecId, err := codec.id()
+	if err != nil {
+		return nil, err
+	}
+	p := &parquetWriter{w: w, columns: columns, codec: codec, codecId: codecId}
+	return p, p.write([]byte(parquetMagic))
+}
+
+func (p *parquetWriter) write(data []byte) error {
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+// Write 写入一个 row group, 每行的值与列一一对应
+func (p *parquetWriter) Write(rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	group := rowGroup{numRows: int64(len(rows))}
+	for i, column := range p.columns {
+		page, err := encodePage(column, rows, i)
+		if err != nil {
+			return err
+		}
+		compressed, err := p.codec.compress(page)
+		if err != nil {
+			return err
+		}
+
+		var header thriftWriter
+		header.i32(1, pageTypeData)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(compressed)))
+		header.structField(5, func() {
+			header.i32(1, int32(len(rows)))
+			header.i32(2, encodingPlain)
+			header.i32(3, encodingRle)
+			header.i32(4, encodingRle)
+		})
+		header.stop()
+
+		chunk := columnChunk{
+			offset:           p.offset,
+			uncompressedSize: int64(header.buf.Len() + len(page)),
+			compressedSize:   int64(header.buf.Len() + len(compressed)),
+			numValues:        int64(len(rows)),
+		}
+		if err := p.write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := p.write(compressed); err != nil {
+			return err
+		}
+		group.columns = append(group.columns, chunk)
+		group.totalSize += chunk.uncompressedSize
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.numRows += group.numRows
+	return nil
+}
+
+// Close 写入 FileMetaData, 不关闭底层的 io.Writer
+func (p *parquetWriter) Close() error {
+	var meta thriftWriter
+	meta.i32(1, 1)
+	meta.listField(2, thriftStruct, len(p.columns)+1, func() {
+		meta.listStruct(func() {
+			meta.string(4, "schema")
+			meta.i32(5, int32(len(p.columns)))
+		})
+		for _, column := range p.columns {
+			column := column
+			meta.listStruct(func() {
+				meta.i32(1, column.Type.physical())
+				repetition := int32(repetitionRequired)
+				if column.Optional {
+					repetition = repetitionOptional
+				}
+				meta.i32(3, repetition)
+				meta.string(4, column.Name)
+				if converted, ok := column.Type.converted(); ok {
+					meta.i32(6, converted)
+				}
+			})
+		}
+	})
+	meta.i64(3, p.numRows)
+	meta.listField(4, thriftStruct, len(p.rowGroups), func() {
+		for _, group := range p.rowGroups {
+			group := group
+			meta.listStruct(func() {
+				meta.listField(1, thriftStruct, len(group.columns), func() {
+					for i, chunk := range group.columns {
+						column, chunk := p.columns[i], chunk
+						meta.listStruct(func() {
+							meta.i64(2, chunk.offset)
+							meta.structField(3, func() {
+								meta.i32(1, column.Type.physical())
+								meta.listField(2, thriftI32, 2, func() {
+									meta.listI32(encodingPlain)
+									meta.listI32(encodingRle)
+								})
+								meta.listField(3, thriftBinary, 1, func() {
+									meta.listString(column.Name)
+								})
+								meta.i32(4, p.codecId)
+								meta.i64(5, chunk.numValues)
+								meta.i64(6, chunk.uncompressedSize)
+								meta.i64(7, chunk.compressedSize)
+								meta.i64(9, chunk.offset)
+							})
+						})
+					}
+				})
+				meta.i64(2, group.totalSize)
+				meta.i64(3, group.numRows)
+			})
+		}
+	})
+	meta.string(6, "metago export")
+	meta.stop()
+
+	footer := meta.buf.Bytes()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	if err := p.write(size[:]); err != nil {
+		return err
+	}
+	return p.write([]byte(parquetMagic))
+}
+
+func (t ColumnType) physical() int32 {
+	switch t {
+	case String:
+		return physicalByteArray
+	case Bool:
+		return physicalBoolean
+	default:
+		return physicalInt64
+	}
+}
+
+func (t ColumnType) converted() (int32, bool) {
+	switch t {
+	case String:
+		return convertedUtf8, true
+	case Timestamp:
+		return convertedTimestampMillis, true
+	}
+	return 0, false
+}
+
+// encodePage 编码一列的 definition level 和非空值
+func encodePage(column Column, rows [][]interface{}, index int) ([]byte, error) {
+	var page bytes.Buffer
+	if column.Optional {
+		levels := make([]bool, len(rows))
+		for i, row := range rows {
+			levels[i] = row[index] != nil
+		}
+		encoded := rleLevels(levels)
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(encoded)))
+		page.Write(size[:])
+		page.Write(encoded)
+	}
+
+	var bits []bool
+	for _, row := range rows {
+		value := row[index]
+		if value == nil {
+			if !column.Optional {
+				return nil, fmt.Errorf("export: required column %s is null", column.Name)
+			}
+			continue
+		}
+		switch column.Type {
+		case Int64:
+			v, ok := value.(int64)
+			if !ok {
+				return nil, fmt.Errorf("export: column %s wants int64, got %T", column.Name, value)
+			}
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(v))
+			page.Write(b[:])
+		case Timestamp:
+			v, ok := value.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("export: column %s wants time.Time, got %T", column.Name, value)
+			}
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(v.UnixMilli()))
+			page.Write(b[:])
+		case String:
+			v, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("export: column %s wants string, got %T", column.Name, value)
+			}
+			if len(v) > math.MaxInt32 {
+				return nil, fmt.Errorf("export: column %s value too large", column.Name)
+			}
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], uint32(len(v)))
+			page.Write(b[:])
+			page.WriteString(v)
+		case Bool:
+			v, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("export: column %s wants bool, got %T", column.Name, value)
+			}
+			bits = append(bits, v)
+		}
+	}
+	if column.Type == Bool {
+		// PLAIN 编码的布尔值按位打包, 低位在前
+		packed := make([]byte, (len(bits)+7)/8)
+		for i, v := range bits {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		page.Write(packed)
+	}
+	return page.Bytes(), nil
+}
+
+// rleLevels 用 RLE/bit-packing 混合编码中的 RLE run 编码位宽为1的 definition level
+func rleLevels(levels []bool) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		if levels[i] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// Thrift compact protocol 的类型
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter 按 Thrift compact protocol 编码结构体, 字段需要按 id 递增的顺序写入
+type thriftWriter struct {
+	buf    bytes.Buffer
+	last   int16
+	parent []int16
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(zigzag(int64(id)))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) string(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.listString(v)
+}
+
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
+
+func (t *thriftWriter) begin() {
+	t.parent = append(t.parent, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) end() {
+	t.stop()
+	t.last = t.parent[len(t.parent)-1]
+	t.parent = t.parent[:len(t.parent)-1]
+}
+
+func (t *thriftWriter) structField(id int16, fields func()) {
+	t.field(id, thriftStruct)
+	t.begin()
+	fields()
+	t.end()
+}
+
+func (t *thriftWriter) listField(id int16, elem byte, size int, elems func()) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xf0 | elem)
+		t.varint(uint64(size))
+	}
+	elems()
+}
+
+func (t *thriftWriter) listStruct(fields func()) {
+	t.begin()
+	fields()
+	t.end()
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) listString(v string) {
+	t.varint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
@@ -0,0 +1,256 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/traitmeta/metago/core/chain"
+)
+
+// ColumnType 导出列的类型, 大整数统一导出为十进制字符串
+type ColumnType int
+
+const (
+	Int64 ColumnType = iota
+	String
+	Bool
+	// Timestamp 毫秒精度的 UTC 时间
+	Timestamp
+)
+
+var columnTypeNames = map[ColumnType]string{Int64: "int64", String: "string", Bool: "bool", Timestamp: "timestamp"}
+
+func (t ColumnType) String() string {
+	return columnTypeNames[t]
+}
+
+func (t ColumnType) MarshalText() ([]byte, error) {
+	name, ok := columnTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("export: unknown column type %d", int(t))
+	}
+	return []byte(name), nil
+}
+
+func (t *ColumnType) UnmarshalText(text []byte) error {
+	for typ, name := range columnTypeNames {
+		if name == string(text) {
+			*t = typ
+			return nil
+		}
+	}
+	return fmt.Errorf("export: unknown column type %q", text)
+}
+
+// Column 导出文件中的一列, Optional 的列可以为空
+type Column struct {
+	Name     string     `json:"name"`
+	Type     ColumnType `json:"type"`
+	Optional bool       `json:"optional,omitempty"`
+}
+
+// Table 一张导出表, 列的顺序和类型就是导出文件的 schema, 修改后需要导出到新的目录
+type Table struct {
+	Name    string
+	Columns []Column
+	// Rows 把一个区块的数据转换为行, 值的类型与列对应, 可选列的空值为 nil
+	Rows func(data *chain.BlockData) [][]interface{}
+}
+
+func bigValue(v *big.Int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.String()
+}
+
+// bigList ERC-1155 批量转移的数组导出为十进制字符串的 JSON 数组
+func bigList(vs []*big.Int) interface{} {
+	if len(vs) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, v.String())
+	}
+	data, _ := json.Marshal(out)
+	return string(data)
+}
+
+func optString(v string) interface{} {
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
+var BlocksTable = Table{
+	Name: "blocks",
+	Columns: []Column{
+		{Name: "height", Type: Int64},
+		{Name: "hash", Type: String},
+		{Name: "parent_hash", Type: String},
+		{Name: "timestamp", Type: Timestamp},
+		{Name: "miner", Type: String},
+		{Name: "gas_limit", Type: Int64},
+		{Name: "gas_used", Type: Int64},
+		{Name: "base_fee_per_gas", Type: String, Optional: true},
+		{Name: "difficulty", Type: String, Optional: true},
+		{Name: "nonce", Type: String},
+		{Name: "size", Type: Int64},
+		{Name: "transaction_count", Type: Int64},
+	},
+	Rows: func(data *chain.BlockData) [][]interface{} {
+		b := &data.Block
+		return [][]interface{}{{
+			int64(b.BlockHeight), b.BlockHash, b.ParentHash, b.Timestamp.UTC(), b.MinerHash,
+			int64(b.GasLimit), int64(b.GasUsed), bigValue(b.BaseFeePerGas), bigValue(b.Difficulty),
+			b.Nonce, int64(b.Size), int64(len(data.Transactions)),
+		}}
+	},
+}
+
+var TransactionsTable = Table{
+	Name: "transactions",
+	Columns: []Column{
+		{Name: "block_number", Type: Int64},
+		{Name: "block_hash", Type: String},
+		{Name: "block_timestamp", Type: Timestamp},
+		{Name: "hash", Type: String},
+		{Name: "transaction_index", Type: Int64},
+		{Name: "type", Type: Int64},
+		{Name: "from", Type: String},
+		{Name: "to", Type: String, Optional: true},
+		{Name: "contract", Type: String, Optional: true},
+		{Name: "contract_creation", Type: Bool},
+		{Name: "value", Type: String},
+		{Name: "nonce", Type: Int64},
+		{Name: "status", Type: Int64},
+		{Name: "input", Type: String},
+		{Name: "gas", Type: Int64},
+		{Name: "gas_price", Type: String, Optional: true},
+		{Name: "gas_tip_cap", Type: String, Optional: true},
+		{Name: "gas_used", Type: Int64},
+		{Name: "cumulative_gas_used", Type: Int64},
+		{Name: "effective_gas_price", Type: String, Optional: true},
+		{Name: "revert_reason", Type: String, Optional: true},
+	},
+	Rows: func(data *chain.BlockData) [][]interface{} {
+		rows := make([][]interface{}, 0, len(data.Transactions))
+		for i := range data.Transactions {
+			t := &data.Transactions[i]
+			rows = append(rows, []interface{}{
+				int64(t.BlockNumber), t.BlockHash, data.Block.Timestamp.UTC(), t.TxHash, int64(t.TxIndex), int64(t.Type),
+				t.From, optString(t.To), optString(t.Contract), t.ContractCreation, t.Value, int64(t.Nonce), int64(t.Status),
+				"0x" + t.InputData, int64(t.Gas), optString(t.GasPrice), optString(t.GasTipCap), int64(t.GasUsed),
+				int64(t.CumulativeGasUsed), optString(t.EffectiveGasPrice), optString(t.RevertReason),
+			})
+		}
+		return rows
+	},
+}
+
+var EventsTable = Table{
+	Name: "events",
+	Columns: []Column{
+		{Name: "block_number", Type: Int64},
+		{Name: "block_hash", Type: String},
+		{Name: "block_timestamp", Type: Timestamp},
+		{Name: "transaction_hash", Type: String},
+		{Name: "transaction_index", Type: Int64},
+		{Name: "log_index", Type: Int64},
+		{Name: "address", Type: String},
+		{Name: "topic0", Type: String, Optional: true},
+		{Name: "topic1", Type: String, Optional: true},
+		{Name: "topic2", Type: String, Optional: true},
+		{Name: "topic3", Type: String, Optional: true},
+		{Name: "data", Type: String},
+		{Name: "removed", Type: Bool},
+	},
+	Rows: func(data *chain.BlockData) [][]interface{} {
+		rows := make([][]interface{}, 0, len(data.Events))
+		for i := range data.Events {
+			e := &data.Events[i]
+			rows = append(rows, []interface{}{
+				int64(e.BlockNumber), e.BlockHash, data.Block.Timestamp.UTC(), e.TxHash, int64(e.TxIndex), int64(e.LogIndex),
+				e.Address, optString(e.FirstTopic), optString(e.SecondTopic), optString(e.ThirdTopic), optString(e.FourthTopic),
+				"0x" + strings.TrimPrefix(e.Data, "0x"), e.Removed,
+			})
+		}
+		return rows
+	},
+}
+
+var TokenTransfersTable = Table{
+	Name: "token_transfers",
+	Columns: []Column{
+		{Name: "block_number", Type: Int64},
+		{Name: "block_hash", Type: String},
+		{Name: "block_timestamp", Type: Timestamp},
+		{Name: "transaction_hash", Type: String},
+		{Name: "log_index", Type: Int64},
+		{Name: "token", Type: String},
+		{Name: "from", Type: String},
+		{Name: "to", Type: String},
+		{Name: "amount", Type: String, Optional: true},
+		{Name: "token_id", Type: String, Optional: true},
+		{Name: "amounts", Type: String, Optional: true},
+		{Name: "token_ids", Type: String, Optional: true},
+	},
+	Rows: func(data *chain.BlockData) [][]interface{} {
+		rows := make([][]interface{}, 0, len(data.TokenTransfers))
+		for i := range data.TokenTransfers {
+			t := &data.TokenTransfers[i]
+			rows = append(rows, []interface{}{
+				int64(t.BlockNumber), t.BlockHash, data.Block.Timestamp.UTC(), t.TransactionHash, int64(t.LogIndex),
+				t.TokenContractAddress, t.FromAddress, t.ToAddress, bigValue(t.Amount), bigValue(t.TokenId),
+				bigList(t.Amounts), bigList(t.TokenIds),
+			})
+		}
+		return rows
+	},
+}
+
+// Tables 默认导出的表
+var Tables = []Table{BlocksTable, TransactionsTable, EventsTable, TokenTransfersTable}
+
+// LookupTables 按名称选择导出表, 名称为空时返回全部
+func LookupTables(names []string) ([]Table, error) {
+	if len(names) == 0 {
+		return Tables, nil
+	}
+	var tables []Table
+	for _, name := range names {
+		found := false
+		for _, t := range Tables {
+			if t.Name == strings.TrimSpace(name) {
+				tables, found = append(tables, t), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("export: unknown table %q", name)
+		}
+	}
+	return tables, nil
+}
+
+// formatValue CSV 中的值, 时间为 RFC3339 毫秒精度, 空值为空字符串
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return fmt.Sprint(v)
+	case bool:
+		return fmt.Sprint(v)
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,134 @@
+/*
+## Overview
+fixture 录制和回放 EVM 区块解析过程中对节点的全部请求, 用真实区块离线复现解析问题.
+
+录制时在 HTTP 传输层记录 JSON-RPC 请求和响应, 包括区块、交易回执、合约代码、
+eth_call 和 debug_traceTransaction 的结果, 写入一个 JSON 文件. 回放时用同样的
+传输层按 method + params 返回录制的结果, 不访问网络, 未录制的请求返回错误.
+
+录制和回放都会临时替换 config.EthRpcClient 和 config.BlockChain, 同一时间只能运行一个,
+并且每次都使用新的交易池代币缓存, 保证录制下来的请求完整.
+*/
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/core/chain"
+)
+
+// replayUrl 回放时使用的节点地址, 请求不会离开进程
+const replayUrl = "http://fixture.invalid"
+
+// Fixture 一个区块的录制结果
+type Fixture struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+	// Tracer 录制时是否通过 debug_traceTransaction 获取失败原因, 回放时保持一致
+	Tracer bool   `json:"tracer,omitempty"`
+	Calls  []Call `json:"calls"`
+}
+
+// Call 一次 JSON-RPC 请求及其响应
+type Call struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *CallError      `json:"error,omitempty"`
+}
+
+// CallError JSON-RPC 错误, Data 保留 revert 数据
+type CallError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Load 读取录制文件
+func Load(path string) (*Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, errors.Wrapf(err, "decode fixture %s", path)
+	}
+	return &f, nil
+}
+
+// Save 写入录制文件, 缩进后便于在代码评审中查看差异
+func (f *Fixture) Save(path string) error {
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// Record 通过 rpcUrl 解析区块并录制全部节点请求, 同时返回解析结果
+func Record(ctx context.Context, rpcUrl string, height uint64, tracer bool) (*Fixture, *chain.BlockData, error) {
+	rec := newRecorder(http.DefaultTransport)
+	data, err := run(ctx, rpcUrl, rec, height, tracer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Fixture{
+		Height: height,
+		Hash:   data.Block.BlockHash,
+		Tracer: tracer,
+		Calls:  rec.recorded(),
+	}, data, nil
+}
+
+// Replay 离线回放录制的区块, 执行完整的区块解析流程
+func Replay(ctx context.Context, f *Fixture) (*chain.BlockData, error) {
+	data, err := run(ctx, replayUrl, newReplayer(f.Calls), f.Height, f.Tracer)
+	if err != nil {
+		return nil, err
+	}
+	if f.Hash != "" && data.Block.BlockHash != f.Hash {
+		return nil, errors.Errorf("replayed block hash %s, fixture %s", data.Block.BlockHash, f.Hash)
+	}
+	return data, nil
+}
+
+var mu sync.Mutex
+
+func run(ctx context.Context, rpcUrl string, transport http.RoundTripper, height uint64, tracer bool) (*chain.BlockData, error) {
+	rpcClient, err := rpc.DialOptions(ctx, rpcUrl, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		return nil, errors.Wrap(err, "dial rpc")
+	}
+	client := ethclient.NewClient(rpcClient)
+	defer client.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	prevClient, prevConf := config.EthRpcClient, config.BlockChain
+	defer func() { config.EthRpcClient, config.BlockChain = prevClient, prevConf }()
+
+	conf := setting.BlockChainConfig{}
+	if prevConf != nil {
+		conf = *prevConf
+	}
+	conf.Tracer = tracer
+	config.EthRpcClient, config.BlockChain = client, &conf
+
+	block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "get block %d", height)
+	}
+	return chain.ParseBlockWith(ctx, block, chain.NewRpcPoolTokenCache())
+}
@@ -0,0 +1,179 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// message JSON-RPC 请求或响应
+type message struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *CallError      `json:"error,omitempty"`
+}
+
+// callKey 相同 method 和 params 的请求视为同一个请求
+func callKey(method string, params json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return method + " " + string(params)
+	}
+	return method + " " + buf.String()
+}
+
+// decodeMessages 解析单个或批量的 JSON-RPC 消息
+func decodeMessages(body []byte) ([]message, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var msgs []message
+		err := json.Unmarshal(body, &msgs)
+		return msgs, true, err
+	}
+	var msg message
+	err := json.Unmarshal(body, &msg)
+	return []message{msg}, false, err
+}
+
+// recorder 转发请求并记录每个请求的响应, 重复的请求只记录第一次
+type recorder struct {
+	next  http.RoundTripper
+	mu    sync.Mutex
+	seen  map[string]bool
+	calls []Call
+}
+
+func newRecorder(next http.RoundTripper) *recorder {
+	return &recorder{next: next, seen: map[string]bool{}}
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if resp.StatusCode == http.StatusOK {
+		if err := r.add(body, respBody); err != nil {
+			return nil, errors.Wrap(err, "record rpc call")
+		}
+	}
+	return resp, nil
+}
+
+func (r *recorder) add(reqBody, respBody []byte) error {
+	reqs, _, err := decodeMessages(reqBody)
+	if err != nil {
+		return err
+	}
+	resps, _, err := decodeMessages(respBody)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]message, len(resps))
+	for _, resp := range resps {
+		byID[string(resp.ID)] = resp
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, req := range reqs {
+		resp, ok := byID[string(req.ID)]
+		key := callKey(req.Method, req.Params)
+		if !ok || r.seen[key] {
+			continue
+		}
+		r.seen[key] = true
+		r.calls = append(r.calls, Call{Method: req.Method, Params: req.Params, Result: resp.Result, Error: resp.Error})
+	}
+	return nil
+}
+
+func (r *recorder) recorded() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// replayer 按录制的结果响应请求, 不访问网络
+type replayer struct {
+	calls map[string]Call
+}
+
+func newReplayer(calls []Call) *replayer {
+	r := &replayer{calls: make(map[string]Call, len(calls))}
+	for _, call := range calls {
+		r.calls[callKey(call.Method, call.Params)] = call
+	}
+	return r
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	reqs, batch, err := decodeMessages(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]message, 0, len(reqs))
+	for _, msg := range reqs {
+		resp := message{Version: "2.0", ID: msg.ID}
+		if call, ok := r.calls[callKey(msg.Method, msg.Params)]; ok {
+			resp.Result, resp.Error = call.Result, call.Error
+		} else {
+			resp.Error = &CallError{Code: -32000, Message: "fixture: call not recorded: " + callKey(msg.Method, msg.Params)}
+		}
+		resps = append(resps, resp)
+	}
+
+	var out []byte
+	if batch {
+		out, err = json.Marshal(resps)
+	} else {
+		out, err = json.Marshal(resps[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(out)),
+		ContentLength: int64(len(out)),
+		Request:       req,
+	}, nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
@@ -0,0 +1,151 @@
+package mempool
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+/*
+## Overview
+  交易池中的交易入库后为 pending, 之后按以下规则更新状态:
+
+  | 状态       | 时机                                                              |
+  |------------|-------------------------------------------------------------------|
+  | `mined`    | 区块提交后交易哈希出现在区块中                                    |
+  | `replaced` | 相同 sender 和 nonce 的另一笔交易上链, replaced_by 为上链的交易    |
+  | `dropped`  | sender 更大的 nonce 已上链, 或超过 DropAfter 仍未上链              |
+
+  状态在区块提交之后的钩子中更新, 失败只记录日志, 不影响区块同步.
+*/
+
+// HookName 在 chain 中注册的提交钩子名称
+const HookName = "mempool"
+
+const defaultDropAfter = time.Hour
+
+// ToPendingTransaction 转换交易池中的交易, seen 为第一次看到的时间
+func ToPendingTransaction(tx *types.Transaction, seen time.Time) (models.PendingTransaction, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return models.PendingTransaction{}, errors.Wrap(err, "recover sender")
+	}
+
+	pending := models.PendingTransaction{
+		TxHash:    tx.Hash().Hex(),
+		From:      from.Hex(),
+		Nonce:     tx.Nonce(),
+		Value:     tx.Value().String(),
+		Type:      tx.Type(),
+		Gas:       tx.Gas(),
+		GasPrice:  tx.GasPrice().String(),
+		GasTipCap: tx.GasTipCap().String(),
+		InputData: hex.EncodeToString(tx.Data()),
+		FirstSeen: seen.UTC(),
+		Status:    common.PendingStatusPending,
+	}
+	if tx.To() != nil {
+		pending.To = tx.To().Hex()
+	}
+	return pending, nil
+}
+
+// Settlement 一个区块上链后需要更新状态的交易池交易
+type Settlement struct {
+	Mined []string
+	// Replaced 被替换的交易哈希到上链交易哈希
+	Replaced map[string]string
+	Dropped  []string
+}
+
+// Settle 根据区块中的交易计算交易池交易的新状态, pending 为区块中 sender 仍处于 pending 的交易
+func Settle(trxs []models.Transaction, pending []models.PendingTransaction) Settlement {
+	settlement := Settlement{Replaced: map[string]string{}}
+	type senderNonce struct {
+		from  string
+		nonce uint64
+	}
+	minedHashes := make(map[string]bool, len(trxs))
+	minedByNonce := make(map[senderNonce]string, len(trxs))
+	maxNonce := make(map[string]uint64, len(trxs))
+	for _, trx := range trxs {
+		settlement.Mined = append(settlement.Mined, trx.TxHash)
+		minedHashes[trx.TxHash] = true
+		minedByNonce[senderNonce{trx.From, trx.Nonce}] = trx.TxHash
+		if nonce, ok := maxNonce[trx.From]; !ok || trx.Nonce > nonce {
+			maxNonce[trx.From] = trx.Nonce
+		}
+	}
+
+	for _, tx := range pending {
+		if minedHashes[tx.TxHash] {
+			continue
+		}
+		if hash, ok := minedByNonce[senderNonce{tx.From, tx.Nonce}]; ok {
+			settlement.Replaced[tx.TxHash] = hash
+			continue
+		}
+		if nonce, ok := maxNonce[tx.From]; ok && tx.Nonce < nonce {
+			settlement.Dropped = append(settlement.Dropped, tx.TxHash)
+		}
+	}
+	return settlement
+}
+
+// CommitHook 区块提交后更新交易池交易的状态
+func CommitHook(ctx context.Context, data *chain.BlockData) error {
+	senders := make([]string, 0, len(data.Transactions))
+	seen := make(map[string]bool, len(data.Transactions))
+	for _, trx := range data.Transactions {
+		if !seen[trx.From] {
+			seen[trx.From] = true
+			senders = append(senders, trx.From)
+		}
+	}
+	pending, err := dal.PendingTransaction.ListPendingBySenders(ctx, senders)
+	if err != nil {
+		return errors.Wrap(err, "list pending transactions")
+	}
+
+	settlement := Settle(data.Transactions, pending)
+	if err := dal.PendingTransaction.MarkMined(ctx, data.Block.BlockHeight, settlement.Mined); err != nil {
+		return errors.Wrap(err, "mark mined")
+	}
+	for hash, replacedBy := range settlement.Replaced {
+		if err := dal.PendingTransaction.UpdateStatus(ctx, []string{hash}, common.PendingStatusReplaced, replacedBy); err != nil {
+			return errors.Wrap(err, "mark replaced")
+		}
+	}
+	if err := dal.PendingTransaction.UpdateStatus(ctx, settlement.Dropped, common.PendingStatusDropped, ""); err != nil {
+		return errors.Wrap(err, "mark dropped")
+	}
+	return nil
+}
+
+// Setup 启动交易池监听并注册提交钩子, 未开启时返回 nil
+func Setup(ctx context.Context, conf *setting.MempoolConfig, chainConf *setting.BlockChainConfig) (*Watcher, error) {
+	if conf == nil || !conf.Enable {
+		return nil, nil
+	}
+	wsUrl := chainConf.WebsocketUrl()
+	if wsUrl == "" {
+		return nil, errors.New("mempool watcher needs a websocket endpoint, set BlockChain.WsUrl")
+	}
+
+	watcher := NewWatcher(wsUrl)
+	if conf.DropAfter > 0 {
+		watcher.DropAfter = conf.DropAfter
+	}
+	chain.RegisterCommitHook(HookName, CommitHook)
+	go watcher.Start(ctx)
+	return watcher, nil
+}
@@ -0,0 +1,132 @@
+package mempool
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+func TestToPendingTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := ethcommon.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	chainId := big.NewInt(8453)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainId), &types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     7,
+		To:        &to,
+		Value:     big.NewInt(1000),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(2e9),
+		GasTipCap: big.NewInt(1e8),
+		Data:      []byte{0xa9, 0x05, 0x9c, 0xbb},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+8", 8*3600))
+	got, err := ToPendingTransaction(tx, seen)
+	if err != nil {
+		t.Fatalf("ToPendingTransaction() error = %v", err)
+	}
+	want := models.PendingTransaction{
+		TxHash:    tx.Hash().Hex(),
+		From:      crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Nonce:     7,
+		To:        to.Hex(),
+		Value:     "1000",
+		Type:      types.DynamicFeeTxType,
+		Gas:       21000,
+		GasPrice:  "2000000000",
+		GasTipCap: "100000000",
+		InputData: "a9059cbb",
+		FirstSeen: seen.UTC(),
+		Status:    common.PendingStatusPending,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPendingTransaction() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	alice := "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	bob := "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+
+	trxs := []models.Transaction{
+		{TxHash: "0x01", From: alice, Nonce: 5},
+		{TxHash: "0x02", From: alice, Nonce: 6},
+		{TxHash: "0x03", From: bob, Nonce: 1},
+	}
+	pending := []models.PendingTransaction{
+		// 已上链, 由 Mined 处理
+		{TxHash: "0x01", From: alice, Nonce: 5},
+		// 加速交易, 相同 nonce 的另一笔上链
+		{TxHash: "0x11", From: alice, Nonce: 6},
+		// 更早的 nonce 没有上链
+		{TxHash: "0x12", From: alice, Nonce: 3},
+		// 之后的 nonce 仍然有效
+		{TxHash: "0x13", From: alice, Nonce: 7},
+		{TxHash: "0x14", From: bob, Nonce: 2},
+	}
+
+	got := Settle(trxs, pending)
+	want := Settlement{
+		Mined:    []string{"0x01", "0x02", "0x03"},
+		Replaced: map[string]string{"0x11": "0x02"},
+		Dropped:  []string{"0x12"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Settle() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWatcherFlushesAfterCancel(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainId := big.NewInt(8453)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainId), &types.DynamicFeeTx{ChainID: chainId, Gas: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inserted []models.PendingTransaction
+	w := NewWatcher("")
+	w.FlushInterval = time.Hour
+	w.insert = func(ctx context.Context, txs []models.PendingTransaction) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("final flush has no timeout")
+		}
+		inserted = append(inserted, txs...)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txs := make(chan *types.Transaction)
+	done := make(chan error)
+	go func() { done <- w.collect(ctx, txs, nil) }()
+	txs <- tx
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+	if len(inserted) != 1 || inserted[0].TxHash != tx.Hash().Hex() {
+		t.Errorf("inserted %+v, want the pending transaction received before cancel", inserted)
+	}
+}
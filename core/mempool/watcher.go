@@ -0,0 +1,171 @@
+package mempool
+
+import (
+	"context"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	defaultFlushInterval    = time.Second
+	defaultSweepInterval    = time.Minute
+	defaultResubscribeDelay = 5 * time.Second
+	// finalFlushTimeout 退出时写入最后一批交易的超时时间
+	finalFlushTimeout = 5 * time.Second
+)
+
+// Watcher 订阅 newPendingTransactions 并批量入库, 节点不支持完整交易时订阅哈希再逐个查询
+type Watcher struct {
+	DropAfter        time.Duration
+	FlushInterval    time.Duration
+	SweepInterval    time.Duration
+	ResubscribeDelay time.Duration
+
+	wsUrl  string
+	insert func(ctx context.Context, txs []models.PendingTransaction) error
+}
+
+func NewWatcher(wsUrl string) *Watcher {
+	return &Watcher{
+		DropAfter:        defaultDropAfter,
+		FlushInterval:    defaultFlushInterval,
+		SweepInterval:    defaultSweepInterval,
+		ResubscribeDelay: defaultResubscribeDelay,
+		wsUrl:            wsUrl,
+		insert:           dal.PendingTransaction.Inserts,
+	}
+}
+
+// Start 订阅断开后重新订阅, 直到 ctx 结束
+func (w *Watcher) Start(ctx context.Context) {
+	go w.sweep(ctx)
+	for ctx.Err() == nil {
+		if err := w.subscribe(ctx); err != nil && ctx.Err() == nil {
+			log.WithField("err", err).Warn("pending transaction subscription dropped, resubscribing")
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.ResubscribeDelay):
+			}
+		}
+	}
+}
+
+// sweep 定时把超时未上链的交易标记为丢弃
+func (w *Watcher) sweep(ctx context.Context) {
+	ticker := time.NewTicker(w.SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dropped, err := dal.PendingTransaction.MarkDropped(ctx, time.Now().Add(-w.DropAfter))
+			if err != nil {
+				log.WithField("err", err).Error("mark dropped pending transactions fail")
+			} else if dropped > 0 {
+				log.WithField("count", dropped).Info("pending transactions dropped")
+			}
+		}
+	}
+}
+
+func (w *Watcher) subscribe(ctx context.Context) error {
+	client, err := rpc.DialContext(ctx, w.wsUrl)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	// 订阅结束时停止查询交易的协程
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txs := make(chan *types.Transaction, 1024)
+	sub, err := gethclient.New(client).SubscribeFullPendingTransactions(ctx, txs)
+	if err != nil {
+		log.WithField("err", err).Info("full pending transactions not supported, subscribing hashes")
+		hashes := make(chan ethcommon.Hash, 1024)
+		if sub, err = gethclient.New(client).SubscribePendingTransactions(ctx, hashes); err != nil {
+			return err
+		}
+		go fetchTransactions(ctx, ethclient.NewClient(client), hashes, txs)
+	}
+	defer sub.Unsubscribe()
+
+	return w.collect(ctx, txs, sub.Err())
+}
+
+// collect 按 FlushInterval 或 BatchSize 批量写入收到的交易, 订阅出错或 ctx 结束时返回.
+// ctx 结束后仍然写入最后一批, 使用不受 ctx 取消影响的超时
+func (w *Watcher) collect(ctx context.Context, txs <-chan *types.Transaction, subErr <-chan error) error {
+	ticker := time.NewTicker(w.FlushInterval)
+	defer ticker.Stop()
+	var batch []models.PendingTransaction
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.insert(ctx, batch); err != nil {
+			log.WithField("err", err).WithField("count", len(batch)).Error("insert pending transactions fail")
+		}
+		batch = batch[:0]
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalFlushTimeout)
+		defer cancel()
+		flush(flushCtx)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subErr:
+			return err
+		case <-ticker.C:
+			flush(ctx)
+		case tx := <-txs:
+			pending, err := ToPendingTransaction(tx, time.Now())
+			if err != nil {
+				log.WithField("txHash", tx.Hash().Hex()).WithField("err", err).Warn("skip pending transaction")
+				continue
+			}
+			batch = append(batch, pending)
+			if len(batch) >= common.BatchSize {
+				flush(ctx)
+			}
+		}
+	}
+}
+
+// fetchTransactions 只收到哈希时查询完整交易, 查询不到的交易已经上链或被丢弃
+func fetchTransactions(ctx context.Context, client *ethclient.Client, hashes <-chan ethcommon.Hash, txs chan<- *types.Transaction) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case hash, ok := <-hashes:
+			if !ok {
+				return
+			}
+			tx, isPending, err := client.TransactionByHash(ctx, hash)
+			if err != nil || !isPending {
+				continue
+			}
+			select {
+			case txs <- tx:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
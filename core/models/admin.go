@@ -0,0 +1,42 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+const (
+	AddressLabelExchange = "exchange"
+	AddressLabelBridge   = "bridge"
+	AddressLabelScam     = "scam"
+)
+
+// AddressLabel 管理后台给地址打的标签, 同一个地址的同一种标签只有一条
+type AddressLabel struct {
+	*gorm.Model
+
+	Address   string `json:"address" gorm:"column:address; type:char(42); uniqueIndex:address_labels_unique; comment:地址;"`
+	Label     string `json:"label" gorm:"column:label; type:varchar(32); uniqueIndex:address_labels_unique; comment:标签 exchange, bridge, scam;"`
+	Name      string `json:"name" gorm:"column:name; type:varchar(128); default:''; comment:展示名称, 例如交易所名称;"`
+	Note      string `json:"note" gorm:"column:note; type:text; comment:备注;"`
+	CreatedBy string `json:"created_by" gorm:"column:created_by; type:varchar(64); comment:操作人;"`
+}
+
+func (l *AddressLabel) TableName() string {
+	return "address_labels"
+}
+
+// AdminAuditLog 管理后台的操作记录, Before 和 After 为修改前后的 JSON
+type AdminAuditLog struct {
+	*gorm.Model
+
+	Actor      string `json:"actor" gorm:"column:actor; type:varchar(64); index; comment:操作人;"`
+	Action     string `json:"action" gorm:"column:action; type:varchar(64); comment:操作;"`
+	TargetType string `json:"target_type" gorm:"column:target_type; type:varchar(32); comment:对象类型 token, address;"`
+	Target     string `json:"target" gorm:"column:target; type:varchar(128); index; comment:对象;"`
+	Before     string `json:"before" gorm:"column:before; type:text; comment:修改前;"`
+	After      string `json:"after" gorm:"column:after; type:text; comment:修改后;"`
+}
+
+func (a *AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
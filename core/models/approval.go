@@ -0,0 +1,44 @@
+package models
+
+import (
+	"math/big"
+
+	"gorm.io/gorm"
+)
+
+// TokenAllowance ERC20 当前授权额度, 每个 (token, owner, spender) 只保留最后一次 Approval
+type TokenAllowance struct {
+	*gorm.Model
+
+	Token           string   `json:"token" gorm:"column:token; type:char(42); uniqueIndex:idx_token_allowance; comment:Token合约地址;"`
+	Owner           string   `json:"owner" gorm:"column:owner; type:char(42); uniqueIndex:idx_token_allowance; index; comment:授权人;"`
+	Spender         string   `json:"spender" gorm:"column:spender; type:char(42); uniqueIndex:idx_token_allowance; index; comment:被授权人;"`
+	Amount          *big.Int `json:"amount" gorm:"column:amount; type:numeric; serializer:json; comment:授权额度, 0表示已撤销;"`
+	Unlimited       bool     `json:"unlimited" gorm:"column:unlimited; default:false; comment:是否无限授权;"`
+	TransactionHash string   `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); comment:交易哈希;"`
+	BlockNumber     uint64   `json:"block_number" gorm:"column:block_number; comment:区块高度;"`
+	BlockHash       string   `json:"block_hash" gorm:"column:block_hash; type:char(66); comment:区块哈希;"`
+	LogIndex        uint     `json:"log_index" gorm:"column:log_index; comment:日志索引;"`
+}
+
+func (a *TokenAllowance) TableName() string {
+	return "token_allowances"
+}
+
+// OperatorApproval ERC721/ERC1155 的 ApprovalForAll 当前状态
+type OperatorApproval struct {
+	*gorm.Model
+
+	Collection      string `json:"collection" gorm:"column:collection; type:char(42); uniqueIndex:idx_operator_approval; comment:NFT合约地址;"`
+	Owner           string `json:"owner" gorm:"column:owner; type:char(42); uniqueIndex:idx_operator_approval; index; comment:授权人;"`
+	Operator        string `json:"operator" gorm:"column:operator; type:char(42); uniqueIndex:idx_operator_approval; index; comment:被授权的操作人;"`
+	Approved        bool   `json:"approved" gorm:"column:approved; default:false; comment:是否授权, false表示已撤销;"`
+	TransactionHash string `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); comment:交易哈希;"`
+	BlockNumber     uint64 `json:"block_number" gorm:"column:block_number; comment:区块高度;"`
+	BlockHash       string `json:"block_hash" gorm:"column:block_hash; type:char(66); comment:区块哈希;"`
+	LogIndex        uint   `json:"log_index" gorm:"column:log_index; comment:日志索引;"`
+}
+
+func (a *OperatorApproval) TableName() string {
+	return "operator_approvals"
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// SyncCursor 记录各个后台任务已经处理到的区块高度
+type SyncCursor struct {
+	*gorm.Model
+
+	Name        string `json:"name" gorm:"column:name; type:varchar(64); uniqueIndex; comment:任务名称;"`
+	BlockHeight uint64 `json:"block_height" gorm:"column:block_height; default:0; comment:已处理的区块高度;"`
+	BlockHash   string `json:"block_hash" gorm:"column:block_hash; default:''; comment:已处理的区块hash;"`
+}
+
+func (c *SyncCursor) TableName() string {
+	return "sync_cursors"
+}
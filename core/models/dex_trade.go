@@ -0,0 +1,36 @@
+package models
+
+import (
+	"math/big"
+
+	"gorm.io/gorm"
+)
+
+// DexTrade 从 Uniswap V2/V3 类交易池 Swap 日志解析出的成交记录
+type DexTrade struct {
+	*gorm.Model
+
+	Protocol        string   `json:"protocol" gorm:"column:protocol; type:varchar(16); comment:协议, uniswap_v2/uniswap_v3;"`
+	Pool            string   `json:"pool" gorm:"column:pool; type:char(42); index; comment:交易池合约地址;"`
+	Trader          string   `json:"trader" gorm:"column:trader; type:char(42); index; comment:发起交易的地址;"`
+	Sender          string   `json:"sender" gorm:"column:sender; type:char(42); comment:调用交易池的地址, 通常是路由合约;"`
+	Recipient       string   `json:"recipient" gorm:"column:recipient; type:char(42); comment:接收买入代币的地址;"`
+	TokenIn         string   `json:"token_in" gorm:"column:token_in; type:char(42); index; comment:卖出的代币;"`
+	TokenOut        string   `json:"token_out" gorm:"column:token_out; type:char(42); index; comment:买入的代币;"`
+	AmountIn        *big.Int `json:"amount_in" gorm:"column:amount_in; type:numeric; serializer:json; comment:卖出数量;"`
+	AmountOut       *big.Int `json:"amount_out" gorm:"column:amount_out; type:numeric; serializer:json; comment:买入数量;"`
+	Price           float64  `json:"price" gorm:"column:price; comment:成交价格, amount_out/amount_in, 未按精度换算;"`
+	Reserve0        *big.Int `json:"reserve0,omitempty" gorm:"column:reserve0; type:numeric; serializer:json; comment:V2 成交后 token0 储备;"`
+	Reserve1        *big.Int `json:"reserve1,omitempty" gorm:"column:reserve1; type:numeric; serializer:json; comment:V2 成交后 token1 储备;"`
+	SqrtPriceX96    *big.Int `json:"sqrt_price_x96,omitempty" gorm:"column:sqrt_price_x96; type:numeric; serializer:json; comment:V3 成交后价格;"`
+	Liquidity       *big.Int `json:"liquidity,omitempty" gorm:"column:liquidity; type:numeric; serializer:json; comment:V3 成交后流动性;"`
+	Tick            int32    `json:"tick,omitempty" gorm:"column:tick; comment:V3 成交后 tick;"`
+	TransactionHash string   `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); uniqueIndex:idx_dex_trade_log; comment:交易哈希;"`
+	BlockNumber     uint64   `json:"block_number" gorm:"column:block_number; index; comment:区块高度;"`
+	BlockHash       string   `json:"block_hash" gorm:"column:block_hash; type:char(66); comment:区块哈希;"`
+	LogIndex        uint     `json:"log_index" gorm:"column:log_index; uniqueIndex:idx_dex_trade_log; comment:Swap 日志索引;"`
+}
+
+func (d *DexTrade) TableName() string {
+	return "dex_trades"
+}
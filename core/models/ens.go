@@ -0,0 +1,41 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// EnsName ENS 注册表中一个节点的当前状态, Address 和 NameRecord 只记录当前解析器设置的值
+type EnsName struct {
+	*gorm.Model
+
+	Node       string `json:"node" gorm:"column:node; type:char(66); uniqueIndex; comment:namehash;"`
+	ParentNode string `json:"parent_node" gorm:"column:parent_node; type:char(66); default:''; index; comment:父节点;"`
+	LabelHash  string `json:"label_hash" gorm:"column:label_hash; type:char(66); default:''; comment:标签哈希;"`
+	// Name 节点的明文名称, 只有从 NameChanged 或反向注册中得到过明文时才有值
+	Name            string `json:"name" gorm:"column:name; type:text; default:''; comment:名称;"`
+	Owner           string `json:"owner" gorm:"column:owner; type:char(42); default:''; index; comment:所有者;"`
+	Resolver        string `json:"resolver" gorm:"column:resolver; type:char(42); default:''; comment:解析器合约;"`
+	Address         string `json:"address" gorm:"column:address; type:char(42); default:''; index; comment:addr 记录;"`
+	TransactionHash string `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); comment:最后修改的交易哈希;"`
+	BlockNumber     uint64 `json:"block_number" gorm:"column:block_number; comment:最后修改的区块高度;"`
+}
+
+func (n *EnsName) TableName() string {
+	return "ens_names"
+}
+
+// EnsReverseRecord 地址的反向记录, Name 为反向节点上设置的主名称, ForwardNode 为主名称的 namehash
+type EnsReverseRecord struct {
+	*gorm.Model
+
+	Address         string `json:"address" gorm:"column:address; type:char(42); uniqueIndex; comment:地址;"`
+	Node            string `json:"node" gorm:"column:node; type:char(66); index; comment:反向节点;"`
+	Name            string `json:"name" gorm:"column:name; type:text; default:''; comment:主名称;"`
+	ForwardNode     string `json:"forward_node" gorm:"column:forward_node; type:char(66); default:''; index; comment:主名称的namehash;"`
+	TransactionHash string `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); comment:最后修改的交易哈希;"`
+	BlockNumber     uint64 `json:"block_number" gorm:"column:block_number; comment:最后修改的区块高度;"`
+}
+
+func (r *EnsReverseRecord) TableName() string {
+	return "ens_reverse_records"
+}
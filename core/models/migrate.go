@@ -2,10 +2,20 @@ package models
 
 import "github.com/traitmeta/gotos/lib/db"
 
+// TokenSearchDocument Token 全文检索的文档表达式, 查询时需要使用相同的表达式才能命中索引
+const TokenSearchDocument = "to_tsvector('english'::regconfig, (symbol || ' '::text) || name)"
+
+// tokenSearchIndex 与 migrations/4.address_token.up.sql 中的 tokens_trgm_idx 一致
+const tokenSearchIndex = "CREATE INDEX IF NOT EXISTS tokens_trgm_idx ON tokens USING gin (" + TokenSearchDocument + ")"
+
 // MigrateDb 初始化数据库表
 func MigrateDb() error {
-	if err := db.DBEngine.AutoMigrate(&Block{}, &Transaction{}, &Event{}); err != nil {
+	if err := db.DBEngine.AutoMigrate(&Block{}, &Transaction{}, &Event{}, &TokenTransfer{}, &SyncCursor{},
+		&DailyChainStat{}, &DailyTokenStat{}, &DailyActiveAddress{},
+		&Watchlist{}, &WebhookDelivery{}, &WebhookDeadLetter{}, &UserOperation{}, &DexTrade{}, &NftSale{},
+		&TokenAllowance{}, &OperatorApproval{}, &PendingTransaction{}, &Token{}, &AddressLabel{}, &AdminAuditLog{}, &TokenPrice{},
+		&EnsName{}, &EnsReverseRecord{}); err != nil {
 		return err
 	}
-	return nil
+	return db.DBEngine.Exec(tokenSearchIndex).Error
 }
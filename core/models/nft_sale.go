@@ -0,0 +1,33 @@
+package models
+
+import (
+	"math/big"
+
+	"gorm.io/gorm"
+)
+
+// NftSale NFT 市场成交记录, 每个成交的 NFT 对应一条 token 转移中的一个 Token ID
+type NftSale struct {
+	*gorm.Model
+
+	Marketplace      string   `json:"marketplace" gorm:"column:marketplace; type:varchar(16); index; comment:交易市场;"`
+	Collection       string   `json:"collection" gorm:"column:collection; type:char(42); index:idx_nft_sale_token; comment:NFT合约地址;"`
+	TokenId          *big.Int `json:"token_id" gorm:"column:token_id; type:numeric; serializer:json; index:idx_nft_sale_token; uniqueIndex:idx_nft_sale_transfer; comment:Token ID;"`
+	Amount           *big.Int `json:"amount" gorm:"column:amount; type:numeric; serializer:json; comment:成交数量, ERC721为1;"`
+	Seller           string   `json:"seller" gorm:"column:seller; type:char(42); index; comment:卖家;"`
+	Buyer            string   `json:"buyer" gorm:"column:buyer; type:char(42); index; comment:买家;"`
+	Price            *big.Int `json:"price" gorm:"column:price; type:numeric; serializer:json; comment:成交价格, 包含费用;"`
+	Currency         string   `json:"currency" gorm:"column:currency; type:char(42); comment:支付代币, 原生币为零地址;"`
+	PlatformFee      *big.Int `json:"platform_fee" gorm:"column:platform_fee; type:numeric; serializer:json; comment:平台费, 无法确定时为空;"`
+	Royalty          *big.Int `json:"royalty" gorm:"column:royalty; type:numeric; serializer:json; comment:版税, 无法确定时为空;"`
+	OrderHash        string   `json:"order_hash,omitempty" gorm:"column:order_hash; type:varchar(66); comment:订单哈希;"`
+	TransactionHash  string   `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); uniqueIndex:idx_nft_sale_transfer; comment:交易哈希;"`
+	BlockNumber      uint64   `json:"block_number" gorm:"column:block_number; index; comment:区块高度;"`
+	BlockHash        string   `json:"block_hash" gorm:"column:block_hash; type:char(66); comment:区块哈希;"`
+	LogIndex         uint     `json:"log_index" gorm:"column:log_index; comment:成交事件日志索引;"`
+	TransferLogIndex uint     `json:"transfer_log_index" gorm:"column:transfer_log_index; uniqueIndex:idx_nft_sale_transfer; comment:对应NFT转移的日志索引;"`
+}
+
+func (n *NftSale) TableName() string {
+	return "nft_sales"
+}
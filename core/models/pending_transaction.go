@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PendingTransaction 交易池中看到的交易, 上链、被替换或丢弃后更新状态
+type PendingTransaction struct {
+	*gorm.Model
+
+	TxHash      string    `json:"tx_hash" gorm:"column:tx_hash; type:char(66); uniqueIndex; comment:交易哈希;"`
+	From        string    `json:"from" gorm:"column:from_address; type:char(42); index:idx_pending_sender; comment:发送方;"`
+	Nonce       uint64    `json:"nonce" gorm:"column:nonce; index:idx_pending_sender; comment:Nonce;"`
+	To          string    `json:"to" gorm:"column:to_address; type:char(42); comment:接收方, 合约创建时为空;"`
+	Value       string    `json:"value" gorm:"column:value; type:varchar(78); comment:转账金额;"`
+	Type        uint8     `json:"type" gorm:"column:type; comment:交易类型;"`
+	Gas         uint64    `json:"gas" gorm:"column:gas; comment:Gas上限;"`
+	GasPrice    string    `json:"gas_price" gorm:"column:gas_price; type:varchar(78); comment:Gas价格, EIP-1559交易为 max fee;"`
+	GasTipCap   string    `json:"gas_tip_cap" gorm:"column:gas_tip_cap; type:varchar(78); comment:EIP-1559 priority fee;"`
+	InputData   string    `json:"input_data" gorm:"column:input_data; type:text; comment:调用数据;"`
+	FirstSeen   time.Time `json:"first_seen" gorm:"column:first_seen; index; comment:第一次在交易池中看到的时间;"`
+	Status      string    `json:"status" gorm:"column:status; type:varchar(16); index; comment:pending, mined, replaced, dropped;"`
+	BlockNumber uint64    `json:"block_number" gorm:"column:block_number; comment:上链的区块高度;"`
+	ReplacedBy  string    `json:"replaced_by,omitempty" gorm:"column:replaced_by; type:char(66); comment:相同 sender 和 nonce 上链的交易哈希;"`
+}
+
+func (p *PendingTransaction) TableName() string {
+	return "pending_transactions"
+}
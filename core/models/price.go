@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TokenPrice Token 的历史价格, 每次更新价格写入一条
+type TokenPrice struct {
+	*gorm.Model
+
+	ContractAddress string    `json:"contract_address" gorm:"column:contract_address; type:char(42); index:token_prices_token_time; comment:Token 合约地址;"`
+	Source          string    `json:"source" gorm:"column:source; type:varchar(32); comment:价格来源;"`
+	PriceUsd        float64   `json:"price_usd" gorm:"column:price_usd; comment:美元价格;"`
+	MarketCapUsd    float64   `json:"market_cap_usd" gorm:"column:market_cap_usd; comment:美元市值;"`
+	FetchedAt       time.Time `json:"fetched_at" gorm:"column:fetched_at; index:token_prices_token_time; comment:获取价格的时间;"`
+}
+
+func (p *TokenPrice) TableName() string {
+	return "token_prices"
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DailyChainStat 按天汇总的链上统计
+type DailyChainStat struct {
+	*gorm.Model
+
+	Date               time.Time `json:"date" gorm:"column:date; type:date; uniqueIndex; comment:统计日期(UTC);"`
+	BlockCount         int64     `json:"block_count" gorm:"column:block_count; default:0; comment:区块数量;"`
+	TransactionCount   int64     `json:"transaction_count" gorm:"column:transaction_count; default:0; comment:交易数量;"`
+	ActiveAddressCount int64     `json:"active_address_count" gorm:"column:active_address_count; default:0; comment:活跃地址数量;"`
+	NewContractCount   int64     `json:"new_contract_count" gorm:"column:new_contract_count; default:0; comment:新建合约数量;"`
+	TokenTransferCount int64     `json:"token_transfer_count" gorm:"column:token_transfer_count; default:0; comment:Token转移数量;"`
+	GasUsed            uint64    `json:"gas_used" gorm:"column:gas_used; default:0; comment:GAS使用量;"`
+}
+
+func (s *DailyChainStat) TableName() string {
+	return "daily_chain_stats"
+}
+
+// DailyTokenStat 按天、按Token汇总的转移统计
+type DailyTokenStat struct {
+	*gorm.Model
+
+	Date                 time.Time `json:"date" gorm:"column:date; type:date; uniqueIndex:daily_token_stats_date_token; comment:统计日期(UTC);"`
+	TokenContractAddress string    `json:"token_contract_address" gorm:"column:token_contract_address; type:char(42); uniqueIndex:daily_token_stats_date_token; comment:Token合约地址;"`
+	TransferCount        int64     `json:"transfer_count" gorm:"column:transfer_count; default:0; comment:转移次数;"`
+	Volume               string    `json:"volume" gorm:"column:volume; type:numeric; default:0; comment:ERC20转移总量;"`
+}
+
+func (s *DailyTokenStat) TableName() string {
+	return "daily_token_stats"
+}
+
+// DailyActiveAddress 每天出现过的地址, 用于去重计算活跃地址数
+type DailyActiveAddress struct {
+	Date    time.Time `json:"date" gorm:"column:date; type:date; primaryKey; comment:统计日期(UTC);"`
+	Address string    `json:"address" gorm:"column:address; type:char(42); primaryKey; comment:地址;"`
+}
+
+func (a *DailyActiveAddress) TableName() string {
+	return "daily_active_addresses"
+}
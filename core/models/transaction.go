@@ -15,6 +15,21 @@ type Transaction struct {
 	Contract    string `json:"contract" gorm:"type:char(42)" `
 	Status      uint64 `json:"status"`
 	InputData   string `json:"input_data" gorm:"type:varchar(4096)"`
+	// ContractCreation 交易为合约创建时 Contract 即新合约地址
+	ContractCreation bool `json:"contract_creation"`
+
+	BlockHash         string `json:"block_hash" gorm:"type:char(66)"`
+	TxIndex           uint   `json:"tx_index"`
+	Type              uint8  `json:"type"`
+	Nonce             uint64 `json:"nonce"`
+	Gas               uint64 `json:"gas"`
+	GasPrice          string `json:"gas_price" gorm:"type:varchar(78)"`
+	GasTipCap         string `json:"gas_tip_cap" gorm:"type:varchar(78)"`
+	GasUsed           uint64 `json:"gas_used"`
+	CumulativeGasUsed uint64 `json:"cumulative_gas_used"`
+	EffectiveGasPrice string `json:"effective_gas_price" gorm:"type:varchar(78)"`
+	// RevertReason 失败交易的原因, 成功或无法获取时为空
+	RevertReason string `json:"revert_reason,omitempty" gorm:"type:text"`
 }
 
 func (tx *Transaction) TableName() string {
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"math/big"
+
+	"gorm.io/gorm"
+)
+
+// UserOperation ERC-4337 EntryPoint 执行的用户操作
+type UserOperation struct {
+	*gorm.Model
+
+	UserOpHash        string   `json:"user_op_hash" gorm:"column:user_op_hash; type:char(66); uniqueIndex; comment:UserOperation哈希;"`
+	Sender            string   `json:"sender" gorm:"column:sender; type:char(42); index; comment:智能合约钱包地址;"`
+	Paymaster         string   `json:"paymaster" gorm:"column:paymaster; type:char(42); index; comment:代付GAS的Paymaster, 没有为零地址;"`
+	Nonce             *big.Int `json:"nonce" gorm:"column:nonce; type:numeric; serializer:json; comment:Nonce;"`
+	Success           bool     `json:"success" gorm:"column:success; comment:是否执行成功;"`
+	ActualGasCost     *big.Int `json:"actual_gas_cost" gorm:"column:actual_gas_cost; type:numeric; serializer:json; comment:实际GAS费用;"`
+	ActualGasUsed     *big.Int `json:"actual_gas_used" gorm:"column:actual_gas_used; type:numeric; serializer:json; comment:实际GAS使用量;"`
+	Factory           string   `json:"factory,omitempty" gorm:"column:factory; type:varchar(42); default:''; comment:部署钱包的工厂合约;"`
+	RevertReason      string   `json:"revert_reason,omitempty" gorm:"column:revert_reason; type:text; comment:执行失败原因(hex);"`
+	EntryPoint        string   `json:"entry_point" gorm:"column:entry_point; type:char(42); comment:EntryPoint合约地址;"`
+	EntryPointVersion string   `json:"entry_point_version" gorm:"column:entry_point_version; type:varchar(8); comment:EntryPoint版本;"`
+	Bundler           string   `json:"bundler" gorm:"column:bundler; type:char(42); comment:提交handleOps的地址;"`
+	TransactionHash   string   `json:"transaction_hash" gorm:"column:transaction_hash; type:char(66); index; comment:所属交易哈希;"`
+	BlockNumber       uint64   `json:"block_number" gorm:"column:block_number; index; comment:区块高度;"`
+	BlockHash         string   `json:"block_hash" gorm:"column:block_hash; type:char(66); comment:区块哈希;"`
+	LogIndex          uint     `json:"log_index" gorm:"column:log_index; comment:UserOperationEvent 日志索引;"`
+}
+
+func (u *UserOperation) TableName() string {
+	return "user_operations"
+}
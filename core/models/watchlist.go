@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookEventTransaction   = "transaction"
+	WebhookEventTokenTransfer = "token_transfer"
+
+	WebhookStatusPending   = "pending"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusDead      = "dead"
+)
+
+// Watchlist 关注的地址, 命中的交易和Token转移会推送到 WebhookUrl
+type Watchlist struct {
+	*gorm.Model
+
+	Name                 string `json:"name" gorm:"column:name; type:varchar(128); comment:名称;"`
+	Address              string `json:"address" gorm:"column:address; type:char(42); index; comment:关注的地址;"`
+	TokenContractAddress string `json:"token_contract_address" gorm:"column:token_contract_address; type:varchar(42); default:''; comment:只关注该Token的转移, 为空时关注所有;"`
+	WebhookUrl           string `json:"webhook_url" gorm:"column:webhook_url; type:varchar(1024); comment:推送地址;"`
+	Secret               string `json:"-" gorm:"column:secret; type:varchar(256); comment:HMAC签名密钥;"`
+	Enabled              bool   `json:"enabled" gorm:"column:enabled; default:true; comment:是否启用;"`
+}
+
+func (w *Watchlist) TableName() string {
+	return "watchlists"
+}
+
+// WebhookDelivery 待推送和已推送的 webhook 消息
+type WebhookDelivery struct {
+	*gorm.Model
+
+	WatchlistId   uint       `json:"watchlist_id" gorm:"column:watchlist_id; uniqueIndex:webhook_deliveries_unique_event; comment:关注ID;"`
+	EventType     string     `json:"event_type" gorm:"column:event_type; type:varchar(32); uniqueIndex:webhook_deliveries_unique_event; comment:事件类型;"`
+	BlockNumber   uint64     `json:"block_number" gorm:"column:block_number; comment:区块高度;"`
+	BlockHash     string     `json:"block_hash" gorm:"column:block_hash; type:char(66); uniqueIndex:webhook_deliveries_unique_event; comment:区块hash;"`
+	TxHash        string     `json:"tx_hash" gorm:"column:tx_hash; type:char(66); uniqueIndex:webhook_deliveries_unique_event; comment:交易hash;"`
+	LogIndex      uint       `json:"log_index" gorm:"column:log_index; uniqueIndex:webhook_deliveries_unique_event; comment:日志索引, 交易事件为0;"`
+	Payload       string     `json:"payload" gorm:"column:payload; type:text; comment:推送内容;"`
+	Status        string     `json:"status" gorm:"column:status; type:varchar(16); index; default:pending; comment:pending, delivered, dead;"`
+	Attempts      int        `json:"attempts" gorm:"column:attempts; default:0; comment:已尝试次数;"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"column:next_attempt_at; index; comment:下次推送时间;"`
+	LastError     string     `json:"last_error" gorm:"column:last_error; type:text; comment:最后一次失败原因;"`
+	DeliveredAt   *time.Time `json:"delivered_at" gorm:"column:delivered_at; comment:推送成功时间;"`
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeadLetter 超过最大重试次数仍然失败的推送, 可以手动重放
+type WebhookDeadLetter struct {
+	*gorm.Model
+
+	DeliveryId  uint       `json:"delivery_id" gorm:"column:delivery_id; uniqueIndex; comment:推送ID;"`
+	WatchlistId uint       `json:"watchlist_id" gorm:"column:watchlist_id; index; comment:关注ID;"`
+	WebhookUrl  string     `json:"webhook_url" gorm:"column:webhook_url; type:varchar(1024); comment:推送地址;"`
+	Payload     string     `json:"payload" gorm:"column:payload; type:text; comment:推送内容;"`
+	Attempts    int        `json:"attempts" gorm:"column:attempts; comment:已尝试次数;"`
+	LastError   string     `json:"last_error" gorm:"column:last_error; type:text; comment:最后一次失败原因;"`
+	ReplayedAt  *time.Time `json:"replayed_at" gorm:"column:replayed_at; comment:重放时间;"`
+}
+
+func (d *WebhookDeadLetter) TableName() string {
+	return "webhook_dead_letters"
+}
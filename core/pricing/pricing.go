@@ -0,0 +1,168 @@
+/*
+## Overview
+pricing 定时从价格来源读取 Token 的美元价格, 更新 tokens 表的 fiat_value 和 circulating_market_cap, 并写入 token_prices 历史.
+
+  - PriceSource 为价格来源接口, 内置 CoinGecko 兼容接口、本地文件和从 DEX 成交推导三种实现
+  - 本地文件与 CoinGecko simple/token_price 的响应格式相同, 可以直接保存接口响应用于离线测试
+  - 价格来源没有返回市值时, 按总供应量和价格估算
+  - UsdValue 按 Token 精度和价格换算数量的美元价值, 供接口展示余额和转移的美元价值
+*/
+package pricing
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Quote 一个 Token 的美元报价, MarketCapUsd 为0表示来源没有提供市值
+type Quote struct {
+	PriceUsd     float64
+	MarketCapUsd float64
+}
+
+// PriceSource 价格来源, 返回按 checksum 合约地址索引的报价, 没有报价的 Token 不返回
+type PriceSource interface {
+	Name() string
+	Prices(ctx context.Context, tokens []models.Token) (map[string]Quote, error)
+}
+
+// Updater 定时分批更新所有 ERC-20 Token 的价格
+type Updater struct {
+	Source    PriceSource
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewUpdater(source PriceSource) *Updater {
+	return &Updater{
+		Source:    source,
+		Interval:  time.Minute * 5,
+		BatchSize: 100,
+	}
+}
+
+// Start 启动时先更新一次, 之后按 Interval 定时更新, 直到 ctx 结束
+func (u *Updater) Start(ctx context.Context) {
+	ticker := time.NewTicker(u.Interval)
+	defer ticker.Stop()
+	for {
+		if n, err := u.RunOnce(ctx); err != nil {
+			log.WithField("source", u.Source.Name()).WithField("err", err).Error("price updater run failed")
+		} else {
+			log.WithField("source", u.Source.Name()).WithField("tokens", n).Info("price updater updated token prices")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce 更新一轮所有 Token 的价格, 返回更新的 Token 数量
+func (u *Updater) RunOnce(ctx context.Context) (int, error) {
+	var afterId uint
+	updated := 0
+	for {
+		tokens, err := dal.Token.ListPriceable(ctx, afterId, u.BatchSize)
+		if err != nil {
+			return updated, errors.Wrap(err, "list tokens")
+		}
+		if len(tokens) == 0 {
+			return updated, nil
+		}
+		afterId = tokens[len(tokens)-1].ID
+
+		quotes, err := u.Source.Prices(ctx, tokens)
+		if err != nil {
+			return updated, errors.Wrap(err, "get prices")
+		}
+		prices := Prices(u.Source.Name(), tokens, quotes, time.Now())
+		err = dal.WithTx(ctx, func(txCtx context.Context) error {
+			for _, p := range prices {
+				if err := dal.Token.UpdatePrice(txCtx, p.ContractAddress, FormatUsd(p.PriceUsd), FormatUsd(p.MarketCapUsd)); err != nil {
+					return err
+				}
+			}
+			return dal.Price.Inserts(txCtx, prices)
+		})
+		if err != nil {
+			return updated, errors.Wrap(err, "save prices")
+		}
+		updated += len(prices)
+	}
+}
+
+// Prices 把报价转换为历史价格记录, 缺少市值时按总供应量估算
+func Prices(source string, tokens []models.Token, quotes map[string]Quote, now time.Time) []models.TokenPrice {
+	prices := make([]models.TokenPrice, 0, len(quotes))
+	for i := range tokens {
+		quote, ok := quotes[tokens[i].ContractAddress]
+		if !ok || quote.PriceUsd <= 0 || math.IsInf(quote.PriceUsd, 0) || math.IsNaN(quote.PriceUsd) {
+			continue
+		}
+		prices = append(prices, models.TokenPrice{
+			ContractAddress: tokens[i].ContractAddress,
+			Source:          source,
+			PriceUsd:        quote.PriceUsd,
+			MarketCapUsd:    MarketCap(&tokens[i], quote),
+			FetchedAt:       now,
+		})
+	}
+	return prices
+}
+
+// MarketCap 优先使用来源提供的市值, 否则按总供应量估算, 总供应量未知时为0
+func MarketCap(token *models.Token, quote Quote) float64 {
+	if quote.MarketCapUsd > 0 {
+		return quote.MarketCapUsd
+	}
+	if token.TotalSupply == nil || token.TotalSupply.Sign() <= 0 {
+		return 0
+	}
+	supply, _ := scaleDown(token.TotalSupply, token.Decimals).Float64()
+	return supply * quote.PriceUsd
+}
+
+// FormatUsd 价格为0时返回空字符串
+func FormatUsd(v float64) string {
+	if v <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// UsdValue 按精度和单价计算数量的美元价值, 保留6位小数, 数量或价格未知时返回空字符串
+func UsdValue(amount *big.Int, decimals uint8, priceUsd string) string {
+	if amount == nil || priceUsd == "" {
+		return ""
+	}
+	price, ok := new(big.Float).SetString(priceUsd)
+	if !ok {
+		return ""
+	}
+	value := new(big.Float).Mul(scaleDown(amount, decimals), price)
+	text := value.Text('f', 6)
+	text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	if text == "-0" {
+		return "0"
+	}
+	return text
+}
+
+// scaleDown 按精度把最小单位的数量换算为 Token 数量
+func scaleDown(amount *big.Int, decimals uint8) *big.Float {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Float).SetPrec(256).Quo(new(big.Float).SetPrec(256).SetInt(amount), new(big.Float).SetInt(scale))
+}
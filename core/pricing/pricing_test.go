@@ -0,0 +1,234 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	usdc = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	weth = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	dai  = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+)
+
+func testTokens() []models.Token {
+	supply, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	return []models.Token{
+		{ContractAddress: usdc, Symbol: "USDC", Decimals: 6},
+		{ContractAddress: weth, Symbol: "WETH", Decimals: 18},
+		{ContractAddress: dai, Symbol: "DAI", Decimals: 18, TotalSupply: supply},
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+const coingeckoResponse = `{
+  "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": {"usd": 1.0001, "usd_market_cap": 32000000000},
+  "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": {"usd": 3500.12},
+  "0x0000000000000000000000000000000000000001": {"usd": 5},
+  "not-an-address": {"usd": 1}
+}`
+
+func TestCoinGeckoSource(t *testing.T) {
+	var requests []string
+	var apiKey string
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.Query().Get("contract_addresses"))
+		apiKey = r.Header.Get("x-cg-demo-api-key")
+		if r.URL.Query().Get("vs_currencies") != "usd" || r.URL.Query().Get("include_market_cap") != "true" {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(coingeckoResponse))
+	}))
+	defer node.Close()
+
+	source := NewCoinGeckoSource(node.URL+"/", "ethereum", "demo-key")
+	source.BatchSize = 2
+	quotes, err := source.Prices(context.Background(), testTokens())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[0] != "/simple/token_price/ethereum?"+strings.ToLower(usdc+","+weth) {
+		t.Errorf("requests = %v", requests)
+	}
+	if apiKey != "demo-key" {
+		t.Errorf("api key header = %q", apiKey)
+	}
+	want := map[string]Quote{
+		usdc: {PriceUsd: 1.0001, MarketCapUsd: 32000000000},
+		weth: {PriceUsd: 3500.12},
+	}
+	if len(quotes) != len(want) {
+		t.Fatalf("quotes = %v, want %v", quotes, want)
+	}
+	for address, quote := range want {
+		if quotes[address] != quote {
+			t.Errorf("quote %s = %v, want %v", address, quotes[address], quote)
+		}
+	}
+
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"status":{"error_code":429}}`, http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	if _, err := NewCoinGeckoSource(limited.URL, "ethereum", "").Prices(context.Background(), testTokens()); err == nil ||
+		!strings.Contains(err.Error(), "429") {
+		t.Errorf("rate limited error = %v", err)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(path, []byte(coingeckoResponse), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewSource(&setting.PricingConfig{Source: "file", File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotes, err := source.Prices(context.Background(), testTokens()[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quotes) != 1 || quotes[weth].PriceUsd != 3500.12 {
+		t.Errorf("quotes = %v", quotes)
+	}
+
+	if _, err := (&FileSource{Path: filepath.Join(t.TempDir(), "missing.json")}).Prices(context.Background(), testTokens()); err == nil {
+		t.Error("missing file should fail")
+	}
+}
+
+func TestDexSource(t *testing.T) {
+	wethAmount, _ := new(big.Int).SetString("2000000000000000000", 10) // 2 WETH
+	daiAmount, _ := new(big.Int).SetString("7000000000000000000000", 10)
+	trades := map[string][]*models.DexTrade{
+		weth + usdc: {
+			// 2 WETH -> 6800 USDC
+			{TokenIn: weth, TokenOut: usdc, AmountIn: wethAmount, AmountOut: big.NewInt(6800_000000), BlockNumber: 10, LogIndex: 1},
+		},
+		weth + dai: {
+			// 7000 DAI -> 2 WETH, newer than the USDC trade
+			{TokenIn: dai, TokenOut: weth, AmountIn: daiAmount, AmountOut: wethAmount, BlockNumber: 12},
+		},
+		dai + usdc: {
+			{TokenIn: dai, TokenOut: usdc, AmountIn: big.NewInt(0), AmountOut: big.NewInt(1), BlockNumber: 11},
+		},
+	}
+	source := NewDexSource([]setting.PriceAnchor{
+		{Address: strings.ToLower(usdc), Decimals: 6, PriceUsd: 1},
+		{Address: dai, Decimals: 18, PriceUsd: 0.999},
+	})
+	source.Lookup = func(_ context.Context, a, b string) (*models.DexTrade, error) {
+		if ts := trades[a+b]; len(ts) > 0 {
+			return ts[0], nil
+		}
+		return nil, nil
+	}
+
+	quotes, err := source.Prices(context.Background(), testTokens())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quotes[usdc].PriceUsd != 1 {
+		t.Errorf("anchor price = %v, want 1", quotes[usdc].PriceUsd)
+	}
+	if quotes[dai].PriceUsd != 0.999 {
+		t.Errorf("anchor price = %v, want 0.999", quotes[dai].PriceUsd)
+	}
+	// 最新的一笔是 DAI 成交: 7000 * 0.999 / 2
+	if !almostEqual(quotes[weth].PriceUsd, 3496.5) {
+		t.Errorf("weth price = %v, want 3496.5", quotes[weth].PriceUsd)
+	}
+
+	delete(trades, weth+dai)
+	quotes, _ = source.Prices(context.Background(), testTokens()[1:2])
+	if !almostEqual(quotes[weth].PriceUsd, 3400) {
+		t.Errorf("weth price from usdc trade = %v, want 3400", quotes[weth].PriceUsd)
+	}
+}
+
+func TestPrices(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]Quote{
+		usdc: {PriceUsd: 1, MarketCapUsd: 100},
+		weth: {PriceUsd: math.Inf(1)},
+		dai:  {PriceUsd: 0.5},
+	}
+	prices := Prices("test", testTokens(), quotes, now)
+	if len(prices) != 2 {
+		t.Fatalf("prices = %+v", prices)
+	}
+	if prices[0].ContractAddress != usdc || prices[0].MarketCapUsd != 100 || prices[0].Source != "test" || !prices[0].FetchedAt.Equal(now) {
+		t.Errorf("usdc price = %+v", prices[0])
+	}
+	// 总供应量 1,000,000 DAI * 0.5
+	if prices[1].ContractAddress != dai || !almostEqual(prices[1].MarketCapUsd, 500000) {
+		t.Errorf("dai price = %+v", prices[1])
+	}
+}
+
+func TestUsdValue(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		price    string
+		want     string
+	}{
+		{"1500000", 6, "1", "1.5"},
+		{"2000000000000000000", 18, "3500.12", "7000.24"},
+		{"1", 18, "3500", "0"},
+		{"123456789", 0, "0.01", "1234567.89"},
+		{"-2500000", 6, "2", "-5"},
+		{"1000000", 6, "", ""},
+		{"1000000", 6, "abc", ""},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := UsdValue(amount, tt.decimals, tt.price); got != tt.want {
+			t.Errorf("UsdValue(%s, %d, %q) = %q, want %q", tt.amount, tt.decimals, tt.price, got, tt.want)
+		}
+	}
+	if got := UsdValue(nil, 6, "1"); got != "" {
+		t.Errorf("UsdValue(nil) = %q", got)
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	tests := []struct {
+		conf setting.PricingConfig
+		name string
+		ok   bool
+	}{
+		{setting.PricingConfig{Source: "coingecko", CoinGeckoUrl: "https://api.coingecko.com/api/v3"}, "coingecko", true},
+		{setting.PricingConfig{Source: "file", File: "prices.json"}, "file", true},
+		{setting.PricingConfig{Source: "file"}, "", false},
+		{setting.PricingConfig{Source: "dex", Anchors: []setting.PriceAnchor{{Address: usdc, Decimals: 6, PriceUsd: 1}}}, "dex", true},
+		{setting.PricingConfig{Source: "dex"}, "", false},
+		{setting.PricingConfig{Source: "oracle"}, "", false},
+	}
+	for _, tt := range tests {
+		source, err := NewSource(&tt.conf)
+		if (err == nil) != tt.ok {
+			t.Errorf("NewSource(%q) err = %v", tt.conf.Source, err)
+			continue
+		}
+		if tt.ok && source.Name() != tt.name {
+			t.Errorf("NewSource(%q).Name() = %s", tt.conf.Source, source.Name())
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Setup 根据配置创建价格来源并启动定时更新, 未启用时返回 nil
+func Setup(ctx context.Context, conf *setting.PricingConfig) (*Updater, error) {
+	if conf == nil || !conf.Enable {
+		return nil, nil
+	}
+	source, err := NewSource(conf)
+	if err != nil {
+		return nil, err
+	}
+	updater := NewUpdater(source)
+	if conf.Interval > 0 {
+		updater.Interval = conf.Interval
+	}
+	go updater.Start(ctx)
+	return updater, nil
+}
+
+// NewSource 根据配置创建价格来源
+func NewSource(conf *setting.PricingConfig) (PriceSource, error) {
+	switch conf.Source {
+	case "coingecko":
+		return NewCoinGeckoSource(conf.CoinGeckoUrl, conf.CoinGeckoPlatform, conf.CoinGeckoApiKey), nil
+	case "file":
+		if conf.File == "" {
+			return nil, errors.New("pricing: file source needs Pricing.File")
+		}
+		return &FileSource{Path: conf.File}, nil
+	case "dex":
+		if len(conf.Anchors) == 0 {
+			return nil, errors.New("pricing: dex source needs Pricing.Anchors")
+		}
+		return NewDexSource(conf.Anchors), nil
+	default:
+		return nil, fmt.Errorf("pricing: unknown price source %q", conf.Source)
+	}
+}
+
+// tokenPrice CoinGecko simple/token_price 响应中一个 Token 的价格
+type tokenPrice struct {
+	Usd          float64 `json:"usd"`
+	UsdMarketCap float64 `json:"usd_market_cap"`
+}
+
+// parseQuotes 解析 simple/token_price 格式的响应, 只保留 tokens 中的 Token
+func parseQuotes(data []byte, tokens []models.Token) (map[string]Quote, error) {
+	var raw map[string]tokenPrice
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		wanted[t.ContractAddress] = true
+	}
+
+	quotes := make(map[string]Quote)
+	for address, price := range raw {
+		if !common.IsHexAddress(address) {
+			continue
+		}
+		address = common.HexToAddress(address).Hex()
+		if wanted[address] && price.Usd > 0 {
+			quotes[address] = Quote{PriceUsd: price.Usd, MarketCapUsd: price.UsdMarketCap}
+		}
+	}
+	return quotes, nil
+}
+
+// CoinGeckoSource CoinGecko 兼容的 simple/token_price 接口
+type CoinGeckoSource struct {
+	BaseUrl  string
+	Platform string
+	ApiKey   string
+	// BatchSize 每次请求查询的合约数量, 避免 URL 过长
+	BatchSize int
+	Client    *http.Client
+}
+
+func NewCoinGeckoSource(baseUrl, platform, apiKey string) *CoinGeckoSource {
+	return &CoinGeckoSource{
+		BaseUrl:   strings.TrimSuffix(baseUrl, "/"),
+		Platform:  platform,
+		ApiKey:    apiKey,
+		BatchSize: 50,
+	}
+}
+
+func (s *CoinGeckoSource) Name() string {
+	return "coingecko"
+}
+
+func (s *CoinGeckoSource) Prices(ctx context.Context, tokens []models.Token) (map[string]Quote, error) {
+	quotes := make(map[string]Quote)
+	for start := 0; start < len(tokens); start += s.BatchSize {
+		end := min(start+s.BatchSize, len(tokens))
+		batch, err := s.fetch(ctx, tokens[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for address, quote := range batch {
+			quotes[address] = quote
+		}
+	}
+	return quotes, nil
+}
+
+func (s *CoinGeckoSource) fetch(ctx context.Context, tokens []models.Token) (map[string]Quote, error) {
+	addresses := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		addresses = append(addresses, strings.ToLower(t.ContractAddress))
+	}
+	query := url.Values{}
+	query.Set("contract_addresses", strings.Join(addresses, ","))
+	query.Set("vs_currencies", "usd")
+	query.Set("include_market_cap", "true")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		s.BaseUrl+"/simple/token_price/"+url.PathEscape(s.Platform)+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.ApiKey != "" {
+		// 付费接口和免费接口使用不同的请求头
+		if strings.Contains(s.BaseUrl, "pro-api.") {
+			req.Header.Set("x-cg-pro-api-key", s.ApiKey)
+		} else {
+			req.Header.Set("x-cg-demo-api-key", s.ApiKey)
+		}
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pricing: coingecko %s: %s", resp.Status, strings.TrimSpace(string(body[:min(len(body), 256)])))
+	}
+	return parseQuotes(body, tokens)
+}
+
+// FileSource 从本地文件读取价格, 每次更新都重新读取文件
+type FileSource struct {
+	Path string
+}
+
+func (s *FileSource) Name() string {
+	return "file"
+}
+
+func (s *FileSource) Prices(_ context.Context, tokens []models.Token) (map[string]Quote, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseQuotes(data, tokens)
+}
+
+// TradeLookup 查询两个代币之间最新的一笔成交, 没有成交时返回 nil
+type TradeLookup func(ctx context.Context, tokenA, tokenB string) (*models.DexTrade, error)
+
+// DexSource 用 Token 与锚定 Token 最新一笔 DEX 成交的兑换比例推导价格
+type DexSource struct {
+	Anchors []setting.PriceAnchor
+	Lookup  TradeLookup
+}
+
+func NewDexSource(anchors []setting.PriceAnchor) *DexSource {
+	normalized := make([]setting.PriceAnchor, 0, len(anchors))
+	for _, a := range anchors {
+		a.Address = common.HexToAddress(a.Address).Hex()
+		normalized = append(normalized, a)
+	}
+	return &DexSource{Anchors: normalized, Lookup: latestTrade}
+}
+
+func latestTrade(ctx context.Context, tokenA, tokenB string) (*models.DexTrade, error) {
+	trade, err := dal.DexTrade.GetLatestBetween(ctx, tokenA, tokenB)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return trade, err
+}
+
+func (s *DexSource) Name() string {
+	return "dex"
+}
+
+// Prices 每个 Token 使用与所有锚定 Token 之间最新的一笔成交
+func (s *DexSource) Prices(ctx context.Context, tokens []models.Token) (map[string]Quote, error) {
+	quotes := make(map[string]Quote)
+	for i := range tokens {
+		token := &tokens[i]
+		var latest *models.DexTrade
+		var price float64
+		for _, anchor := range s.Anchors {
+			if anchor.Address == token.ContractAddress {
+				latest, price = nil, anchor.PriceUsd
+				break
+			}
+			trade, err := s.Lookup(ctx, token.ContractAddress, anchor.Address)
+			if err != nil {
+				return nil, err
+			}
+			if trade == nil || (latest != nil && !newerTrade(trade, latest)) {
+				continue
+			}
+			if p, ok := tradePrice(trade, token, anchor); ok {
+				latest, price = trade, p
+			}
+		}
+		if price > 0 {
+			quotes[token.ContractAddress] = Quote{PriceUsd: price}
+		}
+	}
+	return quotes, nil
+}
+
+func newerTrade(a, b *models.DexTrade) bool {
+	return a.BlockNumber > b.BlockNumber || (a.BlockNumber == b.BlockNumber && a.LogIndex > b.LogIndex)
+}
+
+// tradePrice 成交中锚定 Token 的美元价值除以 Token 数量
+func tradePrice(trade *models.DexTrade, token *models.Token, anchor setting.PriceAnchor) (float64, bool) {
+	tokenAmount, anchorAmount := trade.AmountIn, trade.AmountOut
+	if trade.TokenIn == anchor.Address {
+		tokenAmount, anchorAmount = trade.AmountOut, trade.AmountIn
+	}
+	if tokenAmount == nil || anchorAmount == nil || tokenAmount.Sign() <= 0 || anchorAmount.Sign() <= 0 {
+		return 0, false
+	}
+	value := new(big.Float).Mul(scaleDown(anchorAmount, anchor.Decimals), big.NewFloat(anchor.PriceUsd))
+	price, _ := new(big.Float).Quo(value, scaleDown(tokenAmount, token.Decimals)).Float64()
+	return price, price > 0
+}
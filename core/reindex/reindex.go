@@ -0,0 +1,207 @@
+/*
+## Overview
+reindex 校验已经入库的区块数据是否与链上一致, 并重新索引不一致的高度.
+
+校验按高度比较最后一次写入的区块哈希、交易数和日志数, 链上的日志数通过
+eth_getLogs 按区块哈希查询, 不需要拉取每笔交易的回执. 不一致的高度会被标记为
+refetch_needed, 重新索引时在一个事务中删除该高度的全部数据后再执行区块解析入库.
+
+只校验同步进度之前的高度, 尚未同步的区块交给正常的同步任务处理.
+*/
+package reindex
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+)
+
+// batchSize 每次从数据库读取的高度数量
+const batchSize = 100
+
+// Reason 不一致的原因
+type Reason string
+
+const (
+	ReasonMissing  Reason = "missing"
+	ReasonHash     Reason = "hash"
+	ReasonTxCount  Reason = "tx_count"
+	ReasonLogCount Reason = "log_count"
+)
+
+// BlockSummary 用于比较的区块摘要
+type BlockSummary struct {
+	Hash     string
+	TxCount  int
+	LogCount int
+}
+
+// Mismatch 一个高度的不一致信息, Stored 为空表示该高度没有入库
+type Mismatch struct {
+	Height uint64
+	Reason Reason
+	Stored *BlockSummary
+	Chain  BlockSummary
+}
+
+func (m Mismatch) String() string {
+	if m.Stored == nil {
+		return fmt.Sprintf("block %d: %s, chain %s", m.Height, m.Reason, m.Chain.Hash)
+	}
+	return fmt.Sprintf("block %d: %s, stored %s txs=%d logs=%d, chain %s txs=%d logs=%d", m.Height, m.Reason,
+		m.Stored.Hash, m.Stored.TxCount, m.Stored.LogCount, m.Chain.Hash, m.Chain.TxCount, m.Chain.LogCount)
+}
+
+// Compare 比较入库的区块摘要和链上的区块摘要, 一致时返回 nil
+func Compare(height uint64, stored *BlockSummary, onChain BlockSummary) *Mismatch {
+	var reason Reason
+	switch {
+	case stored == nil:
+		reason = ReasonMissing
+	case !sameHash(stored.Hash, onChain.Hash):
+		reason = ReasonHash
+	case stored.TxCount != onChain.TxCount:
+		reason = ReasonTxCount
+	case stored.LogCount != onChain.LogCount:
+		reason = ReasonLogCount
+	default:
+		return nil
+	}
+	return &Mismatch{Height: height, Reason: reason, Stored: stored, Chain: onChain}
+}
+
+func sameHash(a, b string) bool {
+	return common.HexToHash(a) == common.HexToHash(b)
+}
+
+// ChainReader 校验和重新索引需要的节点接口, *ethclient.Client 满足该接口
+type ChainReader interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// ChainSummary 从节点读取区块摘要
+func ChainSummary(ctx context.Context, client ChainReader, height uint64) (BlockSummary, error) {
+	block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return BlockSummary{}, errors.Wrapf(err, "get block %d", height)
+	}
+	hash := block.Hash()
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{BlockHash: &hash})
+	if err != nil {
+		return BlockSummary{}, errors.Wrapf(err, "get logs of block %d", height)
+	}
+	return BlockSummary{Hash: hash.Hex(), TxCount: len(block.Transactions()), LogCount: len(logs)}, nil
+}
+
+// storedSummaries 读取 [from, to] 高度区间内最后一次写入的区块摘要
+func storedSummaries(ctx context.Context, from, to uint64) (map[uint64]*BlockSummary, error) {
+	blocks, err := dal.Block.GetByHeightRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "get blocks")
+	}
+	txCounts, err := dal.Transaction.CountByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "count transactions")
+	}
+	logCounts, err := dal.Event.CountByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "count events")
+	}
+
+	summaries := make(map[uint64]*BlockSummary, len(blocks))
+	for _, block := range blocks {
+		if block.BlockHash == "" {
+			continue
+		}
+		summaries[block.BlockHeight] = &BlockSummary{
+			Hash:     block.BlockHash,
+			TxCount:  txCounts[block.BlockHash],
+			LogCount: logCounts[block.BlockHash],
+		}
+	}
+	return summaries, nil
+}
+
+// Verify 校验 [from, to] 高度区间内的区块, 超过同步进度的部分会被忽略
+func Verify(ctx context.Context, client ChainReader, from, to uint64) ([]Mismatch, error) {
+	next, err := dal.Block.GetNextHeight(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get sync height")
+	}
+	if next == 0 || from >= next {
+		return nil, nil
+	}
+	to = min(to, next-1)
+
+	var mismatches []Mismatch
+	for start := from; start <= to; start += batchSize {
+		end := min(start+batchSize-1, to)
+		stored, err := storedSummaries(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for height := start; height <= end; height++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			onChain, err := ChainSummary(ctx, client, height)
+			if err != nil {
+				return nil, err
+			}
+			if m := Compare(height, stored[height], onChain); m != nil {
+				log.Warnf("reindex: %s", m)
+				mismatches = append(mismatches, *m)
+			}
+		}
+		log.Infof("reindex: verified blocks %d to %d", start, end)
+	}
+	return mismatches, nil
+}
+
+// Flag 将不一致的高度标记为需要重新拉取
+func Flag(ctx context.Context, mismatches []Mismatch) error {
+	heights := make([]uint64, 0, len(mismatches))
+	for _, m := range mismatches {
+		if m.Stored != nil {
+			heights = append(heights, m.Height)
+		}
+	}
+	return dal.Block.MarkRefetchNeeded(ctx, heights)
+}
+
+// Heights 返回不一致的高度
+func Heights(mismatches []Mismatch) []uint64 {
+	heights := make([]uint64, 0, len(mismatches))
+	for _, m := range mismatches {
+		heights = append(heights, m.Height)
+	}
+	return heights
+}
+
+// Reindex 逐个重新索引指定的高度, 每个高度在单独的事务中替换
+func Reindex(ctx context.Context, client ChainReader, heights []uint64) error {
+	for _, height := range heights {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			return errors.Wrapf(err, "get block %d", height)
+		}
+		if err := chain.ReindexBlock(ctx, block); err != nil {
+			return errors.Wrapf(err, "reindex block %d", height)
+		}
+		log.Infof("reindex: block %d replaced with %s", height, block.Hash().Hex())
+	}
+	return nil
+}
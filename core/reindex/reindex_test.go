@@ -0,0 +1,90 @@
+package reindex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestCompare(t *testing.T) {
+	onChain := BlockSummary{Hash: "0x" + "ab" + common.Hash{}.Hex()[4:], TxCount: 2, LogCount: 3}
+	tests := []struct {
+		name   string
+		stored *BlockSummary
+		want   Reason
+	}{
+		{"match", &BlockSummary{Hash: onChain.Hash, TxCount: 2, LogCount: 3}, ""},
+		{"hash case insensitive", &BlockSummary{Hash: "0x" + "AB" + common.Hash{}.Hex()[4:], TxCount: 2, LogCount: 3}, ""},
+		{"missing", nil, ReasonMissing},
+		{"hash", &BlockSummary{Hash: common.Hash{}.Hex(), TxCount: 2, LogCount: 3}, ReasonHash},
+		{"tx count", &BlockSummary{Hash: onChain.Hash, TxCount: 1, LogCount: 3}, ReasonTxCount},
+		{"log count", &BlockSummary{Hash: onChain.Hash, TxCount: 2, LogCount: 0}, ReasonLogCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Compare(7, tt.stored, onChain)
+			if tt.want == "" {
+				if m != nil {
+					t.Fatalf("Compare() = %s, want match", m)
+				}
+				return
+			}
+			if m == nil || m.Reason != tt.want || m.Height != 7 {
+				t.Fatalf("Compare() = %v, want %s", m, tt.want)
+			}
+		})
+	}
+}
+
+type fakeChain struct {
+	blocks map[uint64]*types.Block
+	logs   map[common.Hash][]types.Log
+}
+
+func (f *fakeChain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block, ok := f.blocks[number.Uint64()]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return block, nil
+}
+
+func (f *fakeChain) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return f.logs[*q.BlockHash], nil
+}
+
+func TestChainSummary(t *testing.T) {
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: 21000}),
+		types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000}),
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}).WithBody(txs, nil)
+	client := &fakeChain{
+		blocks: map[uint64]*types.Block{10: block},
+		logs:   map[common.Hash][]types.Log{block.Hash(): make([]types.Log, 3)},
+	}
+
+	got, err := ChainSummary(context.Background(), client, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BlockSummary{Hash: block.Hash().Hex(), TxCount: 2, LogCount: 3}
+	if got != want {
+		t.Fatalf("ChainSummary() = %+v, want %+v", got, want)
+	}
+
+	if _, err := ChainSummary(context.Background(), client, 11); err == nil {
+		t.Fatal("ChainSummary() of unknown block should fail")
+	}
+}
+
+func TestHeights(t *testing.T) {
+	got := Heights([]Mismatch{{Height: 3}, {Height: 5}})
+	if len(got) != 2 || got[0] != 3 || got[1] != 5 {
+		t.Fatalf("Heights() = %v", got)
+	}
+}
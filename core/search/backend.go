@@ -0,0 +1,40 @@
+package search
+
+import (
+	"context"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Backend 检索读取数据的接口, 查询不到时返回 nil
+type Backend interface {
+	SearchTokens(ctx context.Context, tsQuery string, limit int) ([]dal.TokenMatch, error)
+	Token(ctx context.Context, contractAddress string) (*models.Token, error)
+	TransactionByHash(ctx context.Context, hash string) (*models.Transaction, error)
+	BlockByHash(ctx context.Context, hash string) (*models.Block, error)
+	BlockByHeight(ctx context.Context, height uint64) (*models.Block, error)
+}
+
+// dbBackend 通过 dal 读取已经入库的数据
+type dbBackend struct{}
+
+func (dbBackend) SearchTokens(ctx context.Context, tsQuery string, limit int) ([]dal.TokenMatch, error) {
+	return dal.Token.Search(ctx, tsQuery, limit)
+}
+
+func (dbBackend) Token(ctx context.Context, contractAddress string) (*models.Token, error) {
+	return dal.IgnoreNotFound(dal.Token.GetByContractAddress(ctx, contractAddress))
+}
+
+func (dbBackend) TransactionByHash(ctx context.Context, hash string) (*models.Transaction, error) {
+	return dal.IgnoreNotFound(dal.Transaction.GetByHash(ctx, hash))
+}
+
+func (dbBackend) BlockByHash(ctx context.Context, hash string) (*models.Block, error) {
+	return dal.IgnoreNotFound(dal.Block.GetByHash(ctx, hash))
+}
+
+func (dbBackend) BlockByHeight(ctx context.Context, height uint64) (*models.Block, error) {
+	return dal.IgnoreNotFound(dal.Block.GetByHeight(ctx, height))
+}
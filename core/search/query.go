@@ -0,0 +1,83 @@
+package search
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Kind 查询字符串的类型
+type Kind int
+
+const (
+	KindEmpty Kind = iota
+	KindText
+	KindAddress
+	KindHash
+	KindHeight
+)
+
+// maxWords 全文检索最多使用的词数, 避免过长的查询拖慢数据库
+const maxWords = 8
+
+var (
+	addressPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]{40}$`)
+	hashPattern    = regexp.MustCompile(`^0[xX][0-9a-fA-F]{64}$`)
+	wordPattern    = regexp.MustCompile(`[\p{L}\p{N}]+`)
+)
+
+// Classify 判断查询字符串的类型
+func Classify(query string) Kind {
+	query = strings.TrimSpace(query)
+	switch {
+	case query == "":
+		return KindEmpty
+	case addressPattern.MatchString(query):
+		return KindAddress
+	case hashPattern.MatchString(query):
+		return KindHash
+	}
+	if _, err := strconv.ParseUint(query, 10, 64); err == nil {
+		return KindHeight
+	}
+	return KindText
+}
+
+// TSQuery 将查询字符串转换为 to_tsquery 语法, 只保留字母和数字组成的词, 每个词按前缀匹配
+func TSQuery(query string) string {
+	words := wordPattern.FindAllString(strings.ToLower(query), maxWords)
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// Score Token 排序得分, 文本匹配度按持有人数的对数放大, 已验证的 Token 加倍, symbol 完全匹配额外加分
+func Score(rank float64, holders int32, verified, exactSymbol bool) float64 {
+	if holders < 0 {
+		holders = 0
+	}
+	score := rank * (1 + math.Log1p(float64(holders)))
+	if verified {
+		score *= 2
+	}
+	if exactSymbol {
+		score++
+	}
+	return score
+}
+
+func tokenResult(t *models.Token, rank float64, exactSymbol bool) Result {
+	return Result{
+		Type:        TypeToken,
+		Address:     t.ContractAddress,
+		Name:        t.Name,
+		Symbol:      t.Symbol,
+		HolderCount: t.HolderCount,
+		Verified:    t.IsVerifiedViaAdminPanel,
+		Score:       Score(rank, t.HolderCount, t.IsVerifiedViaAdminPanel, exactSymbol),
+	}
+}
@@ -0,0 +1,58 @@
+package search
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/traitmeta/metago/core/explorer"
+)
+
+// Routes 检索接口, 注册到浏览器 REST 服务上
+func (s *Searcher) Routes() []explorer.Route {
+	queryParam := explorer.Param{Name: "q", In: "query", Type: "string", Description: "token name or symbol, address, transaction hash, block hash or height", Required: true}
+
+	return []explorer.Route{
+		{
+			Method: http.MethodGet, Path: "/search", Name: "search", Tag: "search",
+			Summary: "Search tokens, addresses, transactions and blocks",
+			Params: []explorer.Param{queryParam,
+				{Name: "limit", In: "query", Type: "integer", Description: "max results, 1 to 100, defaults to 20"}},
+			Result: []Result{}, Handle: s.handleSearch,
+		},
+		{
+			Method: http.MethodGet, Path: "/search/suggest", Name: "searchSuggestions", Tag: "search",
+			Summary: "Type-ahead suggestions for a partial query",
+			Params: []explorer.Param{queryParam,
+				{Name: "limit", In: "query", Type: "integer", Description: "max suggestions, 1 to 10, defaults to 5"}},
+			Result: []Suggestion{}, Handle: s.handleSuggest,
+		},
+	}
+}
+
+func queryArgs(r *explorer.Request) (string, int, error) {
+	q := strings.TrimSpace(r.Query("q"))
+	if q == "" {
+		return "", 0, explorer.InvalidParam("q")
+	}
+	limit, err := r.IntQuery("limit", 0)
+	if err != nil {
+		return "", 0, err
+	}
+	return q, limit, nil
+}
+
+func (s *Searcher) handleSearch(r *explorer.Request) (interface{}, error) {
+	q, limit, err := queryArgs(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Search(r.Context(), q, limit)
+}
+
+func (s *Searcher) handleSuggest(r *explorer.Request) (interface{}, error) {
+	q, limit, err := queryArgs(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Suggest(r.Context(), q, limit)
+}
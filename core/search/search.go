@@ -0,0 +1,199 @@
+/*
+## Overview
+search 用一个查询字符串同时检索 Token、地址、交易哈希和区块高度.
+
+  - 0x 开头的 40 位十六进制按地址处理, 地址是 Token 合约时同时返回 Token
+  - 0x 开头的 64 位十六进制按交易哈希和区块哈希查找
+  - 纯数字按区块高度查找, 同时作为文本检索 Token
+  - 其余按全文检索匹配 Token 的 symbol 和 name, 按匹配度、持有人数和是否已验证综合排序
+  - Suggest 返回输入提示, 每个词都按前缀匹配, 适合边输入边查询
+*/
+package search
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 10
+
+	// candidateFactor 数据库按文本匹配度取候选, 在内存中综合排序后截断
+	candidateFactor = 5
+	minCandidates   = 50
+)
+
+// 结果类型
+const (
+	TypeToken       = "token"
+	TypeAddress     = "address"
+	TypeTransaction = "transaction"
+	TypeBlock       = "block"
+)
+
+// Result 一条检索结果, 按 Type 填充对应字段
+type Result struct {
+	Type        string  `json:"type"`
+	Address     string  `json:"address,omitempty"`
+	Hash        string  `json:"hash,omitempty"`
+	Height      uint64  `json:"height,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	Symbol      string  `json:"symbol,omitempty"`
+	HolderCount int32   `json:"holder_count,omitempty"`
+	Verified    bool    `json:"verified,omitempty"`
+	Score       float64 `json:"score"`
+}
+
+// Suggestion 输入提示, Label 用于展示, Value 用于跳转
+type Suggestion struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Suggestion 转换为输入提示
+func (r Result) Suggestion() Suggestion {
+	switch r.Type {
+	case TypeToken:
+		label := r.Name
+		if r.Symbol != "" {
+			label += " (" + r.Symbol + ")"
+		}
+		return Suggestion{Type: r.Type, Label: strings.TrimSpace(label), Value: r.Address}
+	case TypeAddress:
+		return Suggestion{Type: r.Type, Label: r.Address, Value: r.Address}
+	case TypeTransaction:
+		return Suggestion{Type: r.Type, Label: r.Hash, Value: r.Hash}
+	default:
+		height := strconv.FormatUint(r.Height, 10)
+		return Suggestion{Type: r.Type, Label: "Block #" + height, Value: height}
+	}
+}
+
+// Searcher 检索服务
+type Searcher struct {
+	backend Backend
+}
+
+// NewSearcher 返回读取数据库的检索服务
+func NewSearcher() *Searcher {
+	return &Searcher{backend: dbBackend{}}
+}
+
+// Search 检索 query, limit 超出范围时使用默认值
+func (s *Searcher) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	return s.search(ctx, query, clampLimit(limit, defaultLimit, maxLimit))
+}
+
+// Suggest 返回 query 的输入提示
+func (s *Searcher) Suggest(ctx context.Context, query string, limit int) ([]Suggestion, error) {
+	results, err := s.search(ctx, query, clampLimit(limit, defaultSuggestLimit, maxSuggestLimit))
+	if err != nil {
+		return nil, err
+	}
+	suggestions := make([]Suggestion, 0, len(results))
+	for _, r := range results {
+		suggestions = append(suggestions, r.Suggestion())
+	}
+	return suggestions, nil
+}
+
+func clampLimit(limit, def, max int) int {
+	if limit <= 0 || limit > max {
+		return def
+	}
+	return limit
+}
+
+func (s *Searcher) search(ctx context.Context, query string, limit int) ([]Result, error) {
+	query = strings.TrimSpace(query)
+	results := []Result{}
+
+	switch Classify(query) {
+	case KindAddress:
+		address := common.HexToAddress(query).Hex()
+		token, err := s.backend.Token(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			results = append(results, tokenResult(token, 1, true))
+		}
+		return append(results, Result{Type: TypeAddress, Address: address}), nil
+	case KindHash:
+		hash := strings.ToLower(query)
+		trx, err := s.backend.TransactionByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if trx != nil {
+			results = append(results, Result{Type: TypeTransaction, Hash: trx.TxHash, Height: trx.BlockNumber})
+		}
+		block, err := s.backend.BlockByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			results = append(results, Result{Type: TypeBlock, Hash: block.BlockHash, Height: block.BlockHeight})
+		}
+		return results, nil
+	case KindHeight:
+		height, _ := strconv.ParseUint(query, 10, 64)
+		block, err := s.backend.BlockByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			results = append(results, Result{Type: TypeBlock, Hash: block.BlockHash, Height: block.BlockHeight})
+		}
+	case KindEmpty:
+		return results, nil
+	}
+
+	tokens, err := s.searchTokens(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, tokens...)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchTokens 全文检索 Token 后按 Score 重新排序
+func (s *Searcher) searchTokens(ctx context.Context, query string, limit int) ([]Result, error) {
+	tsQuery := TSQuery(query)
+	if tsQuery == "" {
+		return nil, nil
+	}
+	candidates := limit * candidateFactor
+	if candidates < minCandidates {
+		candidates = minCandidates
+	}
+	matches, err := s.backend.SearchTokens(ctx, tsQuery, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(matches))
+	for i := range matches {
+		exact := strings.EqualFold(matches[i].Symbol, query)
+		results = append(results, tokenResult(&matches[i].Token, matches[i].Rank, exact))
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
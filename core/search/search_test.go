@@ -0,0 +1,303 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/explorer"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	usdcAddress = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	txHash      = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+)
+
+type fakeBackend struct {
+	tokens   []dal.TokenMatch
+	tsQuery  string
+	trx      *models.Transaction
+	block    *models.Block
+	heights  map[uint64]*models.Block
+	tokenErr error
+}
+
+func (f *fakeBackend) SearchTokens(_ context.Context, tsQuery string, limit int) ([]dal.TokenMatch, error) {
+	f.tsQuery = tsQuery
+	if f.tokenErr != nil {
+		return nil, f.tokenErr
+	}
+	if len(f.tokens) > limit {
+		return f.tokens[:limit], nil
+	}
+	return f.tokens, nil
+}
+
+func (f *fakeBackend) Token(_ context.Context, contractAddress string) (*models.Token, error) {
+	for i := range f.tokens {
+		if f.tokens[i].ContractAddress == contractAddress {
+			return &f.tokens[i].Token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeBackend) TransactionByHash(_ context.Context, hash string) (*models.Transaction, error) {
+	if f.trx != nil && f.trx.TxHash == hash {
+		return f.trx, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeBackend) BlockByHash(_ context.Context, hash string) (*models.Block, error) {
+	if f.block != nil && f.block.BlockHash == hash {
+		return f.block, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeBackend) BlockByHeight(_ context.Context, height uint64) (*models.Block, error) {
+	return f.heights[height], nil
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		tokens: []dal.TokenMatch{
+			{Token: models.Token{ContractAddress: "0x0000000000000000000000000000000000000001", Name: "USDC Fake", Symbol: "USDCF", HolderCount: 3}, Rank: 0.1},
+			{Token: models.Token{ContractAddress: usdcAddress, Name: "USD Coin", Symbol: "USDC", HolderCount: 1000000, IsVerifiedViaAdminPanel: true}, Rank: 0.06},
+			{Token: models.Token{ContractAddress: "0x0000000000000000000000000000000000000002", Name: "Bridged USDC", Symbol: "USDC.e", HolderCount: 5000}, Rank: 0.06},
+		},
+		trx:     &models.Transaction{TxHash: txHash, BlockNumber: 100},
+		block:   &models.Block{BlockHash: "0x" + txHash[4:] + "00", BlockHeight: 100},
+		heights: map[uint64]*models.Block{100: {BlockHash: "0xblock100", BlockHeight: 100}},
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Kind
+	}{
+		{"", KindEmpty},
+		{"   ", KindEmpty},
+		{usdcAddress, KindAddress},
+		{"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", KindAddress},
+		{"a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", KindText},
+		{txHash, KindHash},
+		{"0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b2206", KindText},
+		{"17000000", KindHeight},
+		{" 42 ", KindHeight},
+		{"-1", KindText},
+		{"usdc", KindText},
+		{"0x1234", KindText},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.query); got != tt.want {
+			t.Errorf("Classify(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"USDC", "usdc:*"},
+		{"usd coin", "usd:* & coin:*"},
+		{"  Wrapped   Ether ", "wrapped:* & ether:*"},
+		{"USDC.e", "usdc:* & e:*"},
+		{"a & b | !c:*", "a:* & b:* & c:*"},
+		{"'); drop table tokens; --", "drop:* & table:* & tokens:*"},
+		{"!!!", ""},
+		{"1 2 3 4 5 6 7 8 9 10", "1:* & 2:* & 3:* & 4:* & 5:* & 6:* & 7:* & 8:*"},
+	}
+	for _, tt := range tests {
+		if got := TSQuery(tt.query); got != tt.want {
+			t.Errorf("TSQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	if got := Score(0.1, 0, false, false); got != 0.1 {
+		t.Errorf("Score without holders = %v, want 0.1", got)
+	}
+	if Score(0.1, -5, false, false) != Score(0.1, 0, false, false) {
+		t.Error("negative holder count should count as zero")
+	}
+	if Score(0.1, 1000, false, false) <= Score(0.1, 10, false, false) {
+		t.Error("more holders should rank higher")
+	}
+	if Score(0.1, 10, true, false) != 2*Score(0.1, 10, false, false) {
+		t.Error("verified tokens should double the score")
+	}
+	if Score(0.01, 0, false, true) <= Score(0.1, 10, false, false) {
+		t.Error("exact symbol match should outrank a partial match")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		want    []string // type:address|hash|height
+		tsQuery string
+	}{
+		{
+			name:    "tokens ranked by score",
+			query:   "usdc",
+			want:    []string{"token:" + usdcAddress, "token:0x0000000000000000000000000000000000000002", "token:0x0000000000000000000000000000000000000001"},
+			tsQuery: "usdc:*",
+		},
+		{
+			name:  "limit",
+			query: "usdc",
+			limit: 1,
+			want:  []string{"token:" + usdcAddress},
+		},
+		{
+			name:  "token contract address",
+			query: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			want:  []string{"token:" + usdcAddress, "address:" + usdcAddress},
+		},
+		{
+			name:  "plain address",
+			query: "0xd8da6bf26964af9d7eed9e03e53415d37aa96045",
+			want:  []string{"address:0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"},
+		},
+		{
+			name:  "transaction hash",
+			query: "0x5C504ED432CB51138BCF09AA5E8A410DD4A1E204EF84BFED1BE16DFBA1B22060",
+			want:  []string{"transaction:" + txHash},
+		},
+		{
+			name:  "unknown hash",
+			query: "0x" + txHash[4:] + "11",
+			want:  []string{},
+		},
+		{
+			name:    "block height also searches tokens",
+			query:   "100",
+			want:    []string{"block:100", "token:" + usdcAddress, "token:0x0000000000000000000000000000000000000002", "token:0x0000000000000000000000000000000000000001"},
+			tsQuery: "100:*",
+		},
+		{
+			name:  "empty",
+			query: " ",
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend()
+			s := &Searcher{backend: backend}
+			results, err := s.Search(context.Background(), tt.query, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(results))
+			for _, r := range results {
+				got = append(got, resultKey(r))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("results = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("results = %v, want %v", got, tt.want)
+				}
+			}
+			if tt.tsQuery != "" && backend.tsQuery != tt.tsQuery {
+				t.Errorf("tsquery = %q, want %q", backend.tsQuery, tt.tsQuery)
+			}
+		})
+	}
+}
+
+func resultKey(r Result) string {
+	switch r.Type {
+	case TypeToken, TypeAddress:
+		return r.Type + ":" + r.Address
+	case TypeTransaction:
+		return r.Type + ":" + r.Hash
+	default:
+		return r.Type + ":" + Result{Height: r.Height}.Suggestion().Value
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	s := &Searcher{backend: newFakeBackend()}
+	suggestions, err := s.Suggest(context.Background(), "100", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Suggestion{
+		{Type: TypeBlock, Label: "Block #100", Value: "100"},
+		{Type: TypeToken, Label: "USD Coin (USDC)", Value: usdcAddress},
+		{Type: TypeToken, Label: "Bridged USDC (USDC.e)", Value: "0x0000000000000000000000000000000000000002"},
+		{Type: TypeToken, Label: "USDC Fake (USDCF)", Value: "0x0000000000000000000000000000000000000001"},
+	}
+	if len(suggestions) != len(want) {
+		t.Fatalf("suggestions = %+v, want %+v", suggestions, want)
+	}
+	for i := range want {
+		if suggestions[i] != want[i] {
+			t.Errorf("suggestion %d = %+v, want %+v", i, suggestions[i], want[i])
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	backend := newFakeBackend()
+	srv := explorer.NewServer(explorer.DefaultPrefix)
+	srv.Register((&Searcher{backend: backend}).Routes()...)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		count  int
+	}{
+		{name: "search", path: "/search?q=usdc", status: http.StatusOK, count: 3},
+		{name: "search limit", path: "/search?q=usdc&limit=2", status: http.StatusOK, count: 2},
+		{name: "suggest", path: "/search/suggest?q=usd", status: http.StatusOK, count: 3},
+		{name: "missing query", path: "/search", status: http.StatusBadRequest},
+		{name: "bad limit", path: "/search?q=usdc&limit=x", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, explorer.DefaultPrefix+tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Data []json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Data) != tt.count {
+				t.Errorf("got %d items, want %d", len(resp.Data), tt.count)
+			}
+		})
+	}
+
+	backend.tokenErr = errors.New("db down")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, explorer.DefaultPrefix+"/search?q=usdc", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("backend error status = %d, want 500", w.Code)
+	}
+}
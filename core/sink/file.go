@@ -0,0 +1,52 @@
+package sink
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/core/chain"
+)
+
+// FileSink 以 newline-delimited JSON 的格式追加写入文件, 每个区块一行
+type FileSink struct {
+	name string
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(name, path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open sink file %s", path)
+	}
+	return &FileSink{name: name, file: file}, nil
+}
+
+func (s *FileSink) Name() string {
+	return s.name
+}
+
+func (s *FileSink) Write(ctx context.Context, data *chain.BlockData) error {
+	line, err := NewMessage(data).Marshal()
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	// 落盘之后才允许推进游标
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
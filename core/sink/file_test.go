@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/models"
+)
+
+func TestFileSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocks.ndjson")
+	s, err := NewFileSink("file", path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+
+	for _, height := range []uint64{1, 2} {
+		data := &chain.BlockData{
+			Block:        models.Block{BlockHeight: height, BlockHash: "0x01"},
+			Transactions: []models.Transaction{{BlockNumber: height, TxHash: "0xaa"}},
+		}
+		if err := s.Write(context.Background(), data); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var heights []uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		if len(msg.Data.Transactions) != 1 || msg.Data.Transactions[0].TxHash != "0xaa" {
+			t.Errorf("unexpected message %+v", msg)
+		}
+		heights = append(heights, msg.BlockNumber)
+	}
+	if len(heights) != 2 || heights[0] != 1 || heights[1] != 2 {
+		t.Errorf("heights = %v, want [1 2]", heights)
+	}
+}
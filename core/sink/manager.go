@@ -0,0 +1,192 @@
+package sink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// HookName 在 chain 中注册的提交钩子名称
+const HookName = "sink"
+
+// CursorStore 保存每个 sink 已经投递到的区块高度
+type CursorStore interface {
+	Get(ctx context.Context, name string) (*models.SyncCursor, error)
+	Upsert(ctx context.Context, name string, blockHeight uint64, blockHash string) error
+}
+
+// Loader 读取 [from, to] 区间内已经提交的区块数据
+type Loader func(ctx context.Context, from, to uint64) ([]*chain.BlockData, error)
+
+// TipFunc 返回已经提交的最大区块高度
+type TipFunc func(ctx context.Context) (uint64, error)
+
+// Manager 在区块提交之后把数据投递给所有 sink.
+// 每个 sink 独立维护游标, 先写 sink 再推进游标, 保证至少投递一次.
+type Manager struct {
+	BatchSize     uint64
+	PollInterval  time.Duration
+	RetryInterval time.Duration
+	// StartHeight 新 sink 没有游标时从该高度开始投递, 为0时从当前最新区块开始
+	StartHeight uint64
+
+	sinks   []Sink
+	cursors CursorStore
+	load    Loader
+	tip     TipFunc
+
+	mu     sync.Mutex
+	notify map[string]chan struct{}
+}
+
+func NewManager(sinks ...Sink) *Manager {
+	return &Manager{
+		BatchSize:     50,
+		PollInterval:  5 * time.Second,
+		RetryInterval: 5 * time.Second,
+		sinks:         sinks,
+		cursors:       dal.Cursor,
+		load:          chain.LoadBlockData,
+		tip:           dal.Block.GetMaxHeight,
+		notify:        map[string]chan struct{}{},
+	}
+}
+
+func CursorName(s Sink) string {
+	return "sink:" + s.Name()
+}
+
+// Start 注册提交钩子并为每个 sink 启动投递协程, ctx 结束后关闭所有 sink
+func (m *Manager) Start(ctx context.Context) {
+	chain.RegisterCommitHook(HookName, func(ctx context.Context, data *chain.BlockData) error {
+		m.Notify()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for _, s := range m.sinks {
+		wg.Add(1)
+		go func(s Sink) {
+			defer wg.Done()
+			m.run(ctx, s)
+		}(s)
+	}
+
+	go func() {
+		wg.Wait()
+		for _, s := range m.sinks {
+			if err := s.Close(); err != nil {
+				log.WithField("sink", s.Name()).WithField("err", err).Error("close sink failed")
+			}
+		}
+	}()
+}
+
+// Notify 唤醒所有 sink 的投递协程
+func (m *Manager) Notify() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.notify {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (m *Manager) run(ctx context.Context, s Sink) {
+	ch := make(chan struct{}, 1)
+	m.mu.Lock()
+	m.notify[s.Name()] = ch
+	m.mu.Unlock()
+
+	ticker := time.NewTicker(m.PollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			done, err := m.Flush(ctx, s)
+			if err != nil {
+				log.WithField("sink", s.Name()).WithField("err", err).Error("sink delivery failed")
+				select {
+				case <-time.After(m.RetryInterval):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			if done {
+				break
+			}
+		}
+
+		select {
+		case <-ch:
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Flush 投递游标之后的一批区块, 返回是否已经追上最新区块
+func (m *Manager) Flush(ctx context.Context, s Sink) (bool, error) {
+	name := CursorName(s)
+	cursor, err := m.cursors.Get(ctx, name)
+	if err != nil {
+		return false, errors.Wrap(err, "get sink cursor")
+	}
+
+	tip, err := m.tip(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "get tip")
+	}
+
+	from := cursor.BlockHeight + 1
+	if cursor.BlockHash == "" {
+		from = m.StartHeight
+		if from == 0 {
+			from = tip
+		}
+	}
+	if from > tip {
+		return true, nil
+	}
+
+	to := from + m.BatchSize - 1
+	if to > tip {
+		to = tip
+	}
+
+	blocks, err := m.load(ctx, from, to)
+	if err != nil {
+		return false, errors.Wrap(err, "load blocks")
+	}
+	for _, data := range blocks {
+		if err := s.Write(ctx, data); err != nil {
+			return false, errors.Wrapf(err, "write block %d", data.Block.BlockHeight)
+		}
+		if err := m.cursors.Upsert(ctx, name, data.Block.BlockHeight, data.Block.BlockHash); err != nil {
+			return false, errors.Wrap(err, "update sink cursor")
+		}
+	}
+	if len(blocks) == 0 {
+		// 区间内没有区块也需要推进游标, 避免反复读取空区间.
+		// 空哈希表示游标尚未初始化, 下一次会从 StartHeight 重新开始, 所以使用占位哈希
+		hash := cursor.BlockHash
+		if hash == "" {
+			hash = dal.NoBlockHash
+		}
+		if err := m.cursors.Upsert(ctx, name, to, hash); err != nil {
+			return false, errors.Wrap(err, "update sink cursor")
+		}
+	}
+
+	return to == tip, nil
+}
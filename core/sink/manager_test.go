@@ -0,0 +1,127 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/models"
+)
+
+type memoryCursors map[string]models.SyncCursor
+
+func (c memoryCursors) Get(ctx context.Context, name string) (*models.SyncCursor, error) {
+	cursor := c[name]
+	cursor.Name = name
+	return &cursor, nil
+}
+
+func (c memoryCursors) Upsert(ctx context.Context, name string, blockHeight uint64, blockHash string) error {
+	c[name] = models.SyncCursor{Name: name, BlockHeight: blockHeight, BlockHash: blockHash}
+	return nil
+}
+
+func newTestManager(tip *uint64, s Sink) (*Manager, memoryCursors) {
+	cursors := memoryCursors{}
+	m := NewManager(s)
+	m.BatchSize = 2
+	m.StartHeight = 10
+	m.cursors = cursors
+	m.tip = func(ctx context.Context) (uint64, error) { return *tip, nil }
+	m.load = func(ctx context.Context, from, to uint64) ([]*chain.BlockData, error) {
+		var blocks []*chain.BlockData
+		for h := from; h <= to; h++ {
+			blocks = append(blocks, &chain.BlockData{Block: models.Block{BlockHeight: h, BlockHash: "0x" + string(rune('a'+h-10))}})
+		}
+		return blocks, nil
+	}
+	return m, cursors
+}
+
+func TestManager_Flush(t *testing.T) {
+	ctx := context.Background()
+	tip := uint64(14)
+	s := NewMemorySink("memory")
+	m, cursors := newTestManager(&tip, s)
+
+	for i := 0; i < 3; i++ {
+		if _, err := m.Flush(ctx, s); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+	}
+	if got := len(s.Blocks()); got != 5 {
+		t.Fatalf("delivered %d blocks, want 5", got)
+	}
+	if cursor := cursors[CursorName(s)]; cursor.BlockHeight != 14 || cursor.BlockHash != "0xe" {
+		t.Errorf("cursor = %+v, want height 14", cursor)
+	}
+
+	done, err := m.Flush(ctx, s)
+	if err != nil || !done {
+		t.Errorf("Flush() at tip = %v, %v, want done", done, err)
+	}
+}
+
+func TestManager_FlushRetriesFailedBlock(t *testing.T) {
+	ctx := context.Background()
+	tip := uint64(11)
+	s := NewMemorySink("memory")
+	m, cursors := newTestManager(&tip, s)
+
+	s.FailNext = 1
+	s.Err = errors.New("downstream unavailable")
+	if _, err := m.Flush(ctx, s); err == nil {
+		t.Fatal("Flush() expected error")
+	}
+	if _, ok := cursors[CursorName(s)]; ok {
+		t.Fatal("cursor advanced after failed write")
+	}
+
+	done, err := m.Flush(ctx, s)
+	if err != nil || !done {
+		t.Fatalf("Flush() = %v, %v", done, err)
+	}
+	blocks := s.Blocks()
+	if len(blocks) != 2 || blocks[0].Block.BlockHeight != 10 || blocks[1].Block.BlockHeight != 11 {
+		t.Errorf("delivered %+v, want blocks 10 and 11", blocks)
+	}
+}
+
+func TestManager_FlushEmptyLeadingRange(t *testing.T) {
+	ctx := context.Background()
+	tip := uint64(14)
+	s := NewMemorySink("memory")
+	m, cursors := newTestManager(&tip, s)
+	load := m.load
+	// 10-11 没有已提交的区块
+	m.load = func(ctx context.Context, from, to uint64) ([]*chain.BlockData, error) {
+		if from < 12 {
+			from = 12
+		}
+		if from > to {
+			return nil, nil
+		}
+		return load(ctx, from, to)
+	}
+
+	if _, err := m.Flush(ctx, s); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if cursor := cursors[CursorName(s)]; cursor.BlockHeight != 11 || cursor.BlockHash == "" {
+		t.Fatalf("cursor = %+v, want height 11 with a placeholder hash", cursor)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.Flush(ctx, s); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+	}
+	blocks := s.Blocks()
+	if len(blocks) != 3 || blocks[0].Block.BlockHeight != 12 {
+		t.Fatalf("delivered %d blocks, want 12-14", len(blocks))
+	}
+	if cursor := cursors[CursorName(s)]; cursor.BlockHeight != 14 || cursor.BlockHash != "0xe" {
+		t.Errorf("cursor = %+v, want height 14", cursor)
+	}
+}
@@ -0,0 +1,48 @@
+package sink
+
+import (
+	"context"
+	"sync"
+
+	"github.com/traitmeta/metago/core/chain"
+)
+
+// MemorySink 把区块数据保存在内存中, 用于测试
+type MemorySink struct {
+	name string
+	mu   sync.Mutex
+	// FailNext 大于0时接下来的 Write 返回 Err, 用于模拟下游故障
+	FailNext int
+	Err      error
+	blocks   []*chain.BlockData
+}
+
+func NewMemorySink(name string) *MemorySink {
+	return &MemorySink{name: name}
+}
+
+func (s *MemorySink) Name() string {
+	return s.name
+}
+
+func (s *MemorySink) Write(ctx context.Context, data *chain.BlockData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.FailNext > 0 {
+		s.FailNext--
+		return s.Err
+	}
+	s.blocks = append(s.blocks, data)
+	return nil
+}
+
+func (s *MemorySink) Close() error {
+	return nil
+}
+
+// Blocks 已经写入的区块数据
+func (s *MemorySink) Blocks() []*chain.BlockData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*chain.BlockData(nil), s.blocks...)
+}
@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/traitmeta/metago/core/chain"
+)
+
+// RedisSink 将每个区块写入一条 Redis Stream 消息
+type RedisSink struct {
+	name   string
+	stream string
+	maxLen int64
+	rds    *redis.Client
+}
+
+// NewRedisSink maxLen 大于0时按近似长度裁剪 stream
+func NewRedisSink(name string, rds *redis.Client, stream string, maxLen int64) *RedisSink {
+	return &RedisSink{
+		name:   name,
+		stream: stream,
+		maxLen: maxLen,
+		rds:    rds,
+	}
+}
+
+func (s *RedisSink) Name() string {
+	return s.name
+}
+
+func (s *RedisSink) Write(ctx context.Context, data *chain.BlockData) error {
+	body, err := NewMessage(data).Marshal()
+	if err != nil {
+		return err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]interface{}{
+			"block_number": data.Block.BlockHeight,
+			"block_hash":   data.Block.BlockHash,
+			"data":         body,
+		},
+	}
+	if s.maxLen > 0 {
+		args.MaxLen = s.maxLen
+		args.Approx = true
+	}
+	return s.rds.XAdd(ctx, args).Err()
+}
+
+func (s *RedisSink) Close() error {
+	return nil
+}
@@ -0,0 +1,44 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+// Setup 根据配置创建 sink 并启动投递, 没有配置任何 sink 时返回 nil
+func Setup(ctx context.Context, conf *setting.SinkConfig) (*Manager, error) {
+	if conf == nil {
+		return nil, nil
+	}
+
+	var sinks []Sink
+	if conf.File != "" {
+		fileSink, err := NewFileSink("file", conf.File)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	if conf.RedisAddr != "" {
+		rds := redis.NewClient(&redis.Options{
+			Addr:     conf.RedisAddr,
+			Password: conf.RedisPwd,
+			DB:       conf.RedisDB,
+		})
+		if err := rds.Ping(ctx).Err(); err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, NewRedisSink("redis", rds, conf.RedisStream, conf.RedisMaxLen))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	manager := NewManager(sinks...)
+	manager.StartHeight = conf.StartHeight
+	manager.Start(ctx)
+	return manager, nil
+}
@@ -0,0 +1,34 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/traitmeta/metago/core/chain"
+)
+
+// Sink 接收已经提交到数据库的区块数据, Write 返回成功之后才会推进游标
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, data *chain.BlockData) error
+	Close() error
+}
+
+// Message 输出到下游的消息格式, 下游可以用 block_hash 去重
+type Message struct {
+	BlockNumber uint64           `json:"block_number"`
+	BlockHash   string           `json:"block_hash"`
+	Data        *chain.BlockData `json:"data"`
+}
+
+func NewMessage(data *chain.BlockData) Message {
+	return Message{
+		BlockNumber: data.Block.BlockHeight,
+		BlockHash:   data.Block.BlockHash,
+		Data:        data,
+	}
+}
+
+func (m Message) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
@@ -0,0 +1,68 @@
+package snapshot
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+type jsonHolder struct {
+	Address string   `json:"address"`
+	Balance string   `json:"balance"`
+	Proof   []string `json:"proof"`
+}
+
+type jsonSnapshot struct {
+	Token            string       `json:"token"`
+	Type             string       `json:"type"`
+	Height           uint64       `json:"height"`
+	TokenId          string       `json:"token_id,omitempty"`
+	HolderCount      int          `json:"holder_count"`
+	Total            string       `json:"total"`
+	NegativeBalances int          `json:"negative_balances"`
+	MerkleRoot       string       `json:"merkle_root"`
+	Holders          []jsonHolder `json:"holders"`
+}
+
+// WriteCSV 输出 address,balance, 余额为十进制字符串
+func (s *Snapshot) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"address", "balance"}); err != nil {
+		return err
+	}
+	for _, holder := range s.Holders {
+		if err := writer.Write([]string{holder.Address, holder.Balance.String()}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON 输出快照、Merkle 根和每个持有人的证明, 数值为十进制字符串
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	out := jsonSnapshot{
+		Token:            s.Token,
+		Type:             s.Type,
+		Height:           s.Height,
+		HolderCount:      len(s.Holders),
+		Total:            s.Total().String(),
+		NegativeBalances: s.NegativeBalances,
+		MerkleRoot:       s.MerkleRoot.Hex(),
+		Holders:          make([]jsonHolder, 0, len(s.Holders)),
+	}
+	if s.TokenId != nil {
+		out.TokenId = s.TokenId.String()
+	}
+	for _, holder := range s.Holders {
+		proof := make([]string, 0, len(holder.Proof))
+		for _, node := range holder.Proof {
+			proof = append(proof, node.Hex())
+		}
+		out.Holders = append(out.Holders, jsonHolder{Address: holder.Address, Balance: holder.Balance.String(), Proof: proof})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
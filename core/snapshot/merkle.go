@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+/*
+## Overview
+  Merkle 树与 OpenZeppelin StandardMerkleTree 的构造方式相同, 根和证明可以直接用于 MerkleProof.verify:
+  - 叶子为 keccak256(keccak256(abi.encode(address, uint256))).
+  - 叶子按哈希升序排序, 从数组末尾倒序放入完全二叉树.
+  - 父节点为两个子节点按大小排序后拼接的 keccak256.
+*/
+
+var leafArguments = abi.Arguments{{Type: mustType("address")}, {Type: mustType("uint256")}}
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// LeafHash (address, amount) 对应的叶子哈希
+func LeafHash(address string, amount *big.Int) common.Hash {
+	encoded, err := leafArguments.Pack(common.HexToAddress(address), amount)
+	if err != nil {
+		// address 和 uint256 只有在 amount 为负数或超出范围时才会出错
+		panic(err)
+	}
+	return crypto.Keccak256Hash(crypto.Keccak256(encoded))
+}
+
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// MerkleTree 用数组保存的完全二叉树, nodes[0] 为根
+type MerkleTree struct {
+	nodes []common.Hash
+	index map[common.Hash]int
+}
+
+// NewMerkleTree 根据叶子构造 Merkle 树, 叶子不能重复
+func NewMerkleTree(leaves []common.Hash) *MerkleTree {
+	sorted := append([]common.Hash(nil), leaves...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+
+	tree := &MerkleTree{index: make(map[common.Hash]int, len(sorted))}
+	if len(sorted) == 0 {
+		return tree
+	}
+
+	tree.nodes = make([]common.Hash, 2*len(sorted)-1)
+	for i, leaf := range sorted {
+		pos := len(tree.nodes) - 1 - i
+		tree.nodes[pos] = leaf
+		tree.index[leaf] = pos
+	}
+	for i := len(tree.nodes) - 1 - len(sorted); i >= 0; i-- {
+		tree.nodes[i] = hashPair(tree.nodes[2*i+1], tree.nodes[2*i+2])
+	}
+	return tree
+}
+
+// Root 没有叶子时返回零值
+func (t *MerkleTree) Root() common.Hash {
+	if len(t.nodes) == 0 {
+		return common.Hash{}
+	}
+	return t.nodes[0]
+}
+
+// Proof 从叶子到根路径上的兄弟节点
+func (t *MerkleTree) Proof(leaf common.Hash) ([]common.Hash, bool) {
+	pos, ok := t.index[leaf]
+	if !ok {
+		return nil, false
+	}
+
+	proof := []common.Hash{}
+	for pos > 0 {
+		sibling := pos + 1
+		if pos%2 == 0 {
+			sibling = pos - 1
+		}
+		proof = append(proof, t.nodes[sibling])
+		pos = (pos - 1) / 2
+	}
+	return proof, true
+}
+
+// VerifyProof 与 MerkleProof.verify 的计算方式相同
+func VerifyProof(root, leaf common.Hash, proof []common.Hash) bool {
+	hash := leaf
+	for _, node := range proof {
+		hash = hashPair(hash, node)
+	}
+	return hash == root
+}
@@ -0,0 +1,75 @@
+package snapshot
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMerkleTree(t *testing.T) {
+	amount, _ := new(big.Int).SetString("5000000000000000000", 10)
+	half, _ := new(big.Int).SetString("2500000000000000000", 10)
+
+	tests := []struct {
+		name     string
+		holders  []Holder
+		wantRoot string
+	}{
+		{
+			// OpenZeppelin merkle-tree README 中的示例
+			name: "openzeppelin example",
+			holders: []Holder{
+				{Address: "0x1111111111111111111111111111111111111111", Balance: amount},
+				{Address: "0x2222222222222222222222222222222222222222", Balance: half},
+			},
+			wantRoot: "0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77",
+		},
+		{
+			name: "odd leaves",
+			holders: []Holder{
+				{Address: "0x1111111111111111111111111111111111111111", Balance: big.NewInt(1)},
+				{Address: "0x2222222222222222222222222222222222222222", Balance: big.NewInt(2)},
+				{Address: "0x3333333333333333333333333333333333333333", Balance: big.NewInt(3)},
+				{Address: "0x4444444444444444444444444444444444444444", Balance: big.NewInt(4)},
+				{Address: "0x5555555555555555555555555555555555555555", Balance: big.NewInt(5)},
+			},
+		},
+		{
+			name:    "single leaf",
+			holders: []Holder{{Address: "0x1111111111111111111111111111111111111111", Balance: big.NewInt(1)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaves := make([]common.Hash, 0, len(tt.holders))
+			for _, holder := range tt.holders {
+				leaves = append(leaves, LeafHash(holder.Address, holder.Balance))
+			}
+			tree := NewMerkleTree(leaves)
+			if tt.wantRoot != "" && tree.Root().Hex() != tt.wantRoot {
+				t.Errorf("Root() = %v, want %v", tree.Root().Hex(), tt.wantRoot)
+			}
+			if len(leaves) == 1 && tree.Root() != leaves[0] {
+				t.Errorf("Root() = %v, want leaf %v", tree.Root().Hex(), leaves[0].Hex())
+			}
+
+			for _, leaf := range leaves {
+				proof, ok := tree.Proof(leaf)
+				if !ok {
+					t.Fatalf("Proof(%v) not found", leaf.Hex())
+				}
+				if !VerifyProof(tree.Root(), leaf, proof) {
+					t.Errorf("VerifyProof(%v) = false", leaf.Hex())
+				}
+			}
+			if _, ok := tree.Proof(common.Hash{}); ok {
+				t.Error("Proof() of unknown leaf should not be found")
+			}
+		})
+	}
+
+	if root := NewMerkleTree(nil).Root(); root != (common.Hash{}) {
+		t.Errorf("empty tree Root() = %v", root.Hex())
+	}
+}
@@ -0,0 +1,166 @@
+package snapshot
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+/*
+## Overview
+  快照根据已入库的 token_transfers 重放得到指定高度的余额, 每条转移都转换为 (tokenId, amount) 的增量:
+
+  | 类型     | 识别方式                          | 增量                                  |
+  |----------|-----------------------------------|---------------------------------------|
+  | ERC-20   | 只有 amount                       | (nil, amount)                         |
+  | ERC-721  | 只有 token_id                     | (token_id, 1)                         |
+  | ERC-1155 | token_id 和 amount, 或批量数组     | (token_id, amount), 批量时逐个累加    |
+
+  ERC-721/ERC-1155 默认按地址汇总所有 tokenId 的数量, 指定 tokenId 时只统计该 tokenId.
+  零地址不作为持有人. 同步没有从合约创建开始时会出现负余额, 这些地址不会进入快照, 只记录数量.
+*/
+
+// Holder 快照中的一个持有人, Proof 为该持有人在 Merkle 树中的证明
+type Holder struct {
+	Address string
+	Balance *big.Int
+	Proof   []ethcommon.Hash
+}
+
+// Snapshot 某个 Token 在指定高度的持有人快照, Holders 按余额倒序
+type Snapshot struct {
+	Token   string
+	Type    string
+	Height  uint64
+	TokenId *big.Int
+	Holders []Holder
+	// NegativeBalances 余额为负的地址数, 不为0说明转移记录不完整
+	NegativeBalances int
+	MerkleRoot       ethcommon.Hash
+}
+
+// Total 所有持有人余额之和
+func (s *Snapshot) Total() *big.Int {
+	total := new(big.Int)
+	for _, holder := range s.Holders {
+		total.Add(total, holder.Balance)
+	}
+	return total
+}
+
+// Balances 按转移记录累加余额
+type Balances struct {
+	tokenId   *big.Int
+	tokenType string
+	balances  map[string]*big.Int
+}
+
+// NewBalances tokenId 为空时汇总所有 tokenId
+func NewBalances(tokenId *big.Int) *Balances {
+	return &Balances{tokenId: tokenId, balances: make(map[string]*big.Int)}
+}
+
+// Apply 累加一批转移, 顺序不影响结果
+func (b *Balances) Apply(transfers []models.TokenTransfer) {
+	for _, transfer := range transfers {
+		tokenType, ids, amounts := transferDeltas(transfer)
+		if b.tokenType == "" {
+			b.tokenType = tokenType
+		}
+
+		for i, amount := range amounts {
+			if amount == nil {
+				continue
+			}
+			if b.tokenId != nil && ids[i] != nil && ids[i].Cmp(b.tokenId) != 0 {
+				continue
+			}
+			b.add(transfer.FromAddress, new(big.Int).Neg(amount))
+			b.add(transfer.ToAddress, amount)
+		}
+	}
+}
+
+func (b *Balances) add(address string, amount *big.Int) {
+	balance, ok := b.balances[address]
+	if !ok {
+		balance = new(big.Int)
+		b.balances[address] = balance
+	}
+	balance.Add(balance, amount)
+}
+
+func transferDeltas(transfer models.TokenTransfer) (string, []*big.Int, []*big.Int) {
+	switch {
+	case len(transfer.TokenIds) > 0:
+		return common.ERC1155, transfer.TokenIds, transfer.Amounts
+	case transfer.TokenId != nil && transfer.Amount != nil:
+		return common.ERC1155, []*big.Int{transfer.TokenId}, []*big.Int{transfer.Amount}
+	case transfer.TokenId != nil:
+		return common.ERC721, []*big.Int{transfer.TokenId}, []*big.Int{big.NewInt(1)}
+	default:
+		return common.ERC20, []*big.Int{nil}, []*big.Int{transfer.Amount}
+	}
+}
+
+// Snapshot 生成快照和 Merkle 根
+func (b *Balances) Snapshot(token string, height uint64) *Snapshot {
+	snapshot := &Snapshot{
+		Token:   token,
+		Type:    b.tokenType,
+		Height:  height,
+		TokenId: b.tokenId,
+		Holders: []Holder{},
+	}
+	for address, balance := range b.balances {
+		if strings.EqualFold(address, common.ZeroAddress) {
+			continue
+		}
+		switch balance.Sign() {
+		case 1:
+			snapshot.Holders = append(snapshot.Holders, Holder{Address: address, Balance: balance})
+		case -1:
+			snapshot.NegativeBalances++
+		}
+	}
+	sort.Slice(snapshot.Holders, func(i, j int) bool {
+		a, b := snapshot.Holders[i], snapshot.Holders[j]
+		if c := a.Balance.Cmp(b.Balance); c != 0 {
+			return c > 0
+		}
+		return a.Address < b.Address
+	})
+
+	leaves := make([]ethcommon.Hash, 0, len(snapshot.Holders))
+	for _, holder := range snapshot.Holders {
+		leaves = append(leaves, LeafHash(holder.Address, holder.Balance))
+	}
+	tree := NewMerkleTree(leaves)
+	snapshot.MerkleRoot = tree.Root()
+	for i := range snapshot.Holders {
+		snapshot.Holders[i].Proof, _ = tree.Proof(leaves[i])
+	}
+	return snapshot
+}
+
+// Build 从数据库重放 token 在 height 及之前的转移, 生成快照
+func Build(ctx context.Context, token string, height uint64, tokenId *big.Int) (*Snapshot, error) {
+	token = ethcommon.HexToAddress(token).Hex()
+	balances := NewBalances(tokenId)
+	err := dal.TokenTransfer.FindByTokenInBatches(ctx, token, height, func(transfers []models.TokenTransfer) error {
+		balances.Apply(transfers)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "load token transfers")
+	}
+	return balances.Snapshot(token, height), nil
+}
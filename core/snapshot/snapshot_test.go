@@ -0,0 +1,172 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	token = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	alice = "0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e"
+	bob   = "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"
+	carol = "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4"
+)
+
+func balancesOf(s *Snapshot) map[string]int64 {
+	got := make(map[string]int64, len(s.Holders))
+	for _, holder := range s.Holders {
+		got[holder.Address] = holder.Balance.Int64()
+	}
+	return got
+}
+
+func TestBalances_Snapshot(t *testing.T) {
+	tests := []struct {
+		name         string
+		tokenId      *big.Int
+		transfers    []models.TokenTransfer
+		wantType     string
+		want         map[string]int64
+		wantNegative int
+	}{
+		{
+			name: "erc20 mint transfer burn",
+			transfers: []models.TokenTransfer{
+				{FromAddress: common.ZeroAddress, ToAddress: alice, Amount: big.NewInt(100)},
+				{FromAddress: alice, ToAddress: bob, Amount: big.NewInt(30)},
+				{FromAddress: bob, ToAddress: common.ZeroAddress, Amount: big.NewInt(30)},
+				// 转出前的记录缺失
+				{FromAddress: carol, ToAddress: alice, Amount: big.NewInt(5)},
+			},
+			wantType:     common.ERC20,
+			want:         map[string]int64{alice: 75},
+			wantNegative: 1,
+		},
+		{
+			name: "erc721 counts tokens per owner",
+			transfers: []models.TokenTransfer{
+				{FromAddress: common.ZeroAddress, ToAddress: alice, TokenId: big.NewInt(1)},
+				{FromAddress: common.ZeroAddress, ToAddress: alice, TokenId: big.NewInt(2)},
+				{FromAddress: alice, ToAddress: bob, TokenId: big.NewInt(1)},
+				{FromAddress: common.ZeroAddress, ToAddress: carol, TokenId: big.NewInt(3)},
+			},
+			wantType: common.ERC721,
+			want:     map[string]int64{alice: 1, bob: 1, carol: 1},
+		},
+		{
+			name:    "erc1155 single token id",
+			tokenId: big.NewInt(7),
+			transfers: []models.TokenTransfer{
+				{FromAddress: common.ZeroAddress, ToAddress: alice, TokenIds: []*big.Int{big.NewInt(7), big.NewInt(8)}, Amounts: []*big.Int{big.NewInt(10), big.NewInt(20)}},
+				{FromAddress: alice, ToAddress: bob, TokenId: big.NewInt(7), Amount: big.NewInt(4)},
+				{FromAddress: alice, ToAddress: carol, TokenId: big.NewInt(8), Amount: big.NewInt(20)},
+			},
+			wantType: common.ERC1155,
+			want:     map[string]int64{alice: 6, bob: 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := NewBalances(tt.tokenId)
+			// 分批累加与一次累加结果相同
+			balances.Apply(tt.transfers[:1])
+			balances.Apply(tt.transfers[1:])
+			got := balances.Snapshot(token, 100)
+
+			if got.Type != tt.wantType || got.Height != 100 || got.Token != token {
+				t.Errorf("Snapshot() = %+v", got)
+			}
+			if gotBalances := balancesOf(got); len(gotBalances) != len(tt.want) {
+				t.Errorf("Snapshot() balances = %v, want %v", gotBalances, tt.want)
+			} else {
+				for address, balance := range tt.want {
+					if gotBalances[address] != balance {
+						t.Errorf("Snapshot() balances = %v, want %v", gotBalances, tt.want)
+						break
+					}
+				}
+			}
+			if got.NegativeBalances != tt.wantNegative {
+				t.Errorf("Snapshot() NegativeBalances = %d, want %d", got.NegativeBalances, tt.wantNegative)
+			}
+			for i, holder := range got.Holders {
+				if i > 0 && holder.Balance.Cmp(got.Holders[i-1].Balance) > 0 {
+					t.Errorf("Snapshot() holders not sorted by balance: %v", got.Holders)
+				}
+				if !VerifyProof(got.MerkleRoot, LeafHash(holder.Address, holder.Balance), holder.Proof) {
+					t.Errorf("Snapshot() invalid proof for %s", holder.Address)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshot_Verify(t *testing.T) {
+	balances := NewBalances(nil)
+	balances.Apply([]models.TokenTransfer{
+		{FromAddress: common.ZeroAddress, ToAddress: alice, Amount: big.NewInt(100)},
+		{FromAddress: alice, ToAddress: bob, Amount: big.NewInt(30)},
+	})
+	snapshot := balances.Snapshot(token, 100)
+
+	onChain := map[string]int64{alice: 70, bob: 31}
+	balanceOf := func(ctx context.Context, tokenAddress, holder string, tokenId *big.Int, height uint64) (*big.Int, error) {
+		if tokenAddress != token || tokenId != nil || height != 100 {
+			t.Errorf("balanceOf(%s, %s, %v, %d) unexpected args", tokenAddress, holder, tokenId, height)
+		}
+		return big.NewInt(onChain[holder]), nil
+	}
+
+	mismatches, err := snapshot.Verify(context.Background(), snapshot.Sample(10, 1), balanceOf)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(mismatches) != 1 || mismatches[0].Address != bob || mismatches[0].OnChain.Int64() != 31 {
+		t.Errorf("Verify() mismatches = %+v", mismatches)
+	}
+	if sample := snapshot.Sample(1, 1); len(sample) != 1 {
+		t.Errorf("Sample() got %d holders, want 1", len(sample))
+	}
+
+	snapshot.Type = common.ERC1155
+	if _, err := snapshot.Verify(context.Background(), snapshot.Holders, balanceOf); err != errCannotVerify {
+		t.Errorf("Verify() error = %v, want %v", err, errCannotVerify)
+	}
+}
+
+func TestSnapshot_Export(t *testing.T) {
+	balances := NewBalances(nil)
+	balances.Apply([]models.TokenTransfer{
+		{FromAddress: common.ZeroAddress, ToAddress: alice, Amount: big.NewInt(100)},
+		{FromAddress: alice, ToAddress: bob, Amount: big.NewInt(30)},
+	})
+	snapshot := balances.Snapshot(token, 100)
+
+	var csvOut bytes.Buffer
+	if err := snapshot.WriteCSV(&csvOut); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "address,balance\n" + alice + ",70\n" + bob + ",30\n"
+	if csvOut.String() != wantCSV {
+		t.Errorf("WriteCSV() = %q, want %q", csvOut.String(), wantCSV)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := snapshot.WriteJSON(&jsonOut); err != nil {
+		t.Fatal(err)
+	}
+	var got jsonSnapshot
+	if err := json.Unmarshal(jsonOut.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.MerkleRoot != snapshot.MerkleRoot.Hex() || got.Total != "100" || got.HolderCount != 2 ||
+		len(got.Holders) != 2 || got.Holders[0].Balance != "70" || len(got.Holders[0].Proof) != 1 {
+		t.Errorf("WriteJSON() = %+v", got)
+	}
+}
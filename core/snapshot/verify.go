@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/pkg/abi"
+)
+
+var errCannotVerify = errors.New("balanceOf cannot verify this snapshot, ERC-721 needs all token ids and ERC-1155 needs a token id")
+
+// BalanceOfFunc 查询 height 高度的链上余额, tokenId 不为空时按 ERC1155 查询
+type BalanceOfFunc func(ctx context.Context, token, holder string, tokenId *big.Int, height uint64) (*big.Int, error)
+
+// ChainBalanceOf 通过节点查询余额, 节点需要保存 height 的历史状态
+var ChainBalanceOf BalanceOfFunc = abi.GetBalanceOfAt
+
+// Mismatch 快照余额与链上余额不一致的持有人
+type Mismatch struct {
+	Address string
+	Balance *big.Int
+	OnChain *big.Int
+}
+
+// Sample 按 seed 随机选取 n 个持有人, n 不小于持有人数量时返回全部
+func (s *Snapshot) Sample(n int, seed int64) []Holder {
+	if n >= len(s.Holders) {
+		return s.Holders
+	}
+	sample := make([]Holder, 0, n)
+	for _, i := range rand.New(rand.NewSource(seed)).Perm(len(s.Holders))[:n] {
+		sample = append(sample, s.Holders[i])
+	}
+	return sample
+}
+
+// Verify 用 balanceOf 检查持有人余额, 返回不一致的持有人
+func (s *Snapshot) Verify(ctx context.Context, holders []Holder, balanceOf BalanceOfFunc) ([]Mismatch, error) {
+	var tokenId *big.Int
+	switch s.Type {
+	case common.ERC721:
+		if s.TokenId != nil {
+			return nil, errCannotVerify
+		}
+	case common.ERC1155:
+		if s.TokenId == nil {
+			return nil, errCannotVerify
+		}
+		tokenId = s.TokenId
+	}
+
+	var mismatches []Mismatch
+	for _, holder := range holders {
+		onChain, err := balanceOf(ctx, s.Token, holder.Address, tokenId, s.Height)
+		if err != nil {
+			return mismatches, err
+		}
+		if onChain.Cmp(holder.Balance) != 0 {
+			mismatches = append(mismatches, Mismatch{Address: holder.Address, Balance: holder.Balance, OnChain: onChain})
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,177 @@
+package stats
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// dayKey 统计使用的日期, 统一为UTC零点
+func dayKey(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+type tokenKey struct {
+	date  time.Time
+	token string
+}
+
+// Rollup 一批区块汇总后的增量统计结果
+type Rollup struct {
+	ChainStats      []models.DailyChainStat
+	TokenStats      []models.DailyTokenStat
+	ActiveAddresses []models.DailyActiveAddress
+}
+
+// Dates 本次汇总涉及到的日期
+func (r Rollup) Dates() []time.Time {
+	dates := make([]time.Time, 0, len(r.ChainStats))
+	for _, stat := range r.ChainStats {
+		dates = append(dates, stat.Date)
+	}
+	return dates
+}
+
+// Aggregate 将一批区块的交易和日志汇总为每日统计, 只依赖入参, 不访问数据库
+func Aggregate(blocks []models.Block, trxs []models.Transaction, events []models.Event) Rollup {
+	blockDates := make(map[uint64]time.Time, len(blocks))
+	chainStats := make(map[time.Time]*models.DailyChainStat)
+	getChainStat := func(date time.Time) *models.DailyChainStat {
+		stat, ok := chainStats[date]
+		if !ok {
+			stat = &models.DailyChainStat{Date: date}
+			chainStats[date] = stat
+		}
+		return stat
+	}
+
+	for _, block := range blocks {
+		if block.Timestamp.IsZero() {
+			continue
+		}
+		date := dayKey(block.Timestamp)
+		blockDates[block.BlockHeight] = date
+		stat := getChainStat(date)
+		stat.BlockCount++
+		stat.GasUsed += block.GasUsed
+	}
+
+	activeAddresses := make(map[models.DailyActiveAddress]struct{})
+	addActive := func(date time.Time, address string) {
+		if address == "" || address == common.ZeroAddress {
+			return
+		}
+		activeAddresses[models.DailyActiveAddress{Date: date, Address: address}] = struct{}{}
+	}
+
+	for _, trx := range trxs {
+		date, ok := blockDates[trx.BlockNumber]
+		if !ok {
+			continue
+		}
+		stat := getChainStat(date)
+		stat.TransactionCount++
+		if trx.ContractCreation {
+			stat.NewContractCount++
+		}
+		addActive(date, trx.From)
+		addActive(date, trx.To)
+		if !trx.ContractCreation {
+			addActive(date, trx.Contract)
+		}
+	}
+
+	tokenStats := make(map[tokenKey]*models.DailyTokenStat)
+	tokenVolumes := make(map[tokenKey]*big.Int)
+	for _, event := range events {
+		if !isTokenTransferEvent(event) || event.Removed {
+			continue
+		}
+		date, ok := blockDates[event.BlockNumber]
+		if !ok {
+			continue
+		}
+		getChainStat(date).TokenTransferCount++
+
+		key := tokenKey{date: date, token: event.Address}
+		stat, ok := tokenStats[key]
+		if !ok {
+			stat = &models.DailyTokenStat{Date: date, TokenContractAddress: event.Address}
+			tokenStats[key] = stat
+			tokenVolumes[key] = big.NewInt(0)
+		}
+		stat.TransferCount++
+		if amount := fungibleAmount(event); amount != nil {
+			tokenVolumes[key].Add(tokenVolumes[key], amount)
+		}
+	}
+
+	rollup := Rollup{}
+	for _, stat := range chainStats {
+		rollup.ChainStats = append(rollup.ChainStats, *stat)
+	}
+	for key, stat := range tokenStats {
+		stat.Volume = tokenVolumes[key].String()
+		rollup.TokenStats = append(rollup.TokenStats, *stat)
+	}
+	for address := range activeAddresses {
+		rollup.ActiveAddresses = append(rollup.ActiveAddresses, address)
+	}
+
+	sort.Slice(rollup.ChainStats, func(i, j int) bool {
+		return rollup.ChainStats[i].Date.Before(rollup.ChainStats[j].Date)
+	})
+	sort.Slice(rollup.TokenStats, func(i, j int) bool {
+		a, b := rollup.TokenStats[i], rollup.TokenStats[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.TokenContractAddress < b.TokenContractAddress
+	})
+	sort.Slice(rollup.ActiveAddresses, func(i, j int) bool {
+		a, b := rollup.ActiveAddresses[i], rollup.ActiveAddresses[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.Address < b.Address
+	})
+
+	return rollup
+}
+
+func isTokenTransferEvent(event models.Event) bool {
+	switch event.FirstTopic {
+	case common.ERC20TokenTransferEventFuncSign,
+		common.WETHDepositSignature,
+		common.WETHWithdrawalSignature,
+		common.ERC1155SingleTransferSignature,
+		common.ERC1155BatchTransferSignature:
+		return true
+	}
+	return false
+}
+
+// fungibleAmount ERC20 Transfer 和 WETH Deposit/Withdrawal 的数量, 其他类型返回nil
+func fungibleAmount(event models.Event) *big.Int {
+	switch event.FirstTopic {
+	case common.ERC20TokenTransferEventFuncSign:
+		if event.FourthTopic != "" {
+			return nil
+		}
+	case common.WETHDepositSignature, common.WETHWithdrawalSignature:
+	default:
+		return nil
+	}
+
+	data := ethcommon.FromHex(event.Data)
+	if len(data) < 32 {
+		return nil
+	}
+	return new(big.Int).SetBytes(data[:32])
+}
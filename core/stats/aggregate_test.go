@@ -0,0 +1,108 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/metago/core/common"
+	"github.com/traitmeta/metago/core/models"
+)
+
+func TestAggregate(t *testing.T) {
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	token := "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4"
+	alice := "0x8b736035bbda71825e0219f5fe4dfb22c35fbddc"
+	bob := "0x4D8834907CEE521D08A3fC77478A0fbc7c94FD0a"
+
+	blocks := []models.Block{
+		{BlockHeight: 100, GasUsed: 21000, Timestamp: day1.Add(23 * time.Hour)},
+		{BlockHeight: 101, GasUsed: 50000, Timestamp: day2.Add(time.Minute)},
+	}
+	trxs := []models.Transaction{
+		{BlockNumber: 100, From: alice, To: bob},
+		{BlockNumber: 101, From: alice, Contract: token, ContractCreation: true},
+		{BlockNumber: 101, From: bob, Contract: token},
+	}
+	events := []models.Event{
+		{
+			Address:     token,
+			FirstTopic:  common.ERC20TokenTransferEventFuncSign,
+			SecondTopic: "0x0000000000000000000000008b736035bbda71825e0219f5fe4dfb22c35fbddc",
+			ThirdTopic:  "0x0000000000000000000000004d8834907cee521d08a3fc77478a0fbc7c94fd0a",
+			Data:        "00000000000000000000000000000000000000000000000000000000000003e8",
+			BlockNumber: 101,
+		},
+		{
+			Address:     token,
+			FirstTopic:  common.ERC20TokenTransferEventFuncSign,
+			SecondTopic: "0x0000000000000000000000004d8834907cee521d08a3fc77478a0fbc7c94fd0a",
+			ThirdTopic:  "0x0000000000000000000000008b736035bbda71825e0219f5fe4dfb22c35fbddc",
+			Data:        "0x0000000000000000000000000000000000000000000000000000000000000018",
+			BlockNumber: 101,
+		},
+		{
+			Address:     token,
+			FirstTopic:  common.ERC20TokenTransferEventFuncSign,
+			SecondTopic: "0x0000000000000000000000004d8834907cee521d08a3fc77478a0fbc7c94fd0a",
+			ThirdTopic:  "0x0000000000000000000000008b736035bbda71825e0219f5fe4dfb22c35fbddc",
+			FourthTopic: "0x0000000000000000000000000000000000000000000000000000000000000001",
+			BlockNumber: 101,
+		},
+		{
+			Address:     token,
+			FirstTopic:  "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+			BlockNumber: 101,
+		},
+	}
+
+	want := Rollup{
+		ChainStats: []models.DailyChainStat{
+			{Date: day1, BlockCount: 1, TransactionCount: 1, GasUsed: 21000},
+			{Date: day2, BlockCount: 1, TransactionCount: 2, NewContractCount: 1, TokenTransferCount: 3, GasUsed: 50000},
+		},
+		TokenStats: []models.DailyTokenStat{
+			{Date: day2, TokenContractAddress: token, TransferCount: 3, Volume: "1024"},
+		},
+		ActiveAddresses: []models.DailyActiveAddress{
+			{Date: day1, Address: bob},
+			{Date: day1, Address: alice},
+			{Date: day2, Address: bob},
+			{Date: day2, Address: alice},
+			{Date: day2, Address: token},
+		},
+	}
+
+	got := Aggregate(blocks, trxs, events)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Aggregate() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_dayKey(t *testing.T) {
+	shanghai := time.FixedZone("Asia/Shanghai", 8*3600)
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "utc",
+			t:    time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC),
+			want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "other zone",
+			t:    time.Date(2024, 3, 2, 7, 0, 0, 0, shanghai),
+			want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dayKey(tt.t); !got.Equal(tt.want) {
+				t.Errorf("dayKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
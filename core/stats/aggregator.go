@@ -0,0 +1,179 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+)
+
+const (
+	// CursorName 统计任务在 sync_cursors 中的名称
+	CursorName = "daily_stats"
+	// HookName 在 chain 中注册的区块钩子名称
+	HookName = "stats"
+)
+
+// Aggregator 定时把已入库的区块增量汇总到每日统计表
+type Aggregator struct {
+	Interval  time.Duration
+	BatchSize uint64
+
+	store Store
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		Interval:  time.Second * 10,
+		BatchSize: 100,
+		store:     dalStore{},
+	}
+}
+
+// Setup 创建汇总任务并注册区块钩子, 定时汇总需要调用 Start
+func Setup() *Aggregator {
+	a := NewAggregator()
+	chain.RegisterBlockHook(HookName, a.BlockHook)
+	return a
+}
+
+// BlockHook 重新索引区块时在区块事务中重建新旧区块所在日期的统计, 游标之后的区块仍由增量汇总处理
+func (a *Aggregator) BlockHook(ctx context.Context, data *chain.BlockData) error {
+	if data.Replaced == nil {
+		return nil
+	}
+	start, end := data.Replaced.Block.Timestamp, data.Block.Timestamp
+	if end.Before(start) {
+		start, end = end, start
+	}
+	return a.Rebuild(ctx, start, end.AddDate(0, 0, 1))
+}
+
+// Start 按 Interval 定时汇总, 直到 ctx 结束
+func (a *Aggregator) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for {
+				done, err := a.RunOnce(ctx)
+				if err != nil {
+					log.WithField("err", err).Error("stats aggregator run failed")
+					break
+				}
+				if done {
+					break
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce 汇总游标之后的一批区块, 返回是否已经追上最新区块
+func (a *Aggregator) RunOnce(ctx context.Context) (bool, error) {
+	cursor, err := a.store.GetCursor(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "get stats cursor")
+	}
+
+	tip, err := a.store.GetMaxHeight(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "get max block height")
+	}
+
+	from := cursor.BlockHeight + 1
+	if cursor.BlockHash == "" {
+		if from, err = a.store.GetMinHeight(ctx); err != nil {
+			return false, errors.Wrap(err, "get min block height")
+		}
+	}
+	if from > tip {
+		return true, nil
+	}
+
+	to := from + a.BatchSize - 1
+	if to > tip {
+		to = tip
+	}
+
+	rollup, lastHash, err := a.load(ctx, from, to)
+	if err != nil {
+		return false, err
+	}
+	if lastHash == "" {
+		// 区间内没有区块时保留原来的哈希, 空哈希会让下一次从最小高度重新汇总
+		lastHash = cursor.BlockHash
+		if lastHash == "" {
+			lastHash = dal.NoBlockHash
+		}
+	}
+
+	err = a.store.WithTx(ctx, func(txCtx context.Context) error {
+		if err := a.store.Save(txCtx, rollup); err != nil {
+			return err
+		}
+		return a.store.UpsertCursor(txCtx, to, lastHash)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	log.WithField("from", from).WithField("to", to).Info("stats aggregator rolled up blocks")
+	return to == tip, nil
+}
+
+// Rebuild 删除并重新计算 [start, end) 日期区间内的统计, 用于区块回滚之后修正数据
+func (a *Aggregator) Rebuild(ctx context.Context, start, end time.Time) error {
+	start, end = dayKey(start), dayKey(end)
+	if !end.After(start) {
+		return errors.Errorf("invalid rebuild range %s - %s", start, end)
+	}
+
+	minHeight, maxHeight, found, err := a.store.GetHeightRangeByTime(ctx, start, end)
+	if err != nil {
+		return errors.Wrap(err, "get block range by time")
+	}
+
+	cursor, err := a.store.GetCursor(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get stats cursor")
+	}
+	// 只重建游标已经汇总过的区块, 剩下的交给增量任务
+	if found && maxHeight > cursor.BlockHeight {
+		maxHeight = cursor.BlockHeight
+	}
+
+	rollup := Rollup{}
+	if found && minHeight <= maxHeight {
+		if rollup, _, err = a.load(ctx, minHeight, maxHeight); err != nil {
+			return err
+		}
+	}
+
+	return a.store.WithTx(ctx, func(txCtx context.Context) error {
+		if err := a.store.DeleteByDateRange(txCtx, start, end); err != nil {
+			return errors.Wrap(err, "delete stats")
+		}
+		return a.store.Save(txCtx, rollup)
+	})
+}
+
+func (a *Aggregator) load(ctx context.Context, from, to uint64) (Rollup, string, error) {
+	blocks, trxs, events, err := a.store.Load(ctx, from, to)
+	if err != nil {
+		return Rollup{}, "", err
+	}
+
+	lastHash := ""
+	if len(blocks) > 0 {
+		lastHash = blocks[len(blocks)-1].BlockHash
+	}
+	return Aggregate(blocks, trxs, events), lastHash, nil
+}
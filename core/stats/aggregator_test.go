@@ -0,0 +1,240 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// memoryStore 内存中的 Store, Save 和 DeleteByDateRange 只维护每日链上统计
+type memoryStore struct {
+	cursor models.SyncCursor
+	// minHeight 不为0时作为最小高度, 模拟最小高度之后的区块还没有写入
+	minHeight uint64
+	blocks    []models.Block
+	trxs      []models.Transaction
+	stats     map[time.Time]models.DailyChainStat
+}
+
+func (s *memoryStore) GetCursor(ctx context.Context) (*models.SyncCursor, error) {
+	cursor := s.cursor
+	return &cursor, nil
+}
+
+func (s *memoryStore) UpsertCursor(ctx context.Context, blockHeight uint64, blockHash string) error {
+	s.cursor = models.SyncCursor{Name: CursorName, BlockHeight: blockHeight, BlockHash: blockHash}
+	return nil
+}
+
+func (s *memoryStore) GetMinHeight(ctx context.Context) (uint64, error) {
+	if s.minHeight != 0 {
+		return s.minHeight, nil
+	}
+	return s.blocks[0].BlockHeight, nil
+}
+
+func (s *memoryStore) GetMaxHeight(ctx context.Context) (uint64, error) {
+	return s.blocks[len(s.blocks)-1].BlockHeight, nil
+}
+
+func (s *memoryStore) GetHeightRangeByTime(ctx context.Context, start, end time.Time) (uint64, uint64, bool, error) {
+	var min, max uint64
+	found := false
+	for _, block := range s.blocks {
+		if block.Timestamp.Before(start) || !block.Timestamp.Before(end) {
+			continue
+		}
+		if !found {
+			min = block.BlockHeight
+		}
+		max, found = block.BlockHeight, true
+	}
+	return min, max, found, nil
+}
+
+func (s *memoryStore) Load(ctx context.Context, from, to uint64) ([]models.Block, []models.Transaction, []models.Event, error) {
+	var blocks []models.Block
+	var trxs []models.Transaction
+	for _, block := range s.blocks {
+		if block.BlockHeight >= from && block.BlockHeight <= to {
+			blocks = append(blocks, block)
+		}
+	}
+	for _, trx := range s.trxs {
+		if trx.BlockNumber >= from && trx.BlockNumber <= to {
+			trxs = append(trxs, trx)
+		}
+	}
+	return blocks, trxs, nil, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, rollup Rollup) error {
+	for _, add := range rollup.ChainStats {
+		stat := s.stats[add.Date]
+		stat.Date = add.Date
+		stat.BlockCount += add.BlockCount
+		stat.TransactionCount += add.TransactionCount
+		stat.GasUsed += add.GasUsed
+		s.stats[add.Date] = stat
+	}
+	return nil
+}
+
+func (s *memoryStore) DeleteByDateRange(ctx context.Context, start, end time.Time) error {
+	for date := range s.stats {
+		if !date.Before(start) && date.Before(end) {
+			delete(s.stats, date)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) WithTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	return fn(ctx)
+}
+
+var (
+	day1 = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 = time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+)
+
+// newTestAggregator 区块 100-102 在 day1, 103-104 在 day2, 每个区块一笔交易
+func newTestAggregator() (*Aggregator, *memoryStore) {
+	store := &memoryStore{stats: map[time.Time]models.DailyChainStat{}}
+	for h := uint64(100); h <= 104; h++ {
+		ts := day1.Add(time.Duration(h-100) * time.Hour)
+		if h >= 103 {
+			ts = day2.Add(time.Duration(h-103) * time.Hour)
+		}
+		store.blocks = append(store.blocks, models.Block{BlockHeight: h, BlockHash: "0x" + string(rune('a'+h-100)), GasUsed: 21000, Timestamp: ts})
+		store.trxs = append(store.trxs, models.Transaction{BlockNumber: h, From: "0x8b736035bbda71825e0219f5fe4dfb22c35fbddc"})
+	}
+	a := NewAggregator()
+	a.BatchSize = 2
+	a.store = store
+	return a, store
+}
+
+func TestAggregator_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	a, store := newTestAggregator()
+
+	var runs int
+	for done := false; !done; runs++ {
+		var err error
+		if done, err = a.RunOnce(ctx); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+	}
+	if runs != 3 {
+		t.Errorf("caught up after %d runs, want 3", runs)
+	}
+	if store.cursor.BlockHeight != 104 || store.cursor.BlockHash != "0xe" {
+		t.Errorf("cursor = %+v, want 104 0xe", store.cursor)
+	}
+	if got := store.stats[day1]; got.BlockCount != 3 || got.TransactionCount != 3 || got.GasUsed != 63000 {
+		t.Errorf("day1 stats = %+v", got)
+	}
+	if got := store.stats[day2]; got.BlockCount != 2 || got.TransactionCount != 2 {
+		t.Errorf("day2 stats = %+v", got)
+	}
+
+	// 已经追上最新区块时不再重复累加
+	if done, err := a.RunOnce(ctx); err != nil || !done {
+		t.Errorf("RunOnce() at tip = %v, %v, want done", done, err)
+	}
+	if got := store.stats[day1].BlockCount; got != 3 {
+		t.Errorf("day1 block count = %d after caught up, want 3", got)
+	}
+}
+
+func TestAggregator_RunOnceEmptyRange(t *testing.T) {
+	ctx := context.Background()
+	a, store := newTestAggregator()
+	store.minHeight = 98
+	if _, err := a.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if store.cursor.BlockHeight != 99 || store.cursor.BlockHash != dal.NoBlockHash {
+		t.Fatalf("cursor = %+v, want 99 with placeholder hash", store.cursor)
+	}
+	if _, err := a.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if store.cursor.BlockHeight != 101 {
+		t.Errorf("cursor = %+v, want 101, the empty range must not restart from the min height", store.cursor)
+	}
+}
+
+func TestAggregator_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	a, store := newTestAggregator()
+	for done := false; !done; {
+		var err error
+		if done, err = a.RunOnce(ctx); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+	}
+
+	// 区块 101 被替换为没有交易、gas 更高的区块, 区块 104 之后的数据还没有汇总
+	store.blocks[1] = models.Block{BlockHeight: 101, BlockHash: "0xb2", GasUsed: 50000, Timestamp: day1.Add(time.Hour)}
+	store.trxs = append(store.trxs[:1], store.trxs[2:]...)
+	store.blocks = append(store.blocks, models.Block{BlockHeight: 105, BlockHash: "0xf", GasUsed: 21000, Timestamp: day2.Add(3 * time.Hour)})
+
+	if err := a.Rebuild(ctx, day1.Add(time.Hour), day1.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if got := store.stats[day1]; got.BlockCount != 3 || got.TransactionCount != 2 || got.GasUsed != 92000 {
+		t.Errorf("day1 stats = %+v, want 3 blocks, 2 transactions, 92000 gas", got)
+	}
+
+	if err := a.Rebuild(ctx, day2, day2.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if got := store.stats[day2]; got.BlockCount != 2 {
+		t.Errorf("day2 block count = %d, want 2, blocks after the cursor are left to RunOnce", got.BlockCount)
+	}
+
+	if err := a.Rebuild(ctx, day2, day2); err == nil {
+		t.Error("Rebuild() with an empty range expected error")
+	}
+}
+
+func TestAggregator_BlockHook(t *testing.T) {
+	ctx := context.Background()
+	a, store := newTestAggregator()
+	for done := false; !done; {
+		var err error
+		if done, err = a.RunOnce(ctx); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+	}
+
+	// 没有替换旧区块时不重建
+	store.blocks[1].GasUsed = 0
+	if err := a.BlockHook(ctx, &chain.BlockData{Block: store.blocks[1]}); err != nil {
+		t.Fatalf("BlockHook() error = %v", err)
+	}
+	if got := store.stats[day1].GasUsed; got != 63000 {
+		t.Errorf("day1 gas = %d, want 63000 without a replaced block", got)
+	}
+
+	// 区块 101 被重新索引为没有交易的区块, 新区块已在同一事务中写入
+	old := store.blocks[1]
+	store.blocks[1] = models.Block{BlockHeight: 101, BlockHash: "0xb2", GasUsed: 50000, Timestamp: day1.Add(time.Hour)}
+	store.trxs = append(store.trxs[:1], store.trxs[2:]...)
+	data := &chain.BlockData{Block: store.blocks[1], Reindexed: true, Replaced: &chain.ReplacedBlock{Block: old}}
+	if err := a.BlockHook(ctx, data); err != nil {
+		t.Fatalf("BlockHook() error = %v", err)
+	}
+	if got := store.stats[day1]; got.BlockCount != 3 || got.TransactionCount != 2 || got.GasUsed != 92000 {
+		t.Errorf("day1 stats = %+v, want 3 blocks, 2 transactions, 92000 gas", got)
+	}
+	if got := store.stats[day2]; got.BlockCount != 2 || got.TransactionCount != 2 {
+		t.Errorf("day2 stats = %+v, want unchanged", got)
+	}
+}
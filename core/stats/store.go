@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Store 汇总任务读写的数据, 默认使用 dal, 测试中替换为内存实现
+type Store interface {
+	GetCursor(ctx context.Context) (*models.SyncCursor, error)
+	UpsertCursor(ctx context.Context, blockHeight uint64, blockHash string) error
+	GetMinHeight(ctx context.Context) (uint64, error)
+	GetMaxHeight(ctx context.Context) (uint64, error)
+	GetHeightRangeByTime(ctx context.Context, start, end time.Time) (uint64, uint64, bool, error)
+	// Load 读取 [from, to] 区间内的区块、交易和日志
+	Load(ctx context.Context, from, to uint64) ([]models.Block, []models.Transaction, []models.Event, error)
+	// Save 把增量统计累加到每日统计表
+	Save(ctx context.Context, rollup Rollup) error
+	DeleteByDateRange(ctx context.Context, start, end time.Time) error
+	// WithTx 在同一个事务中执行 fn
+	WithTx(ctx context.Context, fn func(txCtx context.Context) error) error
+}
+
+type dalStore struct{}
+
+func (dalStore) GetCursor(ctx context.Context) (*models.SyncCursor, error) {
+	return dal.Cursor.Get(ctx, CursorName)
+}
+
+func (dalStore) UpsertCursor(ctx context.Context, blockHeight uint64, blockHash string) error {
+	return dal.Cursor.Upsert(ctx, CursorName, blockHeight, blockHash)
+}
+
+func (dalStore) GetMinHeight(ctx context.Context) (uint64, error) {
+	return dal.Block.GetMinHeight(ctx)
+}
+
+func (dalStore) GetMaxHeight(ctx context.Context) (uint64, error) {
+	return dal.Block.GetMaxHeight(ctx)
+}
+
+func (dalStore) GetHeightRangeByTime(ctx context.Context, start, end time.Time) (uint64, uint64, bool, error) {
+	return dal.Block.GetHeightRangeByTime(ctx, start, end)
+}
+
+func (dalStore) Load(ctx context.Context, from, to uint64) ([]models.Block, []models.Transaction, []models.Event, error) {
+	blocks, err := dal.Block.GetByHeightRange(ctx, from, to)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "get blocks")
+	}
+	trxs, err := dal.Transaction.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "get transactions")
+	}
+	events, err := dal.Event.GetByBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "get events")
+	}
+	return blocks, trxs, events, nil
+}
+
+func (dalStore) Save(ctx context.Context, rollup Rollup) error {
+	if err := dal.Stats.AddDailyChainStats(ctx, rollup.ChainStats); err != nil {
+		return errors.Wrap(err, "add daily chain stats")
+	}
+	if err := dal.Stats.AddDailyTokenStats(ctx, rollup.TokenStats); err != nil {
+		return errors.Wrap(err, "add daily token stats")
+	}
+	if err := dal.Stats.InsertActiveAddresses(ctx, rollup.ActiveAddresses); err != nil {
+		return errors.Wrap(err, "insert active addresses")
+	}
+	for _, date := range rollup.Dates() {
+		if err := dal.Stats.RefreshActiveAddressCount(ctx, date); err != nil {
+			return errors.Wrap(err, "refresh active address count")
+		}
+	}
+	return nil
+}
+
+func (dalStore) DeleteByDateRange(ctx context.Context, start, end time.Time) error {
+	return dal.Stats.DeleteByDateRange(ctx, start, end)
+}
+
+func (dalStore) WithTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	return dal.WithTx(ctx, fn)
+}
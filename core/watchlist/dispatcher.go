@@ -0,0 +1,180 @@
+package watchlist
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+const (
+	HeaderSignature = "X-Metago-Signature"
+	HeaderTimestamp = "X-Metago-Timestamp"
+	HeaderDelivery  = "X-Metago-Delivery"
+	HeaderEvent     = "X-Metago-Event"
+)
+
+// Sign 计算 webhook 签名, 签名内容为 "timestamp.body"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 接收方校验 webhook 签名
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Dispatcher 定时推送待推送的 webhook, 失败按指数退避重试, 超过次数进入死信表
+type Dispatcher struct {
+	Client      *http.Client
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	now func() time.Time
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		Interval:    time.Second,
+		BatchSize:   100,
+		MaxAttempts: 8,
+		BaseBackoff: 5 * time.Second,
+		MaxBackoff:  time.Hour,
+		now:         time.Now,
+	}
+}
+
+// Start 按 Interval 推送, 直到 ctx 结束
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := d.RunOnce(ctx); err != nil {
+				log.WithField("err", err).Error("webhook dispatcher run failed")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce 推送一批到期的消息, 返回处理的数量
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := dal.Watchlist.GetDueDeliveries(ctx, d.now(), d.BatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "get due deliveries")
+	}
+
+	watchlists := make(map[uint]*models.Watchlist)
+	for _, delivery := range deliveries {
+		watchlist, ok := watchlists[delivery.WatchlistId]
+		if !ok {
+			watchlist, err = dal.Watchlist.GetById(ctx, delivery.WatchlistId)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, errors.Wrapf(err, "get watchlist %d", delivery.WatchlistId)
+			}
+			watchlists[delivery.WatchlistId] = watchlist
+		}
+
+		if watchlist == nil || !watchlist.Enabled {
+			// 关注已删除或停用, 不再推送, 直接进入死信表
+			delivery.LastError = "watchlist removed or disabled"
+			if err := dal.WithTx(ctx, func(txCtx context.Context) error {
+				return dal.Watchlist.MoveToDeadLetter(txCtx, delivery, "")
+			}); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := d.deliver(ctx, watchlist, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, watchlist *models.Watchlist, delivery models.WebhookDelivery) error {
+	delivery.Attempts++
+	sendErr := d.Send(ctx, watchlist.WebhookUrl, watchlist.Secret, delivery)
+	if sendErr == nil {
+		return dal.Watchlist.MarkDelivered(ctx, delivery.ID, delivery.Attempts, d.now())
+	}
+
+	delivery.LastError = sendErr.Error()
+	log.WithField("delivery", delivery.ID).WithField("attempts", delivery.Attempts).
+		WithField("err", sendErr).Warn("webhook delivery failed")
+	if delivery.Attempts >= d.MaxAttempts {
+		return dal.WithTx(ctx, func(txCtx context.Context) error {
+			return dal.Watchlist.MoveToDeadLetter(txCtx, delivery, watchlist.WebhookUrl)
+		})
+	}
+
+	return dal.Watchlist.MarkRetry(ctx, delivery.ID, delivery.Attempts, d.now().Add(d.Backoff(delivery.Attempts)), delivery.LastError)
+}
+
+// Backoff 第 attempts 次失败之后的等待时间
+func (d *Dispatcher) Backoff(attempts int) time.Duration {
+	backoff := d.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= d.MaxBackoff {
+			return d.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// Send 推送一条消息, 非 2xx 响应视为失败
+func (d *Dispatcher) Send(ctx context.Context, url, secret string, delivery models.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	timestamp := d.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	if delivery.Model != nil {
+		req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
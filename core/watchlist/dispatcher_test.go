@@ -0,0 +1,82 @@
+package watchlist
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+func TestDispatcher_Send(t *testing.T) {
+	secret := "s3cret"
+	payload := `{"event_type":"transaction","block_number":100}`
+
+	var gotBody []byte
+	var gotHeader http.Header
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher()
+	d.now = func() time.Time { return time.Unix(1700000000, 0) }
+	delivery := models.WebhookDelivery{
+		Model:     &gorm.Model{ID: 7},
+		EventType: models.WebhookEventTransaction,
+		Payload:   payload,
+	}
+
+	if err := d.Send(context.Background(), server.URL, secret, delivery); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if string(gotBody) != payload {
+		t.Errorf("Send() body = %s, want %s", gotBody, payload)
+	}
+	if gotHeader.Get(HeaderDelivery) != "7" || gotHeader.Get(HeaderEvent) != models.WebhookEventTransaction {
+		t.Errorf("Send() unexpected headers %v", gotHeader)
+	}
+	timestamp, _ := strconv.ParseInt(gotHeader.Get(HeaderTimestamp), 10, 64)
+	if !Verify(secret, timestamp, gotBody, gotHeader.Get(HeaderSignature)) {
+		t.Errorf("Send() signature %s does not verify", gotHeader.Get(HeaderSignature))
+	}
+	if Verify("other", timestamp, gotBody, gotHeader.Get(HeaderSignature)) {
+		t.Errorf("Verify() accepted signature with wrong secret")
+	}
+
+	status = http.StatusInternalServerError
+	if err := d.Send(context.Background(), server.URL, secret, delivery); err == nil {
+		t.Errorf("Send() expected error on status %d", status)
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher()
+	d.BaseBackoff = time.Second
+	d.MaxBackoff = 10 * time.Second
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 30, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := d.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
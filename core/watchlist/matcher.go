@@ -0,0 +1,104 @@
+package watchlist
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+// Payload webhook 推送的JSON内容
+type Payload struct {
+	WatchlistId   uint                  `json:"watchlist_id"`
+	Address       string                `json:"address"`
+	EventType     string                `json:"event_type"`
+	BlockNumber   uint64                `json:"block_number"`
+	BlockHash     string                `json:"block_hash"`
+	Timestamp     int64                 `json:"timestamp"`
+	Transaction   *models.Transaction   `json:"transaction,omitempty"`
+	TokenTransfer *models.TokenTransfer `json:"token_transfer,omitempty"`
+}
+
+// Match 找出区块中命中关注地址的交易和Token转移, 生成待推送消息
+func Match(watchlists []models.Watchlist, block models.Block, trxs []models.Transaction,
+	transfers []models.TokenTransfer, now time.Time) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	for _, watchlist := range watchlists {
+		if !watchlist.Enabled {
+			continue
+		}
+
+		if watchlist.TokenContractAddress == "" {
+			for i := range trxs {
+				trx := trxs[i]
+				if !sameAddress(watchlist.Address, trx.From, trx.To, trx.Contract) {
+					continue
+				}
+
+				delivery, err := newDelivery(watchlist, block, now, models.WebhookEventTransaction, trx.TxHash, 0,
+					Payload{Transaction: &trx})
+				if err != nil {
+					return nil, err
+				}
+				deliveries = append(deliveries, delivery)
+			}
+		}
+
+		for i := range transfers {
+			transfer := transfers[i]
+			if watchlist.TokenContractAddress != "" &&
+				!strings.EqualFold(watchlist.TokenContractAddress, transfer.TokenContractAddress) {
+				continue
+			}
+			if !sameAddress(watchlist.Address, transfer.FromAddress, transfer.ToAddress) {
+				continue
+			}
+
+			delivery, err := newDelivery(watchlist, block, now, models.WebhookEventTokenTransfer, transfer.TransactionHash,
+				transfer.LogIndex, Payload{TokenTransfer: &transfer})
+			if err != nil {
+				return nil, err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries, nil
+}
+
+func newDelivery(watchlist models.Watchlist, block models.Block, now time.Time, eventType, txHash string,
+	logIndex uint, payload Payload) (models.WebhookDelivery, error) {
+	payload.WatchlistId = watchlist.ID
+	payload.Address = watchlist.Address
+	payload.EventType = eventType
+	payload.BlockNumber = block.BlockHeight
+	payload.BlockHash = block.BlockHash
+	payload.Timestamp = block.Timestamp.Unix()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	return models.WebhookDelivery{
+		WatchlistId:   watchlist.ID,
+		EventType:     eventType,
+		BlockNumber:   block.BlockHeight,
+		BlockHash:     block.BlockHash,
+		TxHash:        txHash,
+		LogIndex:      logIndex,
+		Payload:       string(body),
+		Status:        models.WebhookStatusPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+func sameAddress(address string, candidates ...string) bool {
+	for _, candidate := range candidates {
+		if candidate != "" && strings.EqualFold(address, candidate) {
+			return true
+		}
+	}
+	return false
+}
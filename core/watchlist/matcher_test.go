@@ -0,0 +1,90 @@
+package watchlist
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/traitmeta/metago/core/models"
+)
+
+func TestMatch(t *testing.T) {
+	hot := "0x8b736035BBDA71825e0219f5fe4dFB22c35fbddc"
+	other := "0x4D8834907CEE521D08A3fC77478A0fbc7c94FD0a"
+	usdc := "0xbCa5858dfd00cEa2eb85e2AB678a5867a18A24c4"
+	dai := "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	now := time.Unix(1700000000, 0)
+
+	block := models.Block{BlockHeight: 100, BlockHash: "0xabc", Timestamp: now}
+	trxs := []models.Transaction{
+		{BlockNumber: 100, TxHash: "0x01", From: "0x8b736035bbda71825e0219f5fe4dfb22c35fbddc", To: other},
+		{BlockNumber: 100, TxHash: "0x02", From: other, Contract: usdc},
+	}
+	transfers := []models.TokenTransfer{
+		{TransactionHash: "0x02", LogIndex: 3, FromAddress: other, ToAddress: hot, TokenContractAddress: usdc, Amount: big.NewInt(10)},
+		{TransactionHash: "0x02", LogIndex: 4, FromAddress: other, ToAddress: hot, TokenContractAddress: dai, Amount: big.NewInt(20)},
+	}
+
+	tests := []struct {
+		name       string
+		watchlists []models.Watchlist
+		want       []string
+	}{
+		{
+			name:       "address matches transactions and all token transfers",
+			watchlists: []models.Watchlist{{Model: &gorm.Model{ID: 1}, Address: hot, Enabled: true}},
+			want:       []string{"transaction:0x01:0", "token_transfer:0x02:3", "token_transfer:0x02:4"},
+		},
+		{
+			name:       "token filter only matches transfers of that token",
+			watchlists: []models.Watchlist{{Model: &gorm.Model{ID: 2}, Address: hot, TokenContractAddress: dai, Enabled: true}},
+			want:       []string{"token_transfer:0x02:4"},
+		},
+		{
+			name:       "contract call matches watched contract",
+			watchlists: []models.Watchlist{{Model: &gorm.Model{ID: 3}, Address: usdc, Enabled: true}},
+			want:       []string{"transaction:0x02:0"},
+		},
+		{
+			name:       "disabled watchlist is skipped",
+			watchlists: []models.Watchlist{{Model: &gorm.Model{ID: 4}, Address: hot, Enabled: false}},
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries, err := Match(tt.watchlists, block, trxs, transfers, now)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+
+			var got []string
+			for _, delivery := range deliveries {
+				got = append(got, delivery.EventType+":"+delivery.TxHash+":"+strconv.FormatUint(uint64(delivery.LogIndex), 10))
+
+				var payload Payload
+				if err := json.Unmarshal([]byte(delivery.Payload), &payload); err != nil {
+					t.Fatalf("unmarshal payload error = %v", err)
+				}
+				if payload.WatchlistId != tt.watchlists[0].ID || payload.BlockNumber != 100 || payload.EventType != delivery.EventType {
+					t.Errorf("unexpected payload %+v", payload)
+				}
+				if delivery.Status != models.WebhookStatusPending || !delivery.NextAttemptAt.Equal(now) {
+					t.Errorf("unexpected delivery state %+v", delivery)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Match() got = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
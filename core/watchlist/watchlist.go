@@ -0,0 +1,91 @@
+package watchlist
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/models"
+)
+
+// HookName 在 chain 中注册的区块钩子名称
+const HookName = "watchlist"
+
+// Register 添加一个关注地址, tokenContractAddress 为空时关注该地址的所有交易和Token转移
+func Register(ctx context.Context, name, address, tokenContractAddress, webhookUrl, secret string) (*models.Watchlist, error) {
+	if !ethcommon.IsHexAddress(address) {
+		return nil, errors.Errorf("invalid address %q", address)
+	}
+	if tokenContractAddress != "" && !ethcommon.IsHexAddress(tokenContractAddress) {
+		return nil, errors.Errorf("invalid token contract address %q", tokenContractAddress)
+	}
+	u, err := url.Parse(webhookUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("invalid webhook url %q", webhookUrl)
+	}
+	if secret == "" {
+		return nil, errors.New("webhook secret is required")
+	}
+
+	watchlist := &models.Watchlist{
+		Name:       name,
+		Address:    ethcommon.HexToAddress(address).Hex(),
+		WebhookUrl: webhookUrl,
+		Secret:     secret,
+		Enabled:    true,
+	}
+	if tokenContractAddress != "" {
+		watchlist.TokenContractAddress = ethcommon.HexToAddress(tokenContractAddress).Hex()
+	}
+
+	if err := dal.Watchlist.Insert(ctx, watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// Replay 将死信重新放回推送队列
+func Replay(ctx context.Context, deadLetterId uint) error {
+	deadLetter, err := dal.Watchlist.GetDeadLetter(ctx, deadLetterId)
+	if err != nil {
+		return err
+	}
+	if deadLetter.ReplayedAt != nil {
+		return errors.Errorf("dead letter %d already replayed", deadLetterId)
+	}
+
+	return dal.WithTx(ctx, func(txCtx context.Context) error {
+		return dal.Watchlist.ReplayDeadLetter(txCtx, *deadLetter, time.Now())
+	})
+}
+
+// BlockHook 在区块写库的事务中匹配关注地址, 推送消息与区块一起提交, 由 Dispatcher 在提交后发送
+func BlockHook(ctx context.Context, data *chain.BlockData) error {
+	watchlists, err := dal.Watchlist.ListEnabled(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list watchlists")
+	}
+	if len(watchlists) == 0 {
+		return nil
+	}
+
+	deliveries, err := Match(watchlists, data.Block, data.Transactions, data.TokenTransfers, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "match watchlists")
+	}
+
+	return dal.Watchlist.InsertDeliveries(ctx, deliveries)
+}
+
+// Setup 注册区块钩子并启动推送任务
+func Setup(ctx context.Context) *Dispatcher {
+	chain.RegisterBlockHook(HookName, BlockHook)
+	dispatcher := NewDispatcher()
+	go dispatcher.Start(ctx)
+	return dispatcher
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/traitmeta/gotos/lib/db"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/config/setting"
+	"github.com/traitmeta/metago/core/chain"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/ens"
+	"github.com/traitmeta/metago/core/mempool"
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/core/pricing"
+	"github.com/traitmeta/metago/core/sink"
+	"github.com/traitmeta/metago/core/stats"
+	"github.com/traitmeta/metago/core/watchlist"
+	"github.com/traitmeta/metago/pkg/metrics"
+)
+
+// setupDB 连接数据库并初始化 dal, 退出时关闭连接
+func (a *app) setupDB() {
+	db.SetupDBEngine(*config.DB)
+	dal.Init()
+	a.OnStop(func() {
+		if sqlDB, err := db.DBEngine.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+// setupEth 连接 EVM 节点, 退出时关闭连接
+func (a *app) setupEth() {
+	config.SetupEthClient()
+	a.OnStop(config.EthRpcClient.Close)
+}
+
+func runEthIndex(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("eth index")
+	migrate := fs.Bool("migrate", true, "migrate the database schema before indexing")
+	fs.Parse(args)
+
+	a.setupDB()
+	if *migrate {
+		if err := models.MigrateDb(); err != nil {
+			return errors.Wrap(err, "migrate")
+		}
+	}
+	a.setupEth()
+
+	log.Println(setting.RedactUrl(config.BlockChain.RpcUrl))
+	if _, err := metrics.Setup(ctx, config.Metrics); err != nil {
+		return errors.Wrap(err, "metrics.Setup")
+	}
+	if err := chain.InitBlock(ctx); err != nil {
+		if ctx.Err() != nil {
+			log.Println("interrupted before sync started")
+			return nil
+		}
+		return errors.Wrap(err, "chain.InitBlock")
+	}
+	aggregator := stats.Setup()
+	a.Go(func() { aggregator.Start(ctx) })
+	watchlist.Setup(ctx)
+	ens.Setup()
+	if _, err := sink.Setup(ctx, config.Sink); err != nil {
+		return errors.Wrap(err, "sink.Setup")
+	}
+	if _, err := mempool.Setup(ctx, config.Mempool, config.BlockChain); err != nil {
+		return errors.Wrap(err, "mempool.Setup")
+	}
+	if _, err := pricing.Setup(ctx, config.Pricing); err != nil {
+		return errors.Wrap(err, "pricing.Setup")
+	}
+
+	state, err := chain.SyncTask(ctx)
+	log.Println("sync stopped:", state)
+	return err
+}
+
+func runEthBackfill(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("eth backfill")
+	from := fs.Uint64("from", 0, "first block height to process")
+	to := fs.Uint64("to", 0, "last block height to process")
+	fs.Parse(args)
+	if *to < *from || *to == 0 {
+		fs.Usage()
+		return errors.New("-to must be set and not below -from")
+	}
+
+	a.setupDB()
+	a.setupEth()
+	state, err := chain.Backfill(ctx, *from, *to)
+	log.Println("backfill stopped:", state)
+	return err
+}
+
+func runStatsRebuild(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("stats rebuild")
+	from := fs.String("from", "", "first day to rebuild, as 2006-01-02 in UTC")
+	to := fs.String("to", "", "last day to rebuild, defaults to -from")
+	fs.Parse(args)
+	if *to == "" {
+		*to = *from
+	}
+	start, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		fs.Usage()
+		return errors.Wrap(err, "-from")
+	}
+	end, err := time.Parse(time.DateOnly, *to)
+	if err != nil {
+		fs.Usage()
+		return errors.Wrap(err, "-to")
+	}
+
+	a.setupDB()
+	if err := stats.NewAggregator().Rebuild(ctx, start, end.AddDate(0, 0, 1)); err != nil {
+		return err
+	}
+	log.Printf("daily stats rebuilt from %s to %s", *from, *to)
+	return nil
+}
+
+func runMigrate(ctx context.Context, a *app, args []string) error {
+	newFlagSet("migrate").Parse(args)
+	a.setupDB()
+	if err := models.MigrateDb(); err != nil {
+		return err
+	}
+	log.Println("database migrated")
+	return nil
+}
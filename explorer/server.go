@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/admin"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/explorer"
+	"github.com/traitmeta/metago/core/search"
+)
+
+func init() {
+	config.SetupConfig()
+	db.SetupDBEngine(*config.DB)
+	dal.Init()
+}
+
+func main() {
+	addr := config.ListenAddr(config.Server.Explorer)
+
+	srv := explorer.NewServer(explorer.DefaultPrefix)
+	srv.Register(search.NewSearcher().Routes()...)
+	http.Handle(explorer.DefaultPrefix+"/", srv)
+
+	if config.Admin != nil && len(config.Admin.Keys) > 0 {
+		adminSrv, err := admin.NewServer(admin.DefaultPrefix, config.Admin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		http.Handle(admin.DefaultPrefix+"/", adminSrv)
+		// 本地存储的图标由同一个服务提供
+		if icons, ok := adminSrv.Icons().(*admin.LocalIconStore); ok && strings.HasPrefix(icons.BaseUrl, "/") {
+			prefix := strings.TrimSuffix(icons.BaseUrl, "/") + "/"
+			http.Handle(prefix, http.StripPrefix(prefix, icons.Handler()))
+		}
+		log.Printf("serving admin API under %s/", admin.DefaultPrefix)
+	}
+
+	log.Printf("serving explorer API on %s under %s/, OpenAPI at %s/openapi.json", addr, explorer.DefaultPrefix, explorer.DefaultPrefix)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/export"
+)
+
+var (
+	dir           = flag.String("dir", "", "output directory, holds manifest.json and one sub directory per table")
+	format        = flag.String("format", "parquet", "output format, parquet or csv")
+	codec         = flag.String("codec", "snappy", "parquet compression, none, snappy or gzip")
+	tables        = flag.String("tables", "", "comma separated tables to export, all when empty")
+	from          = flag.Uint64("from", 0, "first block height when the directory has no manifest")
+	to            = flag.Uint64("to", 0, "last block height to export, defaults to the tip minus confirmations")
+	partition     = flag.Uint64("partition", 10000, "blocks per partition file")
+	chunk         = flag.Uint64("chunk", 100, "blocks loaded from the database per batch")
+	confirmations = flag.Uint64("confirmations", 12, "blocks kept behind the tip")
+	follow        = flag.Bool("follow", false, "keep exporting new partitions behind the tip")
+	interval      = flag.Duration("interval", time.Minute, "poll interval with -follow")
+)
+
+func init() {
+	config.SetupConfig()
+	db.SetupDBEngine(*config.DB)
+	dal.Init()
+}
+
+func main() {
+	flag.Parse()
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var names []string
+	if *tables != "" {
+		names = strings.Split(*tables, ",")
+	}
+	selected, err := export.LookupTables(names)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	e := export.NewExporter(*dir, export.Format(*format))
+	e.Codec = export.Codec(*codec)
+	e.Tables = selected
+	e.From = *from
+	e.PartitionSize = *partition
+	e.ChunkSize = *chunk
+	e.Confirmations = *confirmations
+	e.Interval = *interval
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *follow {
+		e.Start(ctx)
+		return
+	}
+
+	last := *to
+	if last == 0 {
+		tip, err := e.Tip(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if tip < e.Confirmations {
+			log.Fatalf("tip %d is below %d confirmations", tip, e.Confirmations)
+		}
+		last = tip - e.Confirmations
+	}
+	n, err := e.RunOnce(ctx, last)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("exported %d partitions up to block %d into %s", n, last, *dir)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/fixture"
+)
+
+var (
+	height = flag.Uint64("height", 0, "block height to record")
+	output = flag.String("out", "", "fixture file to write, defaults to block-<height>.json")
+	rpcUrl = flag.String("rpc", "", "http rpc url to record from, defaults to BlockChain.RpcUrl")
+	replay = flag.String("replay", "", "replay this fixture offline and print the parsed block data")
+)
+
+func init() {
+	config.SetupConfig()
+}
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+
+	if *replay != "" {
+		f, err := fixture.Load(*replay)
+		if err != nil {
+			log.Fatal(err)
+		}
+		data, err := fixture.Replay(ctx, f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(data); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *height == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	url := *rpcUrl
+	if url == "" {
+		url = config.BlockChain.RpcUrl
+	}
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("block-%d.json", *height)
+	}
+
+	f, data, err := fixture.Record(ctx, url, *height, config.BlockChain.Tracer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Save(path); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("recorded block %d (%s): %d rpc calls, %d transactions, %d events, %d token transfers to %s",
+		f.Height, f.Hash, len(f.Calls), len(data.Transactions), len(data.Events), len(data.TokenTransfers), path)
+}
@@ -0,0 +1,43 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/graphql/graph/model"
+)
+
+// TokenAllowances is the resolver for the tokenAllowances field.
+func (r *queryResolver) TokenAllowances(ctx context.Context, owner *string, spender *string, token *string, unlimitedOnly *bool, includeRevoked *bool, offset *int, limit *int) ([]*model.TokenAllowance, error) {
+	o, l := pageArgs(offset, limit)
+	allowances, err := dal.Approval.ListAllowances(ctx, addressArg(owner), addressArg(spender), addressArg(token),
+		derefBool(unlimitedOnly), derefBool(includeRevoked), o, l)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.TokenAllowance, 0, len(allowances))
+	for i := range allowances {
+		res = append(res, toTokenAllowance(&allowances[i]))
+	}
+	return res, nil
+}
+
+// OperatorApprovals is the resolver for the operatorApprovals field.
+func (r *queryResolver) OperatorApprovals(ctx context.Context, owner *string, operator *string, collection *string, includeRevoked *bool, offset *int, limit *int) ([]*model.OperatorApproval, error) {
+	o, l := pageArgs(offset, limit)
+	approvals, err := dal.Approval.ListOperatorApprovals(ctx, addressArg(owner), addressArg(operator), addressArg(collection),
+		derefBool(includeRevoked), o, l)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.OperatorApproval, 0, len(approvals))
+	for i := range approvals {
+		res = append(res, toOperatorApproval(&approvals[i]))
+	}
+	return res, nil
+}
@@ -0,0 +1,178 @@
+package graph
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/core/search"
+	"github.com/traitmeta/metago/graphql/graph/model"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// pageArgs 处理分页参数, limit 超出范围时使用默认值
+func pageArgs(offset, limit *int) (int, int) {
+	o, l := 0, defaultLimit
+	if offset != nil && *offset > 0 {
+		o = *offset
+	}
+	if limit != nil && *limit > 0 && *limit <= maxLimit {
+		l = *limit
+	}
+	return o, l
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefBool(b *bool) bool {
+	return b != nil && *b
+}
+
+func derefInt(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// addressArg 地址按 checksum 格式入库, 查询参数统一转换
+func addressArg(s *string) string {
+	if s == nil || !common.IsHexAddress(*s) {
+		return derefString(s)
+	}
+	return common.HexToAddress(*s).Hex()
+}
+
+// optString 空字符串返回 nil, 用于可空字段
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optInt 零值返回 nil, 用于可空字段
+func optInt(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+func toUserOperation(u *models.UserOperation) *model.UserOperation {
+	return &model.UserOperation{
+		UserOpHash:        u.UserOpHash,
+		Sender:            u.Sender,
+		Paymaster:         u.Paymaster,
+		Nonce:             bigString(u.Nonce),
+		Success:           u.Success,
+		ActualGasCost:     bigString(u.ActualGasCost),
+		ActualGasUsed:     bigString(u.ActualGasUsed),
+		Factory:           u.Factory,
+		RevertReason:      u.RevertReason,
+		EntryPoint:        u.EntryPoint,
+		EntryPointVersion: u.EntryPointVersion,
+		Bundler:           u.Bundler,
+		TransactionHash:   u.TransactionHash,
+		BlockNumber:       int(u.BlockNumber),
+		BlockHash:         u.BlockHash,
+		LogIndex:          int(u.LogIndex),
+	}
+}
+
+func toTokenAllowance(a *models.TokenAllowance) *model.TokenAllowance {
+	return &model.TokenAllowance{
+		Token:           a.Token,
+		Owner:           a.Owner,
+		Spender:         a.Spender,
+		Amount:          bigString(a.Amount),
+		Unlimited:       a.Unlimited,
+		TransactionHash: a.TransactionHash,
+		BlockNumber:     int(a.BlockNumber),
+		BlockHash:       a.BlockHash,
+		LogIndex:        int(a.LogIndex),
+	}
+}
+
+func toOperatorApproval(a *models.OperatorApproval) *model.OperatorApproval {
+	return &model.OperatorApproval{
+		Collection:      a.Collection,
+		Owner:           a.Owner,
+		Operator:        a.Operator,
+		Approved:        a.Approved,
+		TransactionHash: a.TransactionHash,
+		BlockNumber:     int(a.BlockNumber),
+		BlockHash:       a.BlockHash,
+		LogIndex:        int(a.LogIndex),
+	}
+}
+
+func toTransaction(t *models.Transaction) *model.Transaction {
+	return &model.Transaction{
+		Hash:              t.TxHash,
+		BlockNumber:       int(t.BlockNumber),
+		BlockHash:         t.BlockHash,
+		TransactionIndex:  int(t.TxIndex),
+		From:              t.From,
+		To:                t.To,
+		Contract:          t.Contract,
+		ContractCreation:  t.ContractCreation,
+		Value:             t.Value,
+		Nonce:             int(t.Nonce),
+		Gas:               int(t.Gas),
+		GasUsed:           int(t.GasUsed),
+		EffectiveGasPrice: t.EffectiveGasPrice,
+		Status:            int(t.Status),
+		RevertReason:      t.RevertReason,
+	}
+}
+
+func toPendingTransaction(t *models.PendingTransaction) *model.PendingTransaction {
+	return &model.PendingTransaction{
+		Hash:        t.TxHash,
+		From:        t.From,
+		Nonce:       int(t.Nonce),
+		To:          t.To,
+		Value:       t.Value,
+		Type:        int(t.Type),
+		Gas:         int(t.Gas),
+		GasPrice:    t.GasPrice,
+		GasTipCap:   t.GasTipCap,
+		FirstSeen:   t.FirstSeen.Format(time.RFC3339),
+		Status:      t.Status,
+		BlockNumber: int(t.BlockNumber),
+		ReplacedBy:  t.ReplacedBy,
+	}
+}
+
+func toSearchResult(r *search.Result) *model.SearchResult {
+	return &model.SearchResult{
+		Type:        r.Type,
+		Address:     optString(r.Address),
+		Hash:        optString(r.Hash),
+		Height:      optInt(int(r.Height)),
+		Name:        optString(r.Name),
+		Symbol:      optString(r.Symbol),
+		HolderCount: optInt(int(r.HolderCount)),
+		Verified:    r.Verified,
+		Score:       r.Score,
+	}
+}
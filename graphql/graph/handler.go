@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/playground"
+
+	"github.com/traitmeta/metago/graphql/graph/generated"
+)
+
+// NewHandler GraphQL 接口和 playground 的路由, 接口路径为 /query
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	mux.Handle("/query", handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}})))
+	return mux
+}
@@ -7,7 +7,102 @@ type NewTodo struct {
 	UserID string `json:"userId"`
 }
 
+type OperatorApproval struct {
+	Collection      string `json:"collection"`
+	Owner           string `json:"owner"`
+	Operator        string `json:"operator"`
+	Approved        bool   `json:"approved"`
+	TransactionHash string `json:"transactionHash"`
+	BlockNumber     int    `json:"blockNumber"`
+	BlockHash       string `json:"blockHash"`
+	LogIndex        int    `json:"logIndex"`
+}
+
+type PendingTransaction struct {
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	Nonce     int    `json:"nonce"`
+	To        string `json:"to"`
+	Value     string `json:"value"`
+	Type      int    `json:"type"`
+	Gas       int    `json:"gas"`
+	GasPrice  string `json:"gasPrice"`
+	GasTipCap string `json:"gasTipCap"`
+	FirstSeen string `json:"firstSeen"`
+	// pending, mined, replaced or dropped
+	Status      string `json:"status"`
+	BlockNumber int    `json:"blockNumber"`
+	ReplacedBy  string `json:"replacedBy"`
+}
+
+type SearchResult struct {
+	Type        string  `json:"type"`
+	Address     *string `json:"address"`
+	Hash        *string `json:"hash"`
+	Height      *int    `json:"height"`
+	Name        *string `json:"name"`
+	Symbol      *string `json:"symbol"`
+	HolderCount *int    `json:"holderCount"`
+	Verified    bool    `json:"verified"`
+	Score       float64 `json:"score"`
+}
+
+type SearchSuggestion struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+type TokenAllowance struct {
+	Token           string `json:"token"`
+	Owner           string `json:"owner"`
+	Spender         string `json:"spender"`
+	Amount          string `json:"amount"`
+	Unlimited       bool   `json:"unlimited"`
+	TransactionHash string `json:"transactionHash"`
+	BlockNumber     int    `json:"blockNumber"`
+	BlockHash       string `json:"blockHash"`
+	LogIndex        int    `json:"logIndex"`
+}
+
+type Transaction struct {
+	Hash              string `json:"hash"`
+	BlockNumber       int    `json:"blockNumber"`
+	BlockHash         string `json:"blockHash"`
+	TransactionIndex  int    `json:"transactionIndex"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	Contract          string `json:"contract"`
+	ContractCreation  bool   `json:"contractCreation"`
+	Value             string `json:"value"`
+	Nonce             int    `json:"nonce"`
+	Gas               int    `json:"gas"`
+	GasUsed           int    `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+	Status            int    `json:"status"`
+	RevertReason      string `json:"revertReason"`
+}
+
 type User struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
+
+type UserOperation struct {
+	UserOpHash        string `json:"userOpHash"`
+	Sender            string `json:"sender"`
+	Paymaster         string `json:"paymaster"`
+	Nonce             string `json:"nonce"`
+	Success           bool   `json:"success"`
+	ActualGasCost     string `json:"actualGasCost"`
+	ActualGasUsed     string `json:"actualGasUsed"`
+	Factory           string `json:"factory"`
+	RevertReason      string `json:"revertReason"`
+	EntryPoint        string `json:"entryPoint"`
+	EntryPointVersion string `json:"entryPointVersion"`
+	Bundler           string `json:"bundler"`
+	TransactionHash   string `json:"transactionHash"`
+	BlockNumber       int    `json:"blockNumber"`
+	BlockHash         string `json:"blockHash"`
+	LogIndex          int    `json:"logIndex"`
+}
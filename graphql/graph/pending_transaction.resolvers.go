@@ -0,0 +1,41 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/graphql/graph/model"
+	"gorm.io/gorm"
+)
+
+// PendingTransaction is the resolver for the pendingTransaction field.
+func (r *queryResolver) PendingTransaction(ctx context.Context, hash string) (*model.PendingTransaction, error) {
+	tx, err := dal.PendingTransaction.GetByHash(ctx, strings.ToLower(hash))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toPendingTransaction(tx), nil
+}
+
+// PendingTransactions is the resolver for the pendingTransactions field.
+func (r *queryResolver) PendingTransactions(ctx context.Context, from *string, status *string, offset *int, limit *int) ([]*model.PendingTransaction, error) {
+	o, l := pageArgs(offset, limit)
+	txs, err := dal.PendingTransaction.List(ctx, addressArg(from), derefString(status), o, l)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.PendingTransaction, 0, len(txs))
+	for i := range txs {
+		res = append(res, toPendingTransaction(&txs[i]))
+	}
+	return res, nil
+}
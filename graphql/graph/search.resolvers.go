@@ -0,0 +1,39 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+
+	"github.com/traitmeta/metago/core/search"
+	"github.com/traitmeta/metago/graphql/graph/model"
+)
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, limit *int) ([]*model.SearchResult, error) {
+	results, err := search.NewSearcher().Search(ctx, query, derefInt(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.SearchResult, 0, len(results))
+	for i := range results {
+		res = append(res, toSearchResult(&results[i]))
+	}
+	return res, nil
+}
+
+// SearchSuggestions is the resolver for the searchSuggestions field.
+func (r *queryResolver) SearchSuggestions(ctx context.Context, query string, limit *int) ([]*model.SearchSuggestion, error) {
+	suggestions, err := search.NewSearcher().Suggest(ctx, query, derefInt(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.SearchSuggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		res = append(res, &model.SearchSuggestion{Type: s.Type, Label: s.Label, Value: s.Value})
+	}
+	return res, nil
+}
@@ -0,0 +1,26 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/graphql/graph/model"
+	"gorm.io/gorm"
+)
+
+// Transaction is the resolver for the transaction field.
+func (r *queryResolver) Transaction(ctx context.Context, hash string) (*model.Transaction, error) {
+	trx, err := dal.Transaction.GetByHash(ctx, strings.ToLower(hash))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toTransaction(trx), nil
+}
@@ -0,0 +1,40 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"errors"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/graphql/graph/model"
+	"gorm.io/gorm"
+)
+
+// UserOperation is the resolver for the userOperation field.
+func (r *queryResolver) UserOperation(ctx context.Context, hash string) (*model.UserOperation, error) {
+	userOp, err := dal.UserOperation.GetByHash(ctx, hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUserOperation(userOp), nil
+}
+
+// UserOperations is the resolver for the userOperations field.
+func (r *queryResolver) UserOperations(ctx context.Context, sender *string, transactionHash *string, offset *int, limit *int) ([]*model.UserOperation, error) {
+	o, l := pageArgs(offset, limit)
+	userOps, err := dal.UserOperation.List(ctx, derefString(sender), derefString(transactionHash), o, l)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.UserOperation, 0, len(userOps))
+	for i := range userOps {
+		res = append(res, toUserOperation(&userOps[i]))
+	}
+	return res, nil
+}
@@ -3,27 +3,21 @@ package main
 import (
 	"log"
 	"net/http"
-	"os"
 
-	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/dal"
 	"github.com/traitmeta/metago/graphql/graph"
-	"github.com/traitmeta/metago/graphql/graph/generated"
 )
 
-const defaultPort = "8080"
+func init() {
+	config.SetupConfig()
+	db.SetupDBEngine(*config.DB)
+	dal.Init()
+}
 
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
-	}
-
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}))
-
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
-
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	addr := config.ListenAddr(config.Server.Graphql)
+	log.Printf("serving GraphQL playground on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, graph.NewHandler()))
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"net/http"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/ethrpc"
+)
+
+func init() {
+	config.SetupConfig()
+	db.SetupDBEngine(*config.DB)
+	dal.Init()
+}
+
+func chainId() *big.Int {
+	if config.BlockChain.ChainId != 0 {
+		return new(big.Int).SetUint64(config.BlockChain.ChainId)
+	}
+
+	config.SetupEthClient()
+	id, err := config.EthRpcClient.ChainID(context.Background())
+	if err != nil {
+		log.Fatalf("get chain id error: %v", err)
+	}
+	return id
+}
+
+func main() {
+	addr := config.ListenAddr(config.Server.JsonRpc)
+
+	srv, err := ethrpc.NewServer(ethrpc.NewEthAPI(chainId()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	http.Handle("/", srv)
+
+	log.Printf("serving JSON-RPC on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
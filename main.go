@@ -1,32 +1,223 @@
+/*
+## Overview
+metago 的统一入口, 子命令共享配置加载、日志和生命周期管理, 用法为 metago <command> [flags].
+
+  - eth index: 同步 EVM 区块, 默认先迁移数据库; eth backfill -from -to: 重新处理指定区间的 EVM 区块
+  - btc index-blocks: 同步 BTC 区块头信息; tap index: 索引 TAP DMT 铭文; brc20 index: 索引 BRC-20 事件
+  - inscribe: 创建 Runes 铭刻订单, 支付后广播铸造交易; runes mint: 使用本地钱包铸造 Runes
+  - serve graphql: 启动 GraphQL 服务; migrate: 迁移数据库
+  - config: 检查配置并打印生效的配置, 密码和密钥会被隐藏
+  - 全局参数 -config 指定配置文件, -set 段.字段=值 覆盖配置项, -watch 热加载 Log.Level 和 Metrics 配置
+  - 日志通过 pkg/blog/bzap 输出, logrus 和标准库 log 的日志也转发到 zap
+  - 收到 SIGINT/SIGTERM 后取消 ctx, 子命令返回后等待后台任务退出, 再按注册的逆序关闭数据库、节点连接等资源
+*/
 package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
 
-	"github.com/traitmeta/gotos/lib/db"
 	"github.com/traitmeta/metago/config"
-	"github.com/traitmeta/metago/core/chain"
-	"github.com/traitmeta/metago/core/dal"
-	"github.com/traitmeta/metago/core/models"
+	"github.com/traitmeta/metago/pkg/blog"
+	"github.com/traitmeta/metago/pkg/blog/bzap"
+	"github.com/traitmeta/metago/pkg/metrics"
+)
+
+const (
+	// shutdownTimeout 退出时等待后台任务的最长时间
+	shutdownTimeout = 30 * time.Second
+	// watchInterval -watch 检查配置文件变更的间隔
+	watchInterval = 2 * time.Second
 )
 
-func init() {
-	config.SetupConfig()
-	db.SetupDBEngine(*config.DB)
-	config.SetupEthClient()
-	err := models.MigrateDb()
+// command 子命令, name 为空格分隔的命令路径
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = []*command{
+	{name: "eth index", usage: "index EVM blocks from the chain head", run: runEthIndex},
+	{name: "eth backfill", usage: "reindex a range of EVM blocks", run: runEthBackfill},
+	{name: "stats rebuild", usage: "recompute the daily stats of a date range", run: runStatsRebuild},
+	{name: "watchlist add", usage: "watch an address and post its transfers to a webhook", run: runWatchlistAdd},
+	{name: "watchlist dead-letters", usage: "list webhook deliveries that ran out of retries", run: runWatchlistDeadLetters},
+	{name: "watchlist replay", usage: "queue a dead letter for delivery again", run: runWatchlistReplay},
+	{name: "btc index-blocks", usage: "index bitcoin block headers", run: runBtcIndexBlocks},
+	{name: "tap index", usage: "index TAP DMT inscriptions", run: runTapIndex},
+	{name: "brc20 index", usage: "index BRC-20 events", run: runBrc20Index},
+	{name: "inscribe", usage: "create a runes inscribe order, then mint it once paid", run: runInscribe},
+	{name: "runes mint", usage: "mint runes with the local wallets", run: runRunesMint},
+	{name: "serve graphql", usage: "serve the GraphQL API", run: runServeGraphql},
+	{name: "migrate", usage: "migrate the database schema", run: runMigrate},
+	{name: "config", usage: "validate the config and print it with secrets redacted", run: runConfig},
+}
+
+// lookup 按最长的命令路径匹配子命令, 返回剩余的参数
+func lookup(args []string) (*command, []string) {
+	var found *command
+	var rest []string
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(words) > len(args) || (found != nil && len(words) <= len(strings.Fields(found.name))) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == cmd.name {
+			found, rest = cmd, args[len(words):]
+		}
+	}
+	return found, rest
+}
+
+func usage(w io.Writer, global *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: metago [global flags] <command> [flags]")
+	fmt.Fprintln(w)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-24s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "global flags:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run metago <command> -h for the flags of a command")
+}
+
+// setFlags 可以重复的 -set 参数
+type setFlags []string
+
+func (s *setFlags) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *setFlags) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// newFlagSet 子命令的参数, 解析失败时退出
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ExitOnError)
+}
+
+// app 子命令共享的生命周期
+type app struct {
+	wg      sync.WaitGroup
+	closers []func()
+}
+
+// Go 在后台运行 fn, 退出时等待 fn 返回
+func (a *app) Go(fn func()) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		fn()
+	}()
+}
+
+// OnStop 注册退出时执行的清理函数, 按注册的逆序执行
+func (a *app) OnStop(fn func()) {
+	a.closers = append(a.closers, fn)
+}
+
+// shutdown 等待后台任务退出, 超过 timeout 后不再等待, 然后执行清理函数
+func (a *app) shutdown(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("background tasks did not stop within %s", timeout)
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+}
+
+// setupLog 按配置初始化 zap
+func setupLog(conf *blog.LogConf) (func(), error) {
+	zl, err := bzap.SetUp(*conf)
 	if err != nil {
-		log.Panic("config.MigrateDb error : ", err)
+		return nil, err
+	}
+	restore := bzap.RedirectLogrus(zl.Logger, logrusLevel(conf.Level))
+	return func() {
+		zl.Logger.Sync()
+		restore()
+	}, nil
+}
+
+// logrusLevel 转发到 zap 的 logrus 日志级别, zap 的级别在启动后不能修改
+func logrusLevel(level string) logrus.Level {
+	if level == "error" || level == "severe" {
+		return logrus.ErrorLevel
 	}
+	return logrus.InfoLevel
+}
 
+// reloaded 应用热加载的配置
+func reloaded(changed []string) {
+	logrus.SetLevel(logrusLevel(config.Log.Level))
+	metrics.Reload(config.Metrics)
+}
+
+func runConfig(ctx context.Context, a *app, args []string) error {
+	newFlagSet("config").Parse(args)
+	config.Print(os.Stdout)
+	return nil
 }
 
 func main() {
-	ctx := context.Background()
-	dal.Init()
+	global := flag.NewFlagSet("metago", flag.ExitOnError)
+	configPath := global.String("config", "", "config file, defaults to $METAGO_CONFIG or config/config.yml")
+	var sets setFlags
+	global.Var(&sets, "set", "override a config key, Section.Field=value, can be repeated")
+	watch := global.Bool("watch", false, "reload Log.Level and Metrics settings when the config file changes")
+	global.Usage = func() { usage(os.Stderr, global) }
+	global.Parse(os.Args[1:])
+
+	cmd, args := lookup(global.Args())
+	if cmd == nil {
+		global.Usage()
+		os.Exit(2)
+	}
 
-	log.Println(config.BlockChain.RpcUrl)
-	chain.InitBlock(ctx)
-	chain.SyncTask(ctx)
+	if err := config.Load(config.Options{Path: *configPath, Sets: sets}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	closeLog, err := setupLog(config.Log)
+	if err != nil {
+		log.Fatal("setup log error : ", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	a := &app{}
+	if *watch {
+		a.Go(func() { config.Watch(ctx, watchInterval, reloaded) })
+	}
+	err = cmd.run(ctx, a, args)
+	stop()
+	a.shutdown(shutdownTimeout)
+	if err != nil {
+		log.Printf("%s error : %v", cmd.name, err)
+		closeLog()
+		os.Exit(1)
+	}
+	closeLog()
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		cmd  string
+		rest []string
+	}{
+		{name: "two words", args: []string{"eth", "index"}, cmd: "eth index", rest: []string{}},
+		{name: "two words with flags", args: []string{"eth", "backfill", "-from", "1"}, cmd: "eth backfill", rest: []string{"-from", "1"}},
+		{name: "watchlist", args: []string{"watchlist", "replay", "-id", "3"}, cmd: "watchlist replay", rest: []string{"-id", "3"}},
+		{name: "one word", args: []string{"inscribe", "-order", "o.json"}, cmd: "inscribe", rest: []string{"-order", "o.json"}},
+		{name: "partial path", args: []string{"eth"}},
+		{name: "unknown", args: []string{"sol", "index"}},
+		{name: "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, rest := lookup(tt.args)
+			if tt.cmd == "" {
+				if cmd != nil {
+					t.Fatalf("lookup(%v) = %q, want nil", tt.args, cmd.name)
+				}
+				return
+			}
+			if cmd == nil || cmd.name != tt.cmd {
+				t.Fatalf("lookup(%v) = %v, want %q", tt.args, cmd, tt.cmd)
+			}
+			if !reflect.DeepEqual(rest, tt.rest) {
+				t.Errorf("rest = %v, want %v", rest, tt.rest)
+			}
+		})
+	}
+}
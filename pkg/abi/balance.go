@@ -0,0 +1,52 @@
+package abi
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/config"
+)
+
+// balanceOfABI ERC20/ERC721 的 balanceOf(address) 和 ERC1155 的 balanceOf(address,uint256)
+const balanceOfABI = `[
+{"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"owner","type":"address"},{"name":"id","type":"uint256"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}
+]`
+
+// GetBalanceOfAt 读取 height 高度时的余额, tokenId 不为空时按 ERC1155 查询, 需要节点保存历史状态
+func GetBalanceOfAt(ctx context.Context, contractAddress, holder string, tokenId *big.Int, height uint64) (*big.Int, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(balanceOfABI))
+	if err != nil {
+		return nil, err
+	}
+
+	// 重载的方法在解析后名称为 balanceOf0
+	var input []byte
+	if tokenId == nil {
+		input, err = contractAbi.Pack("balanceOf", common.HexToAddress(holder))
+	} else {
+		input, err = contractAbi.Pack("balanceOf0", common.HexToAddress(holder), tokenId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token := common.HexToAddress(contractAddress)
+	output, err := config.EthRpcClient.CallContract(ctx, ethereum.CallMsg{To: &token, Data: input},
+		new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, errors.WithMessage(err, "balanceOf")
+	}
+
+	values, err := contractAbi.Methods["balanceOf"].Outputs.Unpack(output)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
@@ -0,0 +1,361 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package blur
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// Fee is an auto generated low-level Go binding around an user-defined struct.
+type Fee struct {
+	Rate      uint16
+	Recipient common.Address
+}
+
+// Order is an auto generated low-level Go binding around an user-defined struct.
+type Order struct {
+	Trader         common.Address
+	Side           uint8
+	MatchingPolicy common.Address
+	Collection     common.Address
+	TokenId        *big.Int
+	Amount         *big.Int
+	PaymentToken   common.Address
+	Price          *big.Int
+	ListingTime    *big.Int
+	ExpirationTime *big.Int
+	Fees           []Fee
+	Salt           *big.Int
+	ExtraParams    []byte
+}
+
+// BlurExchangeMetaData contains all meta data concerning the BlurExchange contract.
+var BlurExchangeMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"name\":\"maker\",\"type\":\"address\",\"indexed\":true},{\"name\":\"taker\",\"type\":\"address\",\"indexed\":true},{\"name\":\"sell\",\"type\":\"tuple\",\"indexed\":false,\"components\":[{\"name\":\"trader\",\"type\":\"address\"},{\"name\":\"side\",\"type\":\"uint8\",\"internalType\":\"enumSide\"},{\"name\":\"matchingPolicy\",\"type\":\"address\"},{\"name\":\"collection\",\"type\":\"address\"},{\"name\":\"tokenId\",\"type\":\"uint256\"},{\"name\":\"amount\",\"type\":\"uint256\"},{\"name\":\"paymentToken\",\"type\":\"address\"},{\"name\":\"price\",\"type\":\"uint256\"},{\"name\":\"listingTime\",\"type\":\"uint256\"},{\"name\":\"expirationTime\",\"type\":\"uint256\"},{\"name\":\"fees\",\"type\":\"tuple[]\",\"components\":[{\"name\":\"rate\",\"type\":\"uint16\"},{\"name\":\"recipient\",\"type\":\"address\",\"internalType\":\"addresspayable\"}],\"internalType\":\"structFee[]\"},{\"name\":\"salt\",\"type\":\"uint256\"},{\"name\":\"extraParams\",\"type\":\"bytes\"}],\"internalType\":\"structOrder\"},{\"name\":\"sellHash\",\"type\":\"bytes32\",\"indexed\":false},{\"name\":\"buy\",\"type\":\"tuple\",\"indexed\":false,\"components\":[{\"name\":\"trader\",\"type\":\"address\"},{\"name\":\"side\",\"type\":\"uint8\",\"internalType\":\"enumSide\"},{\"name\":\"matchingPolicy\",\"type\":\"address\"},{\"name\":\"collection\",\"type\":\"address\"},{\"name\":\"tokenId\",\"type\":\"uint256\"},{\"name\":\"amount\",\"type\":\"uint256\"},{\"name\":\"paymentToken\",\"type\":\"address\"},{\"name\":\"price\",\"type\":\"uint256\"},{\"name\":\"listingTime\",\"type\":\"uint256\"},{\"name\":\"expirationTime\",\"type\":\"uint256\"},{\"name\":\"fees\",\"type\":\"tuple[]\",\"components\":[{\"name\":\"rate\",\"type\":\"uint16\"},{\"name\":\"recipient\",\"type\":\"address\",\"internalType\":\"addresspayable\"}],\"internalType\":\"structFee[]\"},{\"name\":\"salt\",\"type\":\"uint256\"},{\"name\":\"extraParams\",\"type\":\"bytes\"}],\"internalType\":\"structOrder\"},{\"name\":\"buyHash\",\"type\":\"bytes32\",\"indexed\":false}],\"name\":\"OrdersMatched\",\"type\":\"event\"}]",
+}
+
+// BlurExchangeABI is the input ABI used to generate the binding from.
+// Deprecated: Use BlurExchangeMetaData.ABI instead.
+var BlurExchangeABI = BlurExchangeMetaData.ABI
+
+// BlurExchange is an auto generated Go binding around an Ethereum contract.
+type BlurExchange struct {
+	BlurExchangeCaller     // Read-only binding to the contract
+	BlurExchangeTransactor // Write-only binding to the contract
+	BlurExchangeFilterer   // Log filterer for contract events
+}
+
+// BlurExchangeCaller is an auto generated read-only Go binding around an Ethereum contract.
+type BlurExchangeCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BlurExchangeTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type BlurExchangeTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BlurExchangeFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type BlurExchangeFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BlurExchangeSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type BlurExchangeSession struct {
+	Contract     *BlurExchange     // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BlurExchangeCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type BlurExchangeCallerSession struct {
+	Contract *BlurExchangeCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts       // Call options to use throughout this session
+}
+
+// BlurExchangeTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type BlurExchangeTransactorSession struct {
+	Contract     *BlurExchangeTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// BlurExchangeRaw is an auto generated low-level Go binding around an Ethereum contract.
+type BlurExchangeRaw struct {
+	Contract *BlurExchange // Generic contract binding to access the raw methods on
+}
+
+// BlurExchangeCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type BlurExchangeCallerRaw struct {
+	Contract *BlurExchangeCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// BlurExchangeTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type BlurExchangeTransactorRaw struct {
+	Contract *BlurExchangeTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewBlurExchange creates a new instance of BlurExchange, bound to a specific deployed contract.
+func NewBlurExchange(address common.Address, backend bind.ContractBackend) (*BlurExchange, error) {
+	contract, err := bindBlurExchange(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &BlurExchange{BlurExchangeCaller: BlurExchangeCaller{contract: contract}, BlurExchangeTransactor: BlurExchangeTransactor{contract: contract}, BlurExchangeFilterer: BlurExchangeFilterer{contract: contract}}, nil
+}
+
+// NewBlurExchangeCaller creates a new read-only instance of BlurExchange, bound to a specific deployed contract.
+func NewBlurExchangeCaller(address common.Address, caller bind.ContractCaller) (*BlurExchangeCaller, error) {
+	contract, err := bindBlurExchange(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BlurExchangeCaller{contract: contract}, nil
+}
+
+// NewBlurExchangeTransactor creates a new write-only instance of BlurExchange, bound to a specific deployed contract.
+func NewBlurExchangeTransactor(address common.Address, transactor bind.ContractTransactor) (*BlurExchangeTransactor, error) {
+	contract, err := bindBlurExchange(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BlurExchangeTransactor{contract: contract}, nil
+}
+
+// NewBlurExchangeFilterer creates a new log filterer instance of BlurExchange, bound to a specific deployed contract.
+func NewBlurExchangeFilterer(address common.Address, filterer bind.ContractFilterer) (*BlurExchangeFilterer, error) {
+	contract, err := bindBlurExchange(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &BlurExchangeFilterer{contract: contract}, nil
+}
+
+// bindBlurExchange binds a generic wrapper to an already deployed contract.
+func bindBlurExchange(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := BlurExchangeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_BlurExchange *BlurExchangeRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _BlurExchange.Contract.BlurExchangeCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_BlurExchange *BlurExchangeRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _BlurExchange.Contract.BlurExchangeTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_BlurExchange *BlurExchangeRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _BlurExchange.Contract.BlurExchangeTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_BlurExchange *BlurExchangeCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _BlurExchange.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_BlurExchange *BlurExchangeTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _BlurExchange.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_BlurExchange *BlurExchangeTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _BlurExchange.Contract.contract.Transact(opts, method, params...)
+}
+
+// BlurExchangeOrdersMatchedIterator is returned from FilterOrdersMatched and is used to iterate over the raw logs and unpacked data for OrdersMatched events raised by the BlurExchange contract.
+type BlurExchangeOrdersMatchedIterator struct {
+	Event *BlurExchangeOrdersMatched // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *BlurExchangeOrdersMatchedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(BlurExchangeOrdersMatched)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(BlurExchangeOrdersMatched)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *BlurExchangeOrdersMatchedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *BlurExchangeOrdersMatchedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// BlurExchangeOrdersMatched represents a OrdersMatched event raised by the BlurExchange contract.
+type BlurExchangeOrdersMatched struct {
+	Maker    common.Address
+	Taker    common.Address
+	Sell     Order
+	SellHash [32]byte
+	Buy      Order
+	BuyHash  [32]byte
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterOrdersMatched is a free log retrieval operation binding the contract event 0x61cbb2a3dee0b6064c2e681aadd61677fb4ef319f0b547508d495626f5a62f64.
+//
+// Solidity: event OrdersMatched(address indexed maker, address indexed taker, (address,uint8,address,address,uint256,uint256,address,uint256,uint256,uint256,(uint16,address)[],uint256,bytes) sell, bytes32 sellHash, (address,uint8,address,address,uint256,uint256,address,uint256,uint256,uint256,(uint16,address)[],uint256,bytes) buy, bytes32 buyHash)
+func (_BlurExchange *BlurExchangeFilterer) FilterOrdersMatched(opts *bind.FilterOpts, maker []common.Address, taker []common.Address) (*BlurExchangeOrdersMatchedIterator, error) {
+
+	var makerRule []interface{}
+	for _, makerItem := range maker {
+		makerRule = append(makerRule, makerItem)
+	}
+	var takerRule []interface{}
+	for _, takerItem := range taker {
+		takerRule = append(takerRule, takerItem)
+	}
+
+	logs, sub, err := _BlurExchange.contract.FilterLogs(opts, "OrdersMatched", makerRule, takerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &BlurExchangeOrdersMatchedIterator{contract: _BlurExchange.contract, event: "OrdersMatched", logs: logs, sub: sub}, nil
+}
+
+// WatchOrdersMatched is a free log subscription operation binding the contract event 0x61cbb2a3dee0b6064c2e681aadd61677fb4ef319f0b547508d495626f5a62f64.
+//
+// Solidity: event OrdersMatched(address indexed maker, address indexed taker, (address,uint8,address,address,uint256,uint256,address,uint256,uint256,uint256,(uint16,address)[],uint256,bytes) sell, bytes32 sellHash, (address,uint8,address,address,uint256,uint256,address,uint256,uint256,uint256,(uint16,address)[],uint256,bytes) buy, bytes32 buyHash)
+func (_BlurExchange *BlurExchangeFilterer) WatchOrdersMatched(opts *bind.WatchOpts, sink chan<- *BlurExchangeOrdersMatched, maker []common.Address, taker []common.Address) (event.Subscription, error) {
+
+	var makerRule []interface{}
+	for _, makerItem := range maker {
+		makerRule = append(makerRule, makerItem)
+	}
+	var takerRule []interface{}
+	for _, takerItem := range taker {
+		takerRule = append(takerRule, takerItem)
+	}
+
+	logs, sub, err := _BlurExchange.contract.WatchLogs(opts, "OrdersMatched", makerRule, takerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(BlurExchangeOrdersMatched)
+				if err := _BlurExchange.contract.UnpackLog(event, "OrdersMatched", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOrdersMatched is a log parse operation binding the contract event 0x61cbb2a3dee0b6064c2e681aadd61677fb4ef319f0b547508d495626f5a62f64.
+//
+// Solidity: event OrdersMatched(address indexed maker, address indexed taker, (address,uint8,address,address,uint256,uint256,address,uint256,uint256,uint256,(uint16,address)[],uint256,bytes) sell, bytes32 sellHash, (address,uint8,address,address,uint256,uint256,address,uint256,uint256,uint256,(uint16,address)[],uint256,bytes) buy, bytes32 buyHash)
+func (_BlurExchange *BlurExchangeFilterer) ParseOrdersMatched(log types.Log) (*BlurExchangeOrdersMatched, error) {
+	event := new(BlurExchangeOrdersMatched)
+	if err := _BlurExchange.contract.UnpackLog(event, "OrdersMatched", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
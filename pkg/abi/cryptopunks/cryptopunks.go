@@ -0,0 +1,830 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package cryptopunks
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// CryptoPunksMarketMetaData contains all meta data concerning the CryptoPunksMarket contract.
+var CryptoPunksMarketMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"punkIndex\",\"type\":\"uint256\"}],\"name\":\"Assign\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"punkIndex\",\"type\":\"uint256\"}],\"name\":\"PunkTransfer\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"punkIndex\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"fromAddress\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"toAddress\",\"type\":\"address\"}],\"name\":\"PunkBought\",\"type\":\"event\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"punkIndexToAddress\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// CryptoPunksMarketABI is the input ABI used to generate the binding from.
+// Deprecated: Use CryptoPunksMarketMetaData.ABI instead.
+var CryptoPunksMarketABI = CryptoPunksMarketMetaData.ABI
+
+// CryptoPunksMarket is an auto generated Go binding around an Ethereum contract.
+type CryptoPunksMarket struct {
+	CryptoPunksMarketCaller     // Read-only binding to the contract
+	CryptoPunksMarketTransactor // Write-only binding to the contract
+	CryptoPunksMarketFilterer   // Log filterer for contract events
+}
+
+// CryptoPunksMarketCaller is an auto generated read-only Go binding around an Ethereum contract.
+type CryptoPunksMarketCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CryptoPunksMarketTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type CryptoPunksMarketTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CryptoPunksMarketFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type CryptoPunksMarketFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CryptoPunksMarketSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type CryptoPunksMarketSession struct {
+	Contract     *CryptoPunksMarket // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts      // Call options to use throughout this session
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// CryptoPunksMarketCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type CryptoPunksMarketCallerSession struct {
+	Contract *CryptoPunksMarketCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts            // Call options to use throughout this session
+}
+
+// CryptoPunksMarketTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type CryptoPunksMarketTransactorSession struct {
+	Contract     *CryptoPunksMarketTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts            // Transaction auth options to use throughout this session
+}
+
+// CryptoPunksMarketRaw is an auto generated low-level Go binding around an Ethereum contract.
+type CryptoPunksMarketRaw struct {
+	Contract *CryptoPunksMarket // Generic contract binding to access the raw methods on
+}
+
+// CryptoPunksMarketCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type CryptoPunksMarketCallerRaw struct {
+	Contract *CryptoPunksMarketCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// CryptoPunksMarketTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type CryptoPunksMarketTransactorRaw struct {
+	Contract *CryptoPunksMarketTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewCryptoPunksMarket creates a new instance of CryptoPunksMarket, bound to a specific deployed contract.
+func NewCryptoPunksMarket(address common.Address, backend bind.ContractBackend) (*CryptoPunksMarket, error) {
+	contract, err := bindCryptoPunksMarket(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarket{CryptoPunksMarketCaller: CryptoPunksMarketCaller{contract: contract}, CryptoPunksMarketTransactor: CryptoPunksMarketTransactor{contract: contract}, CryptoPunksMarketFilterer: CryptoPunksMarketFilterer{contract: contract}}, nil
+}
+
+// NewCryptoPunksMarketCaller creates a new read-only instance of CryptoPunksMarket, bound to a specific deployed contract.
+func NewCryptoPunksMarketCaller(address common.Address, caller bind.ContractCaller) (*CryptoPunksMarketCaller, error) {
+	contract, err := bindCryptoPunksMarket(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketCaller{contract: contract}, nil
+}
+
+// NewCryptoPunksMarketTransactor creates a new write-only instance of CryptoPunksMarket, bound to a specific deployed contract.
+func NewCryptoPunksMarketTransactor(address common.Address, transactor bind.ContractTransactor) (*CryptoPunksMarketTransactor, error) {
+	contract, err := bindCryptoPunksMarket(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketTransactor{contract: contract}, nil
+}
+
+// NewCryptoPunksMarketFilterer creates a new log filterer instance of CryptoPunksMarket, bound to a specific deployed contract.
+func NewCryptoPunksMarketFilterer(address common.Address, filterer bind.ContractFilterer) (*CryptoPunksMarketFilterer, error) {
+	contract, err := bindCryptoPunksMarket(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketFilterer{contract: contract}, nil
+}
+
+// bindCryptoPunksMarket binds a generic wrapper to an already deployed contract.
+func bindCryptoPunksMarket(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := CryptoPunksMarketMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CryptoPunksMarket *CryptoPunksMarketRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _CryptoPunksMarket.Contract.CryptoPunksMarketCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CryptoPunksMarket *CryptoPunksMarketRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CryptoPunksMarket.Contract.CryptoPunksMarketTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CryptoPunksMarket *CryptoPunksMarketRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CryptoPunksMarket.Contract.CryptoPunksMarketTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CryptoPunksMarket *CryptoPunksMarketCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _CryptoPunksMarket.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CryptoPunksMarket *CryptoPunksMarketTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CryptoPunksMarket.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CryptoPunksMarket *CryptoPunksMarketTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CryptoPunksMarket.Contract.contract.Transact(opts, method, params...)
+}
+
+// PunkIndexToAddress is a free data retrieval call binding the contract method 0x58178168.
+//
+// Solidity: function punkIndexToAddress(uint256 ) view returns(address)
+func (_CryptoPunksMarket *CryptoPunksMarketCaller) PunkIndexToAddress(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _CryptoPunksMarket.contract.Call(opts, &out, "punkIndexToAddress", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// PunkIndexToAddress is a free data retrieval call binding the contract method 0x58178168.
+//
+// Solidity: function punkIndexToAddress(uint256 ) view returns(address)
+func (_CryptoPunksMarket *CryptoPunksMarketSession) PunkIndexToAddress(arg0 *big.Int) (common.Address, error) {
+	return _CryptoPunksMarket.Contract.PunkIndexToAddress(&_CryptoPunksMarket.CallOpts, arg0)
+}
+
+// PunkIndexToAddress is a free data retrieval call binding the contract method 0x58178168.
+//
+// Solidity: function punkIndexToAddress(uint256 ) view returns(address)
+func (_CryptoPunksMarket *CryptoPunksMarketCallerSession) PunkIndexToAddress(arg0 *big.Int) (common.Address, error) {
+	return _CryptoPunksMarket.Contract.PunkIndexToAddress(&_CryptoPunksMarket.CallOpts, arg0)
+}
+
+// CryptoPunksMarketAssignIterator is returned from FilterAssign and is used to iterate over the raw logs and unpacked data for Assign events raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketAssignIterator struct {
+	Event *CryptoPunksMarketAssign // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CryptoPunksMarketAssignIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CryptoPunksMarketAssign)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CryptoPunksMarketAssign)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CryptoPunksMarketAssignIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CryptoPunksMarketAssignIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CryptoPunksMarketAssign represents a Assign event raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketAssign struct {
+	To        common.Address
+	PunkIndex *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterAssign is a free log retrieval operation binding the contract event 0x8a0e37b73a0d9c82e205d4d1a3ff3d0b57ce5f4d7bccf6bac03336dc101cb7ba.
+//
+// Solidity: event Assign(address indexed to, uint256 punkIndex)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) FilterAssign(opts *bind.FilterOpts, to []common.Address) (*CryptoPunksMarketAssignIterator, error) {
+
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.FilterLogs(opts, "Assign", toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketAssignIterator{contract: _CryptoPunksMarket.contract, event: "Assign", logs: logs, sub: sub}, nil
+}
+
+// WatchAssign is a free log subscription operation binding the contract event 0x8a0e37b73a0d9c82e205d4d1a3ff3d0b57ce5f4d7bccf6bac03336dc101cb7ba.
+//
+// Solidity: event Assign(address indexed to, uint256 punkIndex)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) WatchAssign(opts *bind.WatchOpts, sink chan<- *CryptoPunksMarketAssign, to []common.Address) (event.Subscription, error) {
+
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.WatchLogs(opts, "Assign", toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CryptoPunksMarketAssign)
+				if err := _CryptoPunksMarket.contract.UnpackLog(event, "Assign", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAssign is a log parse operation binding the contract event 0x8a0e37b73a0d9c82e205d4d1a3ff3d0b57ce5f4d7bccf6bac03336dc101cb7ba.
+//
+// Solidity: event Assign(address indexed to, uint256 punkIndex)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) ParseAssign(log types.Log) (*CryptoPunksMarketAssign, error) {
+	event := new(CryptoPunksMarketAssign)
+	if err := _CryptoPunksMarket.contract.UnpackLog(event, "Assign", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// CryptoPunksMarketPunkBoughtIterator is returned from FilterPunkBought and is used to iterate over the raw logs and unpacked data for PunkBought events raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketPunkBoughtIterator struct {
+	Event *CryptoPunksMarketPunkBought // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CryptoPunksMarketPunkBoughtIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CryptoPunksMarketPunkBought)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CryptoPunksMarketPunkBought)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CryptoPunksMarketPunkBoughtIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CryptoPunksMarketPunkBoughtIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CryptoPunksMarketPunkBought represents a PunkBought event raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketPunkBought struct {
+	PunkIndex   *big.Int
+	Value       *big.Int
+	FromAddress common.Address
+	ToAddress   common.Address
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterPunkBought is a free log retrieval operation binding the contract event 0x58e5d5a525e3b40bc15abaa38b5882678db1ee68befd2f60bafe3a7fd06db9e3.
+//
+// Solidity: event PunkBought(uint256 indexed punkIndex, uint256 value, address indexed fromAddress, address indexed toAddress)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) FilterPunkBought(opts *bind.FilterOpts, punkIndex []*big.Int, fromAddress []common.Address, toAddress []common.Address) (*CryptoPunksMarketPunkBoughtIterator, error) {
+
+	var punkIndexRule []interface{}
+	for _, punkIndexItem := range punkIndex {
+		punkIndexRule = append(punkIndexRule, punkIndexItem)
+	}
+
+	var fromAddressRule []interface{}
+	for _, fromAddressItem := range fromAddress {
+		fromAddressRule = append(fromAddressRule, fromAddressItem)
+	}
+	var toAddressRule []interface{}
+	for _, toAddressItem := range toAddress {
+		toAddressRule = append(toAddressRule, toAddressItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.FilterLogs(opts, "PunkBought", punkIndexRule, fromAddressRule, toAddressRule)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketPunkBoughtIterator{contract: _CryptoPunksMarket.contract, event: "PunkBought", logs: logs, sub: sub}, nil
+}
+
+// WatchPunkBought is a free log subscription operation binding the contract event 0x58e5d5a525e3b40bc15abaa38b5882678db1ee68befd2f60bafe3a7fd06db9e3.
+//
+// Solidity: event PunkBought(uint256 indexed punkIndex, uint256 value, address indexed fromAddress, address indexed toAddress)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) WatchPunkBought(opts *bind.WatchOpts, sink chan<- *CryptoPunksMarketPunkBought, punkIndex []*big.Int, fromAddress []common.Address, toAddress []common.Address) (event.Subscription, error) {
+
+	var punkIndexRule []interface{}
+	for _, punkIndexItem := range punkIndex {
+		punkIndexRule = append(punkIndexRule, punkIndexItem)
+	}
+
+	var fromAddressRule []interface{}
+	for _, fromAddressItem := range fromAddress {
+		fromAddressRule = append(fromAddressRule, fromAddressItem)
+	}
+	var toAddressRule []interface{}
+	for _, toAddressItem := range toAddress {
+		toAddressRule = append(toAddressRule, toAddressItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.WatchLogs(opts, "PunkBought", punkIndexRule, fromAddressRule, toAddressRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CryptoPunksMarketPunkBought)
+				if err := _CryptoPunksMarket.contract.UnpackLog(event, "PunkBought", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePunkBought is a log parse operation binding the contract event 0x58e5d5a525e3b40bc15abaa38b5882678db1ee68befd2f60bafe3a7fd06db9e3.
+//
+// Solidity: event PunkBought(uint256 indexed punkIndex, uint256 value, address indexed fromAddress, address indexed toAddress)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) ParsePunkBought(log types.Log) (*CryptoPunksMarketPunkBought, error) {
+	event := new(CryptoPunksMarketPunkBought)
+	if err := _CryptoPunksMarket.contract.UnpackLog(event, "PunkBought", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// CryptoPunksMarketPunkTransferIterator is returned from FilterPunkTransfer and is used to iterate over the raw logs and unpacked data for PunkTransfer events raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketPunkTransferIterator struct {
+	Event *CryptoPunksMarketPunkTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CryptoPunksMarketPunkTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CryptoPunksMarketPunkTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CryptoPunksMarketPunkTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CryptoPunksMarketPunkTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CryptoPunksMarketPunkTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CryptoPunksMarketPunkTransfer represents a PunkTransfer event raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketPunkTransfer struct {
+	From      common.Address
+	To        common.Address
+	PunkIndex *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterPunkTransfer is a free log retrieval operation binding the contract event 0x05af636b70da6819000c49f85b21fa82081c632069bb626f30932034099107d8.
+//
+// Solidity: event PunkTransfer(address indexed from, address indexed to, uint256 punkIndex)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) FilterPunkTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*CryptoPunksMarketPunkTransferIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.FilterLogs(opts, "PunkTransfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketPunkTransferIterator{contract: _CryptoPunksMarket.contract, event: "PunkTransfer", logs: logs, sub: sub}, nil
+}
+
+// WatchPunkTransfer is a free log subscription operation binding the contract event 0x05af636b70da6819000c49f85b21fa82081c632069bb626f30932034099107d8.
+//
+// Solidity: event PunkTransfer(address indexed from, address indexed to, uint256 punkIndex)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) WatchPunkTransfer(opts *bind.WatchOpts, sink chan<- *CryptoPunksMarketPunkTransfer, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.WatchLogs(opts, "PunkTransfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CryptoPunksMarketPunkTransfer)
+				if err := _CryptoPunksMarket.contract.UnpackLog(event, "PunkTransfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePunkTransfer is a log parse operation binding the contract event 0x05af636b70da6819000c49f85b21fa82081c632069bb626f30932034099107d8.
+//
+// Solidity: event PunkTransfer(address indexed from, address indexed to, uint256 punkIndex)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) ParsePunkTransfer(log types.Log) (*CryptoPunksMarketPunkTransfer, error) {
+	event := new(CryptoPunksMarketPunkTransfer)
+	if err := _CryptoPunksMarket.contract.UnpackLog(event, "PunkTransfer", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// CryptoPunksMarketTransferIterator is returned from FilterTransfer and is used to iterate over the raw logs and unpacked data for Transfer events raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketTransferIterator struct {
+	Event *CryptoPunksMarketTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CryptoPunksMarketTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CryptoPunksMarketTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CryptoPunksMarketTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CryptoPunksMarketTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CryptoPunksMarketTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CryptoPunksMarketTransfer represents a Transfer event raised by the CryptoPunksMarket contract.
+type CryptoPunksMarketTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransfer is a free log retrieval operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*CryptoPunksMarketTransferIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.FilterLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoPunksMarketTransferIterator{contract: _CryptoPunksMarket.contract, event: "Transfer", logs: logs, sub: sub}, nil
+}
+
+// WatchTransfer is a free log subscription operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *CryptoPunksMarketTransfer, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _CryptoPunksMarket.contract.WatchLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CryptoPunksMarketTransfer)
+				if err := _CryptoPunksMarket.contract.UnpackLog(event, "Transfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTransfer is a log parse operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_CryptoPunksMarket *CryptoPunksMarketFilterer) ParseTransfer(log types.Log) (*CryptoPunksMarketTransfer, error) {
+	event := new(CryptoPunksMarketTransfer)
+	if err := _CryptoPunksMarket.contract.UnpackLog(event, "Transfer", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,45 @@
+package abi
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ensEventsABI ENS 注册表和解析器的事件, node/label 为 indexed 字段, 不在 data 中
+const ensEventsABI = `[
+{"type":"event","name":"NewOwner","inputs":[{"name":"node","type":"bytes32","indexed":true},{"name":"label","type":"bytes32","indexed":true},{"name":"owner","type":"address","indexed":false}]},
+{"type":"event","name":"NewResolver","inputs":[{"name":"node","type":"bytes32","indexed":true},{"name":"resolver","type":"address","indexed":false}]},
+{"type":"event","name":"Transfer","inputs":[{"name":"node","type":"bytes32","indexed":true},{"name":"owner","type":"address","indexed":false}]},
+{"type":"event","name":"AddrChanged","inputs":[{"name":"node","type":"bytes32","indexed":true},{"name":"a","type":"address","indexed":false}]},
+{"type":"event","name":"NameChanged","inputs":[{"name":"node","type":"bytes32","indexed":true},{"name":"name","type":"string","indexed":false}]}
+]`
+
+// ParseEnsAddressLog 解析 data 中只有一个地址的 ENS 事件: NewOwner, NewResolver, Transfer, AddrChanged
+func ParseEnsAddressLog(name string, data []byte) (common.Address, error) {
+	event := struct {
+		Owner    common.Address
+		Resolver common.Address
+		A        common.Address
+	}{}
+	if err := unpackLog(ensEventsABI, name, data, &event); err != nil {
+		return common.Address{}, err
+	}
+	switch name {
+	case "NewResolver":
+		return event.Resolver, nil
+	case "AddrChanged":
+		return event.A, nil
+	default:
+		return event.Owner, nil
+	}
+}
+
+// ParseEnsNameChangedLog 解析解析器 NameChanged 中的名称
+func ParseEnsNameChangedLog(data []byte) (string, error) {
+	event := struct {
+		Name string
+	}{}
+	if err := unpackLog(ensEventsABI, "NameChanged", data, &event); err != nil {
+		return "", err
+	}
+	return event.Name, nil
+}
@@ -0,0 +1,72 @@
+package abi
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/pkg/abi/entrypoint"
+)
+
+// UserOperationEventLog UserOperationEvent 中未 indexed 的字段
+type UserOperationEventLog struct {
+	Nonce         *big.Int
+	Success       bool
+	ActualGasCost *big.Int
+	ActualGasUsed *big.Int
+}
+
+func ParseUserOperationEventLog(data []byte) (*UserOperationEventLog, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(entrypoint.EntryPointABI))
+	if err != nil {
+		return nil, err
+	}
+
+	var event UserOperationEventLog
+	err = contractAbi.UnpackIntoInterface(&event, "UserOperationEvent", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func ParseAccountDeployedLog(data []byte) (factory common.Address, paymaster common.Address, err error) {
+	contractAbi, err := abi.JSON(strings.NewReader(entrypoint.EntryPointABI))
+	if err != nil {
+		return
+	}
+
+	event := struct {
+		Factory   common.Address
+		Paymaster common.Address
+	}{}
+
+	err = contractAbi.UnpackIntoInterface(&event, "AccountDeployed", data)
+	if err != nil {
+		return
+	}
+
+	return event.Factory, event.Paymaster, nil
+}
+
+func ParseUserOperationRevertReasonLog(data []byte) (*big.Int, []byte, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(entrypoint.EntryPointABI))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event := struct {
+		Nonce        *big.Int
+		RevertReason []byte
+	}{}
+
+	err = contractAbi.UnpackIntoInterface(&event, "UserOperationRevertReason", data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return event.Nonce, event.RevertReason, nil
+}
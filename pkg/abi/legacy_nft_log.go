@@ -0,0 +1,41 @@
+package abi
+
+import (
+	"math/big"
+
+	"github.com/traitmeta/metago/pkg/abi/cryptopunks"
+	"github.com/traitmeta/metago/pkg/abi/mooncats"
+)
+
+// ParsePunkIndexLog 解析 Assign 和 PunkTransfer 中的 punkIndex, 两个事件 data 只有这一个字段
+func ParsePunkIndexLog(name string, data []byte) (*big.Int, error) {
+	event := struct {
+		PunkIndex *big.Int
+	}{}
+	if err := unpackLog(cryptopunks.CryptoPunksMarketABI, name, data, &event); err != nil {
+		return nil, err
+	}
+	return event.PunkIndex, nil
+}
+
+// ParsePunkBoughtLog punkIndex/fromAddress/toAddress 为 indexed 字段, data 中只有成交价格
+func ParsePunkBoughtLog(data []byte) (*big.Int, error) {
+	event := struct {
+		Value *big.Int
+	}{}
+	if err := unpackLog(cryptopunks.CryptoPunksMarketABI, "PunkBought", data, &event); err != nil {
+		return nil, err
+	}
+	return event.Value, nil
+}
+
+// ParseCatAdoptedLog catId/from/to 为 indexed 字段, data 中只有领养价格
+func ParseCatAdoptedLog(data []byte) (*big.Int, error) {
+	event := struct {
+		Price *big.Int
+	}{}
+	if err := unpackLog(mooncats.MoonCatRescueABI, "CatAdopted", data, &event); err != nil {
+		return nil, err
+	}
+	return event.Price, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/traitmeta/metago/config"
 	"github.com/traitmeta/metago/pkg/abi/erc1155"
 	"github.com/traitmeta/metago/pkg/abi/erc20"
+	"github.com/traitmeta/metago/pkg/abi/erc721"
 )
 
 func GetErc20Metadata(contractAddress string) (totalSupply *big.Int, name, symbol string, decimals uint8, err error) {
@@ -117,3 +118,40 @@ func ParseErc1155SignleTransferLog(data []byte) (*big.Int, *big.Int, error) {
 
 	return transferEvent.Id, transferEvent.Value, nil
 }
+
+func ParseErc20ApprovalLog(data []byte) (*big.Int, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(string(erc20.Erc20ABI)))
+	if err != nil {
+		return nil, err
+	}
+
+	approvalEvent := struct {
+		Value *big.Int
+	}{}
+
+	err = contractAbi.UnpackIntoInterface(&approvalEvent, "Approval", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return approvalEvent.Value, nil
+}
+
+// ParseApprovalForAllLog ERC721 和 ERC1155 的 ApprovalForAll 格式相同
+func ParseApprovalForAllLog(data []byte) (bool, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(string(erc721.Erc721ABI)))
+	if err != nil {
+		return false, err
+	}
+
+	approvalEvent := struct {
+		Approved bool
+	}{}
+
+	err = contractAbi.UnpackIntoInterface(&approvalEvent, "ApprovalForAll", data)
+	if err != nil {
+		return false, err
+	}
+
+	return approvalEvent.Approved, nil
+}
@@ -0,0 +1,683 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package looksrare
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// LooksRareExchangeMetaData contains all meta data concerning the LooksRareExchange contract.
+var LooksRareExchangeMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"name\":\"orderHash\",\"type\":\"bytes32\",\"indexed\":false},{\"name\":\"orderNonce\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"taker\",\"type\":\"address\",\"indexed\":true},{\"name\":\"maker\",\"type\":\"address\",\"indexed\":true},{\"name\":\"strategy\",\"type\":\"address\",\"indexed\":true},{\"name\":\"currency\",\"type\":\"address\",\"indexed\":false},{\"name\":\"collection\",\"type\":\"address\",\"indexed\":false},{\"name\":\"tokenId\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"amount\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"price\",\"type\":\"uint256\",\"indexed\":false}],\"name\":\"TakerAsk\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"name\":\"orderHash\",\"type\":\"bytes32\",\"indexed\":false},{\"name\":\"orderNonce\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"taker\",\"type\":\"address\",\"indexed\":true},{\"name\":\"maker\",\"type\":\"address\",\"indexed\":true},{\"name\":\"strategy\",\"type\":\"address\",\"indexed\":true},{\"name\":\"currency\",\"type\":\"address\",\"indexed\":false},{\"name\":\"collection\",\"type\":\"address\",\"indexed\":false},{\"name\":\"tokenId\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"amount\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"price\",\"type\":\"uint256\",\"indexed\":false}],\"name\":\"TakerBid\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"name\":\"collection\",\"type\":\"address\",\"indexed\":true},{\"name\":\"tokenId\",\"type\":\"uint256\",\"indexed\":true},{\"name\":\"royaltyRecipient\",\"type\":\"address\",\"indexed\":true},{\"name\":\"currency\",\"type\":\"address\",\"indexed\":false},{\"name\":\"amount\",\"type\":\"uint256\",\"indexed\":false}],\"name\":\"RoyaltyPayment\",\"type\":\"event\"}]",
+}
+
+// LooksRareExchangeABI is the input ABI used to generate the binding from.
+// Deprecated: Use LooksRareExchangeMetaData.ABI instead.
+var LooksRareExchangeABI = LooksRareExchangeMetaData.ABI
+
+// LooksRareExchange is an auto generated Go binding around an Ethereum contract.
+type LooksRareExchange struct {
+	LooksRareExchangeCaller     // Read-only binding to the contract
+	LooksRareExchangeTransactor // Write-only binding to the contract
+	LooksRareExchangeFilterer   // Log filterer for contract events
+}
+
+// LooksRareExchangeCaller is an auto generated read-only Go binding around an Ethereum contract.
+type LooksRareExchangeCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LooksRareExchangeTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type LooksRareExchangeTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LooksRareExchangeFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type LooksRareExchangeFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LooksRareExchangeSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type LooksRareExchangeSession struct {
+	Contract     *LooksRareExchange // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts      // Call options to use throughout this session
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// LooksRareExchangeCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type LooksRareExchangeCallerSession struct {
+	Contract *LooksRareExchangeCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts            // Call options to use throughout this session
+}
+
+// LooksRareExchangeTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type LooksRareExchangeTransactorSession struct {
+	Contract     *LooksRareExchangeTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts            // Transaction auth options to use throughout this session
+}
+
+// LooksRareExchangeRaw is an auto generated low-level Go binding around an Ethereum contract.
+type LooksRareExchangeRaw struct {
+	Contract *LooksRareExchange // Generic contract binding to access the raw methods on
+}
+
+// LooksRareExchangeCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type LooksRareExchangeCallerRaw struct {
+	Contract *LooksRareExchangeCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// LooksRareExchangeTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type LooksRareExchangeTransactorRaw struct {
+	Contract *LooksRareExchangeTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewLooksRareExchange creates a new instance of LooksRareExchange, bound to a specific deployed contract.
+func NewLooksRareExchange(address common.Address, backend bind.ContractBackend) (*LooksRareExchange, error) {
+	contract, err := bindLooksRareExchange(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchange{LooksRareExchangeCaller: LooksRareExchangeCaller{contract: contract}, LooksRareExchangeTransactor: LooksRareExchangeTransactor{contract: contract}, LooksRareExchangeFilterer: LooksRareExchangeFilterer{contract: contract}}, nil
+}
+
+// NewLooksRareExchangeCaller creates a new read-only instance of LooksRareExchange, bound to a specific deployed contract.
+func NewLooksRareExchangeCaller(address common.Address, caller bind.ContractCaller) (*LooksRareExchangeCaller, error) {
+	contract, err := bindLooksRareExchange(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchangeCaller{contract: contract}, nil
+}
+
+// NewLooksRareExchangeTransactor creates a new write-only instance of LooksRareExchange, bound to a specific deployed contract.
+func NewLooksRareExchangeTransactor(address common.Address, transactor bind.ContractTransactor) (*LooksRareExchangeTransactor, error) {
+	contract, err := bindLooksRareExchange(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchangeTransactor{contract: contract}, nil
+}
+
+// NewLooksRareExchangeFilterer creates a new log filterer instance of LooksRareExchange, bound to a specific deployed contract.
+func NewLooksRareExchangeFilterer(address common.Address, filterer bind.ContractFilterer) (*LooksRareExchangeFilterer, error) {
+	contract, err := bindLooksRareExchange(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchangeFilterer{contract: contract}, nil
+}
+
+// bindLooksRareExchange binds a generic wrapper to an already deployed contract.
+func bindLooksRareExchange(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := LooksRareExchangeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LooksRareExchange *LooksRareExchangeRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LooksRareExchange.Contract.LooksRareExchangeCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LooksRareExchange *LooksRareExchangeRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LooksRareExchange.Contract.LooksRareExchangeTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LooksRareExchange *LooksRareExchangeRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LooksRareExchange.Contract.LooksRareExchangeTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LooksRareExchange *LooksRareExchangeCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LooksRareExchange.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LooksRareExchange *LooksRareExchangeTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LooksRareExchange.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LooksRareExchange *LooksRareExchangeTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LooksRareExchange.Contract.contract.Transact(opts, method, params...)
+}
+
+// LooksRareExchangeRoyaltyPaymentIterator is returned from FilterRoyaltyPayment and is used to iterate over the raw logs and unpacked data for RoyaltyPayment events raised by the LooksRareExchange contract.
+type LooksRareExchangeRoyaltyPaymentIterator struct {
+	Event *LooksRareExchangeRoyaltyPayment // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LooksRareExchangeRoyaltyPaymentIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LooksRareExchangeRoyaltyPayment)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LooksRareExchangeRoyaltyPayment)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LooksRareExchangeRoyaltyPaymentIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LooksRareExchangeRoyaltyPaymentIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LooksRareExchangeRoyaltyPayment represents a RoyaltyPayment event raised by the LooksRareExchange contract.
+type LooksRareExchangeRoyaltyPayment struct {
+	Collection       common.Address
+	TokenId          *big.Int
+	RoyaltyRecipient common.Address
+	Currency         common.Address
+	Amount           *big.Int
+	Raw              types.Log // Blockchain specific contextual infos
+}
+
+// FilterRoyaltyPayment is a free log retrieval operation binding the contract event 0x27c4f0403323142b599832f26acd21c74a9e5b809f2215726e244a4ac588cd7d.
+//
+// Solidity: event RoyaltyPayment(address indexed collection, uint256 indexed tokenId, address indexed royaltyRecipient, address currency, uint256 amount)
+func (_LooksRareExchange *LooksRareExchangeFilterer) FilterRoyaltyPayment(opts *bind.FilterOpts, collection []common.Address, tokenId []*big.Int, royaltyRecipient []common.Address) (*LooksRareExchangeRoyaltyPaymentIterator, error) {
+
+	var collectionRule []interface{}
+	for _, collectionItem := range collection {
+		collectionRule = append(collectionRule, collectionItem)
+	}
+	var tokenIdRule []interface{}
+	for _, tokenIdItem := range tokenId {
+		tokenIdRule = append(tokenIdRule, tokenIdItem)
+	}
+	var royaltyRecipientRule []interface{}
+	for _, royaltyRecipientItem := range royaltyRecipient {
+		royaltyRecipientRule = append(royaltyRecipientRule, royaltyRecipientItem)
+	}
+
+	logs, sub, err := _LooksRareExchange.contract.FilterLogs(opts, "RoyaltyPayment", collectionRule, tokenIdRule, royaltyRecipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchangeRoyaltyPaymentIterator{contract: _LooksRareExchange.contract, event: "RoyaltyPayment", logs: logs, sub: sub}, nil
+}
+
+// WatchRoyaltyPayment is a free log subscription operation binding the contract event 0x27c4f0403323142b599832f26acd21c74a9e5b809f2215726e244a4ac588cd7d.
+//
+// Solidity: event RoyaltyPayment(address indexed collection, uint256 indexed tokenId, address indexed royaltyRecipient, address currency, uint256 amount)
+func (_LooksRareExchange *LooksRareExchangeFilterer) WatchRoyaltyPayment(opts *bind.WatchOpts, sink chan<- *LooksRareExchangeRoyaltyPayment, collection []common.Address, tokenId []*big.Int, royaltyRecipient []common.Address) (event.Subscription, error) {
+
+	var collectionRule []interface{}
+	for _, collectionItem := range collection {
+		collectionRule = append(collectionRule, collectionItem)
+	}
+	var tokenIdRule []interface{}
+	for _, tokenIdItem := range tokenId {
+		tokenIdRule = append(tokenIdRule, tokenIdItem)
+	}
+	var royaltyRecipientRule []interface{}
+	for _, royaltyRecipientItem := range royaltyRecipient {
+		royaltyRecipientRule = append(royaltyRecipientRule, royaltyRecipientItem)
+	}
+
+	logs, sub, err := _LooksRareExchange.contract.WatchLogs(opts, "RoyaltyPayment", collectionRule, tokenIdRule, royaltyRecipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LooksRareExchangeRoyaltyPayment)
+				if err := _LooksRareExchange.contract.UnpackLog(event, "RoyaltyPayment", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRoyaltyPayment is a log parse operation binding the contract event 0x27c4f0403323142b599832f26acd21c74a9e5b809f2215726e244a4ac588cd7d.
+//
+// Solidity: event RoyaltyPayment(address indexed collection, uint256 indexed tokenId, address indexed royaltyRecipient, address currency, uint256 amount)
+func (_LooksRareExchange *LooksRareExchangeFilterer) ParseRoyaltyPayment(log types.Log) (*LooksRareExchangeRoyaltyPayment, error) {
+	event := new(LooksRareExchangeRoyaltyPayment)
+	if err := _LooksRareExchange.contract.UnpackLog(event, "RoyaltyPayment", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LooksRareExchangeTakerAskIterator is returned from FilterTakerAsk and is used to iterate over the raw logs and unpacked data for TakerAsk events raised by the LooksRareExchange contract.
+type LooksRareExchangeTakerAskIterator struct {
+	Event *LooksRareExchangeTakerAsk // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LooksRareExchangeTakerAskIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LooksRareExchangeTakerAsk)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LooksRareExchangeTakerAsk)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LooksRareExchangeTakerAskIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LooksRareExchangeTakerAskIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LooksRareExchangeTakerAsk represents a TakerAsk event raised by the LooksRareExchange contract.
+type LooksRareExchangeTakerAsk struct {
+	OrderHash  [32]byte
+	OrderNonce *big.Int
+	Taker      common.Address
+	Maker      common.Address
+	Strategy   common.Address
+	Currency   common.Address
+	Collection common.Address
+	TokenId    *big.Int
+	Amount     *big.Int
+	Price      *big.Int
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterTakerAsk is a free log retrieval operation binding the contract event 0x68cd251d4d267c6e2034ff0088b990352b97b2002c0476587d0c4da889c11330.
+//
+// Solidity: event TakerAsk(bytes32 orderHash, uint256 orderNonce, address indexed taker, address indexed maker, address indexed strategy, address currency, address collection, uint256 tokenId, uint256 amount, uint256 price)
+func (_LooksRareExchange *LooksRareExchangeFilterer) FilterTakerAsk(opts *bind.FilterOpts, taker []common.Address, maker []common.Address, strategy []common.Address) (*LooksRareExchangeTakerAskIterator, error) {
+
+	var takerRule []interface{}
+	for _, takerItem := range taker {
+		takerRule = append(takerRule, takerItem)
+	}
+	var makerRule []interface{}
+	for _, makerItem := range maker {
+		makerRule = append(makerRule, makerItem)
+	}
+	var strategyRule []interface{}
+	for _, strategyItem := range strategy {
+		strategyRule = append(strategyRule, strategyItem)
+	}
+
+	logs, sub, err := _LooksRareExchange.contract.FilterLogs(opts, "TakerAsk", takerRule, makerRule, strategyRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchangeTakerAskIterator{contract: _LooksRareExchange.contract, event: "TakerAsk", logs: logs, sub: sub}, nil
+}
+
+// WatchTakerAsk is a free log subscription operation binding the contract event 0x68cd251d4d267c6e2034ff0088b990352b97b2002c0476587d0c4da889c11330.
+//
+// Solidity: event TakerAsk(bytes32 orderHash, uint256 orderNonce, address indexed taker, address indexed maker, address indexed strategy, address currency, address collection, uint256 tokenId, uint256 amount, uint256 price)
+func (_LooksRareExchange *LooksRareExchangeFilterer) WatchTakerAsk(opts *bind.WatchOpts, sink chan<- *LooksRareExchangeTakerAsk, taker []common.Address, maker []common.Address, strategy []common.Address) (event.Subscription, error) {
+
+	var takerRule []interface{}
+	for _, takerItem := range taker {
+		takerRule = append(takerRule, takerItem)
+	}
+	var makerRule []interface{}
+	for _, makerItem := range maker {
+		makerRule = append(makerRule, makerItem)
+	}
+	var strategyRule []interface{}
+	for _, strategyItem := range strategy {
+		strategyRule = append(strategyRule, strategyItem)
+	}
+
+	logs, sub, err := _LooksRareExchange.contract.WatchLogs(opts, "TakerAsk", takerRule, makerRule, strategyRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LooksRareExchangeTakerAsk)
+				if err := _LooksRareExchange.contract.UnpackLog(event, "TakerAsk", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTakerAsk is a log parse operation binding the contract event 0x68cd251d4d267c6e2034ff0088b990352b97b2002c0476587d0c4da889c11330.
+//
+// Solidity: event TakerAsk(bytes32 orderHash, uint256 orderNonce, address indexed taker, address indexed maker, address indexed strategy, address currency, address collection, uint256 tokenId, uint256 amount, uint256 price)
+func (_LooksRareExchange *LooksRareExchangeFilterer) ParseTakerAsk(log types.Log) (*LooksRareExchangeTakerAsk, error) {
+	event := new(LooksRareExchangeTakerAsk)
+	if err := _LooksRareExchange.contract.UnpackLog(event, "TakerAsk", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LooksRareExchangeTakerBidIterator is returned from FilterTakerBid and is used to iterate over the raw logs and unpacked data for TakerBid events raised by the LooksRareExchange contract.
+type LooksRareExchangeTakerBidIterator struct {
+	Event *LooksRareExchangeTakerBid // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LooksRareExchangeTakerBidIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LooksRareExchangeTakerBid)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LooksRareExchangeTakerBid)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LooksRareExchangeTakerBidIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LooksRareExchangeTakerBidIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LooksRareExchangeTakerBid represents a TakerBid event raised by the LooksRareExchange contract.
+type LooksRareExchangeTakerBid struct {
+	OrderHash  [32]byte
+	OrderNonce *big.Int
+	Taker      common.Address
+	Maker      common.Address
+	Strategy   common.Address
+	Currency   common.Address
+	Collection common.Address
+	TokenId    *big.Int
+	Amount     *big.Int
+	Price      *big.Int
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterTakerBid is a free log retrieval operation binding the contract event 0x95fb6205e23ff6bda16a2d1dba56b9ad7c783f67c96fa149785052f47696f2be.
+//
+// Solidity: event TakerBid(bytes32 orderHash, uint256 orderNonce, address indexed taker, address indexed maker, address indexed strategy, address currency, address collection, uint256 tokenId, uint256 amount, uint256 price)
+func (_LooksRareExchange *LooksRareExchangeFilterer) FilterTakerBid(opts *bind.FilterOpts, taker []common.Address, maker []common.Address, strategy []common.Address) (*LooksRareExchangeTakerBidIterator, error) {
+
+	var takerRule []interface{}
+	for _, takerItem := range taker {
+		takerRule = append(takerRule, takerItem)
+	}
+	var makerRule []interface{}
+	for _, makerItem := range maker {
+		makerRule = append(makerRule, makerItem)
+	}
+	var strategyRule []interface{}
+	for _, strategyItem := range strategy {
+		strategyRule = append(strategyRule, strategyItem)
+	}
+
+	logs, sub, err := _LooksRareExchange.contract.FilterLogs(opts, "TakerBid", takerRule, makerRule, strategyRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LooksRareExchangeTakerBidIterator{contract: _LooksRareExchange.contract, event: "TakerBid", logs: logs, sub: sub}, nil
+}
+
+// WatchTakerBid is a free log subscription operation binding the contract event 0x95fb6205e23ff6bda16a2d1dba56b9ad7c783f67c96fa149785052f47696f2be.
+//
+// Solidity: event TakerBid(bytes32 orderHash, uint256 orderNonce, address indexed taker, address indexed maker, address indexed strategy, address currency, address collection, uint256 tokenId, uint256 amount, uint256 price)
+func (_LooksRareExchange *LooksRareExchangeFilterer) WatchTakerBid(opts *bind.WatchOpts, sink chan<- *LooksRareExchangeTakerBid, taker []common.Address, maker []common.Address, strategy []common.Address) (event.Subscription, error) {
+
+	var takerRule []interface{}
+	for _, takerItem := range taker {
+		takerRule = append(takerRule, takerItem)
+	}
+	var makerRule []interface{}
+	for _, makerItem := range maker {
+		makerRule = append(makerRule, makerItem)
+	}
+	var strategyRule []interface{}
+	for _, strategyItem := range strategy {
+		strategyRule = append(strategyRule, strategyItem)
+	}
+
+	logs, sub, err := _LooksRareExchange.contract.WatchLogs(opts, "TakerBid", takerRule, makerRule, strategyRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LooksRareExchangeTakerBid)
+				if err := _LooksRareExchange.contract.UnpackLog(event, "TakerBid", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTakerBid is a log parse operation binding the contract event 0x95fb6205e23ff6bda16a2d1dba56b9ad7c783f67c96fa149785052f47696f2be.
+//
+// Solidity: event TakerBid(bytes32 orderHash, uint256 orderNonce, address indexed taker, address indexed maker, address indexed strategy, address currency, address collection, uint256 tokenId, uint256 amount, uint256 price)
+func (_LooksRareExchange *LooksRareExchangeFilterer) ParseTakerBid(log types.Log) (*LooksRareExchangeTakerBid, error) {
+	event := new(LooksRareExchangeTakerBid)
+	if err := _LooksRareExchange.contract.UnpackLog(event, "TakerBid", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,561 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package mooncats
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// MoonCatRescueMetaData contains all meta data concerning the MoonCatRescue contract.
+var MoonCatRescueMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"bytes5\",\"name\":\"catId\",\"type\":\"bytes5\"}],\"name\":\"CatRescued\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes5\",\"name\":\"catId\",\"type\":\"bytes5\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"}],\"name\":\"CatAdopted\",\"type\":\"event\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"bytes5\"}],\"name\":\"catOwners\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"rescueOrder\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes5\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// MoonCatRescueABI is the input ABI used to generate the binding from.
+// Deprecated: Use MoonCatRescueMetaData.ABI instead.
+var MoonCatRescueABI = MoonCatRescueMetaData.ABI
+
+// MoonCatRescue is an auto generated Go binding around an Ethereum contract.
+type MoonCatRescue struct {
+	MoonCatRescueCaller     // Read-only binding to the contract
+	MoonCatRescueTransactor // Write-only binding to the contract
+	MoonCatRescueFilterer   // Log filterer for contract events
+}
+
+// MoonCatRescueCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MoonCatRescueCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MoonCatRescueTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MoonCatRescueTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MoonCatRescueFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type MoonCatRescueFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MoonCatRescueSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MoonCatRescueSession struct {
+	Contract     *MoonCatRescue    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MoonCatRescueCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MoonCatRescueCallerSession struct {
+	Contract *MoonCatRescueCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// MoonCatRescueTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MoonCatRescueTransactorSession struct {
+	Contract     *MoonCatRescueTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// MoonCatRescueRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MoonCatRescueRaw struct {
+	Contract *MoonCatRescue // Generic contract binding to access the raw methods on
+}
+
+// MoonCatRescueCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MoonCatRescueCallerRaw struct {
+	Contract *MoonCatRescueCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MoonCatRescueTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MoonCatRescueTransactorRaw struct {
+	Contract *MoonCatRescueTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMoonCatRescue creates a new instance of MoonCatRescue, bound to a specific deployed contract.
+func NewMoonCatRescue(address common.Address, backend bind.ContractBackend) (*MoonCatRescue, error) {
+	contract, err := bindMoonCatRescue(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MoonCatRescue{MoonCatRescueCaller: MoonCatRescueCaller{contract: contract}, MoonCatRescueTransactor: MoonCatRescueTransactor{contract: contract}, MoonCatRescueFilterer: MoonCatRescueFilterer{contract: contract}}, nil
+}
+
+// NewMoonCatRescueCaller creates a new read-only instance of MoonCatRescue, bound to a specific deployed contract.
+func NewMoonCatRescueCaller(address common.Address, caller bind.ContractCaller) (*MoonCatRescueCaller, error) {
+	contract, err := bindMoonCatRescue(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MoonCatRescueCaller{contract: contract}, nil
+}
+
+// NewMoonCatRescueTransactor creates a new write-only instance of MoonCatRescue, bound to a specific deployed contract.
+func NewMoonCatRescueTransactor(address common.Address, transactor bind.ContractTransactor) (*MoonCatRescueTransactor, error) {
+	contract, err := bindMoonCatRescue(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MoonCatRescueTransactor{contract: contract}, nil
+}
+
+// NewMoonCatRescueFilterer creates a new log filterer instance of MoonCatRescue, bound to a specific deployed contract.
+func NewMoonCatRescueFilterer(address common.Address, filterer bind.ContractFilterer) (*MoonCatRescueFilterer, error) {
+	contract, err := bindMoonCatRescue(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MoonCatRescueFilterer{contract: contract}, nil
+}
+
+// bindMoonCatRescue binds a generic wrapper to an already deployed contract.
+func bindMoonCatRescue(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := MoonCatRescueMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MoonCatRescue *MoonCatRescueRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _MoonCatRescue.Contract.MoonCatRescueCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MoonCatRescue *MoonCatRescueRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MoonCatRescue.Contract.MoonCatRescueTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MoonCatRescue *MoonCatRescueRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MoonCatRescue.Contract.MoonCatRescueTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MoonCatRescue *MoonCatRescueCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _MoonCatRescue.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MoonCatRescue *MoonCatRescueTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MoonCatRescue.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MoonCatRescue *MoonCatRescueTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MoonCatRescue.Contract.contract.Transact(opts, method, params...)
+}
+
+// CatOwners is a free data retrieval call binding the contract method 0x3894ca57.
+//
+// Solidity: function catOwners(bytes5 ) view returns(address)
+func (_MoonCatRescue *MoonCatRescueCaller) CatOwners(opts *bind.CallOpts, arg0 [5]byte) (common.Address, error) {
+	var out []interface{}
+	err := _MoonCatRescue.contract.Call(opts, &out, "catOwners", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// CatOwners is a free data retrieval call binding the contract method 0x3894ca57.
+//
+// Solidity: function catOwners(bytes5 ) view returns(address)
+func (_MoonCatRescue *MoonCatRescueSession) CatOwners(arg0 [5]byte) (common.Address, error) {
+	return _MoonCatRescue.Contract.CatOwners(&_MoonCatRescue.CallOpts, arg0)
+}
+
+// CatOwners is a free data retrieval call binding the contract method 0x3894ca57.
+//
+// Solidity: function catOwners(bytes5 ) view returns(address)
+func (_MoonCatRescue *MoonCatRescueCallerSession) CatOwners(arg0 [5]byte) (common.Address, error) {
+	return _MoonCatRescue.Contract.CatOwners(&_MoonCatRescue.CallOpts, arg0)
+}
+
+// RescueOrder is a free data retrieval call binding the contract method 0x434b1208.
+//
+// Solidity: function rescueOrder(uint256 ) view returns(bytes5)
+func (_MoonCatRescue *MoonCatRescueCaller) RescueOrder(opts *bind.CallOpts, arg0 *big.Int) ([5]byte, error) {
+	var out []interface{}
+	err := _MoonCatRescue.contract.Call(opts, &out, "rescueOrder", arg0)
+
+	if err != nil {
+		return *new([5]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([5]byte)).(*[5]byte)
+
+	return out0, err
+
+}
+
+// RescueOrder is a free data retrieval call binding the contract method 0x434b1208.
+//
+// Solidity: function rescueOrder(uint256 ) view returns(bytes5)
+func (_MoonCatRescue *MoonCatRescueSession) RescueOrder(arg0 *big.Int) ([5]byte, error) {
+	return _MoonCatRescue.Contract.RescueOrder(&_MoonCatRescue.CallOpts, arg0)
+}
+
+// RescueOrder is a free data retrieval call binding the contract method 0x434b1208.
+//
+// Solidity: function rescueOrder(uint256 ) view returns(bytes5)
+func (_MoonCatRescue *MoonCatRescueCallerSession) RescueOrder(arg0 *big.Int) ([5]byte, error) {
+	return _MoonCatRescue.Contract.RescueOrder(&_MoonCatRescue.CallOpts, arg0)
+}
+
+// MoonCatRescueCatAdoptedIterator is returned from FilterCatAdopted and is used to iterate over the raw logs and unpacked data for CatAdopted events raised by the MoonCatRescue contract.
+type MoonCatRescueCatAdoptedIterator struct {
+	Event *MoonCatRescueCatAdopted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MoonCatRescueCatAdoptedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MoonCatRescueCatAdopted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MoonCatRescueCatAdopted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MoonCatRescueCatAdoptedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MoonCatRescueCatAdoptedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MoonCatRescueCatAdopted represents a CatAdopted event raised by the MoonCatRescue contract.
+type MoonCatRescueCatAdopted struct {
+	CatId [5]byte
+	Price *big.Int
+	From  common.Address
+	To    common.Address
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterCatAdopted is a free log retrieval operation binding the contract event 0x1d9becf52be84ecb1e1e8de532c6cea871c0903fdcc9675123ca5c3c2cb43625.
+//
+// Solidity: event CatAdopted(bytes5 indexed catId, uint256 price, address indexed from, address indexed to)
+func (_MoonCatRescue *MoonCatRescueFilterer) FilterCatAdopted(opts *bind.FilterOpts, catId [][5]byte, from []common.Address, to []common.Address) (*MoonCatRescueCatAdoptedIterator, error) {
+
+	var catIdRule []interface{}
+	for _, catIdItem := range catId {
+		catIdRule = append(catIdRule, catIdItem)
+	}
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _MoonCatRescue.contract.FilterLogs(opts, "CatAdopted", catIdRule, fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MoonCatRescueCatAdoptedIterator{contract: _MoonCatRescue.contract, event: "CatAdopted", logs: logs, sub: sub}, nil
+}
+
+// WatchCatAdopted is a free log subscription operation binding the contract event 0x1d9becf52be84ecb1e1e8de532c6cea871c0903fdcc9675123ca5c3c2cb43625.
+//
+// Solidity: event CatAdopted(bytes5 indexed catId, uint256 price, address indexed from, address indexed to)
+func (_MoonCatRescue *MoonCatRescueFilterer) WatchCatAdopted(opts *bind.WatchOpts, sink chan<- *MoonCatRescueCatAdopted, catId [][5]byte, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var catIdRule []interface{}
+	for _, catIdItem := range catId {
+		catIdRule = append(catIdRule, catIdItem)
+	}
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _MoonCatRescue.contract.WatchLogs(opts, "CatAdopted", catIdRule, fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MoonCatRescueCatAdopted)
+				if err := _MoonCatRescue.contract.UnpackLog(event, "CatAdopted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCatAdopted is a log parse operation binding the contract event 0x1d9becf52be84ecb1e1e8de532c6cea871c0903fdcc9675123ca5c3c2cb43625.
+//
+// Solidity: event CatAdopted(bytes5 indexed catId, uint256 price, address indexed from, address indexed to)
+func (_MoonCatRescue *MoonCatRescueFilterer) ParseCatAdopted(log types.Log) (*MoonCatRescueCatAdopted, error) {
+	event := new(MoonCatRescueCatAdopted)
+	if err := _MoonCatRescue.contract.UnpackLog(event, "CatAdopted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// MoonCatRescueCatRescuedIterator is returned from FilterCatRescued and is used to iterate over the raw logs and unpacked data for CatRescued events raised by the MoonCatRescue contract.
+type MoonCatRescueCatRescuedIterator struct {
+	Event *MoonCatRescueCatRescued // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MoonCatRescueCatRescuedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MoonCatRescueCatRescued)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MoonCatRescueCatRescued)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MoonCatRescueCatRescuedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MoonCatRescueCatRescuedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MoonCatRescueCatRescued represents a CatRescued event raised by the MoonCatRescue contract.
+type MoonCatRescueCatRescued struct {
+	To    common.Address
+	CatId [5]byte
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterCatRescued is a free log retrieval operation binding the contract event 0x80d2c1a6c75f471130a64fd71b80dc7208f721037766fb7decf53e10f82211cd.
+//
+// Solidity: event CatRescued(address indexed to, bytes5 indexed catId)
+func (_MoonCatRescue *MoonCatRescueFilterer) FilterCatRescued(opts *bind.FilterOpts, to []common.Address, catId [][5]byte) (*MoonCatRescueCatRescuedIterator, error) {
+
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+	var catIdRule []interface{}
+	for _, catIdItem := range catId {
+		catIdRule = append(catIdRule, catIdItem)
+	}
+
+	logs, sub, err := _MoonCatRescue.contract.FilterLogs(opts, "CatRescued", toRule, catIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MoonCatRescueCatRescuedIterator{contract: _MoonCatRescue.contract, event: "CatRescued", logs: logs, sub: sub}, nil
+}
+
+// WatchCatRescued is a free log subscription operation binding the contract event 0x80d2c1a6c75f471130a64fd71b80dc7208f721037766fb7decf53e10f82211cd.
+//
+// Solidity: event CatRescued(address indexed to, bytes5 indexed catId)
+func (_MoonCatRescue *MoonCatRescueFilterer) WatchCatRescued(opts *bind.WatchOpts, sink chan<- *MoonCatRescueCatRescued, to []common.Address, catId [][5]byte) (event.Subscription, error) {
+
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+	var catIdRule []interface{}
+	for _, catIdItem := range catId {
+		catIdRule = append(catIdRule, catIdItem)
+	}
+
+	logs, sub, err := _MoonCatRescue.contract.WatchLogs(opts, "CatRescued", toRule, catIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MoonCatRescueCatRescued)
+				if err := _MoonCatRescue.contract.UnpackLog(event, "CatRescued", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCatRescued is a log parse operation binding the contract event 0x80d2c1a6c75f471130a64fd71b80dc7208f721037766fb7decf53e10f82211cd.
+//
+// Solidity: event CatRescued(address indexed to, bytes5 indexed catId)
+func (_MoonCatRescue *MoonCatRescueFilterer) ParseCatRescued(log types.Log) (*MoonCatRescueCatRescued, error) {
+	event := new(MoonCatRescueCatRescued)
+	if err := _MoonCatRescue.contract.UnpackLog(event, "CatRescued", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
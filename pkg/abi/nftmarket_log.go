@@ -0,0 +1,120 @@
+package abi
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/traitmeta/metago/pkg/abi/blur"
+	"github.com/traitmeta/metago/pkg/abi/looksrare"
+	"github.com/traitmeta/metago/pkg/abi/seaport"
+	"github.com/traitmeta/metago/pkg/abi/x2y2"
+)
+
+// LooksRareTakerLog LooksRare TakerAsk/TakerBid 中未 indexed 的字段, 两个事件字段相同
+type LooksRareTakerLog struct {
+	OrderHash  [32]byte
+	OrderNonce *big.Int
+	Currency   common.Address
+	Collection common.Address
+	TokenId    *big.Int
+	Amount     *big.Int
+	Price      *big.Int
+}
+
+// X2Y2Item X2Y2 订单中 item.data 编码的 NFT
+type X2Y2Item struct {
+	Token   common.Address
+	TokenId *big.Int
+	Amount  *big.Int
+}
+
+func unpackLog(abiJSON, name string, data []byte, out interface{}) error {
+	contractAbi, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+
+	return contractAbi.UnpackIntoInterface(out, name, data)
+}
+
+// ParseSeaportOrderFulfilledLog offerer 和 zone 为 indexed 字段, 需要从 topic 中读取
+func ParseSeaportOrderFulfilledLog(data []byte) (*seaport.SeaportOrderFulfilled, error) {
+	var event seaport.SeaportOrderFulfilled
+	if err := unpackLog(seaport.SeaportABI, "OrderFulfilled", data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ParseBlurOrdersMatchedLog maker 和 taker 为 indexed 字段, 需要从 topic 中读取
+func ParseBlurOrdersMatchedLog(data []byte) (*blur.BlurExchangeOrdersMatched, error) {
+	var event blur.BlurExchangeOrdersMatched
+	if err := unpackLog(blur.BlurExchangeABI, "OrdersMatched", data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ParseLooksRareTakerLog name 为 TakerAsk 或 TakerBid
+func ParseLooksRareTakerLog(name string, data []byte) (*LooksRareTakerLog, error) {
+	var event LooksRareTakerLog
+	if err := unpackLog(looksrare.LooksRareExchangeABI, name, data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func ParseLooksRareRoyaltyPaymentLog(data []byte) (currency common.Address, amount *big.Int, err error) {
+	event := struct {
+		Currency common.Address
+		Amount   *big.Int
+	}{}
+	if err = unpackLog(looksrare.LooksRareExchangeABI, "RoyaltyPayment", data, &event); err != nil {
+		return
+	}
+	return event.Currency, event.Amount, nil
+}
+
+// ParseX2Y2InventoryLog itemHash 为 indexed 字段, 需要从 topic 中读取
+func ParseX2Y2InventoryLog(data []byte) (*x2y2.X2Y2EvInventory, error) {
+	var event x2y2.X2Y2EvInventory
+	if err := unpackLog(x2y2.X2Y2ABI, "EvInventory", data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ParseX2Y2ItemData 解析 X2Y2 item.data, ERC721 编码为 (token, tokenId)[], ERC1155 额外带有数量
+func ParseX2Y2ItemData(data []byte, erc1155 bool) ([]X2Y2Item, error) {
+	components := []abi.ArgumentMarshaling{
+		{Name: "token", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+	}
+	if erc1155 {
+		components = append(components, abi.ArgumentMarshaling{Name: "amount", Type: "uint256"})
+	}
+	itemsType, err := abi.NewType("tuple[]", "", components)
+	if err != nil {
+		return nil, err
+	}
+
+	args := abi.Arguments{{Type: itemsType}}
+	values, err := args.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []X2Y2Item
+	if err = args.Copy(&items, values); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].Amount == nil {
+			items[i].Amount = big.NewInt(1)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,182 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/traitmeta/metago/pkg/abi/blur"
+	"github.com/traitmeta/metago/pkg/abi/cryptopunks"
+	"github.com/traitmeta/metago/pkg/abi/entrypoint"
+	"github.com/traitmeta/metago/pkg/abi/erc1155"
+	"github.com/traitmeta/metago/pkg/abi/erc20"
+	"github.com/traitmeta/metago/pkg/abi/erc721"
+	"github.com/traitmeta/metago/pkg/abi/looksrare"
+	"github.com/traitmeta/metago/pkg/abi/mooncats"
+	"github.com/traitmeta/metago/pkg/abi/seaport"
+	"github.com/traitmeta/metago/pkg/abi/uniswapv2"
+	"github.com/traitmeta/metago/pkg/abi/uniswapv3"
+	"github.com/traitmeta/metago/pkg/abi/x2y2"
+)
+
+/*
+## Overview
+  失败交易返回数据的解析顺序:
+  1. `Error(string)` 返回字符串本身.
+  2. `Panic(uint256)` 返回 `panic: <说明>`.
+  3. 自定义错误按前4字节在错误注册表中查找, 返回 `Name(arg, ...)`. 注册表包含 commonErrorsABI、
+     仓库中合约绑定的 ABI 和通过 RegisterErrors 注册的 ABI.
+  4. 查找不到时返回完整的十六进制数据, 之后注册了对应 ABI 可以重新解析.
+*/
+
+var (
+	revertSelector = common.FromHex("0x08c379a0")
+	panicSelector  = common.FromHex("0x4e487b71")
+)
+
+// commonErrorsABI 常见合约的自定义错误, 仓库中的合约绑定在 init 中注册, 其他合约的错误通过 RegisterErrors 注册
+const commonErrorsABI = `[
+{"type":"error","name":"ERC20InsufficientBalance","inputs":[{"name":"sender","type":"address"},{"name":"balance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+{"type":"error","name":"ERC20InsufficientAllowance","inputs":[{"name":"spender","type":"address"},{"name":"allowance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+{"type":"error","name":"ERC20InvalidSender","inputs":[{"name":"sender","type":"address"}]},
+{"type":"error","name":"ERC20InvalidReceiver","inputs":[{"name":"receiver","type":"address"}]},
+{"type":"error","name":"ERC721NonexistentToken","inputs":[{"name":"tokenId","type":"uint256"}]},
+{"type":"error","name":"ERC721IncorrectOwner","inputs":[{"name":"sender","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"owner","type":"address"}]},
+{"type":"error","name":"ERC721InsufficientApproval","inputs":[{"name":"operator","type":"address"},{"name":"tokenId","type":"uint256"}]},
+{"type":"error","name":"ERC1155InsufficientBalance","inputs":[{"name":"sender","type":"address"},{"name":"balance","type":"uint256"},{"name":"needed","type":"uint256"},{"name":"tokenId","type":"uint256"}]},
+{"type":"error","name":"OwnableUnauthorizedAccount","inputs":[{"name":"account","type":"address"}]},
+{"type":"error","name":"AccessControlUnauthorizedAccount","inputs":[{"name":"account","type":"address"},{"name":"neededRole","type":"bytes32"}]},
+{"type":"error","name":"EnforcedPause","inputs":[]},
+{"type":"error","name":"ReentrancyGuardReentrantCall","inputs":[]},
+{"type":"error","name":"SafeERC20FailedOperation","inputs":[{"name":"token","type":"address"}]},
+{"type":"error","name":"FailedOp","inputs":[{"name":"opIndex","type":"uint256"},{"name":"reason","type":"string"}]},
+{"type":"error","name":"FailedOpWithRevert","inputs":[{"name":"opIndex","type":"uint256"},{"name":"reason","type":"string"},{"name":"inner","type":"bytes"}]},
+{"type":"error","name":"ExecutionFailed","inputs":[{"name":"commandIndex","type":"uint256"},{"name":"message","type":"bytes"}]},
+{"type":"error","name":"TransactionDeadlinePassed","inputs":[]},
+{"type":"error","name":"V2TooLittleReceived","inputs":[]},
+{"type":"error","name":"V3TooLittleReceived","inputs":[]},
+{"type":"error","name":"V3TooMuchRequested","inputs":[]}
+]`
+
+var (
+	errorsMu       sync.RWMutex
+	errorsRegistry = map[[4]byte]abi.Error{}
+)
+
+// bindingsMetaData 仓库中生成的合约绑定, ABI 中声明的自定义错误在启动时注册
+var bindingsMetaData = []*bind.MetaData{
+	blur.BlurExchangeMetaData,
+	cryptopunks.CryptoPunksMarketMetaData,
+	entrypoint.EntryPointMetaData,
+	erc1155.Erc1155MetaData,
+	erc20.Erc20MetaData,
+	erc721.Erc721MetaData,
+	looksrare.LooksRareExchangeMetaData,
+	mooncats.MoonCatRescueMetaData,
+	seaport.SeaportMetaData,
+	uniswapv2.UniswapV2PairMetaData,
+	uniswapv3.UniswapV3PoolMetaData,
+	x2y2.X2Y2MetaData,
+}
+
+func init() {
+	if err := RegisterErrors(commonErrorsABI); err != nil {
+		panic(err)
+	}
+	for _, md := range bindingsMetaData {
+		if err := RegisterErrors(md.ABI); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterErrors 注册 ABI 中的自定义错误, 选择器相同时后注册的覆盖之前的
+func RegisterErrors(abiJSON string) error {
+	contractAbi, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+
+	errorsMu.Lock()
+	defer errorsMu.Unlock()
+	for _, e := range contractAbi.Errors {
+		var selector [4]byte
+		copy(selector[:], e.ID[:4])
+		errorsRegistry[selector] = e
+	}
+	return nil
+}
+
+func lookupError(data []byte) (abi.Error, bool) {
+	var selector [4]byte
+	copy(selector[:], data[:4])
+
+	errorsMu.RLock()
+	defer errorsMu.RUnlock()
+	e, ok := errorsRegistry[selector]
+	return e, ok
+}
+
+// DecodeRevertReason 解析失败交易的返回数据, 没有数据时返回空字符串
+func DecodeRevertReason(data []byte) string {
+	if len(data) < 4 {
+		if len(data) == 0 {
+			return ""
+		}
+		return hexutil.Encode(data)
+	}
+
+	switch {
+	case bytes.Equal(data[:4], revertSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return reason
+		}
+	case bytes.Equal(data[:4], panicSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return "panic: " + reason
+		}
+	default:
+		if e, ok := lookupError(data); ok {
+			if values, err := e.Unpack(data); err == nil {
+				return formatError(e, values.([]interface{}))
+			}
+		}
+	}
+	return hexutil.Encode(data)
+}
+
+func formatError(e abi.Error, values []interface{}) string {
+	args := make([]string, 0, len(values))
+	for _, value := range values {
+		args = append(args, formatValue(value))
+	}
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []byte:
+		// 嵌套的返回数据继续解析, 例如 Universal Router 的 ExecutionFailed
+		if len(v) >= 4 {
+			return DecodeRevertReason(v)
+		}
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
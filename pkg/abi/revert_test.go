@@ -0,0 +1,77 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func packError(t *testing.T, signature string, types []string, values ...interface{}) []byte {
+	args := abi.Arguments{}
+	for _, typ := range types {
+		abiType, err := abi.NewType(typ, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, abi.Argument{Type: abiType})
+	}
+	data, err := args.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(crypto.Keccak256([]byte(signature))[:4], data...)
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	sender := common.HexToAddress("0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e")
+	unknown := packError(t, "Unknown(uint256)", []string{"uint256"}, big.NewInt(1))
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "empty", data: nil, want: ""},
+		{
+			name: "error string",
+			data: packError(t, "Error(string)", []string{"string"}, "Ownable: caller is not the owner"),
+			want: "Ownable: caller is not the owner",
+		},
+		{
+			name: "panic",
+			data: packError(t, "Panic(uint256)", []string{"uint256"}, big.NewInt(0x11)),
+			want: "panic: arithmetic underflow or overflow",
+		},
+		{
+			name: "registered custom error",
+			data: packError(t, "ERC20InsufficientBalance(address,uint256,uint256)", []string{"address", "uint256", "uint256"},
+				sender, big.NewInt(100), big.NewInt(200)),
+			want: "ERC20InsufficientBalance(0x2c0E7e53fBB9d1ab31F2E39f7e4c2E3Af1b24D8e, 100, 200)",
+		},
+		{
+			name: "nested revert data",
+			data: packError(t, "ExecutionFailed(uint256,bytes)", []string{"uint256", "bytes"},
+				big.NewInt(1), packError(t, "V3TooLittleReceived()", nil)),
+			want: "ExecutionFailed(1, V3TooLittleReceived())",
+		},
+		{name: "unknown custom error", data: unknown, want: "0x" + common.Bytes2Hex(unknown)},
+		{name: "short data", data: []byte{0x01}, want: "0x01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeRevertReason(tt.data); got != tt.want {
+				t.Errorf("DecodeRevertReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := RegisterErrors(`[{"type":"error","name":"Unknown","inputs":[{"name":"code","type":"uint256"}]}]`); err != nil {
+		t.Fatal(err)
+	}
+	if got := DecodeRevertReason(unknown); got != "Unknown(1)" {
+		t.Errorf("DecodeRevertReason() after RegisterErrors = %v, want Unknown(1)", got)
+	}
+}
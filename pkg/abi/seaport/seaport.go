@@ -0,0 +1,355 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package seaport
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// ReceivedItem is an auto generated low-level Go binding around an user-defined struct.
+type ReceivedItem struct {
+	ItemType   uint8
+	Token      common.Address
+	Identifier *big.Int
+	Amount     *big.Int
+	Recipient  common.Address
+}
+
+// SpentItem is an auto generated low-level Go binding around an user-defined struct.
+type SpentItem struct {
+	ItemType   uint8
+	Token      common.Address
+	Identifier *big.Int
+	Amount     *big.Int
+}
+
+// SeaportMetaData contains all meta data concerning the Seaport contract.
+var SeaportMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"name\":\"orderHash\",\"type\":\"bytes32\",\"indexed\":false},{\"name\":\"offerer\",\"type\":\"address\",\"indexed\":true},{\"name\":\"zone\",\"type\":\"address\",\"indexed\":true},{\"name\":\"recipient\",\"type\":\"address\",\"indexed\":false},{\"name\":\"offer\",\"type\":\"tuple[]\",\"indexed\":false,\"components\":[{\"name\":\"itemType\",\"type\":\"uint8\",\"internalType\":\"enumItemType\"},{\"name\":\"token\",\"type\":\"address\"},{\"name\":\"identifier\",\"type\":\"uint256\"},{\"name\":\"amount\",\"type\":\"uint256\"}],\"internalType\":\"structSpentItem[]\"},{\"name\":\"consideration\",\"type\":\"tuple[]\",\"indexed\":false,\"components\":[{\"name\":\"itemType\",\"type\":\"uint8\",\"internalType\":\"enumItemType\"},{\"name\":\"token\",\"type\":\"address\"},{\"name\":\"identifier\",\"type\":\"uint256\"},{\"name\":\"amount\",\"type\":\"uint256\"},{\"name\":\"recipient\",\"type\":\"address\",\"internalType\":\"addresspayable\"}],\"internalType\":\"structReceivedItem[]\"}],\"name\":\"OrderFulfilled\",\"type\":\"event\"}]",
+}
+
+// SeaportABI is the input ABI used to generate the binding from.
+// Deprecated: Use SeaportMetaData.ABI instead.
+var SeaportABI = SeaportMetaData.ABI
+
+// Seaport is an auto generated Go binding around an Ethereum contract.
+type Seaport struct {
+	SeaportCaller     // Read-only binding to the contract
+	SeaportTransactor // Write-only binding to the contract
+	SeaportFilterer   // Log filterer for contract events
+}
+
+// SeaportCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SeaportCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SeaportTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SeaportTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SeaportFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SeaportFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SeaportSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SeaportSession struct {
+	Contract     *Seaport          // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SeaportCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SeaportCallerSession struct {
+	Contract *SeaportCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts  // Call options to use throughout this session
+}
+
+// SeaportTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SeaportTransactorSession struct {
+	Contract     *SeaportTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// SeaportRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SeaportRaw struct {
+	Contract *Seaport // Generic contract binding to access the raw methods on
+}
+
+// SeaportCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SeaportCallerRaw struct {
+	Contract *SeaportCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SeaportTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SeaportTransactorRaw struct {
+	Contract *SeaportTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSeaport creates a new instance of Seaport, bound to a specific deployed contract.
+func NewSeaport(address common.Address, backend bind.ContractBackend) (*Seaport, error) {
+	contract, err := bindSeaport(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Seaport{SeaportCaller: SeaportCaller{contract: contract}, SeaportTransactor: SeaportTransactor{contract: contract}, SeaportFilterer: SeaportFilterer{contract: contract}}, nil
+}
+
+// NewSeaportCaller creates a new read-only instance of Seaport, bound to a specific deployed contract.
+func NewSeaportCaller(address common.Address, caller bind.ContractCaller) (*SeaportCaller, error) {
+	contract, err := bindSeaport(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportCaller{contract: contract}, nil
+}
+
+// NewSeaportTransactor creates a new write-only instance of Seaport, bound to a specific deployed contract.
+func NewSeaportTransactor(address common.Address, transactor bind.ContractTransactor) (*SeaportTransactor, error) {
+	contract, err := bindSeaport(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportTransactor{contract: contract}, nil
+}
+
+// NewSeaportFilterer creates a new log filterer instance of Seaport, bound to a specific deployed contract.
+func NewSeaportFilterer(address common.Address, filterer bind.ContractFilterer) (*SeaportFilterer, error) {
+	contract, err := bindSeaport(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportFilterer{contract: contract}, nil
+}
+
+// bindSeaport binds a generic wrapper to an already deployed contract.
+func bindSeaport(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SeaportMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Seaport *SeaportRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Seaport.Contract.SeaportCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Seaport *SeaportRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Seaport.Contract.SeaportTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Seaport *SeaportRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Seaport.Contract.SeaportTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Seaport *SeaportCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Seaport.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Seaport *SeaportTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Seaport.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Seaport *SeaportTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Seaport.Contract.contract.Transact(opts, method, params...)
+}
+
+// SeaportOrderFulfilledIterator is returned from FilterOrderFulfilled and is used to iterate over the raw logs and unpacked data for OrderFulfilled events raised by the Seaport contract.
+type SeaportOrderFulfilledIterator struct {
+	Event *SeaportOrderFulfilled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SeaportOrderFulfilledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SeaportOrderFulfilled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SeaportOrderFulfilled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SeaportOrderFulfilledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SeaportOrderFulfilledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SeaportOrderFulfilled represents a OrderFulfilled event raised by the Seaport contract.
+type SeaportOrderFulfilled struct {
+	OrderHash     [32]byte
+	Offerer       common.Address
+	Zone          common.Address
+	Recipient     common.Address
+	Offer         []SpentItem
+	Consideration []ReceivedItem
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOrderFulfilled is a free log retrieval operation binding the contract event 0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31.
+//
+// Solidity: event OrderFulfilled(bytes32 orderHash, address indexed offerer, address indexed zone, address recipient, (uint8,address,uint256,uint256)[] offer, (uint8,address,uint256,uint256,address)[] consideration)
+func (_Seaport *SeaportFilterer) FilterOrderFulfilled(opts *bind.FilterOpts, offerer []common.Address, zone []common.Address) (*SeaportOrderFulfilledIterator, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+	var zoneRule []interface{}
+	for _, zoneItem := range zone {
+		zoneRule = append(zoneRule, zoneItem)
+	}
+
+	logs, sub, err := _Seaport.contract.FilterLogs(opts, "OrderFulfilled", offererRule, zoneRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SeaportOrderFulfilledIterator{contract: _Seaport.contract, event: "OrderFulfilled", logs: logs, sub: sub}, nil
+}
+
+// WatchOrderFulfilled is a free log subscription operation binding the contract event 0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31.
+//
+// Solidity: event OrderFulfilled(bytes32 orderHash, address indexed offerer, address indexed zone, address recipient, (uint8,address,uint256,uint256)[] offer, (uint8,address,uint256,uint256,address)[] consideration)
+func (_Seaport *SeaportFilterer) WatchOrderFulfilled(opts *bind.WatchOpts, sink chan<- *SeaportOrderFulfilled, offerer []common.Address, zone []common.Address) (event.Subscription, error) {
+
+	var offererRule []interface{}
+	for _, offererItem := range offerer {
+		offererRule = append(offererRule, offererItem)
+	}
+	var zoneRule []interface{}
+	for _, zoneItem := range zone {
+		zoneRule = append(zoneRule, zoneItem)
+	}
+
+	logs, sub, err := _Seaport.contract.WatchLogs(opts, "OrderFulfilled", offererRule, zoneRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SeaportOrderFulfilled)
+				if err := _Seaport.contract.UnpackLog(event, "OrderFulfilled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOrderFulfilled is a log parse operation binding the contract event 0x9d9af8e38d66c62e2c12f0225249fd9d721c54b83f48d9352c97c6cacdcb6f31.
+//
+// Solidity: event OrderFulfilled(bytes32 orderHash, address indexed offerer, address indexed zone, address recipient, (uint8,address,uint256,uint256)[] offer, (uint8,address,uint256,uint256,address)[] consideration)
+func (_Seaport *SeaportFilterer) ParseOrderFulfilled(log types.Log) (*SeaportOrderFulfilled, error) {
+	event := new(SeaportOrderFulfilled)
+	if err := _Seaport.contract.UnpackLog(event, "OrderFulfilled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
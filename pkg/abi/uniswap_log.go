@@ -0,0 +1,101 @@
+package abi
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/pkg/abi/uniswapv2"
+	"github.com/traitmeta/metago/pkg/abi/uniswapv3"
+)
+
+// UniswapV2SwapLog Uniswap V2 Swap 中未 indexed 的字段
+type UniswapV2SwapLog struct {
+	Amount0In  *big.Int
+	Amount1In  *big.Int
+	Amount0Out *big.Int
+	Amount1Out *big.Int
+}
+
+// UniswapV3SwapLog Uniswap V3 Swap 中未 indexed 的字段
+type UniswapV3SwapLog struct {
+	Amount0      *big.Int
+	Amount1      *big.Int
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
+	Tick         *big.Int
+}
+
+func ParseUniswapV2SwapLog(data []byte) (*UniswapV2SwapLog, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(uniswapv2.UniswapV2PairABI))
+	if err != nil {
+		return nil, err
+	}
+
+	var event UniswapV2SwapLog
+	err = contractAbi.UnpackIntoInterface(&event, "Swap", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func ParseUniswapV2SyncLog(data []byte) (reserve0 *big.Int, reserve1 *big.Int, err error) {
+	contractAbi, err := abi.JSON(strings.NewReader(uniswapv2.UniswapV2PairABI))
+	if err != nil {
+		return
+	}
+
+	event := struct {
+		Reserve0 *big.Int
+		Reserve1 *big.Int
+	}{}
+
+	err = contractAbi.UnpackIntoInterface(&event, "Sync", data)
+	if err != nil {
+		return
+	}
+
+	return event.Reserve0, event.Reserve1, nil
+}
+
+func ParseUniswapV3SwapLog(data []byte) (*UniswapV3SwapLog, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(uniswapv3.UniswapV3PoolABI))
+	if err != nil {
+		return nil, err
+	}
+
+	var event UniswapV3SwapLog
+	err = contractAbi.UnpackIntoInterface(&event, "Swap", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// GetPoolTokens 读取交易池的 token0 和 token1, V2 和 V3 的方法签名相同
+func GetPoolTokens(poolAddress string) (token0 common.Address, token1 common.Address, err error) {
+	instance, err := uniswapv3.NewUniswapV3Pool(common.HexToAddress(poolAddress), config.EthRpcClient)
+	if err != nil {
+		return
+	}
+
+	token0, err = instance.Token0(nil)
+	if err != nil {
+		err = errors.WithMessage(err, "Token0")
+		return
+	}
+
+	token1, err = instance.Token1(nil)
+	if err != nil {
+		err = errors.WithMessage(err, "Token1")
+		return
+	}
+	return
+}
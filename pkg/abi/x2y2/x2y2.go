@@ -0,0 +1,500 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package x2y2
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// MarketFee is an auto generated low-level Go binding around an user-defined struct.
+type MarketFee struct {
+	Percentage *big.Int
+	To         common.Address
+}
+
+// MarketOrderItem is an auto generated low-level Go binding around an user-defined struct.
+type MarketOrderItem struct {
+	Price *big.Int
+	Data  []byte
+}
+
+// MarketSettleDetail is an auto generated low-level Go binding around an user-defined struct.
+type MarketSettleDetail struct {
+	Op                 uint8
+	OrderIdx           *big.Int
+	ItemIdx            *big.Int
+	Price              *big.Int
+	ItemHash           [32]byte
+	ExecutionDelegate  common.Address
+	DataReplacement    []byte
+	BidIncentivePct    *big.Int
+	AucMinIncrementPct *big.Int
+	AucIncDurationSecs *big.Int
+	Fees               []MarketFee
+}
+
+// X2Y2MetaData contains all meta data concerning the X2Y2 contract.
+var X2Y2MetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"name\":\"itemHash\",\"type\":\"bytes32\",\"indexed\":true},{\"name\":\"maker\",\"type\":\"address\",\"indexed\":false},{\"name\":\"taker\",\"type\":\"address\",\"indexed\":false},{\"name\":\"orderSalt\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"settleSalt\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"intent\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"delegateType\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"deadline\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"currency\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"contractIERC20Upgradeable\"},{\"name\":\"dataMask\",\"type\":\"bytes\",\"indexed\":false},{\"name\":\"item\",\"type\":\"tuple\",\"indexed\":false,\"components\":[{\"name\":\"price\",\"type\":\"uint256\"},{\"name\":\"data\",\"type\":\"bytes\"}],\"internalType\":\"structMarket.OrderItem\"},{\"name\":\"detail\",\"type\":\"tuple\",\"indexed\":false,\"components\":[{\"name\":\"op\",\"type\":\"uint8\",\"internalType\":\"enumMarket.Op\"},{\"name\":\"orderIdx\",\"type\":\"uint256\"},{\"name\":\"itemIdx\",\"type\":\"uint256\"},{\"name\":\"price\",\"type\":\"uint256\"},{\"name\":\"itemHash\",\"type\":\"bytes32\"},{\"name\":\"executionDelegate\",\"type\":\"address\",\"internalType\":\"contractIDelegate\"},{\"name\":\"dataReplacement\",\"type\":\"bytes\"},{\"name\":\"bidIncentivePct\",\"type\":\"uint256\"},{\"name\":\"aucMinIncrementPct\",\"type\":\"uint256\"},{\"name\":\"aucIncDurationSecs\",\"type\":\"uint256\"},{\"name\":\"fees\",\"type\":\"tuple[]\",\"components\":[{\"name\":\"percentage\",\"type\":\"uint256\"},{\"name\":\"to\",\"type\":\"address\"}],\"internalType\":\"structMarket.Fee[]\"}],\"internalType\":\"structMarket.SettleDetail\"}],\"name\":\"EvInventory\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"name\":\"itemHash\",\"type\":\"bytes32\",\"indexed\":false},{\"name\":\"currency\",\"type\":\"address\",\"indexed\":false},{\"name\":\"to\",\"type\":\"address\",\"indexed\":false},{\"name\":\"amount\",\"type\":\"uint256\",\"indexed\":false}],\"name\":\"EvProfit\",\"type\":\"event\"}]",
+}
+
+// X2Y2ABI is the input ABI used to generate the binding from.
+// Deprecated: Use X2Y2MetaData.ABI instead.
+var X2Y2ABI = X2Y2MetaData.ABI
+
+// X2Y2 is an auto generated Go binding around an Ethereum contract.
+type X2Y2 struct {
+	X2Y2Caller     // Read-only binding to the contract
+	X2Y2Transactor // Write-only binding to the contract
+	X2Y2Filterer   // Log filterer for contract events
+}
+
+// X2Y2Caller is an auto generated read-only Go binding around an Ethereum contract.
+type X2Y2Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// X2Y2Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type X2Y2Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// X2Y2Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type X2Y2Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// X2Y2Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type X2Y2Session struct {
+	Contract     *X2Y2             // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// X2Y2CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type X2Y2CallerSession struct {
+	Contract *X2Y2Caller   // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// X2Y2TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type X2Y2TransactorSession struct {
+	Contract     *X2Y2Transactor   // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// X2Y2Raw is an auto generated low-level Go binding around an Ethereum contract.
+type X2Y2Raw struct {
+	Contract *X2Y2 // Generic contract binding to access the raw methods on
+}
+
+// X2Y2CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type X2Y2CallerRaw struct {
+	Contract *X2Y2Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// X2Y2TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type X2Y2TransactorRaw struct {
+	Contract *X2Y2Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewX2Y2 creates a new instance of X2Y2, bound to a specific deployed contract.
+func NewX2Y2(address common.Address, backend bind.ContractBackend) (*X2Y2, error) {
+	contract, err := bindX2Y2(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &X2Y2{X2Y2Caller: X2Y2Caller{contract: contract}, X2Y2Transactor: X2Y2Transactor{contract: contract}, X2Y2Filterer: X2Y2Filterer{contract: contract}}, nil
+}
+
+// NewX2Y2Caller creates a new read-only instance of X2Y2, bound to a specific deployed contract.
+func NewX2Y2Caller(address common.Address, caller bind.ContractCaller) (*X2Y2Caller, error) {
+	contract, err := bindX2Y2(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &X2Y2Caller{contract: contract}, nil
+}
+
+// NewX2Y2Transactor creates a new write-only instance of X2Y2, bound to a specific deployed contract.
+func NewX2Y2Transactor(address common.Address, transactor bind.ContractTransactor) (*X2Y2Transactor, error) {
+	contract, err := bindX2Y2(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &X2Y2Transactor{contract: contract}, nil
+}
+
+// NewX2Y2Filterer creates a new log filterer instance of X2Y2, bound to a specific deployed contract.
+func NewX2Y2Filterer(address common.Address, filterer bind.ContractFilterer) (*X2Y2Filterer, error) {
+	contract, err := bindX2Y2(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &X2Y2Filterer{contract: contract}, nil
+}
+
+// bindX2Y2 binds a generic wrapper to an already deployed contract.
+func bindX2Y2(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := X2Y2MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_X2Y2 *X2Y2Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _X2Y2.Contract.X2Y2Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_X2Y2 *X2Y2Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _X2Y2.Contract.X2Y2Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_X2Y2 *X2Y2Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _X2Y2.Contract.X2Y2Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_X2Y2 *X2Y2CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _X2Y2.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_X2Y2 *X2Y2TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _X2Y2.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_X2Y2 *X2Y2TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _X2Y2.Contract.contract.Transact(opts, method, params...)
+}
+
+// X2Y2EvInventoryIterator is returned from FilterEvInventory and is used to iterate over the raw logs and unpacked data for EvInventory events raised by the X2Y2 contract.
+type X2Y2EvInventoryIterator struct {
+	Event *X2Y2EvInventory // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *X2Y2EvInventoryIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(X2Y2EvInventory)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(X2Y2EvInventory)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *X2Y2EvInventoryIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *X2Y2EvInventoryIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// X2Y2EvInventory represents a EvInventory event raised by the X2Y2 contract.
+type X2Y2EvInventory struct {
+	ItemHash     [32]byte
+	Maker        common.Address
+	Taker        common.Address
+	OrderSalt    *big.Int
+	SettleSalt   *big.Int
+	Intent       *big.Int
+	DelegateType *big.Int
+	Deadline     *big.Int
+	Currency     common.Address
+	DataMask     []byte
+	Item         MarketOrderItem
+	Detail       MarketSettleDetail
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterEvInventory is a free log retrieval operation binding the contract event 0x3cbb63f144840e5b1b0a38a7c19211d2e89de4d7c5faf8b2d3c1776c302d1d33.
+//
+// Solidity: event EvInventory(bytes32 indexed itemHash, address maker, address taker, uint256 orderSalt, uint256 settleSalt, uint256 intent, uint256 delegateType, uint256 deadline, address currency, bytes dataMask, (uint256,bytes) item, (uint8,uint256,uint256,uint256,bytes32,address,bytes,uint256,uint256,uint256,(uint256,address)[]) detail)
+func (_X2Y2 *X2Y2Filterer) FilterEvInventory(opts *bind.FilterOpts, itemHash [][32]byte) (*X2Y2EvInventoryIterator, error) {
+
+	var itemHashRule []interface{}
+	for _, itemHashItem := range itemHash {
+		itemHashRule = append(itemHashRule, itemHashItem)
+	}
+
+	logs, sub, err := _X2Y2.contract.FilterLogs(opts, "EvInventory", itemHashRule)
+	if err != nil {
+		return nil, err
+	}
+	return &X2Y2EvInventoryIterator{contract: _X2Y2.contract, event: "EvInventory", logs: logs, sub: sub}, nil
+}
+
+// WatchEvInventory is a free log subscription operation binding the contract event 0x3cbb63f144840e5b1b0a38a7c19211d2e89de4d7c5faf8b2d3c1776c302d1d33.
+//
+// Solidity: event EvInventory(bytes32 indexed itemHash, address maker, address taker, uint256 orderSalt, uint256 settleSalt, uint256 intent, uint256 delegateType, uint256 deadline, address currency, bytes dataMask, (uint256,bytes) item, (uint8,uint256,uint256,uint256,bytes32,address,bytes,uint256,uint256,uint256,(uint256,address)[]) detail)
+func (_X2Y2 *X2Y2Filterer) WatchEvInventory(opts *bind.WatchOpts, sink chan<- *X2Y2EvInventory, itemHash [][32]byte) (event.Subscription, error) {
+
+	var itemHashRule []interface{}
+	for _, itemHashItem := range itemHash {
+		itemHashRule = append(itemHashRule, itemHashItem)
+	}
+
+	logs, sub, err := _X2Y2.contract.WatchLogs(opts, "EvInventory", itemHashRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(X2Y2EvInventory)
+				if err := _X2Y2.contract.UnpackLog(event, "EvInventory", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseEvInventory is a log parse operation binding the contract event 0x3cbb63f144840e5b1b0a38a7c19211d2e89de4d7c5faf8b2d3c1776c302d1d33.
+//
+// Solidity: event EvInventory(bytes32 indexed itemHash, address maker, address taker, uint256 orderSalt, uint256 settleSalt, uint256 intent, uint256 delegateType, uint256 deadline, address currency, bytes dataMask, (uint256,bytes) item, (uint8,uint256,uint256,uint256,bytes32,address,bytes,uint256,uint256,uint256,(uint256,address)[]) detail)
+func (_X2Y2 *X2Y2Filterer) ParseEvInventory(log types.Log) (*X2Y2EvInventory, error) {
+	event := new(X2Y2EvInventory)
+	if err := _X2Y2.contract.UnpackLog(event, "EvInventory", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// X2Y2EvProfitIterator is returned from FilterEvProfit and is used to iterate over the raw logs and unpacked data for EvProfit events raised by the X2Y2 contract.
+type X2Y2EvProfitIterator struct {
+	Event *X2Y2EvProfit // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *X2Y2EvProfitIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(X2Y2EvProfit)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(X2Y2EvProfit)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *X2Y2EvProfitIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *X2Y2EvProfitIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// X2Y2EvProfit represents a EvProfit event raised by the X2Y2 contract.
+type X2Y2EvProfit struct {
+	ItemHash [32]byte
+	Currency common.Address
+	To       common.Address
+	Amount   *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterEvProfit is a free log retrieval operation binding the contract event 0xe2c49856b032c255ae7e325d18109bc4e22a2804e2e49a017ec0f59f19cd447b.
+//
+// Solidity: event EvProfit(bytes32 itemHash, address currency, address to, uint256 amount)
+func (_X2Y2 *X2Y2Filterer) FilterEvProfit(opts *bind.FilterOpts) (*X2Y2EvProfitIterator, error) {
+
+	logs, sub, err := _X2Y2.contract.FilterLogs(opts, "EvProfit")
+	if err != nil {
+		return nil, err
+	}
+	return &X2Y2EvProfitIterator{contract: _X2Y2.contract, event: "EvProfit", logs: logs, sub: sub}, nil
+}
+
+// WatchEvProfit is a free log subscription operation binding the contract event 0xe2c49856b032c255ae7e325d18109bc4e22a2804e2e49a017ec0f59f19cd447b.
+//
+// Solidity: event EvProfit(bytes32 itemHash, address currency, address to, uint256 amount)
+func (_X2Y2 *X2Y2Filterer) WatchEvProfit(opts *bind.WatchOpts, sink chan<- *X2Y2EvProfit) (event.Subscription, error) {
+
+	logs, sub, err := _X2Y2.contract.WatchLogs(opts, "EvProfit")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(X2Y2EvProfit)
+				if err := _X2Y2.contract.UnpackLog(event, "EvProfit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseEvProfit is a log parse operation binding the contract event 0xe2c49856b032c255ae7e325d18109bc4e22a2804e2e49a017ec0f59f19cd447b.
+//
+// Solidity: event EvProfit(bytes32 itemHash, address currency, address to, uint256 amount)
+func (_X2Y2 *X2Y2Filterer) ParseEvProfit(log types.Log) (*X2Y2EvProfit, error) {
+	event := new(X2Y2EvProfit)
+	if err := _X2Y2.contract.UnpackLog(event, "EvProfit", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
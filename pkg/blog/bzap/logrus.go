@@ -0,0 +1,53 @@
+package bzap
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+// logrusHook 把 logrus 的日志转发到 zap, Panic 和 Fatal 级别由 logrus 自己处理退出
+type logrusHook struct {
+	logger *zap.Logger
+}
+
+func (h *logrusHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *logrusHook) Fire(e *logrus.Entry) error {
+	fields := make([]zap.Field, 0, len(e.Data))
+	for k, v := range e.Data {
+		fields = append(fields, zap.Any(k, v))
+	}
+	switch e.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		h.logger.Error(e.Message, fields...)
+	case logrus.WarnLevel:
+		h.logger.Warn(e.Message, fields...)
+	case logrus.InfoLevel:
+		h.logger.Info(e.Message, fields...)
+	default:
+		h.logger.Debug(e.Message, fields...)
+	}
+	return nil
+}
+
+// RedirectLogrus logrus 和标准库 log 的日志都写入 logger, 返回恢复原来输出的函数
+func RedirectLogrus(logger *zap.Logger, level logrus.Level) func() {
+	std := logrus.StandardLogger()
+	out, oldLevel := std.Out, std.GetLevel()
+	hooks := std.ReplaceHooks(logrus.LevelHooks{})
+	std.AddHook(&logrusHook{logger: logger})
+	std.SetOutput(io.Discard)
+	std.SetLevel(level)
+	restoreStdLog := zap.RedirectStdLog(logger)
+
+	return func() {
+		restoreStdLog()
+		std.ReplaceHooks(hooks)
+		std.SetOutput(out)
+		std.SetLevel(oldLevel)
+	}
+}
@@ -0,0 +1,47 @@
+package bzap
+
+import (
+	"errors"
+	stdlog "log"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRedirectLogrus(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	restore := RedirectLogrus(zap.New(core), logrus.InfoLevel)
+
+	logrus.WithField("height", 10).Info("sync block")
+	logrus.WithField("err", errors.New("timeout")).Warn("retrying")
+	logrus.Error("failed")
+	logrus.Debug("filtered by logrus level")
+	stdlog.Print("from std log")
+	restore()
+	logrus.Info("after restore")
+
+	want := []struct {
+		level zapcore.Level
+		msg   string
+	}{
+		{zapcore.InfoLevel, "sync block"},
+		{zapcore.WarnLevel, "retrying"},
+		{zapcore.ErrorLevel, "failed"},
+		{zapcore.InfoLevel, "from std log"},
+	}
+	entries := logs.All()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries: %v", len(entries), entries)
+	}
+	for i, w := range want {
+		if entries[i].Level != w.level || entries[i].Message != w.msg {
+			t.Errorf("entry %d = %s %q, want %s %q", i, entries[i].Level, entries[i].Message, w.level, w.msg)
+		}
+	}
+	if entries[0].ContextMap()["height"] != int64(10) || entries[1].ContextMap()["err"] != "timeout" {
+		t.Errorf("fields = %v, %v", entries[0].ContextMap(), entries[1].ContextMap())
+	}
+}
@@ -64,6 +64,7 @@ var (
 	ErrFileChunkNotExist          = NewErr(21202, "File Chunk does not exist")
 	ErrFileUnknown                = NewErr(21203, "File type unknown")
 	ErrFileLoadErr                = NewErr(21204, "File load failed")
+	ErrRecordNotFound             = NewErr(22000, "Record not found", http.StatusNotFound)
 
 	ErrUserNotExist = NewErr(11300, "User does not exist")
 )
@@ -101,6 +102,7 @@ var codeToErr = map[uint32]*Err{
 	21203: ErrFileUnknown,
 	21204: ErrFileLoadErr,
 	21300: ErrUserNotExist,
+	22000: ErrRecordNotFound,
 }
 
 func NewErr(code uint32, msg string, httpCode ...int) *Err {
@@ -0,0 +1,285 @@
+/*
+## Overview
+metrics 导出各索引器的 Prometheus 指标, 并提供 /healthz 和 /readyz 健康检查.
+
+  - 每个索引器通过 NewIndexer(name) 注册, 指标用 indexer 标签区分, 例如 eth、btc_block、tap_dmt
+  - metago_indexer_indexed_height / head_height / lag_blocks 为已索引高度、节点最新高度和两者之差
+  - metago_indexer_stage_duration_seconds 按 stage 标签记录 fetch、parse、persist 各阶段耗时
+  - metago_indexer_rpc_errors_total 和 metago_indexer_rollbacks_total 为 RPC 错误和回滚次数
+  - /readyz 所有索引器都已上报高度且落后不超过 MaxLag 时返回 200, 否则返回 503
+  - /healthz 索引器落后超过 MaxLag 且超过 StallTimeout 没有新的进展时返回 503, 正常追块的索引器不影响存活检查
+  - Reload 热加载 MaxLag 和 StallTimeout, 监听地址变更需要重启
+*/
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+// 索引器处理一个区块的阶段
+const (
+	StageFetch   = "fetch"
+	StageParse   = "parse"
+	StagePersist = "persist"
+)
+
+const (
+	// DefaultLagKey MaxLag 中对没有单独配置的索引器生效的键
+	DefaultLagKey       = "default"
+	defaultMaxLag       = 10
+	defaultStallTimeout = 5 * time.Minute
+)
+
+// Registry 指标注册表, 包含 Go 运行时和进程指标
+var Registry = prometheus.NewRegistry()
+
+var (
+	indexedHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "metago", Subsystem: "indexer", Name: "indexed_height",
+		Help: "Last block height persisted by the indexer.",
+	}, []string{"indexer"})
+	headHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "metago", Subsystem: "indexer", Name: "head_height",
+		Help: "Latest block height reported by the chain node.",
+	}, []string{"indexer"})
+	lagBlocks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "metago", Subsystem: "indexer", Name: "lag_blocks",
+		Help: "Blocks between the chain head and the indexed height.",
+	}, []string{"indexer"})
+	stageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "metago", Subsystem: "indexer", Name: "stage_duration_seconds",
+		Help:    "Time spent per block in each indexing stage.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"indexer", "stage"})
+	rpcErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "metago", Subsystem: "indexer", Name: "rpc_errors_total",
+		Help: "Failed calls to the chain node.",
+	}, []string{"indexer"})
+	rollbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "metago", Subsystem: "indexer", Name: "rollbacks_total",
+		Help: "Chain reorganizations rolled back or reindexed.",
+	}, []string{"indexer"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		indexedHeight, headHeight, lagBlocks, stageDuration, rpcErrors, rollbacks,
+	)
+}
+
+var (
+	mu       sync.Mutex
+	indexers = map[string]*Indexer{}
+)
+
+// Indexer 一个索引器的指标和健康状态
+type Indexer struct {
+	name string
+
+	mu         sync.Mutex
+	indexed    uint64
+	head       uint64
+	hasIndexed bool
+	hasHead    bool
+	// progressAt 已索引高度最后一次变化的时间
+	progressAt time.Time
+}
+
+// NewIndexer 注册索引器, 同名的索引器返回同一个实例
+func NewIndexer(name string) *Indexer {
+	mu.Lock()
+	defer mu.Unlock()
+	if i, ok := indexers[name]; ok {
+		return i
+	}
+	i := &Indexer{name: name, progressAt: time.Now()}
+	indexers[name] = i
+	return i
+}
+
+func (i *Indexer) Name() string {
+	return i.name
+}
+
+// SetIndexed 更新已索引的高度
+func (i *Indexer) SetIndexed(height uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.hasIndexed || height != i.indexed {
+		i.progressAt = time.Now()
+	}
+	i.indexed, i.hasIndexed = height, true
+	indexedHeight.WithLabelValues(i.name).Set(float64(height))
+	i.updateLag()
+}
+
+// SetHead 更新节点的最新高度
+func (i *Indexer) SetHead(height uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.head, i.hasHead = height, true
+	headHeight.WithLabelValues(i.name).Set(float64(height))
+	i.updateLag()
+}
+
+func (i *Indexer) updateLag() {
+	if i.hasIndexed && i.hasHead {
+		lagBlocks.WithLabelValues(i.name).Set(float64(i.lag()))
+	}
+}
+
+func (i *Indexer) lag() uint64 {
+	if i.head > i.indexed {
+		return i.head - i.indexed
+	}
+	return 0
+}
+
+// ObserveStage 记录从 start 开始的阶段耗时
+func (i *Indexer) ObserveStage(stage string, start time.Time) {
+	stageDuration.WithLabelValues(i.name, stage).Observe(time.Since(start).Seconds())
+}
+
+// RPCError 记录一次节点调用失败
+func (i *Indexer) RPCError() {
+	rpcErrors.WithLabelValues(i.name).Inc()
+}
+
+// Rollback 记录一次链重组导致的回滚, 补录已经入库的区块不计入
+func (i *Indexer) Rollback() {
+	rollbacks.WithLabelValues(i.name).Inc()
+}
+
+// Status 索引器的健康状态
+type Status struct {
+	Indexer string `json:"indexer"`
+	Indexed uint64 `json:"indexed"`
+	Head    uint64 `json:"head"`
+	Lag     uint64 `json:"lag"`
+	MaxLag  uint64 `json:"max_lag"`
+	Ready   bool   `json:"ready"`
+	Healthy bool   `json:"healthy"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (i *Indexer) status(maxLag uint64, stallTimeout time.Duration, now time.Time) Status {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	s := Status{Indexer: i.name, Indexed: i.indexed, Head: i.head, Lag: i.lag(), MaxLag: maxLag, Ready: true, Healthy: true}
+	switch {
+	case !i.hasIndexed || !i.hasHead:
+		s.Ready, s.Reason = false, "height not reported yet"
+	case s.Lag > maxLag:
+		s.Ready, s.Reason = false, "lag exceeds max lag"
+	}
+	if !s.Ready && now.Sub(i.progressAt) > stallTimeout {
+		s.Healthy, s.Reason = false, s.Reason+", no progress since "+i.progressAt.UTC().Format(time.RFC3339)
+	}
+	return s
+}
+
+// Check 按配置检查所有已注册索引器的状态, 按名称排序
+func Check(conf *setting.MetricsConfig, now time.Time) []Status {
+	mu.Lock()
+	list := make([]*Indexer, 0, len(indexers))
+	for _, i := range indexers {
+		list = append(list, i)
+	}
+	mu.Unlock()
+	sort.Slice(list, func(a, b int) bool { return list[a].name < list[b].name })
+
+	stallTimeout := defaultStallTimeout
+	if conf != nil && conf.StallTimeout > 0 {
+		stallTimeout = conf.StallTimeout
+	}
+	statuses := make([]Status, 0, len(list))
+	for _, i := range list {
+		statuses = append(statuses, i.status(maxLag(conf, i.name), stallTimeout, now))
+	}
+	return statuses
+}
+
+func maxLag(conf *setting.MetricsConfig, name string) uint64 {
+	if conf != nil {
+		if lag, ok := conf.MaxLag[name]; ok {
+			return lag
+		}
+		if lag, ok := conf.MaxLag[DefaultLagKey]; ok {
+			return lag
+		}
+	}
+	return defaultMaxLag
+}
+
+// reloaded 热加载的配置, 设置后代替传给 Handler 的配置, 只有 MaxLag 和 StallTimeout 会生效
+var reloaded atomic.Pointer[setting.MetricsConfig]
+
+// Reload 更新健康检查使用的配置
+func Reload(conf *setting.MetricsConfig) {
+	reloaded.Store(conf)
+}
+
+// Handler 返回 /metrics、/healthz 和 /readyz 的路由
+func Handler(conf *setting.MetricsConfig) http.Handler {
+	current := func() *setting.MetricsConfig {
+		if c := reloaded.Load(); c != nil {
+			return c
+		}
+		return conf
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, Check(current(), time.Now()), func(s Status) bool { return s.Healthy })
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, Check(current(), time.Now()), func(s Status) bool { return s.Ready })
+	})
+	return mux
+}
+
+func writeStatus(w http.ResponseWriter, statuses []Status, ok func(Status) bool) {
+	code, status := http.StatusOK, "ok"
+	for _, s := range statuses {
+		if !ok(s) {
+			code, status = http.StatusServiceUnavailable, "fail"
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "indexers": statuses})
+}
+
+// Setup 在 conf.Addr 上启动指标和健康检查服务, 没有配置时不启动, ctx 结束时关闭
+func Setup(ctx context.Context, conf *setting.MetricsConfig) (*http.Server, error) {
+	if conf == nil || conf.Addr == "" {
+		return nil, nil
+	}
+	srv := &http.Server{Addr: conf.Addr, Handler: Handler(conf), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithField("err", err).Error("metrics server stopped")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	log.WithField("addr", conf.Addr).Info("metrics server started")
+	return srv, nil
+}
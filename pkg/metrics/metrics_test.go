@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/traitmeta/metago/config/setting"
+)
+
+func resetIndexers() {
+	mu.Lock()
+	indexers = map[string]*Indexer{}
+	mu.Unlock()
+}
+
+func TestCheck(t *testing.T) {
+	resetIndexers()
+	defer resetIndexers()
+	conf := &setting.MetricsConfig{MaxLag: map[string]uint64{"default": 5, "btc": 1}, StallTimeout: time.Minute}
+	now := time.Now()
+
+	eth := NewIndexer("eth")
+	if NewIndexer("eth") != eth {
+		t.Fatal("NewIndexer should return the registered indexer")
+	}
+	btc := NewIndexer("btc")
+	tap := NewIndexer("tap")
+
+	eth.SetIndexed(100)
+	eth.SetHead(105)
+	btc.SetIndexed(10)
+	btc.SetHead(12)
+	tap.SetIndexed(7)
+	tap.mu.Lock()
+	tap.progressAt = now.Add(-2 * time.Minute)
+	tap.mu.Unlock()
+
+	tests := []struct {
+		name    string
+		lag     uint64
+		maxLag  uint64
+		ready   bool
+		healthy bool
+	}{
+		{"btc", 2, 1, false, true},
+		{"eth", 5, 5, true, true},
+		{"tap", 0, 5, false, false},
+	}
+	statuses := Check(conf, now)
+	if len(statuses) != len(tests) {
+		t.Fatalf("got %d statuses", len(statuses))
+	}
+	for i, tt := range tests {
+		s := statuses[i]
+		if s.Indexer != tt.name || s.Lag != tt.lag || s.MaxLag != tt.maxLag || s.Ready != tt.ready || s.Healthy != tt.healthy {
+			t.Errorf("status %d = %+v, want %+v", i, s, tt)
+		}
+	}
+
+	// 新的进展之后恢复存活, 但仍然没有就绪
+	tap.SetIndexed(8)
+	if s := Check(conf, now)[2]; !s.Healthy || s.Ready {
+		t.Errorf("tap after progress = %+v", s)
+	}
+	// 节点高度低于已索引高度时 lag 为0
+	eth.SetHead(90)
+	if s := Check(conf, now)[1]; s.Lag != 0 || !s.Ready {
+		t.Errorf("eth behind node = %+v", s)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	resetIndexers()
+	defer resetIndexers()
+	conf := &setting.MetricsConfig{MaxLag: map[string]uint64{"handler": 2}}
+	i := NewIndexer("handler")
+	i.SetIndexed(10)
+	i.SetHead(20)
+	i.ObserveStage(StageFetch, time.Now())
+	i.RPCError()
+	i.Rollback()
+
+	srv := httptest.NewServer(Handler(conf))
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/readyz")
+	var out struct {
+		Status   string   `json:"status"`
+		Indexers []Status `json:"indexers"`
+	}
+	json.Unmarshal([]byte(body), &out)
+	if code != http.StatusServiceUnavailable || out.Status != "fail" || len(out.Indexers) != 1 || out.Indexers[0].Lag != 10 {
+		t.Errorf("readyz = %d %s", code, body)
+	}
+	if code, body = get("/healthz"); code != http.StatusOK {
+		t.Errorf("healthz = %d %s", code, body)
+	}
+
+	i.SetIndexed(19)
+	if code, body = get("/readyz"); code != http.StatusOK {
+		t.Errorf("readyz after catching up = %d %s", code, body)
+	}
+
+	Reload(&setting.MetricsConfig{MaxLag: map[string]uint64{"handler": 0}})
+	defer Reload(nil)
+	if code, body = get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("readyz after reloading MaxLag = %d %s", code, body)
+	}
+
+	code, body = get("/metrics")
+	for _, want := range []string{
+		`metago_indexer_indexed_height{indexer="handler"} 19`,
+		`metago_indexer_head_height{indexer="handler"} 20`,
+		`metago_indexer_lag_blocks{indexer="handler"} 1`,
+		`metago_indexer_stage_duration_seconds_count{indexer="handler",stage="fetch"} 1`,
+		`metago_indexer_rpc_errors_total{indexer="handler"} 1`,
+		`metago_indexer_rollbacks_total{indexer="handler"} 1`,
+		`go_goroutines`,
+	} {
+		if code != http.StatusOK || !strings.Contains(body, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
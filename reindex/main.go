@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/reindex"
+)
+
+var (
+	from    = flag.Uint64("from", 0, "first block height to verify")
+	to      = flag.Uint64("to", 0, "last block height to verify")
+	dryRun  = flag.Bool("dry-run", false, "only report and flag mismatches, do not reindex")
+	flagged = flag.Bool("flagged", false, "also reindex heights already flagged with refetch_needed")
+)
+
+func init() {
+	config.SetupConfig()
+	db.SetupDBEngine(*config.DB)
+	config.SetupEthClient()
+	dal.Init()
+}
+
+func main() {
+	flag.Parse()
+	if *to < *from || *to == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	mismatches, err := reindex.Verify(ctx, config.EthRpcClient, *from, *to)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range mismatches {
+		log.Println(m)
+	}
+	if err := reindex.Flag(ctx, mismatches); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("verified blocks %d to %d, %d mismatches", *from, *to, len(mismatches))
+
+	heights := reindex.Heights(mismatches)
+	if *flagged {
+		more, err := dal.Block.GetRefetchNeeded(ctx, *from, *to)
+		if err != nil {
+			log.Fatal(err)
+		}
+		heights = append(heights, more...)
+		slices.Sort(heights)
+		heights = slices.Compact(heights)
+	}
+	if *dryRun || len(heights) == 0 {
+		return
+	}
+
+	if err := reindex.Reindex(ctx, config.EthRpcClient, heights); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("reindexed %d blocks", len(heights))
+}
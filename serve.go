@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/graphql/graph"
+)
+
+func runServeGraphql(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("serve graphql")
+	addr := fs.String("addr", config.ListenAddr(config.Server.Graphql), "listen address")
+	fs.Parse(args)
+
+	a.setupDB()
+	srv := &http.Server{Addr: *addr, Handler: graph.NewHandler(), ReadHeaderTimeout: 10 * time.Second}
+	return serve(ctx, srv)
+}
+
+// serve 启动 HTTP 服务, ctx 结束时等待进行中的请求完成后返回
+func serve(ctx context.Context, srv *http.Server) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	log.Printf("listening on %s", srv.Addr)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
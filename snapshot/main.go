@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/traitmeta/gotos/lib/db"
+	"github.com/traitmeta/metago/config"
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/snapshot"
+)
+
+var (
+	token   = flag.String("token", "", "token contract address")
+	height  = flag.Uint64("height", 0, "block height of the snapshot")
+	tokenId = flag.String("token-id", "", "only count this token id, for ERC-721/ERC-1155")
+	format  = flag.String("format", "json", "output format, csv or json")
+	output  = flag.String("out", "", "output file, stdout when empty")
+	verify  = flag.Int("verify", 0, "number of sampled holders checked against balanceOf, needs an archive node")
+)
+
+func init() {
+	config.SetupConfig()
+	db.SetupDBEngine(*config.DB)
+	dal.Init()
+}
+
+func main() {
+	flag.Parse()
+	if *token == "" || *height == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var id *big.Int
+	if *tokenId != "" {
+		var ok bool
+		if id, ok = new(big.Int).SetString(*tokenId, 0); !ok {
+			log.Fatalf("invalid token id: %s", *tokenId)
+		}
+	}
+
+	ctx := context.Background()
+	snap, err := snapshot.Build(ctx, *token, *height, id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("token %s at %d: %d holders, merkle root %s", snap.Token, snap.Height, len(snap.Holders), snap.MerkleRoot.Hex())
+	if snap.NegativeBalances > 0 {
+		log.Printf("%d addresses have negative balances, token transfers are incomplete", snap.NegativeBalances)
+	}
+
+	if *verify > 0 {
+		config.SetupEthClient()
+		mismatches, err := snap.Verify(ctx, snap.Sample(*verify, time.Now().UnixNano()), snapshot.ChainBalanceOf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range mismatches {
+			log.Printf("balance mismatch %s: snapshot %s, chain %s", m.Address, m.Balance, m.OnChain)
+		}
+		log.Printf("verified %d sampled holders, %d mismatches", min(*verify, len(snap.Holders)), len(mismatches))
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "csv":
+		err = snap.WriteCSV(w)
+	default:
+		err = snap.WriteJSON(w)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
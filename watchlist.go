@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/traitmeta/metago/core/dal"
+	"github.com/traitmeta/metago/core/watchlist"
+)
+
+// watchlistSecretEnv 未传 -secret 时读取的环境变量, 避免密钥留在 shell 历史中
+const watchlistSecretEnv = "METAGO_WATCHLIST_SECRET"
+
+func runWatchlistAdd(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("watchlist add")
+	name := fs.String("name", "", "name of the watchlist entry")
+	address := fs.String("address", "", "address to watch")
+	token := fs.String("token", "", "only watch transfers of this token contract")
+	webhook := fs.String("webhook", "", "http(s) url the matches are posted to")
+	secret := fs.String("secret", "", "HMAC secret of the webhook, defaults to $"+watchlistSecretEnv)
+	fs.Parse(args)
+	if *secret == "" {
+		*secret = os.Getenv(watchlistSecretEnv)
+	}
+
+	a.setupDB()
+	entry, err := watchlist.Register(ctx, *name, *address, *token, *webhook, *secret)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("watchlist %d added for %s\n", entry.ID, entry.Address)
+	return nil
+}
+
+func runWatchlistDeadLetters(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("watchlist dead-letters")
+	offset := fs.Int("offset", 0, "number of dead letters to skip")
+	limit := fs.Int("limit", 20, "number of dead letters to list")
+	fs.Parse(args)
+
+	a.setupDB()
+	deadLetters, err := dal.Watchlist.ListDeadLetters(ctx, *offset, *limit)
+	if err != nil {
+		return errors.Wrap(err, "list dead letters")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tWATCHLIST\tATTEMPTS\tFAILED AT\tLAST ERROR")
+	for _, d := range deadLetters {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\n", d.ID, d.WatchlistId, d.Attempts, d.CreatedAt.Format(time.DateTime), d.LastError)
+	}
+	return w.Flush()
+}
+
+func runWatchlistReplay(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("watchlist replay")
+	id := fs.Uint("id", 0, "dead letter to put back into the delivery queue")
+	fs.Parse(args)
+	if *id == 0 {
+		fs.Usage()
+		return errors.New("-id is required")
+	}
+
+	a.setupDB()
+	if err := watchlist.Replay(ctx, *id); err != nil {
+		return err
+	}
+	fmt.Printf("dead letter %d queued for delivery\n", *id)
+	return nil
+}